@@ -20,6 +20,7 @@ import (
 	"github.com/cortexproject/cortex/tools/blocksconvert/cleaner"
 	"github.com/cortexproject/cortex/tools/blocksconvert/scanner"
 	"github.com/cortexproject/cortex/tools/blocksconvert/scheduler"
+	"github.com/cortexproject/cortex/tools/blocksconvert/verifier"
 )
 
 type Config struct {
@@ -31,16 +32,18 @@ type Config struct {
 	BuilderConfig   builder.Config
 	SchedulerConfig scheduler.Config
 	CleanerConfig   cleaner.Config
+	VerifierConfig  verifier.Config
 }
 
 func main() {
 	cfg := Config{}
-	flag.StringVar(&cfg.Target, "target", "", "Module to run: Scanner, Scheduler, Builder")
+	flag.StringVar(&cfg.Target, "target", "", "Module to run: Scanner, Scheduler, Builder, Cleaner, Verifier")
 	cfg.SharedConfig.RegisterFlags(flag.CommandLine)
 	cfg.ScannerConfig.RegisterFlags(flag.CommandLine)
 	cfg.BuilderConfig.RegisterFlags(flag.CommandLine)
 	cfg.SchedulerConfig.RegisterFlags(flag.CommandLine)
 	cfg.CleanerConfig.RegisterFlags(flag.CommandLine)
+	cfg.VerifierConfig.RegisterFlags(flag.CommandLine)
 	cfg.ServerConfig.RegisterFlags(flag.CommandLine)
 	flag.Parse()
 
@@ -67,6 +70,8 @@ func main() {
 		targetService, err = scheduler.NewScheduler(cfg.SchedulerConfig, cfg.SharedConfig, util_log.Logger, registry, serv.HTTP, serv.GRPC)
 	case "cleaner":
 		targetService, err = cleaner.NewCleaner(cfg.CleanerConfig, cfg.SharedConfig, util_log.Logger, registry)
+	case "verifier":
+		targetService, err = verifier.NewVerifier(cfg.VerifierConfig, cfg.SharedConfig, util_log.Logger, registry)
 	default:
 		err = fmt.Errorf("unknown target")
 	}
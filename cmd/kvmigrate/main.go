@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/weaveworks/common/logging"
+
+	"github.com/cortexproject/cortex/pkg/distributor"
+	"github.com/cortexproject/cortex/pkg/ring"
+	"github.com/cortexproject/cortex/pkg/ring/kv"
+	"github.com/cortexproject/cortex/pkg/ring/kv/codec"
+	"github.com/cortexproject/cortex/pkg/ring/kv/memberlist"
+	"github.com/cortexproject/cortex/pkg/util/flagext"
+	"github.com/cortexproject/cortex/pkg/util/log"
+	"github.com/cortexproject/cortex/tools/kvmigrate"
+)
+
+const usage = `%s copies the rings, the ingesters maintenance lease and the HA tracker state
+from a source KV store to a destination KV store, and verifies that the destination
+converged to the copied values.
+
+Usage: %s [flags]
+
+The source and destination stores are configured with the same flags used by the
+Cortex components, prefixed with "source." and "destination." respectively (eg.
+-source.store=consul -source.consul.hostname=consul:8500 -destination.store=memberlist).
+Keys are full paths in the stores (eg. collectors/ring), unless -source.prefix or
+-destination.prefix are set.
+
+Flags:
+`
+
+func main() {
+	var (
+		sourceCfg            kv.Config
+		destinationCfg       kv.Config
+		memberlistCfg        memberlist.KVConfig
+		ringKeys             flagext.StringSliceCSV
+		maintenanceLeaseKeys flagext.StringSliceCSV
+		haTrackerPrefix      string
+		dryRun               bool
+		force                bool
+		verifyTimeout        time.Duration
+	)
+
+	logfmt, loglvl := logging.Format{}, logging.Level{}
+	logfmt.RegisterFlags(flag.CommandLine)
+	loglvl.RegisterFlags(flag.CommandLine)
+	sourceCfg.RegisterFlagsWithPrefix("source.", "", flag.CommandLine)
+	destinationCfg.RegisterFlagsWithPrefix("destination.", "", flag.CommandLine)
+	memberlistCfg.RegisterFlags(flag.CommandLine)
+
+	ringKeys = []string{"collectors/ring", "collectors/distributor", "collectors/store-gateway", "collectors/compactor", "rulers/ring", "alertmanagers/alertmanager"}
+	maintenanceLeaseKeys = []string{ring.MaintenanceLeaseKey("collectors/ring")}
+	flag.Var(&ringKeys, "ring-keys", "Comma-separated list of the keys of the rings to migrate.")
+	flag.Var(&maintenanceLeaseKeys, "maintenance-lease-keys", "Comma-separated list of the keys of the ingesters maintenance leases to migrate.")
+	flag.StringVar(&haTrackerPrefix, "ha-tracker-prefix", "ha-tracker/", "Prefix of the HA tracker keys to migrate. All keys under this prefix are migrated. Empty to not migrate the HA tracker state. The HA tracker state is never migrated when the destination is memberlist, which the HA tracker doesn't support.")
+	flag.BoolVar(&dryRun, "dry-run", false, "Only print the keys which would be migrated, without writing to the destination.")
+	flag.BoolVar(&force, "force", false, "Overwrite the keys which already exist in the destination. By default they're skipped.")
+	flag.DurationVar(&verifyTimeout, "verify-timeout", time.Minute, "How long to wait for the destination to converge to the migrated values.")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), usage, os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+
+	// The tool isn't a member of the cluster, so it listens on a random port by default.
+	memberlistCfg.TCPTransport.BindPort = 0
+	flag.Parse()
+
+	if sourceCfg.Store == "memberlist" && destinationCfg.Store == "memberlist" {
+		fatal("the source and destination stores can't both be memberlist")
+	}
+
+	logger, err := log.NewPrometheusLogger(loglvl, logfmt)
+	if err != nil {
+		fatal("failed to create logger: %v", err)
+	}
+
+	if destinationCfg.Store == "memberlist" && haTrackerPrefix != "" {
+		level.Warn(logger).Log("msg", "the HA tracker doesn't support memberlist, so its state is not migrated", "ha_tracker_prefix", haTrackerPrefix)
+		haTrackerPrefix = ""
+	}
+
+	sets := []kvmigrate.KeySet{
+		{Codec: ring.GetCodec(), Keys: ringKeys},
+		{Codec: ring.GetMaintenanceLeaseCodec(), Keys: maintenanceLeaseKeys},
+	}
+	if haTrackerPrefix != "" {
+		sets = append(sets, kvmigrate.KeySet{Codec: distributor.GetReplicaDescCodec(), Prefix: haTrackerPrefix})
+	}
+
+	// The memberlist KV is started only if used by one of the stores.
+	var memberlistKV *memberlist.KV
+	getMemberlistKV := func() (*memberlist.KV, error) {
+		if memberlistKV == nil {
+			codecs := make([]codec.Codec, 0, len(sets))
+			for _, s := range sets {
+				codecs = append(codecs, s.Codec)
+			}
+
+			mkv, stop, err := kvmigrate.NewMemberlistKV(memberlistCfg, codecs, logger)
+			if err != nil {
+				return nil, err
+			}
+			memberlistKV, stopMemberlist = mkv, stop
+		}
+		return memberlistKV, nil
+	}
+	sourceCfg.MemberlistKV = getMemberlistKV
+	destinationCfg.MemberlistKV = getMemberlistKV
+	defer func() { stopMemberlist() }()
+
+	migrator, err := kvmigrate.New(sets, func(c codec.Codec) (kv.Client, error) {
+		return kv.NewClient(sourceCfg, c, nil)
+	}, func(c codec.Codec) (kv.Client, error) {
+		return kv.NewClient(destinationCfg, c, nil)
+	}, logger)
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	ctx := context.Background()
+	keys, err := migrator.Plan(ctx)
+	if err != nil {
+		fatal("%v", err)
+	}
+	if err := kvmigrate.WritePlan(os.Stdout, keys); err != nil {
+		fatal("failed to print the migration plan: %v", err)
+	}
+	if dryRun {
+		return
+	}
+
+	// The elected replicas keep changing while the HA tracker is running, so the copied
+	// values could be immediately stale and the verification never converge.
+	if haTrackerPrefix != "" {
+		fmt.Fprintln(os.Stderr, "WARNING: the HA tracker state is migrated, make sure the HA tracker is disabled in all distributors (or they're stopped) while migrating.")
+	}
+
+	migrated, err := migrator.Migrate(ctx, keys, force)
+	if err != nil {
+		fatal("%v", err)
+	}
+
+	pending, err := migrator.Verify(ctx, migrated, verifyTimeout)
+	if err != nil {
+		fatal("%v", err)
+	}
+	if len(pending) > 0 {
+		fmt.Println()
+		_ = kvmigrate.WritePlan(os.Stdout, pending)
+		fatal("%d key(s) didn't converge in the destination within %s", len(pending), verifyTimeout)
+	}
+
+	skipped := 0
+	for _, key := range keys {
+		if key.Status == kvmigrate.StatusMissing || key.Status == kvmigrate.StatusDifferent {
+			skipped++
+		}
+	}
+	if skipped -= len(migrated); skipped > 0 {
+		fmt.Printf("Migration completed, %d key(s) already existing in the destination have been skipped (use -force to overwrite them).\n", skipped)
+		return
+	}
+	fmt.Println("Migration completed.")
+}
+
+// stopMemberlist leaves the memberlist cluster, if joined.
+var stopMemberlist = func() {}
+
+func fatal(msg string, args ...interface{}) {
+	stopMemberlist()
+	fmt.Fprintf(os.Stderr, msg+"\n", args...)
+	os.Exit(1)
+}
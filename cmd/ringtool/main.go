@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/weaveworks/common/logging"
+
+	"github.com/cortexproject/cortex/pkg/ring"
+	"github.com/cortexproject/cortex/pkg/ring/kv"
+	"github.com/cortexproject/cortex/pkg/ring/kv/memberlist"
+	"github.com/cortexproject/cortex/pkg/util/log"
+	"github.com/cortexproject/cortex/tools/ringtool"
+)
+
+const usage = `%s is a tool to inspect and modify a Cortex ring directly in the KV store,
+without requiring the components using the ring to be running.
+
+Usage: %s [flags] <command> [args]
+
+Commands:
+  status                          Print the ring status.
+  diff <file>                     Print the differences between the ring status saved in the file
+                                  (as printed by "status" with -output=json, or served in JSON by the
+                                  ring status pages) and the current ring status.
+  forget <instance-id>...         Remove the instances from the ring.
+  set-state <instance-id> <state> Change the state of the instance in the ring
+                                  (PENDING, JOINING, ACTIVE, LEAVING, LEFT or READONLY).
+
+Flags:
+`
+
+func main() {
+	var (
+		kvCfg            kv.Config
+		memberlistCfg    memberlist.KVConfig
+		key              string
+		heartbeatTimeout time.Duration
+		output           string
+		showTokens       bool
+	)
+
+	logfmt, loglvl := logging.Format{}, logging.Level{}
+	logfmt.RegisterFlags(flag.CommandLine)
+	loglvl.RegisterFlags(flag.CommandLine)
+	kvCfg.RegisterFlagsWithPrefix("", "collectors/", flag.CommandLine)
+	memberlistCfg.RegisterFlags(flag.CommandLine)
+	flag.StringVar(&key, "ring.key", ring.IngesterRingKey, "The key of the ring in the KV store (ring for the ingesters and rulers, store-gateway, compactor or alertmanager). The key prefix is configured via -ring.prefix.")
+	flag.DurationVar(&heartbeatTimeout, "ring.heartbeat-timeout", time.Minute, "The heartbeat timeout after which instances are reported as unhealthy. 0 = never (timeout disabled).")
+	flag.StringVar(&output, "output", ringtool.OutputTable, "Output format of the status command: table or json.")
+	flag.BoolVar(&showTokens, "show-tokens", false, "Print the tokens of each instance in the table output of the status command.")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), usage, os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+
+	// The tool isn't a member of the cluster, so it listens on a random port by default.
+	memberlistCfg.TCPTransport.BindPort = 0
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	logger, err := log.NewPrometheusLogger(loglvl, logfmt)
+	if err != nil {
+		fatal("failed to create logger: %v", err)
+	}
+
+	client, stop, err := ringtool.NewKVClient(kvCfg, memberlistCfg, logger)
+	if err != nil {
+		fatal("failed to create KV client: %v", err)
+	}
+	defer stop()
+
+	ctx := context.Background()
+	tool := ringtool.New(client, key, heartbeatTimeout)
+	args := flag.Args()[1:]
+
+	switch cmd := flag.Arg(0); cmd {
+	case "status":
+		status, err := tool.Status(ctx)
+		if err != nil {
+			fatal("%v", err)
+		}
+		if err := ringtool.WriteStatus(os.Stdout, status, output, showTokens); err != nil {
+			fatal("failed to print the ring status: %v", err)
+		}
+
+	case "diff":
+		if len(args) != 1 {
+			fatal("usage: diff <file>")
+		}
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			fatal("failed to open %s: %v", args[0], err)
+		}
+		from, err := ringtool.ReadStatus(f)
+		_ = f.Close()
+		if err != nil {
+			fatal("%v", err)
+		}
+
+		to, err := tool.Status(ctx)
+		if err != nil {
+			fatal("%v", err)
+		}
+
+		diffs := ringtool.Diff(from, to)
+		if len(diffs) == 0 {
+			fmt.Println("No differences.")
+		}
+		for _, d := range diffs {
+			fmt.Println(d)
+		}
+
+	case "forget":
+		if len(args) == 0 {
+			fatal("usage: forget <instance-id>...")
+		}
+		if err := tool.Forget(ctx, args); err != nil {
+			fatal("failed to forget instances: %v", err)
+		}
+		fmt.Printf("Forgot %d instance(s).\n", len(args))
+
+	case "set-state":
+		if len(args) != 2 {
+			fatal("usage: set-state <instance-id> <state>")
+		}
+		state, err := ringtool.ParseInstanceState(args[1])
+		if err != nil {
+			fatal("%v", err)
+		}
+		if err := tool.SetState(ctx, args[0], state); err != nil {
+			fatal("failed to change the instance state: %v", err)
+		}
+		fmt.Printf("Instance %s state changed to %s.\n", args[0], state)
+
+	default:
+		fatal("unknown command %q", cmd)
+	}
+}
+
+func fatal(msg string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, msg+"\n", args...)
+	os.Exit(1)
+}
@@ -7,18 +7,23 @@ import (
 	fmt "fmt"
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
+	github_com_gogo_protobuf_types "github.com/gogo/protobuf/types"
+	_ "github.com/golang/protobuf/ptypes/duration"
+	_ "github.com/golang/protobuf/ptypes/timestamp"
 	clusterpb "github.com/prometheus/alertmanager/cluster/clusterpb"
 	io "io"
 	math "math"
 	math_bits "math/bits"
 	reflect "reflect"
 	strings "strings"
+	time "time"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
 var _ = fmt.Errorf
 var _ = math.Inf
+var _ = time.Kitchen
 
 // This is a compile-time assertion to ensure that this generated file
 // is compatible with the proto package it is being compiled against.
@@ -179,36 +184,312 @@ func (m *FullStateDesc) GetState() *clusterpb.FullState {
 	return nil
 }
 
+// NotificationHistoryDesc holds the notification attempts of a tenant's Alertmanager.
+type NotificationHistoryDesc struct {
+	Records []*NotificationRecordDesc `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+}
+
+func (m *NotificationHistoryDesc) Reset()      { *m = NotificationHistoryDesc{} }
+func (*NotificationHistoryDesc) ProtoMessage() {}
+func (*NotificationHistoryDesc) Descriptor() ([]byte, []int) {
+	return fileDescriptor_20493709c38b81dc, []int{3}
+}
+func (m *NotificationHistoryDesc) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NotificationHistoryDesc) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_NotificationHistoryDesc.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *NotificationHistoryDesc) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NotificationHistoryDesc.Merge(m, src)
+}
+func (m *NotificationHistoryDesc) XXX_Size() int {
+	return m.Size()
+}
+func (m *NotificationHistoryDesc) XXX_DiscardUnknown() {
+	xxx_messageInfo_NotificationHistoryDesc.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NotificationHistoryDesc proto.InternalMessageInfo
+
+func (m *NotificationHistoryDesc) GetRecords() []*NotificationRecordDesc {
+	if m != nil {
+		return m.Records
+	}
+	return nil
+}
+
+// NotificationRecordDesc is a notification attempt through a receiver integration.
+type NotificationRecordDesc struct {
+	// Unique ID of the notification attempt.
+	Id               string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Receiver         string `protobuf:"bytes,2,opt,name=receiver,proto3" json:"receiver,omitempty"`
+	Integration      string `protobuf:"bytes,3,opt,name=integration,proto3" json:"integration,omitempty"`
+	IntegrationIndex int32  `protobuf:"varint,4,opt,name=integration_index,json=integrationIndex,proto3" json:"integration_index,omitempty"`
+	GroupKey         string `protobuf:"bytes,5,opt,name=group_key,json=groupKey,proto3" json:"group_key,omitempty"`
+	// Fingerprints of the notified alerts.
+	FiringAlerts   []uint64      `protobuf:"varint,6,rep,packed,name=firing_alerts,json=firingAlerts,proto3" json:"firing_alerts,omitempty"`
+	ResolvedAlerts []uint64      `protobuf:"varint,7,rep,packed,name=resolved_alerts,json=resolvedAlerts,proto3" json:"resolved_alerts,omitempty"`
+	Timestamp      time.Time     `protobuf:"bytes,8,opt,name=timestamp,proto3,stdtime" json:"timestamp"`
+	Duration       time.Duration `protobuf:"bytes,9,opt,name=duration,proto3,stdduration" json:"duration"`
+	// Error of the failed notification attempt, empty on success.
+	Error string `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (m *NotificationRecordDesc) Reset()      { *m = NotificationRecordDesc{} }
+func (*NotificationRecordDesc) ProtoMessage() {}
+func (*NotificationRecordDesc) Descriptor() ([]byte, []int) {
+	return fileDescriptor_20493709c38b81dc, []int{4}
+}
+func (m *NotificationRecordDesc) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *NotificationRecordDesc) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_NotificationRecordDesc.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *NotificationRecordDesc) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NotificationRecordDesc.Merge(m, src)
+}
+func (m *NotificationRecordDesc) XXX_Size() int {
+	return m.Size()
+}
+func (m *NotificationRecordDesc) XXX_DiscardUnknown() {
+	xxx_messageInfo_NotificationRecordDesc.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NotificationRecordDesc proto.InternalMessageInfo
+
+func (m *NotificationRecordDesc) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *NotificationRecordDesc) GetReceiver() string {
+	if m != nil {
+		return m.Receiver
+	}
+	return ""
+}
+
+func (m *NotificationRecordDesc) GetIntegration() string {
+	if m != nil {
+		return m.Integration
+	}
+	return ""
+}
+
+func (m *NotificationRecordDesc) GetIntegrationIndex() int32 {
+	if m != nil {
+		return m.IntegrationIndex
+	}
+	return 0
+}
+
+func (m *NotificationRecordDesc) GetGroupKey() string {
+	if m != nil {
+		return m.GroupKey
+	}
+	return ""
+}
+
+func (m *NotificationRecordDesc) GetFiringAlerts() []uint64 {
+	if m != nil {
+		return m.FiringAlerts
+	}
+	return nil
+}
+
+func (m *NotificationRecordDesc) GetResolvedAlerts() []uint64 {
+	if m != nil {
+		return m.ResolvedAlerts
+	}
+	return nil
+}
+
+func (m *NotificationRecordDesc) GetTimestamp() time.Time {
+	if m != nil {
+		return m.Timestamp
+	}
+	return time.Time{}
+}
+
+func (m *NotificationRecordDesc) GetDuration() time.Duration {
+	if m != nil {
+		return m.Duration
+	}
+	return 0
+}
+
+func (m *NotificationRecordDesc) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// AlertConfigVersionDesc is a stored version of a tenant's Alertmanager configuration.
+type AlertConfigVersionDesc struct {
+	// Unique ID of the version, sortable by creation time.
+	Id        string    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt time.Time `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3,stdtime" json:"created_at"`
+	// Author and user agent of the request which stored the version.
+	CreatedBy string `protobuf:"bytes,3,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	UserAgent string `protobuf:"bytes,4,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	// ID of the version restored by a rollback, empty otherwise.
+	RollbackOf string          `protobuf:"bytes,5,opt,name=rollback_of,json=rollbackOf,proto3" json:"rollback_of,omitempty"`
+	Config     AlertConfigDesc `protobuf:"bytes,6,opt,name=config,proto3" json:"config"`
+}
+
+func (m *AlertConfigVersionDesc) Reset()      { *m = AlertConfigVersionDesc{} }
+func (*AlertConfigVersionDesc) ProtoMessage() {}
+func (*AlertConfigVersionDesc) Descriptor() ([]byte, []int) {
+	return fileDescriptor_20493709c38b81dc, []int{5}
+}
+func (m *AlertConfigVersionDesc) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AlertConfigVersionDesc) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AlertConfigVersionDesc.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *AlertConfigVersionDesc) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AlertConfigVersionDesc.Merge(m, src)
+}
+func (m *AlertConfigVersionDesc) XXX_Size() int {
+	return m.Size()
+}
+func (m *AlertConfigVersionDesc) XXX_DiscardUnknown() {
+	xxx_messageInfo_AlertConfigVersionDesc.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AlertConfigVersionDesc proto.InternalMessageInfo
+
+func (m *AlertConfigVersionDesc) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *AlertConfigVersionDesc) GetCreatedAt() time.Time {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return time.Time{}
+}
+
+func (m *AlertConfigVersionDesc) GetCreatedBy() string {
+	if m != nil {
+		return m.CreatedBy
+	}
+	return ""
+}
+
+func (m *AlertConfigVersionDesc) GetUserAgent() string {
+	if m != nil {
+		return m.UserAgent
+	}
+	return ""
+}
+
+func (m *AlertConfigVersionDesc) GetRollbackOf() string {
+	if m != nil {
+		return m.RollbackOf
+	}
+	return ""
+}
+
+func (m *AlertConfigVersionDesc) GetConfig() AlertConfigDesc {
+	if m != nil {
+		return m.Config
+	}
+	return AlertConfigDesc{}
+}
+
 func init() {
 	proto.RegisterType((*AlertConfigDesc)(nil), "alerts.AlertConfigDesc")
 	proto.RegisterType((*TemplateDesc)(nil), "alerts.TemplateDesc")
 	proto.RegisterType((*FullStateDesc)(nil), "alerts.FullStateDesc")
+	proto.RegisterType((*NotificationHistoryDesc)(nil), "alerts.NotificationHistoryDesc")
+	proto.RegisterType((*NotificationRecordDesc)(nil), "alerts.NotificationRecordDesc")
+	proto.RegisterType((*AlertConfigVersionDesc)(nil), "alerts.AlertConfigVersionDesc")
 }
 
 func init() { proto.RegisterFile("alerts.proto", fileDescriptor_20493709c38b81dc) }
 
 var fileDescriptor_20493709c38b81dc = []byte{
-	// 320 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x50, 0xbf, 0x4e, 0xc2, 0x40,
-	0x18, 0xbf, 0x13, 0x24, 0x70, 0x62, 0x4c, 0x1a, 0x06, 0x42, 0xe2, 0x27, 0x61, 0x22, 0x0e, 0x6d,
-	0x82, 0x9b, 0x03, 0x09, 0x68, 0x7c, 0x00, 0x74, 0x72, 0x31, 0xd7, 0x7a, 0x14, 0x92, 0x96, 0x6b,
-	0xee, 0xae, 0x41, 0x37, 0x1f, 0xc1, 0x47, 0x70, 0xf4, 0x51, 0x1c, 0x19, 0x19, 0xe5, 0x58, 0x18,
-	0x79, 0x04, 0x73, 0x77, 0xa5, 0x38, 0xf5, 0xf7, 0xf5, 0xf7, 0xe7, 0xfb, 0x7e, 0x47, 0x9a, 0x34,
-	0x61, 0x42, 0x49, 0x3f, 0x13, 0x5c, 0x71, 0xaf, 0xe6, 0xa6, 0x4e, 0x2b, 0xe6, 0x31, 0xb7, 0xbf,
-	0x02, 0x83, 0x1c, 0xdb, 0x19, 0xc7, 0x73, 0x35, 0xcb, 0x43, 0x3f, 0xe2, 0x69, 0x90, 0x09, 0x9e,
-	0x32, 0x35, 0x63, 0xb9, 0x0c, 0xac, 0x27, 0xa5, 0x0b, 0x1a, 0x33, 0x11, 0x44, 0x49, 0x2e, 0xd5,
-	0xf1, 0x9b, 0x85, 0x07, 0xe4, 0x32, 0x7a, 0x6f, 0xe4, 0x62, 0x64, 0xf4, 0x77, 0x7c, 0x31, 0x9d,
-	0xc7, 0xf7, 0x4c, 0x46, 0x9e, 0x47, 0xaa, 0xb9, 0x64, 0xa2, 0x8d, 0xbb, 0xb8, 0xdf, 0x98, 0x58,
-	0xec, 0x5d, 0x12, 0x22, 0xe8, 0xf2, 0x25, 0xb2, 0xaa, 0xf6, 0x89, 0x65, 0x1a, 0x82, 0x2e, 0x9d,
-	0xcd, 0x1b, 0x90, 0x86, 0x62, 0x69, 0x96, 0x50, 0xc5, 0x64, 0xbb, 0xd2, 0xad, 0xf4, 0xcf, 0x06,
-	0x2d, 0xbf, 0x68, 0xf2, 0x54, 0x10, 0x26, 0x7b, 0x72, 0x94, 0xf5, 0x86, 0xa4, 0xf9, 0x9f, 0xf2,
-	0x3a, 0xa4, 0x3e, 0x9d, 0x27, 0x6c, 0x41, 0x53, 0x56, 0xac, 0x2e, 0x67, 0x73, 0x52, 0xc8, 0x5f,
-	0xdf, 0x8b, 0xc5, 0x16, 0xf7, 0x46, 0xe4, 0xfc, 0x21, 0x4f, 0x92, 0x47, 0x75, 0x08, 0xb8, 0x26,
-	0xa7, 0xd2, 0x0c, 0xd6, 0x6d, 0x0e, 0x28, 0x3b, 0xfb, 0xa5, 0x70, 0xe2, 0x24, 0xb7, 0xd5, 0xdd,
-	0xd7, 0x15, 0x1a, 0x0f, 0x57, 0x1b, 0x40, 0xeb, 0x0d, 0xa0, 0xfd, 0x06, 0xf0, 0x87, 0x06, 0xfc,
-	0xad, 0x01, 0xff, 0x68, 0xc0, 0x2b, 0x0d, 0xf8, 0x57, 0x03, 0xde, 0x69, 0x40, 0x7b, 0x0d, 0xf8,
-	0x73, 0x0b, 0x68, 0xb5, 0x05, 0xb4, 0xde, 0x02, 0x7a, 0xae, 0xbb, 0x62, 0x59, 0x18, 0xd6, 0xec,
-	0x1b, 0xde, 0xfc, 0x05, 0x00, 0x00, 0xff, 0xff, 0x3d, 0xbd, 0xc1, 0x50, 0xb5, 0x01, 0x00, 0x00,
+	// 683 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x53, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0xb5, 0xf3, 0xd5, 0x78, 0xfa, 0x05, 0xab, 0xaa, 0x35, 0x41, 0x6c, 0xa2, 0x70, 0x20, 0x02,
+	0x29, 0x91, 0x8a, 0x90, 0x10, 0x87, 0xa2, 0xa4, 0x15, 0x02, 0x21, 0x81, 0xe4, 0x56, 0x1c, 0xb8,
+	0x44, 0xb6, 0xb3, 0x71, 0x57, 0x75, 0xbc, 0xd6, 0x7a, 0xdd, 0x36, 0x37, 0x24, 0xfe, 0x40, 0x8f,
+	0x70, 0xe3, 0xc8, 0x4f, 0xe9, 0xb1, 0xc7, 0x9e, 0x80, 0xba, 0x97, 0x1e, 0xfb, 0x13, 0xd0, 0xae,
+	0xd7, 0x69, 0x54, 0x7a, 0xe1, 0xe4, 0x9d, 0x79, 0x6f, 0xde, 0xee, 0xf3, 0xcc, 0xc0, 0x92, 0x1b,
+	0x12, 0x2e, 0x92, 0x6e, 0xcc, 0x99, 0x60, 0xa8, 0x96, 0x47, 0x8d, 0xb5, 0x80, 0x05, 0x4c, 0xa5,
+	0x7a, 0xf2, 0x94, 0xa3, 0x8d, 0x41, 0x40, 0xc5, 0x7e, 0xea, 0x75, 0x7d, 0x36, 0xe9, 0xc5, 0x9c,
+	0x4d, 0x88, 0xd8, 0x27, 0x69, 0xd2, 0x53, 0x35, 0x13, 0x37, 0x72, 0x03, 0xc2, 0x7b, 0x7e, 0x98,
+	0x26, 0xe2, 0xe6, 0x1b, 0x7b, 0xc5, 0x49, 0x6b, 0x34, 0x03, 0xc6, 0x82, 0x90, 0xf4, 0x54, 0xe4,
+	0xa5, 0xe3, 0x9e, 0xa0, 0x13, 0x92, 0x08, 0x77, 0x12, 0x6b, 0x02, 0xbe, 0x4d, 0x18, 0xa5, 0xdc,
+	0x15, 0x94, 0x45, 0x39, 0xde, 0x3e, 0x86, 0xd5, 0xbe, 0xbc, 0x70, 0x9b, 0x45, 0x63, 0x1a, 0xec,
+	0x90, 0xc4, 0x47, 0x08, 0x2a, 0x69, 0x42, 0xb8, 0x6d, 0xb6, 0xcc, 0x8e, 0xe5, 0xa8, 0x33, 0x7a,
+	0x04, 0xc0, 0xdd, 0xa3, 0xa1, 0xaf, 0x58, 0x76, 0x49, 0x21, 0x16, 0x77, 0x8f, 0xf2, 0x32, 0xb4,
+	0x09, 0x96, 0x20, 0x93, 0x38, 0x74, 0x05, 0x49, 0xec, 0x72, 0xab, 0xdc, 0x59, 0xdc, 0x5c, 0xeb,
+	0xea, 0x5f, 0xb1, 0xa7, 0x01, 0xa9, 0xed, 0xdc, 0xd0, 0xda, 0x5b, 0xb0, 0x34, 0x0f, 0xa1, 0x06,
+	0xd4, 0xc7, 0x34, 0x24, 0x91, 0x3b, 0x21, 0xfa, 0xea, 0x59, 0x2c, 0x9f, 0xe4, 0xb1, 0xd1, 0x54,
+	0x5f, 0xac, 0xce, 0xed, 0x3e, 0x2c, 0xbf, 0x49, 0xc3, 0x70, 0x57, 0x14, 0x02, 0x4f, 0xa1, 0x9a,
+	0xc8, 0x40, 0x55, 0xcb, 0x07, 0xcc, 0x7e, 0x5a, 0x77, 0x46, 0x74, 0x72, 0xca, 0xab, 0xca, 0xd5,
+	0x8f, 0xa6, 0xd1, 0xde, 0x85, 0x8d, 0x0f, 0x4c, 0xd0, 0x31, 0xf5, 0xd5, 0x2f, 0x79, 0x4b, 0x13,
+	0xc1, 0xf8, 0x54, 0x89, 0xbd, 0x84, 0x05, 0x4e, 0x7c, 0xc6, 0x47, 0x89, 0x6d, 0x2a, 0x3f, 0xb8,
+	0xf0, 0x33, 0x5f, 0xe1, 0x28, 0x8a, 0x72, 0x56, 0xd0, 0xdb, 0xdf, 0xcb, 0xb0, 0x7e, 0x37, 0x07,
+	0xad, 0x40, 0x89, 0x8e, 0xb4, 0xb9, 0x12, 0x1d, 0x49, 0xcb, 0x9c, 0xf8, 0x84, 0x1e, 0x12, 0xae,
+	0xad, 0xcd, 0x62, 0xd4, 0x82, 0x45, 0x1a, 0x09, 0x12, 0xe4, 0xdd, 0xb2, 0xcb, 0x0a, 0x9e, 0x4f,
+	0xa1, 0x67, 0x70, 0x7f, 0x2e, 0x1c, 0xd2, 0x68, 0x44, 0x8e, 0xed, 0x4a, 0xcb, 0xec, 0x54, 0x9d,
+	0x7b, 0x73, 0xc0, 0x3b, 0x99, 0x47, 0x0f, 0xc1, 0x0a, 0x38, 0x4b, 0xe3, 0xe1, 0x01, 0x99, 0xda,
+	0xd5, 0xfc, 0x2e, 0x95, 0x78, 0x4f, 0xa6, 0xe8, 0x31, 0x2c, 0x8f, 0x29, 0xa7, 0x51, 0x30, 0xcc,
+	0x3d, 0xda, 0xb5, 0x56, 0xb9, 0x53, 0x71, 0x96, 0xf2, 0xa4, 0x9a, 0x8f, 0x04, 0x3d, 0x81, 0x55,
+	0x4e, 0x12, 0x16, 0x1e, 0x92, 0x51, 0x41, 0x5b, 0x50, 0xb4, 0x95, 0x22, 0xad, 0x89, 0x03, 0xb0,
+	0x66, 0x53, 0x68, 0xd7, 0x55, 0x2f, 0x1a, 0xdd, 0x7c, 0x0c, 0xbb, 0xc5, 0x18, 0x76, 0xf7, 0x0a,
+	0xc6, 0xa0, 0x7e, 0xfa, 0xab, 0x69, 0x9c, 0xfc, 0x6e, 0x9a, 0xce, 0x4d, 0x19, 0x7a, 0x0d, 0xf5,
+	0x62, 0x50, 0x6d, 0x4b, 0x49, 0x3c, 0xf8, 0x47, 0x62, 0x47, 0x13, 0x72, 0x85, 0x6f, 0x52, 0x61,
+	0x56, 0x84, 0xd6, 0xa0, 0x4a, 0x38, 0x67, 0xdc, 0x06, 0xe5, 0x35, 0x0f, 0xda, 0x5f, 0x4b, 0xb0,
+	0x3e, 0x37, 0xee, 0x9f, 0x08, 0x4f, 0x28, 0x8b, 0xee, 0xec, 0xcd, 0x36, 0x80, 0xcf, 0x89, 0x2b,
+	0xa4, 0x5b, 0x61, 0x97, 0xfe, 0xc7, 0x86, 0xae, 0xeb, 0x0b, 0xb9, 0x36, 0x85, 0x88, 0x37, 0xd5,
+	0x3d, 0x2c, 0xe0, 0xc1, 0x54, 0xc2, 0x72, 0xbb, 0x86, 0x6e, 0x40, 0x22, 0xa1, 0x5a, 0x67, 0x39,
+	0x96, 0xcc, 0xf4, 0x65, 0x02, 0x35, 0x61, 0x91, 0xb3, 0x30, 0xf4, 0x5c, 0xff, 0x60, 0xc8, 0xc6,
+	0xba, 0x6b, 0x50, 0xa4, 0x3e, 0x8e, 0xd1, 0x0b, 0xa8, 0xe9, 0x8d, 0xac, 0xa9, 0xf7, 0x6d, 0x14,
+	0x33, 0x7a, 0x6b, 0xa5, 0x07, 0x15, 0xf9, 0x38, 0x47, 0x93, 0x07, 0x5b, 0x67, 0x17, 0xd8, 0x38,
+	0xbf, 0xc0, 0xc6, 0xf5, 0x05, 0x36, 0xbf, 0x64, 0xd8, 0xfc, 0x99, 0x61, 0xf3, 0x34, 0xc3, 0xe6,
+	0x59, 0x86, 0xcd, 0x3f, 0x19, 0x36, 0xaf, 0x32, 0x6c, 0x5c, 0x67, 0xd8, 0x3c, 0xb9, 0xc4, 0xc6,
+	0xd9, 0x25, 0x36, 0xce, 0x2f, 0xb1, 0xf1, 0xb9, 0x9e, 0x6b, 0xc7, 0x9e, 0x57, 0x53, 0xf6, 0x9f,
+	0xff, 0x1d, 0x00, 0x67, 0x71, 0x60, 0x9d, 0xed, 0x04, 0x00, 0x00,
 }
 
 func (this *AlertConfigDesc) Equal(that interface{}) bool {
@@ -273,6 +554,135 @@ func (this *TemplateDesc) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *NotificationHistoryDesc) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*NotificationHistoryDesc)
+	if !ok {
+		that2, ok := that.(NotificationHistoryDesc)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Records) != len(that1.Records) {
+		return false
+	}
+	for i := range this.Records {
+		if !this.Records[i].Equal(that1.Records[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *NotificationRecordDesc) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*NotificationRecordDesc)
+	if !ok {
+		that2, ok := that.(NotificationRecordDesc)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Id != that1.Id {
+		return false
+	}
+	if this.Receiver != that1.Receiver {
+		return false
+	}
+	if this.Integration != that1.Integration {
+		return false
+	}
+	if this.IntegrationIndex != that1.IntegrationIndex {
+		return false
+	}
+	if this.GroupKey != that1.GroupKey {
+		return false
+	}
+	if len(this.FiringAlerts) != len(that1.FiringAlerts) {
+		return false
+	}
+	for i := range this.FiringAlerts {
+		if this.FiringAlerts[i] != that1.FiringAlerts[i] {
+			return false
+		}
+	}
+	if len(this.ResolvedAlerts) != len(that1.ResolvedAlerts) {
+		return false
+	}
+	for i := range this.ResolvedAlerts {
+		if this.ResolvedAlerts[i] != that1.ResolvedAlerts[i] {
+			return false
+		}
+	}
+	if !this.Timestamp.Equal(that1.Timestamp) {
+		return false
+	}
+	if this.Duration != that1.Duration {
+		return false
+	}
+	if this.Error != that1.Error {
+		return false
+	}
+	return true
+}
+func (this *AlertConfigVersionDesc) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*AlertConfigVersionDesc)
+	if !ok {
+		that2, ok := that.(AlertConfigVersionDesc)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Id != that1.Id {
+		return false
+	}
+	if !this.CreatedAt.Equal(that1.CreatedAt) {
+		return false
+	}
+	if this.CreatedBy != that1.CreatedBy {
+		return false
+	}
+	if this.UserAgent != that1.UserAgent {
+		return false
+	}
+	if this.RollbackOf != that1.RollbackOf {
+		return false
+	}
+	if !this.Config.Equal(&that1.Config) {
+		return false
+	}
+	return true
+}
 func (this *AlertConfigDesc) GoString() string {
 	if this == nil {
 		return "nil"
@@ -310,23 +720,69 @@ func (this *FullStateDesc) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
-func valueToGoStringAlerts(v interface{}, typ string) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
+func (this *NotificationHistoryDesc) GoString() string {
+	if this == nil {
 		return "nil"
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
-}
-func (m *AlertConfigDesc) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	s := make([]string, 0, 5)
+	s = append(s, "&alertspb.NotificationHistoryDesc{")
+	if this.Records != nil {
+		s = append(s, "Records: "+fmt.Sprintf("%#v", this.Records)+",\n")
 	}
-	return dAtA[:n], nil
-}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *NotificationRecordDesc) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 14)
+	s = append(s, "&alertspb.NotificationRecordDesc{")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	s = append(s, "Receiver: "+fmt.Sprintf("%#v", this.Receiver)+",\n")
+	s = append(s, "Integration: "+fmt.Sprintf("%#v", this.Integration)+",\n")
+	s = append(s, "IntegrationIndex: "+fmt.Sprintf("%#v", this.IntegrationIndex)+",\n")
+	s = append(s, "GroupKey: "+fmt.Sprintf("%#v", this.GroupKey)+",\n")
+	s = append(s, "FiringAlerts: "+fmt.Sprintf("%#v", this.FiringAlerts)+",\n")
+	s = append(s, "ResolvedAlerts: "+fmt.Sprintf("%#v", this.ResolvedAlerts)+",\n")
+	s = append(s, "Timestamp: "+fmt.Sprintf("%#v", this.Timestamp)+",\n")
+	s = append(s, "Duration: "+fmt.Sprintf("%#v", this.Duration)+",\n")
+	s = append(s, "Error: "+fmt.Sprintf("%#v", this.Error)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *AlertConfigVersionDesc) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 10)
+	s = append(s, "&alertspb.AlertConfigVersionDesc{")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	s = append(s, "CreatedAt: "+fmt.Sprintf("%#v", this.CreatedAt)+",\n")
+	s = append(s, "CreatedBy: "+fmt.Sprintf("%#v", this.CreatedBy)+",\n")
+	s = append(s, "UserAgent: "+fmt.Sprintf("%#v", this.UserAgent)+",\n")
+	s = append(s, "RollbackOf: "+fmt.Sprintf("%#v", this.RollbackOf)+",\n")
+	s = append(s, "Config: "+strings.Replace(this.Config.GoString(), `&`, ``, 1)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func valueToGoStringAlerts(v interface{}, typ string) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+}
+func (m *AlertConfigDesc) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
 
 func (m *AlertConfigDesc) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
@@ -441,6 +897,227 @@ func (m *FullStateDesc) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *NotificationHistoryDesc) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NotificationHistoryDesc) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *NotificationHistoryDesc) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Records) > 0 {
+		for iNdEx := len(m.Records) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Records[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintAlerts(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *NotificationRecordDesc) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NotificationRecordDesc) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *NotificationRecordDesc) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Error) > 0 {
+		i -= len(m.Error)
+		copy(dAtA[i:], m.Error)
+		i = encodeVarintAlerts(dAtA, i, uint64(len(m.Error)))
+		i--
+		dAtA[i] = 0x52
+	}
+	n2, err2 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.Duration, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.Duration):])
+	if err2 != nil {
+		return 0, err2
+	}
+	i -= n2
+	i = encodeVarintAlerts(dAtA, i, uint64(n2))
+	i--
+	dAtA[i] = 0x4a
+	n3, err3 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Timestamp, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Timestamp):])
+	if err3 != nil {
+		return 0, err3
+	}
+	i -= n3
+	i = encodeVarintAlerts(dAtA, i, uint64(n3))
+	i--
+	dAtA[i] = 0x42
+	if len(m.ResolvedAlerts) > 0 {
+		dAtA5 := make([]byte, len(m.ResolvedAlerts)*10)
+		var j4 int
+		for _, num := range m.ResolvedAlerts {
+			for num >= 1<<7 {
+				dAtA5[j4] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j4++
+			}
+			dAtA5[j4] = uint8(num)
+			j4++
+		}
+		i -= j4
+		copy(dAtA[i:], dAtA5[:j4])
+		i = encodeVarintAlerts(dAtA, i, uint64(j4))
+		i--
+		dAtA[i] = 0x3a
+	}
+	if len(m.FiringAlerts) > 0 {
+		dAtA7 := make([]byte, len(m.FiringAlerts)*10)
+		var j6 int
+		for _, num := range m.FiringAlerts {
+			for num >= 1<<7 {
+				dAtA7[j6] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j6++
+			}
+			dAtA7[j6] = uint8(num)
+			j6++
+		}
+		i -= j6
+		copy(dAtA[i:], dAtA7[:j6])
+		i = encodeVarintAlerts(dAtA, i, uint64(j6))
+		i--
+		dAtA[i] = 0x32
+	}
+	if len(m.GroupKey) > 0 {
+		i -= len(m.GroupKey)
+		copy(dAtA[i:], m.GroupKey)
+		i = encodeVarintAlerts(dAtA, i, uint64(len(m.GroupKey)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.IntegrationIndex != 0 {
+		i = encodeVarintAlerts(dAtA, i, uint64(m.IntegrationIndex))
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Integration) > 0 {
+		i -= len(m.Integration)
+		copy(dAtA[i:], m.Integration)
+		i = encodeVarintAlerts(dAtA, i, uint64(len(m.Integration)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Receiver) > 0 {
+		i -= len(m.Receiver)
+		copy(dAtA[i:], m.Receiver)
+		i = encodeVarintAlerts(dAtA, i, uint64(len(m.Receiver)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintAlerts(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *AlertConfigVersionDesc) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AlertConfigVersionDesc) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AlertConfigVersionDesc) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	{
+		size, err := m.Config.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintAlerts(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x32
+	if len(m.RollbackOf) > 0 {
+		i -= len(m.RollbackOf)
+		copy(dAtA[i:], m.RollbackOf)
+		i = encodeVarintAlerts(dAtA, i, uint64(len(m.RollbackOf)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if len(m.UserAgent) > 0 {
+		i -= len(m.UserAgent)
+		copy(dAtA[i:], m.UserAgent)
+		i = encodeVarintAlerts(dAtA, i, uint64(len(m.UserAgent)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.CreatedBy) > 0 {
+		i -= len(m.CreatedBy)
+		copy(dAtA[i:], m.CreatedBy)
+		i = encodeVarintAlerts(dAtA, i, uint64(len(m.CreatedBy)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	n9, err9 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.CreatedAt, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.CreatedAt):])
+	if err9 != nil {
+		return 0, err9
+	}
+	i -= n9
+	i = encodeVarintAlerts(dAtA, i, uint64(n9))
+	i--
+	dAtA[i] = 0x12
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintAlerts(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintAlerts(dAtA []byte, offset int, v uint64) int {
 	offset -= sovAlerts(v)
 	base := offset
@@ -505,6 +1182,100 @@ func (m *FullStateDesc) Size() (n int) {
 	return n
 }
 
+func (m *NotificationHistoryDesc) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Records) > 0 {
+		for _, e := range m.Records {
+			l = e.Size()
+			n += 1 + l + sovAlerts(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *NotificationRecordDesc) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovAlerts(uint64(l))
+	}
+	l = len(m.Receiver)
+	if l > 0 {
+		n += 1 + l + sovAlerts(uint64(l))
+	}
+	l = len(m.Integration)
+	if l > 0 {
+		n += 1 + l + sovAlerts(uint64(l))
+	}
+	if m.IntegrationIndex != 0 {
+		n += 1 + sovAlerts(uint64(m.IntegrationIndex))
+	}
+	l = len(m.GroupKey)
+	if l > 0 {
+		n += 1 + l + sovAlerts(uint64(l))
+	}
+	if len(m.FiringAlerts) > 0 {
+		l = 0
+		for _, e := range m.FiringAlerts {
+			l += sovAlerts(uint64(e))
+		}
+		n += 1 + sovAlerts(uint64(l)) + l
+	}
+	if len(m.ResolvedAlerts) > 0 {
+		l = 0
+		for _, e := range m.ResolvedAlerts {
+			l += sovAlerts(uint64(e))
+		}
+		n += 1 + sovAlerts(uint64(l)) + l
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Timestamp)
+	n += 1 + l + sovAlerts(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.Duration)
+	n += 1 + l + sovAlerts(uint64(l))
+	l = len(m.Error)
+	if l > 0 {
+		n += 1 + l + sovAlerts(uint64(l))
+	}
+	return n
+}
+
+func (m *AlertConfigVersionDesc) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovAlerts(uint64(l))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.CreatedAt)
+	n += 1 + l + sovAlerts(uint64(l))
+	l = len(m.CreatedBy)
+	if l > 0 {
+		n += 1 + l + sovAlerts(uint64(l))
+	}
+	l = len(m.UserAgent)
+	if l > 0 {
+		n += 1 + l + sovAlerts(uint64(l))
+	}
+	l = len(m.RollbackOf)
+	if l > 0 {
+		n += 1 + l + sovAlerts(uint64(l))
+	}
+	l = m.Config.Size()
+	n += 1 + l + sovAlerts(uint64(l))
+	return n
+}
+
 func sovAlerts(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -549,6 +1320,55 @@ func (this *FullStateDesc) String() string {
 	}, "")
 	return s
 }
+func (this *NotificationHistoryDesc) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForRecords := "[]*NotificationRecordDesc{"
+	for _, f := range this.Records {
+		repeatedStringForRecords += strings.Replace(f.String(), "NotificationRecordDesc", "NotificationRecordDesc", 1) + ","
+	}
+	repeatedStringForRecords += "}"
+	s := strings.Join([]string{`&NotificationHistoryDesc{`,
+		`Records:` + repeatedStringForRecords + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *NotificationRecordDesc) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&NotificationRecordDesc{`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`Receiver:` + fmt.Sprintf("%v", this.Receiver) + `,`,
+		`Integration:` + fmt.Sprintf("%v", this.Integration) + `,`,
+		`IntegrationIndex:` + fmt.Sprintf("%v", this.IntegrationIndex) + `,`,
+		`GroupKey:` + fmt.Sprintf("%v", this.GroupKey) + `,`,
+		`FiringAlerts:` + fmt.Sprintf("%v", this.FiringAlerts) + `,`,
+		`ResolvedAlerts:` + fmt.Sprintf("%v", this.ResolvedAlerts) + `,`,
+		`Timestamp:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Timestamp), "Timestamp", "timestamp.Timestamp", 1), `&`, ``, 1) + `,`,
+		`Duration:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Duration), "Duration", "duration.Duration", 1), `&`, ``, 1) + `,`,
+		`Error:` + fmt.Sprintf("%v", this.Error) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *AlertConfigVersionDesc) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&AlertConfigVersionDesc{`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`CreatedAt:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.CreatedAt), "Timestamp", "timestamp.Timestamp", 1), `&`, ``, 1) + `,`,
+		`CreatedBy:` + fmt.Sprintf("%v", this.CreatedBy) + `,`,
+		`UserAgent:` + fmt.Sprintf("%v", this.UserAgent) + `,`,
+		`RollbackOf:` + fmt.Sprintf("%v", this.RollbackOf) + `,`,
+		`Config:` + strings.Replace(strings.Replace(this.Config.String(), "AlertConfigDesc", "AlertConfigDesc", 1), `&`, ``, 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func valueToStringAlerts(v interface{}) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -588,7 +1408,566 @@ func (m *AlertConfigDesc) Unmarshal(dAtA []byte) error {
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field User", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field User", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAlerts
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.User = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RawConfig", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAlerts
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RawConfig = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Templates", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAlerts
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Templates = append(m.Templates, &TemplateDesc{})
+			if err := m.Templates[len(m.Templates)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAlerts(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TemplateDesc) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAlerts
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TemplateDesc: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TemplateDesc: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Filename", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAlerts
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Filename = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Body", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAlerts
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Body = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAlerts(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FullStateDesc) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAlerts
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FullStateDesc: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FullStateDesc: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAlerts
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.State == nil {
+				m.State = &clusterpb.FullState{}
+			}
+			if err := m.State.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAlerts(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NotificationHistoryDesc) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAlerts
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NotificationHistoryDesc: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NotificationHistoryDesc: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Records", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAlerts
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Records = append(m.Records, &NotificationRecordDesc{})
+			if err := m.Records[len(m.Records)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAlerts(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NotificationRecordDesc) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAlerts
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NotificationRecordDesc: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NotificationRecordDesc: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAlerts
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Receiver", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAlerts
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Receiver = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Integration", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAlerts
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Integration = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IntegrationIndex", wireType)
+			}
+			m.IntegrationIndex = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAlerts
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.IntegrationIndex |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GroupKey", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -616,13 +1995,165 @@ func (m *AlertConfigDesc) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.User = string(dAtA[iNdEx:postIndex])
+			m.GroupKey = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		case 6:
+			if wireType == 0 {
+				var v uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowAlerts
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.FiringAlerts = append(m.FiringAlerts, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowAlerts
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthAlerts
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthAlerts
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.FiringAlerts) == 0 {
+					m.FiringAlerts = make([]uint64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowAlerts
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.FiringAlerts = append(m.FiringAlerts, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field FiringAlerts", wireType)
+			}
+		case 7:
+			if wireType == 0 {
+				var v uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowAlerts
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.ResolvedAlerts = append(m.ResolvedAlerts, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowAlerts
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthAlerts
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthAlerts
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				var count int
+				for _, integer := range dAtA[iNdEx:postIndex] {
+					if integer < 128 {
+						count++
+					}
+				}
+				elementCount = count
+				if elementCount != 0 && len(m.ResolvedAlerts) == 0 {
+					m.ResolvedAlerts = make([]uint64, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowAlerts
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.ResolvedAlerts = append(m.ResolvedAlerts, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field ResolvedAlerts", wireType)
+			}
+		case 8:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RawConfig", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowAlerts
@@ -632,27 +2163,28 @@ func (m *AlertConfigDesc) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthAlerts
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthAlerts
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.RawConfig = string(dAtA[iNdEx:postIndex])
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Timestamp, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
-		case 3:
+		case 9:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Templates", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Duration", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -679,11 +2211,42 @@ func (m *AlertConfigDesc) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Templates = append(m.Templates, &TemplateDesc{})
-			if err := m.Templates[len(m.Templates)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.Duration, dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAlerts
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Error = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipAlerts(dAtA[iNdEx:])
@@ -708,7 +2271,7 @@ func (m *AlertConfigDesc) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *TemplateDesc) Unmarshal(dAtA []byte) error {
+func (m *AlertConfigVersionDesc) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -731,15 +2294,15 @@ func (m *TemplateDesc) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: TemplateDesc: wiretype end group for non-group")
+			return fmt.Errorf("proto: AlertConfigVersionDesc: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: TemplateDesc: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: AlertConfigVersionDesc: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Filename", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -767,11 +2330,44 @@ func (m *TemplateDesc) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Filename = string(dAtA[iNdEx:postIndex])
+			m.Id = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Body", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatedAt", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAlerts
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.CreatedAt, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreatedBy", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -799,64 +2395,75 @@ func (m *TemplateDesc) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Body = string(dAtA[iNdEx:postIndex])
+			m.CreatedBy = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipAlerts(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UserAgent", wireType)
 			}
-			if skippy < 0 {
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAlerts
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthAlerts
 			}
-			if (iNdEx + skippy) < 0 {
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
 				return ErrInvalidLengthAlerts
 			}
-			if (iNdEx + skippy) > l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *FullStateDesc) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowAlerts
+			m.UserAgent = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RollbackOf", wireType)
 			}
-			if iNdEx >= l {
-				return io.ErrUnexpectedEOF
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAlerts
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAlerts
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: FullStateDesc: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: FullStateDesc: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAlerts
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RollbackOf = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Config", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -883,10 +2490,7 @@ func (m *FullStateDesc) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.State == nil {
-				m.State = &clusterpb.FullState{}
-			}
-			if err := m.State.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Config.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -4,11 +4,15 @@ import (
 	"bytes"
 	"context"
 	"io/ioutil"
+	"path"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-kit/kit/log"
 	"github.com/gogo/protobuf/proto"
+	"github.com/oklog/ulid"
 	"github.com/pkg/errors"
 	"github.com/thanos-io/thanos/pkg/objstore"
 	"github.com/thanos-io/thanos/pkg/runutil"
@@ -32,6 +36,11 @@ const (
 	// The name of alertmanager full state objects (notification log + silences).
 	fullStateName = "fullstate"
 
+	// The bucket prefix under which the versions of the alertmanager configs are stored.
+	// Note that objects stored under this prefix follow the pattern:
+	//     alertmanager-config-versions/<user-id>/<version-id>
+	configVersionsPrefix = "alertmanager-config-versions"
+
 	// How many users to load concurrently.
 	fetchConcurrency = 16
 )
@@ -39,18 +48,28 @@ const (
 // BucketAlertStore is used to support the AlertStore interface against an object storage backend. It is implemented
 // using the Thanos objstore.Bucket interface
 type BucketAlertStore struct {
-	alertsBucket objstore.Bucket
-	amBucket     objstore.Bucket
-	cfgProvider  bucket.TenantConfigProvider
-	logger       log.Logger
+	alertsBucket   objstore.Bucket
+	amBucket       objstore.Bucket
+	versionsBucket objstore.Bucket
+	cfgProvider    bucket.TenantConfigProvider
+	logger         log.Logger
+
+	// Max number of versions of the alertmanager config to keep for each user, 0 to disable versioning.
+	maxConfigVersions int
+
+	// How long the versions of the alertmanager config are kept after the config has been deleted.
+	deletedConfigVersionsRetention time.Duration
 }
 
-func NewBucketAlertStore(bkt objstore.Bucket, cfgProvider bucket.TenantConfigProvider, logger log.Logger) *BucketAlertStore {
+func NewBucketAlertStore(bkt objstore.Bucket, cfgProvider bucket.TenantConfigProvider, maxConfigVersions int, deletedConfigVersionsRetention time.Duration, logger log.Logger) *BucketAlertStore {
 	return &BucketAlertStore{
-		alertsBucket: bucket.NewPrefixedBucketClient(bkt, alertsPrefix),
-		amBucket:     bucket.NewPrefixedBucketClient(bkt, alertmanagerPrefix),
-		cfgProvider:  cfgProvider,
-		logger:       logger,
+		alertsBucket:                   bucket.NewPrefixedBucketClient(bkt, alertsPrefix),
+		amBucket:                       bucket.NewPrefixedBucketClient(bkt, alertmanagerPrefix),
+		versionsBucket:                 bucket.NewPrefixedBucketClient(bkt, configVersionsPrefix),
+		cfgProvider:                    cfgProvider,
+		logger:                         logger,
+		maxConfigVersions:              maxConfigVersions,
+		deletedConfigVersionsRetention: deletedConfigVersionsRetention,
 	}
 }
 
@@ -118,10 +137,151 @@ func (s *BucketAlertStore) DeleteAlertConfig(ctx context.Context, userID string)
 	userBkt := s.getUserBucket(userID)
 
 	err := userBkt.Delete(ctx, userID)
-	if userBkt.IsObjNotFoundErr(err) {
+	if err != nil && !userBkt.IsObjNotFoundErr(err) {
+		return err
+	}
+
+	// The versions of the config are intentionally kept, so that a deleted config can be
+	// restored by rolling back to one of them. They're deleted by DeleteUnusedAlertConfigVersions
+	// once the retention period has elapsed.
+	return nil
+}
+
+// AddAlertConfigVersion implements alertstore.AlertConfigVersionStore.
+func (s *BucketAlertStore) AddAlertConfigVersion(ctx context.Context, version alertspb.AlertConfigVersionDesc) error {
+	if s.maxConfigVersions <= 0 {
 		return nil
 	}
-	return err
+
+	userID := version.Config.User
+	versionBytes, err := version.Marshal()
+	if err != nil {
+		return err
+	}
+
+	if err := s.getConfigVersionsUserBucket(userID).Upload(ctx, version.Id, bytes.NewBuffer(versionBytes)); err != nil {
+		return err
+	}
+
+	ids, err := s.listAlertConfigVersionIDs(ctx, userID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list alertmanager config versions for user %s", userID)
+	}
+	if len(ids) <= s.maxConfigVersions {
+		return nil
+	}
+	return s.deleteAlertConfigVersions(ctx, userID, ids[s.maxConfigVersions:])
+}
+
+// ListAlertConfigVersions implements alertstore.AlertConfigVersionStore.
+func (s *BucketAlertStore) ListAlertConfigVersions(ctx context.Context, userID string) ([]alertspb.AlertConfigVersionDesc, error) {
+	ids, err := s.listAlertConfigVersionIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]alertspb.AlertConfigVersionDesc, 0, len(ids))
+	for _, id := range ids {
+		version, err := s.GetAlertConfigVersion(ctx, userID, id)
+		if errors.Is(err, alertspb.ErrNotFound) {
+			// The version has been deleted in the meanwhile.
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, nil
+}
+
+// GetAlertConfigVersion implements alertstore.AlertConfigVersionStore.
+func (s *BucketAlertStore) GetAlertConfigVersion(ctx context.Context, userID, id string) (alertspb.AlertConfigVersionDesc, error) {
+	version := alertspb.AlertConfigVersionDesc{}
+
+	err := s.get(ctx, s.getConfigVersionsUserBucket(userID), id, &version)
+	if s.versionsBucket.IsObjNotFoundErr(err) {
+		return version, alertspb.ErrNotFound
+	}
+
+	return version, err
+}
+
+// listAlertConfigVersionIDs returns the IDs of the stored versions of the alertmanager config
+// for the given user, most recent first.
+func (s *BucketAlertStore) listAlertConfigVersionIDs(ctx context.Context, userID string) ([]string, error) {
+	var ids []string
+
+	err := s.getConfigVersionsUserBucket(userID).Iter(ctx, "", func(key string) error {
+		ids = append(ids, path.Base(key))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The versions IDs are sortable by creation time.
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+	return ids, nil
+}
+
+// DeleteUnusedAlertConfigVersions implements alertstore.AlertConfigVersionStore.
+func (s *BucketAlertStore) DeleteUnusedAlertConfigVersions(ctx context.Context, users []string) error {
+	// Skip listing the bucket at every sync if versioning is disabled.
+	if s.maxConfigVersions <= 0 {
+		return nil
+	}
+
+	configured := make(map[string]struct{}, len(users))
+	for _, userID := range users {
+		configured[userID] = struct{}{}
+	}
+
+	var userIDs []string
+	err := s.versionsBucket.Iter(ctx, "", func(key string) error {
+		userIDs = append(userIDs, strings.TrimRight(key, "/"))
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to list users with alertmanager config versions")
+	}
+
+	for _, userID := range userIDs {
+		if _, ok := configured[userID]; ok {
+			continue
+		}
+
+		ids, err := s.listAlertConfigVersionIDs(ctx, userID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to list alertmanager config versions for user %s", userID)
+		}
+
+		// The IDs are ULIDs, so the most recent version creation time is the one of the first ID.
+		if len(ids) > 0 {
+			if id, err := ulid.Parse(ids[0]); err == nil && time.Since(ulid.Time(id.Time())) < s.deletedConfigVersionsRetention {
+				continue
+			}
+		}
+
+		if err := s.deleteAlertConfigVersions(ctx, userID, ids); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *BucketAlertStore) deleteAlertConfigVersions(ctx context.Context, userID string, ids []string) error {
+	userBkt := s.getConfigVersionsUserBucket(userID)
+
+	for _, id := range ids {
+		err := userBkt.Delete(ctx, id)
+		if err != nil && !userBkt.IsObjNotFoundErr(err) {
+			return errors.Wrapf(err, "failed to delete alertmanager config version %s for user %s", id, userID)
+		}
+	}
+
+	return nil
 }
 
 // ListUsersWithFullState implements alertstore.AlertStore.
@@ -204,6 +364,10 @@ func (s *BucketAlertStore) getUserBucket(userID string) objstore.Bucket {
 	return bucket.NewSSEBucketClient(userID, s.alertsBucket, s.cfgProvider)
 }
 
+func (s *BucketAlertStore) getConfigVersionsUserBucket(userID string) objstore.Bucket {
+	return bucket.NewUserBucketClient(userID, s.versionsBucket, s.cfgProvider).WithExpectedErrs(s.versionsBucket.IsObjNotFoundErr)
+}
+
 func (s *BucketAlertStore) getAlertmanagerUserBucket(userID string) objstore.Bucket {
 	return bucket.NewUserBucketClient(userID, s.amBucket, s.cfgProvider).WithExpectedErrs(s.amBucket.IsObjNotFoundErr)
 }
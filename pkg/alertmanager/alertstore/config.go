@@ -2,6 +2,7 @@ package alertstore
 
 import (
 	"flag"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -59,6 +60,9 @@ type Config struct {
 	bucket.Config `yaml:",inline"`
 	ConfigDB      client.Config     `yaml:"configdb"`
 	Local         local.StoreConfig `yaml:"local"`
+
+	MaxConfigVersions              int           `yaml:"max_config_versions"`
+	DeletedConfigVersionsRetention time.Duration `yaml:"deleted_config_versions_retention"`
 }
 
 // RegisterFlags registers the backend storage config.
@@ -69,6 +73,9 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	cfg.ConfigDB.RegisterFlagsWithPrefix(prefix, f)
 	cfg.Local.RegisterFlagsWithPrefix(prefix, f)
 	cfg.RegisterFlagsWithPrefix(prefix, f)
+
+	f.IntVar(&cfg.MaxConfigVersions, prefix+"max-config-versions", 0, "Max number of versions of the alertmanager configuration to keep for each tenant, to allow listing and rolling back to the previous versions. Supported only by the object storage backends. 0 to disable.")
+	f.DurationVar(&cfg.DeletedConfigVersionsRetention, prefix+"deleted-config-versions-retention", 7*24*time.Hour, "How long the versions of the alertmanager configuration of a tenant are kept after its configuration has been deleted, to allow rolling back to them. The retention is computed from the creation of the most recent version.")
 }
 
 // IsFullStateSupported returns if the given configuration supports access to FullState objects.
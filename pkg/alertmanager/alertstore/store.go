@@ -54,6 +54,25 @@ type AlertStore interface {
 	DeleteFullState(ctx context.Context, user string) error
 }
 
+// AlertConfigVersionStore is implemented by the stores keeping the previous versions of the
+// users alertmanager configurations.
+type AlertConfigVersionStore interface {
+	// AddAlertConfigVersion stores a new version of the alertmanager configuration of an user,
+	// deleting the oldest versions exceeding the max number of versions to keep.
+	AddAlertConfigVersion(ctx context.Context, version alertspb.AlertConfigVersionDesc) error
+
+	// ListAlertConfigVersions returns the stored versions of the alertmanager configuration
+	// for the given user, most recent first.
+	ListAlertConfigVersions(ctx context.Context, user string) ([]alertspb.AlertConfigVersionDesc, error)
+
+	// GetAlertConfigVersion loads and returns a version of the alertmanager configuration for the given user.
+	GetAlertConfigVersion(ctx context.Context, user, id string) (alertspb.AlertConfigVersionDesc, error)
+
+	// DeleteUnusedAlertConfigVersions deletes the versions of the alertmanager configuration of
+	// the users not in the given list of configured users, once the retention period has elapsed.
+	DeleteUnusedAlertConfigVersions(ctx context.Context, users []string) error
+}
+
 // NewLegacyAlertStore returns a new alertmanager storage backend poller and store
 func NewLegacyAlertStore(cfg LegacyConfig, logger log.Logger) (AlertStore, error) {
 	if cfg.Type == configdb.Name {
@@ -107,5 +126,5 @@ func NewAlertStore(ctx context.Context, cfg Config, cfgProvider bucket.TenantCon
 		return nil, err
 	}
 
-	return bucketclient.NewBucketAlertStore(bucketClient, cfgProvider, logger), nil
+	return bucketclient.NewBucketAlertStore(bucketClient, cfgProvider, cfg.MaxConfigVersions, cfg.DeletedConfigVersionsRetention, logger), nil
 }
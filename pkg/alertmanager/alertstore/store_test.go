@@ -2,10 +2,13 @@ package alertstore
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/go-kit/kit/log"
+	"github.com/oklog/ulid"
 	"github.com/prometheus/alertmanager/cluster/clusterpb"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,6 +18,7 @@ import (
 	"github.com/cortexproject/cortex/pkg/alertmanager/alertstore/bucketclient"
 	"github.com/cortexproject/cortex/pkg/alertmanager/alertstore/objectclient"
 	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/storage/bucket"
 )
 
 func TestAlertStore_ListAllUsers(t *testing.T) {
@@ -156,7 +160,7 @@ func runForEachAlertStore(t *testing.T, testFn func(t *testing.T, store AlertSto
 	legacyStore := objectclient.NewAlertStore(legacyClient, log.NewNopLogger())
 
 	bucketClient := objstore.NewInMemBucket()
-	bucketStore := bucketclient.NewBucketAlertStore(bucketClient, nil, log.NewNopLogger())
+	bucketStore := bucketclient.NewBucketAlertStore(bucketClient, nil, 0, 0, log.NewNopLogger())
 
 	stores := map[string]struct {
 		store  AlertStore
@@ -207,7 +211,7 @@ func makeTestFullState(content string) alertspb.FullStateDesc {
 
 func TestBucketAlertStore_GetSetDeleteFullState(t *testing.T) {
 	bucket := objstore.NewInMemBucket()
-	store := bucketclient.NewBucketAlertStore(bucket, nil, log.NewNopLogger())
+	store := bucketclient.NewBucketAlertStore(bucket, nil, 0, 0, log.NewNopLogger())
 	ctx := context.Background()
 
 	state1 := makeTestFullState("one")
@@ -274,3 +278,109 @@ func TestBucketAlertStore_GetSetDeleteFullState(t *testing.T) {
 		require.NoError(t, store.DeleteFullState(ctx, "user-1"))
 	}
 }
+
+func TestBucketAlertStore_ConfigVersions(t *testing.T) {
+	bucket := objstore.NewInMemBucket()
+	store := bucketclient.NewBucketAlertStore(bucket, nil, 2, time.Hour, log.NewNopLogger())
+	ctx := context.Background()
+
+	makeVersion := func(id, content string) alertspb.AlertConfigVersionDesc {
+		return alertspb.AlertConfigVersionDesc{
+			Id:        id,
+			CreatedAt: time.Unix(1000, 0).UTC(),
+			CreatedBy: "author",
+			Config:    alertspb.AlertConfigDesc{User: "user-1", RawConfig: content},
+		}
+	}
+
+	// The storage is empty.
+	versions, err := store.ListAlertConfigVersions(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Empty(t, versions)
+
+	_, err = store.GetAlertConfigVersion(ctx, "user-1", "01F0000000000000000000000A")
+	assert.Equal(t, alertspb.ErrNotFound, err)
+
+	// Only the most recent versions are kept.
+	v1 := makeVersion("01F0000000000000000000000A", "content-1")
+	v2 := makeVersion("01F0000000000000000000000B", "content-2")
+	v3 := makeVersion("01F0000000000000000000000C", "content-3")
+	require.NoError(t, store.AddAlertConfigVersion(ctx, v1))
+	require.NoError(t, store.AddAlertConfigVersion(ctx, v2))
+	require.NoError(t, store.AddAlertConfigVersion(ctx, v3))
+
+	versions, err = store.ListAlertConfigVersions(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, []alertspb.AlertConfigVersionDesc{v3, v2}, versions)
+
+	version, err := store.GetAlertConfigVersion(ctx, "user-1", v2.Id)
+	require.NoError(t, err)
+	assert.Equal(t, v2, version)
+
+	_, err = store.GetAlertConfigVersion(ctx, "user-1", v1.Id)
+	assert.Equal(t, alertspb.ErrNotFound, err)
+
+	exists, err := bucket.Exists(ctx, "alertmanager-config-versions/user-1/"+v3.Id)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	// The versions are kept when the config is deleted, so that it can be restored.
+	require.NoError(t, store.SetAlertConfig(ctx, v3.Config))
+	require.NoError(t, store.DeleteAlertConfig(ctx, "user-1"))
+
+	versions, err = store.ListAlertConfigVersions(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, []alertspb.AlertConfigVersionDesc{v3, v2}, versions)
+
+	// The versions are not considered alertmanager state.
+	users, err := store.ListUsersWithFullState(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, users)
+
+	// The versions of the configured users are never deleted.
+	require.NoError(t, store.DeleteUnusedAlertConfigVersions(ctx, []string{"user-1"}))
+
+	versions, err = store.ListAlertConfigVersions(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, []alertspb.AlertConfigVersionDesc{v3, v2}, versions)
+
+	// The versions of the users whose config has been deleted are deleted once the most
+	// recent version is older than the retention.
+	recent := makeVersion(ulid.MustNew(ulid.Now(), rand.Reader).String(), "content-4")
+	recent.Config.User = "user-2"
+	require.NoError(t, store.AddAlertConfigVersion(ctx, recent))
+	require.NoError(t, store.DeleteUnusedAlertConfigVersions(ctx, nil))
+
+	versions, err = store.ListAlertConfigVersions(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Empty(t, versions)
+
+	versions, err = store.ListAlertConfigVersions(ctx, "user-2")
+	require.NoError(t, err)
+	assert.Equal(t, []alertspb.AlertConfigVersionDesc{recent}, versions)
+}
+
+func TestBucketAlertStore_ListAlertConfigVersionsFailure(t *testing.T) {
+	bucketClient := &bucket.ClientMock{}
+	bucketClient.MockIter("alertmanager-config-versions/user-1/", []string{"alertmanager-config-versions/user-1/01F0000000000000000000000A"}, errors.New("failed to iterate"))
+
+	store := bucketclient.NewBucketAlertStore(bucketClient, nil, 2, time.Hour, log.NewNopLogger())
+
+	versions, err := store.ListAlertConfigVersions(context.Background(), "user-1")
+	require.Error(t, err)
+	assert.Nil(t, versions)
+}
+
+func TestBucketAlertStore_ConfigVersionsDisabled(t *testing.T) {
+	store := bucketclient.NewBucketAlertStore(objstore.NewInMemBucket(), nil, 0, 0, log.NewNopLogger())
+	ctx := context.Background()
+
+	require.NoError(t, store.AddAlertConfigVersion(ctx, alertspb.AlertConfigVersionDesc{
+		Id:     "01F0000000000000000000000A",
+		Config: alertspb.AlertConfigDesc{User: "user-1"},
+	}))
+
+	versions, err := store.ListAlertConfigVersions(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Empty(t, versions)
+}
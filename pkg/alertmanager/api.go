@@ -102,27 +102,8 @@ func (am *MultitenantAlertmanager) SetUserConfig(w http.ResponseWriter, r *http.
 		return
 	}
 
-	var input io.Reader
-	maxConfigSize := am.limits.AlertmanagerMaxConfigSize(userID)
-	if maxConfigSize > 0 {
-		// LimitReader will return EOF after reading specified number of bytes. To check if
-		// we have read too many bytes, allow one extra byte.
-		input = io.LimitReader(r.Body, int64(maxConfigSize)+1)
-	} else {
-		input = r.Body
-	}
-
-	payload, err := ioutil.ReadAll(input)
-	if err != nil {
-		level.Error(logger).Log("msg", errReadingConfiguration, "err", err.Error())
-		http.Error(w, fmt.Sprintf("%s: %s", errReadingConfiguration, err.Error()), http.StatusBadRequest)
-		return
-	}
-
-	if maxConfigSize > 0 && len(payload) > maxConfigSize {
-		msg := fmt.Sprintf(errConfigurationTooBig, maxConfigSize)
-		level.Warn(logger).Log("msg", msg)
-		http.Error(w, msg, http.StatusBadRequest)
+	payload, ok := am.readUserConfigPayload(w, r, userID, logger)
+	if !ok {
 		return
 	}
 
@@ -148,9 +129,41 @@ func (am *MultitenantAlertmanager) SetUserConfig(w http.ResponseWriter, r *http.
 		return
 	}
 
+	am.addUserConfigVersion(r, cfgDesc, logger)
+
 	w.WriteHeader(http.StatusCreated)
 }
 
+// readUserConfigPayload reads the request body, enforcing the tenant's max configuration size.
+// On failure, the error is written to the response and false is returned.
+func (am *MultitenantAlertmanager) readUserConfigPayload(w http.ResponseWriter, r *http.Request, userID string, logger log.Logger) ([]byte, bool) {
+	var input io.Reader
+	maxConfigSize := am.limits.AlertmanagerMaxConfigSize(userID)
+	if maxConfigSize > 0 {
+		// LimitReader will return EOF after reading specified number of bytes. To check if
+		// we have read too many bytes, allow one extra byte.
+		input = io.LimitReader(r.Body, int64(maxConfigSize)+1)
+	} else {
+		input = r.Body
+	}
+
+	payload, err := ioutil.ReadAll(input)
+	if err != nil {
+		level.Error(logger).Log("msg", errReadingConfiguration, "err", err.Error())
+		http.Error(w, fmt.Sprintf("%s: %s", errReadingConfiguration, err.Error()), http.StatusBadRequest)
+		return nil, false
+	}
+
+	if maxConfigSize > 0 && len(payload) > maxConfigSize {
+		msg := fmt.Sprintf(errConfigurationTooBig, maxConfigSize)
+		level.Warn(logger).Log("msg", msg)
+		http.Error(w, msg, http.StatusBadRequest)
+		return nil, false
+	}
+
+	return payload, true
+}
+
 // DeleteUserConfig is exposed via user-visible API (if enabled, uses DELETE method), but also as an internal endpoint using POST method.
 // Note that if no config exists for a user, StatusOK is returned.
 func (am *MultitenantAlertmanager) DeleteUserConfig(w http.ResponseWriter, r *http.Request) {
@@ -230,35 +243,41 @@ func validateUserConfig(logger log.Logger, cfg alertspb.AlertConfigDesc, limits
 	}
 	defer os.RemoveAll(userTempDir)
 
+	_, err = loadUserTemplates(logger, userTempDir, cfg, amCfg)
+	if err != nil {
+		return err
+	}
+
+	// Note: Not validating the MultitenantAlertmanager.transformConfig function as that
+	// that function shouldn't break configuration. Only way it can fail is if the base
+	// autoWebhookURL itself is broken. In that case, I would argue, we should accept the config
+	// not reject it.
+
+	return nil
+}
+
+// loadUserTemplates stores the templates of the tenant's configuration in dir, and loads
+// the ones referenced by the Alertmanager config.
+func loadUserTemplates(logger log.Logger, dir string, cfg alertspb.AlertConfigDesc, amCfg *config.Config) (*template.Template, error) {
 	for _, tmpl := range cfg.Templates {
-		templateFilepath, err := safeTemplateFilepath(userTempDir, tmpl.Filename)
+		templateFilepath, err := safeTemplateFilepath(dir, tmpl.Filename)
 		if err != nil {
 			level.Error(logger).Log("msg", "unable to create template file path", "err", err, "user", cfg.User)
-			return err
+			return nil, err
 		}
 
 		if _, err = storeTemplateFile(templateFilepath, tmpl.Body); err != nil {
 			level.Error(logger).Log("msg", "unable to store template file", "err", err, "user", cfg.User)
-			return fmt.Errorf("unable to store template file '%s'", tmpl.Filename)
+			return nil, fmt.Errorf("unable to store template file '%s'", tmpl.Filename)
 		}
 	}
 
 	templateFiles := make([]string, len(amCfg.Templates))
 	for i, t := range amCfg.Templates {
-		templateFiles[i] = filepath.Join(userTempDir, t)
+		templateFiles[i] = filepath.Join(dir, t)
 	}
 
-	_, err = template.FromGlobs(templateFiles...)
-	if err != nil {
-		return err
-	}
-
-	// Note: Not validating the MultitenantAlertmanager.transformConfig function as that
-	// that function shouldn't break configuration. Only way it can fail is if the base
-	// autoWebhookURL itself is broken. In that case, I would argue, we should accept the config
-	// not reject it.
-
-	return nil
+	return template.FromGlobs(templateFiles...)
 }
 
 func (am *MultitenantAlertmanager) ListAllConfigs(w http.ResponseWriter, r *http.Request) {
@@ -588,7 +588,7 @@ template_files:
 
 func TestMultitenantAlertmanager_DeleteUserConfig(t *testing.T) {
 	storage := objstore.NewInMemBucket()
-	alertStore := bucketclient.NewBucketAlertStore(storage, nil, log.NewNopLogger())
+	alertStore := bucketclient.NewBucketAlertStore(storage, nil, 0, 0, log.NewNopLogger())
 
 	am := &MultitenantAlertmanager{
 		store:  alertStore,
@@ -672,7 +672,7 @@ receivers:
 	}
 
 	storage := objstore.NewInMemBucket()
-	alertStore := bucketclient.NewBucketAlertStore(storage, nil, log.NewNopLogger())
+	alertStore := bucketclient.NewBucketAlertStore(storage, nil, 0, 0, log.NewNopLogger())
 
 	for u, cfg := range testCases {
 		err := alertStore.SetAlertConfig(context.Background(), alertspb.AlertConfigDesc{
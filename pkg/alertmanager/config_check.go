@@ -0,0 +1,319 @@
+package alertmanager
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v2"
+
+	"github.com/cortexproject/cortex/pkg/alertmanager/alertspb"
+	"github.com/cortexproject/cortex/pkg/tenant"
+	"github.com/cortexproject/cortex/pkg/util"
+	util_log "github.com/cortexproject/cortex/pkg/util/log"
+	util_net "github.com/cortexproject/cortex/pkg/util/net"
+)
+
+const (
+	errLoadingConfiguration = "unable to load the Alertmanager config"
+	errReceiverNotFound     = "receiver %q not found in the Alertmanager config"
+	errReceiverBuild        = "unable to build the receiver integrations"
+
+	// testReceiverTimeout is the max time allowed to send a test notification to all the receiver integrations.
+	testReceiverTimeout = 30 * time.Second
+
+	testAlertName    = model.LabelValue("TestAlert")
+	testAlertSummary = model.LabelValue("This is a test notification sent by the Cortex Alertmanager.")
+)
+
+// TestRoutesRequest is the request to match alerts labels against the routing tree of an
+// Alertmanager configuration. If the configuration is not provided, the stored one is used.
+type TestRoutesRequest struct {
+	UserConfig `yaml:",inline"`
+	Alerts     []model.LabelSet `yaml:"alerts"`
+}
+
+// TestRoutesResponse holds the routes matched by each alert of a TestRoutesRequest, in order.
+type TestRoutesResponse struct {
+	Alerts []TestRoutesAlert `yaml:"alerts"`
+}
+
+// TestRoutesAlert holds the routes matched by the labels of an alert.
+type TestRoutesAlert struct {
+	Labels model.LabelSet    `yaml:"labels"`
+	Routes []TestRoutesMatch `yaml:"routes"`
+}
+
+// TestRoutesMatch is a route matched by an alert.
+type TestRoutesMatch struct {
+	Receiver    string         `yaml:"receiver"`
+	GroupBy     []string       `yaml:"group_by"`
+	GroupKey    string         `yaml:"group_key"`
+	GroupLabels model.LabelSet `yaml:"group_labels"`
+}
+
+// TestReceiverRequest is the request to send a test notification through a receiver of the
+// stored Alertmanager configuration.
+type TestReceiverRequest struct {
+	Receiver    string         `yaml:"receiver"`
+	Labels      model.LabelSet `yaml:"labels"`
+	Annotations model.LabelSet `yaml:"annotations"`
+}
+
+// TestReceiverResponse holds the outcome of the test notification for each receiver integration.
+type TestReceiverResponse struct {
+	Receiver     string                    `yaml:"receiver"`
+	Integrations []TestReceiverIntegration `yaml:"integrations"`
+}
+
+// TestReceiverIntegration is the outcome of the test notification sent through an integration.
+type TestReceiverIntegration struct {
+	Name  string `yaml:"name"`
+	Index int    `yaml:"index"`
+	Error string `yaml:"error,omitempty"`
+}
+
+// TestRoutes matches the labels of the alerts in the request against the routing tree of the
+// provided configuration, which is validated as on upload, or of the stored one. It returns the
+// receivers and grouping keys each alert would be dispatched with.
+func (am *MultitenantAlertmanager) TestRoutes(w http.ResponseWriter, r *http.Request) {
+	logger := util_log.WithContext(r.Context(), am.logger)
+	userID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		level.Error(logger).Log("msg", errNoOrgID, "err", err.Error())
+		http.Error(w, fmt.Sprintf("%s: %s", errNoOrgID, err.Error()), http.StatusUnauthorized)
+		return
+	}
+
+	payload, ok := am.readUserConfigPayload(w, r, userID, logger)
+	if !ok {
+		return
+	}
+
+	req := &TestRoutesRequest{}
+	if err := yaml.Unmarshal(payload, req); err != nil {
+		level.Error(logger).Log("msg", errMarshallingYAML, "err", err.Error())
+		http.Error(w, fmt.Sprintf("%s: %s", errMarshallingYAML, err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	var amCfg *config.Config
+	if req.AlertmanagerConfig != "" {
+		cfgDesc := alertspb.ToProto(req.AlertmanagerConfig, req.TemplateFiles, userID)
+		if err := validateUserConfig(logger, cfgDesc, am.limits, userID); err != nil {
+			level.Warn(logger).Log("msg", errValidatingConfig, "err", err.Error())
+			http.Error(w, fmt.Sprintf("%s: %s", errValidatingConfig, err.Error()), http.StatusBadRequest)
+			return
+		}
+
+		if amCfg, err = config.Load(req.AlertmanagerConfig); err != nil {
+			http.Error(w, fmt.Sprintf("%s: %s", errValidatingConfig, err.Error()), http.StatusBadRequest)
+			return
+		}
+	} else {
+		if _, amCfg, ok = am.loadUserConfig(w, r, userID, logger); !ok {
+			return
+		}
+	}
+
+	route := dispatch.NewRoute(amCfg.Route, nil)
+	resp := TestRoutesResponse{Alerts: make([]TestRoutesAlert, 0, len(req.Alerts))}
+
+	for _, lset := range req.Alerts {
+		alert := TestRoutesAlert{Labels: lset, Routes: []TestRoutesMatch{}}
+
+		for _, match := range route.Match(lset) {
+			groupLabels := model.LabelSet{}
+			groupBy := []string{}
+			if match.RouteOpts.GroupByAll {
+				groupLabels = lset.Clone()
+				groupBy = append(groupBy, "...")
+			} else {
+				for ln := range match.RouteOpts.GroupBy {
+					groupBy = append(groupBy, string(ln))
+					if lv, ok := lset[ln]; ok {
+						groupLabels[ln] = lv
+					}
+				}
+				sort.Strings(groupBy)
+			}
+
+			alert.Routes = append(alert.Routes, TestRoutesMatch{
+				Receiver: match.RouteOpts.Receiver,
+				GroupBy:  groupBy,
+				// Same format of the group key computed by the dispatcher.
+				GroupKey:    fmt.Sprintf("%s:%s", match.Key(), groupLabels),
+				GroupLabels: groupLabels,
+			})
+		}
+
+		resp.Alerts = append(resp.Alerts, alert)
+	}
+
+	util.WriteYAMLResponse(w, resp)
+}
+
+// TestReceiver sends a synthetic notification through all the integrations of a receiver of
+// the stored configuration, using the tenant's templates and the per-tenant firewall.
+func (am *MultitenantAlertmanager) TestReceiver(w http.ResponseWriter, r *http.Request) {
+	logger := util_log.WithContext(r.Context(), am.logger)
+	userID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		level.Error(logger).Log("msg", errNoOrgID, "err", err.Error())
+		http.Error(w, fmt.Sprintf("%s: %s", errNoOrgID, err.Error()), http.StatusUnauthorized)
+		return
+	}
+
+	payload, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req := &TestReceiverRequest{}
+	if err := yaml.Unmarshal(payload, req); err != nil {
+		level.Error(logger).Log("msg", errMarshallingYAML, "err", err.Error())
+		http.Error(w, fmt.Sprintf("%s: %s", errMarshallingYAML, err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	cfgDesc, amCfg, ok := am.loadUserConfig(w, r, userID, logger)
+	if !ok {
+		return
+	}
+
+	var receiver *config.Receiver
+	for _, rcv := range amCfg.Receivers {
+		if rcv.Name == req.Receiver {
+			receiver = rcv
+			break
+		}
+	}
+	if receiver == nil {
+		http.Error(w, fmt.Sprintf(errReceiverNotFound, req.Receiver), http.StatusNotFound)
+		return
+	}
+
+	integrations, err := am.buildTestReceiverIntegrations(logger, userID, cfgDesc, amCfg, receiver)
+	if err != nil {
+		level.Warn(logger).Log("msg", errReceiverBuild, "receiver", receiver.Name, "err", err)
+		http.Error(w, fmt.Sprintf("%s: %s", errReceiverBuild, err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	alert := newTestAlert(req.Labels, req.Annotations, time.Now())
+
+	ctx, cancel := context.WithTimeout(r.Context(), testReceiverTimeout)
+	defer cancel()
+
+	ctx = notify.WithReceiverName(ctx, receiver.Name)
+	ctx = notify.WithGroupKey(ctx, fmt.Sprintf("{}/test:%s", alert.Labels))
+	ctx = notify.WithGroupLabels(ctx, alert.Labels)
+	ctx = notify.WithNow(ctx, alert.UpdatedAt)
+	ctx = notify.WithFiringAlerts(ctx, []uint64{uint64(alert.Fingerprint())})
+	ctx = notify.WithResolvedAlerts(ctx, []uint64{})
+	ctx = notify.WithRepeatInterval(ctx, 0)
+
+	resp := TestReceiverResponse{Receiver: receiver.Name, Integrations: make([]TestReceiverIntegration, 0, len(integrations))}
+	for _, integration := range integrations {
+		result := TestReceiverIntegration{Name: integration.Name(), Index: integration.Index()}
+		if _, err := integration.Notify(ctx, alert); err != nil {
+			level.Debug(logger).Log("msg", "failed to send test notification", "receiver", receiver.Name, "integration", integration.String(), "err", err)
+			result.Error = err.Error()
+		}
+		resp.Integrations = append(resp.Integrations, result)
+	}
+
+	util.WriteYAMLResponse(w, resp)
+}
+
+// loadUserConfig loads the tenant's stored configuration, or the fallback one if the tenant has
+// no configuration. On failure, the error is written to the response and false is returned.
+func (am *MultitenantAlertmanager) loadUserConfig(w http.ResponseWriter, r *http.Request, userID string, logger log.Logger) (alertspb.AlertConfigDesc, *config.Config, bool) {
+	cfgDesc, err := am.store.GetAlertConfig(r.Context(), userID)
+	if errors.Is(err, alertspb.ErrNotFound) && am.fallbackConfig != "" {
+		cfgDesc, err = alertspb.AlertConfigDesc{User: userID, RawConfig: am.fallbackConfig}, nil
+	}
+	if err != nil {
+		if errors.Is(err, alertspb.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			level.Error(logger).Log("msg", errReadingConfiguration, "err", err.Error())
+			http.Error(w, fmt.Sprintf("%s: %s", errReadingConfiguration, err.Error()), http.StatusInternalServerError)
+		}
+		return alertspb.AlertConfigDesc{}, nil, false
+	}
+
+	rawCfg := cfgDesc.RawConfig
+	if rawCfg == "" {
+		rawCfg = am.fallbackConfig
+	}
+
+	amCfg, err := config.Load(rawCfg)
+	if err != nil {
+		level.Warn(logger).Log("msg", errLoadingConfiguration, "err", err.Error())
+		http.Error(w, fmt.Sprintf("%s: %s", errLoadingConfiguration, err.Error()), http.StatusUnprocessableEntity)
+		return alertspb.AlertConfigDesc{}, nil, false
+	}
+
+	return cfgDesc, amCfg, true
+}
+
+// buildTestReceiverIntegrations builds the integrations of the receiver, as done when the
+// configuration is applied but without rate limiting, loading the tenant's templates from
+// a temporary directory.
+func (am *MultitenantAlertmanager) buildTestReceiverIntegrations(logger log.Logger, userID string, cfgDesc alertspb.AlertConfigDesc, amCfg *config.Config, receiver *config.Receiver) ([]notify.Integration, error) {
+	userTempDir, err := ioutil.TempDir("", "test-receiver-"+userID)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(userTempDir)
+
+	tmpl, err := loadUserTemplates(logger, userTempDir, cfgDesc, amCfg)
+	if err != nil {
+		return nil, err
+	}
+	tmpl.ExternalURL = am.cfg.ExternalURL.URL
+
+	firewallDialer := util_net.NewFirewallDialer(newFirewallDialerConfigProvider(userID, am.limits))
+
+	return buildReceiverIntegrations(receiver, tmpl, firewallDialer, logger, func(_ string, _ int, n notify.Notifier) notify.Notifier {
+		return n
+	})
+}
+
+// newTestAlert returns a firing alert with the input labels and annotations, falling back to
+// a default alert name and summary if not set.
+func newTestAlert(labels, annotations model.LabelSet, now time.Time) *types.Alert {
+	labels = labels.Clone()
+	if _, ok := labels[model.AlertNameLabel]; !ok {
+		labels[model.AlertNameLabel] = testAlertName
+	}
+
+	annotations = annotations.Clone()
+	if _, ok := annotations["summary"]; !ok {
+		annotations["summary"] = testAlertSummary
+	}
+
+	return &types.Alert{
+		Alert: model.Alert{
+			Labels:      labels,
+			Annotations: annotations,
+			StartsAt:    now,
+			EndsAt:      now.Add(5 * time.Minute),
+		},
+		UpdatedAt: now,
+	}
+}
@@ -0,0 +1,247 @@
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+	"gopkg.in/yaml.v2"
+
+	"github.com/cortexproject/cortex/pkg/alertmanager/alertspb"
+	"github.com/cortexproject/cortex/pkg/util/flagext"
+	util_log "github.com/cortexproject/cortex/pkg/util/log"
+)
+
+const testRoutesConfig = `
+route:
+  receiver: default
+  group_by: [alertname]
+  routes:
+    - receiver: team-a
+      group_by: [cluster, alertname]
+      matchers: ['team="a"']
+    - receiver: team-b
+      group_by: ['...']
+      matchers: ['team="b"']
+receivers:
+  - name: default
+  - name: team-a
+  - name: team-b
+`
+
+func TestMultitenantAlertmanager_TestRoutes(t *testing.T) {
+	store := prepareInMemoryAlertStore()
+	require.NoError(t, store.SetAlertConfig(context.Background(), alertspb.AlertConfigDesc{
+		User:      "user-1",
+		RawConfig: testRoutesConfig,
+	}))
+
+	am := &MultitenantAlertmanager{
+		store:  store,
+		logger: util_log.Logger,
+		limits: &mockAlertManagerLimits{},
+	}
+
+	tests := map[string]struct {
+		userID           string
+		body             string
+		expectedStatus   int
+		expectedResponse TestRoutesResponse
+		expectedError    string
+	}{
+		"should match the alerts against the stored config": {
+			userID: "user-1",
+			body: `
+alerts:
+  - {alertname: foo}
+  - {alertname: foo, team: a, cluster: c1}
+  - {alertname: foo, team: b}
+`,
+			expectedStatus: http.StatusOK,
+			expectedResponse: TestRoutesResponse{Alerts: []TestRoutesAlert{
+				{
+					Labels: model.LabelSet{"alertname": "foo"},
+					Routes: []TestRoutesMatch{{Receiver: "default", GroupBy: []string{"alertname"}, GroupKey: `{}:{alertname="foo"}`, GroupLabels: model.LabelSet{"alertname": "foo"}}},
+				}, {
+					Labels: model.LabelSet{"alertname": "foo", "team": "a", "cluster": "c1"},
+					Routes: []TestRoutesMatch{{Receiver: "team-a", GroupBy: []string{"alertname", "cluster"}, GroupKey: `{}/{team="a"}:{alertname="foo", cluster="c1"}`, GroupLabels: model.LabelSet{"alertname": "foo", "cluster": "c1"}}},
+				}, {
+					Labels: model.LabelSet{"alertname": "foo", "team": "b"},
+					Routes: []TestRoutesMatch{{Receiver: "team-b", GroupBy: []string{"..."}, GroupKey: `{}/{team="b"}:{alertname="foo", team="b"}`, GroupLabels: model.LabelSet{"alertname": "foo", "team": "b"}}},
+				},
+			}},
+		},
+		"should match the alerts against the provided config": {
+			userID: "user-2",
+			body: `
+alertmanager_config: |
+  route:
+    receiver: other
+  receivers:
+    - name: other
+alerts:
+  - {alertname: foo}
+`,
+			expectedStatus: http.StatusOK,
+			expectedResponse: TestRoutesResponse{Alerts: []TestRoutesAlert{
+				{
+					Labels: model.LabelSet{"alertname": "foo"},
+					Routes: []TestRoutesMatch{{Receiver: "other", GroupBy: []string{}, GroupKey: `{}:{}`, GroupLabels: model.LabelSet{}}},
+				},
+			}},
+		},
+		"should reject an invalid provided config": {
+			userID: "user-1",
+			body: `
+alertmanager_config: |
+  route:
+    receiver: missing
+alerts:
+  - {alertname: foo}
+`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  `error validating Alertmanager config: undefined receiver "missing" used in route`,
+		},
+		"should return not found if the tenant has no config": {
+			userID:         "user-2",
+			body:           `alerts: [{alertname: foo}]`,
+			expectedStatus: http.StatusNotFound,
+			expectedError:  alertspb.ErrNotFound.Error(),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "http://alertmanager/api/v1/alerts/test_routes", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			am.TestRoutes(w, req.WithContext(user.InjectOrgID(req.Context(), tc.userID)))
+
+			resp := w.Result()
+			body, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedStatus, resp.StatusCode, string(body))
+
+			if tc.expectedError != "" {
+				assert.Equal(t, tc.expectedError+"\n", string(body))
+				return
+			}
+
+			actual := TestRoutesResponse{}
+			require.NoError(t, yaml.Unmarshal(body, &actual))
+			assert.Equal(t, tc.expectedResponse, actual)
+		})
+	}
+}
+
+func TestMultitenantAlertmanager_TestReceiver(t *testing.T) {
+	received := make(chan map[string]interface{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := map[string]interface{}{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		received <- payload
+	}))
+	defer server.Close()
+
+	failingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer failingServer.Close()
+
+	store := prepareInMemoryAlertStore()
+	require.NoError(t, store.SetAlertConfig(context.Background(), alertspb.AlertConfigDesc{
+		User: "user-1",
+		RawConfig: `
+route:
+  receiver: webhook
+receivers:
+  - name: webhook
+    webhook_configs:
+      - url: ` + server.URL + `
+  - name: failing
+    webhook_configs:
+      - url: ` + failingServer.URL + `
+        max_alerts: 1
+`,
+	}))
+
+	externalURL := flagext.URLValue{}
+	require.NoError(t, externalURL.Set("http://alertmanager.example.com"))
+
+	am := &MultitenantAlertmanager{
+		cfg:    &MultitenantAlertmanagerConfig{ExternalURL: externalURL},
+		store:  store,
+		logger: util_log.Logger,
+		limits: &mockAlertManagerLimits{},
+	}
+
+	testReceiver := func(body string) (int, string) {
+		req := httptest.NewRequest(http.MethodPost, "http://alertmanager/api/v1/alerts/test_receiver", bytes.NewReader([]byte(body)))
+		w := httptest.NewRecorder()
+		am.TestReceiver(w, req.WithContext(user.InjectOrgID(req.Context(), "user-1")))
+
+		resp := w.Result()
+		data, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(data)
+	}
+
+	t.Run("should send the test notification", func(t *testing.T) {
+		status, body := testReceiver(`{receiver: webhook, labels: {severity: critical}}`)
+		require.Equal(t, http.StatusOK, status, body)
+
+		actual := TestReceiverResponse{}
+		require.NoError(t, yaml.Unmarshal([]byte(body), &actual))
+		assert.Equal(t, TestReceiverResponse{Receiver: "webhook", Integrations: []TestReceiverIntegration{{Name: "webhook", Index: 0}}}, actual)
+
+		payload := <-received
+		assert.Equal(t, "webhook", payload["receiver"])
+		assert.Equal(t, "http://alertmanager.example.com", payload["externalURL"])
+		assert.Equal(t, map[string]interface{}{"alertname": string(testAlertName), "severity": "critical"}, payload["commonLabels"])
+		assert.Equal(t, map[string]interface{}{"summary": string(testAlertSummary)}, payload["commonAnnotations"])
+	})
+
+	t.Run("should report the integrations failures", func(t *testing.T) {
+		status, body := testReceiver(`{receiver: failing}`)
+		require.Equal(t, http.StatusOK, status, body)
+
+		actual := TestReceiverResponse{}
+		require.NoError(t, yaml.Unmarshal([]byte(body), &actual))
+		require.Len(t, actual.Integrations, 1)
+		assert.Contains(t, actual.Integrations[0].Error, "unexpected status code 400")
+	})
+
+	t.Run("should return not found if the receiver doesn't exist", func(t *testing.T) {
+		status, body := testReceiver(`{receiver: missing}`)
+		assert.Equal(t, http.StatusNotFound, status)
+		assert.Equal(t, `receiver "missing" not found in the Alertmanager config`+"\n", body)
+	})
+
+	t.Run("should block the notification if the receiver address is blocked by the firewall", func(t *testing.T) {
+		serverURL, err := url.Parse(server.URL)
+		require.NoError(t, err)
+
+		cidr := flagext.CIDR{}
+		require.NoError(t, cidr.Set(serverURL.Hostname()+"/32"))
+
+		am.limits = &mockAlertManagerLimits{blockedCIDRNetworks: []flagext.CIDR{cidr}}
+		defer func() { am.limits = &mockAlertManagerLimits{} }()
+
+		status, body := testReceiver(`{receiver: webhook}`)
+		require.Equal(t, http.StatusOK, status, body)
+
+		actual := TestReceiverResponse{}
+		require.NoError(t, yaml.Unmarshal([]byte(body), &actual))
+		require.Len(t, actual.Integrations, 1)
+		assert.Contains(t, actual.Integrations[0].Error, "blocked address")
+	})
+}
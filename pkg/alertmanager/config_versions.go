@@ -0,0 +1,225 @@
+package alertmanager
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/gorilla/mux"
+	"github.com/oklog/ulid"
+
+	"github.com/cortexproject/cortex/pkg/alertmanager/alertspb"
+	"github.com/cortexproject/cortex/pkg/alertmanager/alertstore"
+	"github.com/cortexproject/cortex/pkg/tenant"
+	"github.com/cortexproject/cortex/pkg/util"
+	util_log "github.com/cortexproject/cortex/pkg/util/log"
+)
+
+const (
+	// ConfigAuthorHeader is the request header holding the author of an Alertmanager config change,
+	// recorded in the config versions.
+	ConfigAuthorHeader = "X-Cortex-Config-Author"
+
+	// configVersionRouteVar is the name of the route variable holding the config version ID.
+	configVersionRouteVar = "version"
+
+	errConfigVersionsNotSupported = "the Alertmanager storage doesn't support config versions"
+	errListingConfigVersions      = "unable to list the Alertmanager config versions"
+	errReadingConfigVersion       = "unable to read the Alertmanager config version"
+	errStoringConfigVersion       = "unable to store the Alertmanager config version"
+)
+
+// UserConfigVersion is the metadata of a version of the user's Alertmanager config.
+type UserConfigVersion struct {
+	ID         string    `yaml:"id"`
+	CreatedAt  time.Time `yaml:"created_at"`
+	CreatedBy  string    `yaml:"created_by,omitempty"`
+	UserAgent  string    `yaml:"user_agent,omitempty"`
+	RollbackOf string    `yaml:"rollback_of,omitempty"`
+}
+
+// UserConfigVersionsResponse holds the versions of the user's Alertmanager config, most recent first.
+type UserConfigVersionsResponse struct {
+	Versions []UserConfigVersion `yaml:"versions"`
+}
+
+// UserConfigVersionResponse holds a version of the user's Alertmanager config.
+type UserConfigVersionResponse struct {
+	UserConfigVersion `yaml:",inline"`
+	UserConfig        `yaml:",inline"`
+}
+
+func newUserConfigVersion(v alertspb.AlertConfigVersionDesc) UserConfigVersion {
+	return UserConfigVersion{
+		ID:         v.Id,
+		CreatedAt:  v.CreatedAt,
+		CreatedBy:  v.CreatedBy,
+		UserAgent:  v.UserAgent,
+		RollbackOf: v.RollbackOf,
+	}
+}
+
+// ListUserConfigVersions returns the stored versions of the user's Alertmanager config.
+func (am *MultitenantAlertmanager) ListUserConfigVersions(w http.ResponseWriter, r *http.Request) {
+	logger := util_log.WithContext(r.Context(), am.logger)
+
+	userID, store, ok := am.configVersionsRequest(w, r, logger)
+	if !ok {
+		return
+	}
+
+	versions, err := store.ListAlertConfigVersions(r.Context(), userID)
+	if err != nil {
+		level.Error(logger).Log("msg", errListingConfigVersions, "err", err.Error())
+		http.Error(w, fmt.Sprintf("%s: %s", errListingConfigVersions, err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	resp := UserConfigVersionsResponse{Versions: make([]UserConfigVersion, 0, len(versions))}
+	for _, v := range versions {
+		resp.Versions = append(resp.Versions, newUserConfigVersion(v))
+	}
+
+	util.WriteYAMLResponse(w, resp)
+}
+
+// GetUserConfigVersion returns a stored version of the user's Alertmanager config.
+func (am *MultitenantAlertmanager) GetUserConfigVersion(w http.ResponseWriter, r *http.Request) {
+	logger := util_log.WithContext(r.Context(), am.logger)
+
+	userID, store, ok := am.configVersionsRequest(w, r, logger)
+	if !ok {
+		return
+	}
+
+	version, ok := getUserConfigVersion(w, r, store, userID, logger)
+	if !ok {
+		return
+	}
+
+	util.WriteYAMLResponse(w, UserConfigVersionResponse{
+		UserConfigVersion: newUserConfigVersion(version),
+		UserConfig: UserConfig{
+			TemplateFiles:      alertspb.ParseTemplates(version.Config),
+			AlertmanagerConfig: version.Config.RawConfig,
+		},
+	})
+}
+
+// RollbackUserConfig restores a stored version of the user's Alertmanager config. The restored
+// config is recorded as a new version, and it's validated against the current limits.
+func (am *MultitenantAlertmanager) RollbackUserConfig(w http.ResponseWriter, r *http.Request) {
+	logger := util_log.WithContext(r.Context(), am.logger)
+
+	userID, store, ok := am.configVersionsRequest(w, r, logger)
+	if !ok {
+		return
+	}
+
+	version, ok := getUserConfigVersion(w, r, store, userID, logger)
+	if !ok {
+		return
+	}
+
+	cfgDesc := version.Config
+	cfgDesc.User = userID
+	if err := validateUserConfig(logger, cfgDesc, am.limits, userID); err != nil {
+		level.Warn(logger).Log("msg", errValidatingConfig, "err", err.Error())
+		http.Error(w, fmt.Sprintf("%s: %s", errValidatingConfig, err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	if err := am.store.SetAlertConfig(r.Context(), cfgDesc); err != nil {
+		level.Error(logger).Log("msg", errStoringConfiguration, "err", err.Error())
+		http.Error(w, fmt.Sprintf("%s: %s", errStoringConfiguration, err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	rollback := newAlertConfigVersion(r, cfgDesc, version.Id)
+	if err := store.AddAlertConfigVersion(r.Context(), rollback); err != nil {
+		level.Error(logger).Log("msg", errStoringConfigVersion, "err", err.Error())
+		http.Error(w, fmt.Sprintf("%s: %s", errStoringConfigVersion, err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteYAMLResponse(w, newUserConfigVersion(rollback))
+}
+
+// configVersionsRequest returns the user and the config versions store of a config versions API request.
+// On failure, the error is written to the response and false is returned.
+func (am *MultitenantAlertmanager) configVersionsRequest(w http.ResponseWriter, r *http.Request, logger log.Logger) (string, alertstore.AlertConfigVersionStore, bool) {
+	userID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		level.Error(logger).Log("msg", errNoOrgID, "err", err.Error())
+		http.Error(w, fmt.Sprintf("%s: %s", errNoOrgID, err.Error()), http.StatusUnauthorized)
+		return "", nil, false
+	}
+
+	store, ok := am.store.(alertstore.AlertConfigVersionStore)
+	if !ok {
+		http.Error(w, errConfigVersionsNotSupported, http.StatusNotImplemented)
+		return "", nil, false
+	}
+
+	return userID, store, true
+}
+
+func getUserConfigVersion(w http.ResponseWriter, r *http.Request, store alertstore.AlertConfigVersionStore, userID string, logger log.Logger) (alertspb.AlertConfigVersionDesc, bool) {
+	id := mux.Vars(r)[configVersionRouteVar]
+	if _, err := ulid.Parse(id); err != nil {
+		http.Error(w, fmt.Sprintf("invalid config version %q", id), http.StatusBadRequest)
+		return alertspb.AlertConfigVersionDesc{}, false
+	}
+
+	version, err := store.GetAlertConfigVersion(r.Context(), userID, id)
+	if err == alertspb.ErrNotFound {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return version, false
+	} else if err != nil {
+		level.Error(logger).Log("msg", errReadingConfigVersion, "err", err.Error())
+		http.Error(w, fmt.Sprintf("%s: %s", errReadingConfigVersion, err.Error()), http.StatusInternalServerError)
+		return version, false
+	}
+
+	return version, true
+}
+
+// addUserConfigVersion records a new version of the user's Alertmanager config, if supported by the store.
+func (am *MultitenantAlertmanager) addUserConfigVersion(r *http.Request, cfg alertspb.AlertConfigDesc, logger log.Logger) {
+	store, ok := am.store.(alertstore.AlertConfigVersionStore)
+	if !ok {
+		return
+	}
+
+	// The config has already been stored, so failing to record its version doesn't fail the request.
+	if err := store.AddAlertConfigVersion(r.Context(), newAlertConfigVersion(r, cfg, "")); err != nil {
+		level.Warn(logger).Log("msg", errStoringConfigVersion, "err", err.Error())
+	}
+}
+
+var (
+	// configVersionEntropy keeps the IDs of the versions created within the same millisecond sorted.
+	configVersionEntropyMtx sync.Mutex
+	configVersionEntropy    = ulid.Monotonic(rand.Reader, 0)
+)
+
+func newAlertConfigVersion(r *http.Request, cfg alertspb.AlertConfigDesc, rollbackOf string) alertspb.AlertConfigVersionDesc {
+	now := time.Now()
+
+	configVersionEntropyMtx.Lock()
+	id := ulid.MustNew(ulid.Timestamp(now), configVersionEntropy)
+	configVersionEntropyMtx.Unlock()
+
+	return alertspb.AlertConfigVersionDesc{
+		Id:         id.String(),
+		CreatedAt:  now,
+		CreatedBy:  r.Header.Get(ConfigAuthorHeader),
+		UserAgent:  r.UserAgent(),
+		RollbackOf: rollbackOf,
+		Config:     cfg,
+	}
+}
@@ -0,0 +1,148 @@
+package alertmanager
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/thanos/pkg/objstore"
+	"github.com/weaveworks/common/user"
+	"gopkg.in/yaml.v2"
+
+	"github.com/cortexproject/cortex/pkg/alertmanager/alertspb"
+	"github.com/cortexproject/cortex/pkg/alertmanager/alertstore/bucketclient"
+	"github.com/cortexproject/cortex/pkg/alertmanager/alertstore/local"
+	util_log "github.com/cortexproject/cortex/pkg/util/log"
+)
+
+func TestMultitenantAlertmanager_ConfigVersions(t *testing.T) {
+	store := bucketclient.NewBucketAlertStore(objstore.NewInMemBucket(), nil, 10, time.Hour, log.NewNopLogger())
+	am := &MultitenantAlertmanager{
+		store:  store,
+		logger: util_log.Logger,
+		limits: &mockAlertManagerLimits{},
+	}
+
+	const (
+		cfg1 = "route:\n  receiver: first\nreceivers:\n  - name: first\n"
+		cfg2 = "route:\n  receiver: second\nreceivers:\n  - name: second\n"
+	)
+
+	do := func(method, url, body, routeVersion string, handler http.HandlerFunc) (int, string) {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		req.Header.Set(ConfigAuthorHeader, "jane")
+		req.Header.Set("User-Agent", "test-client")
+		if routeVersion != "" {
+			req = mux.SetURLVars(req, map[string]string{configVersionRouteVar: routeVersion})
+		}
+
+		w := httptest.NewRecorder()
+		handler(w, req.WithContext(user.InjectOrgID(req.Context(), "user-1")))
+
+		data, err := ioutil.ReadAll(w.Result().Body)
+		require.NoError(t, err)
+		return w.Code, string(data)
+	}
+
+	listVersions := func() []UserConfigVersion {
+		status, body := do(http.MethodGet, "/api/v1/alerts/versions", "", "", am.ListUserConfigVersions)
+		require.Equal(t, http.StatusOK, status, body)
+
+		resp := UserConfigVersionsResponse{}
+		require.NoError(t, yaml.Unmarshal([]byte(body), &resp))
+		return resp.Versions
+	}
+
+	// No version has been stored yet.
+	assert.Empty(t, listVersions())
+
+	// Each stored config is recorded as a new version.
+	for _, cfg := range []string{cfg1, cfg2} {
+		payload, err := yaml.Marshal(&UserConfig{AlertmanagerConfig: cfg})
+		require.NoError(t, err)
+
+		status, body := do(http.MethodPost, "/api/v1/alerts", string(payload), "", am.SetUserConfig)
+		require.Equal(t, http.StatusCreated, status, body)
+	}
+
+	versions := listVersions()
+	require.Len(t, versions, 2)
+	for _, v := range versions {
+		assert.Equal(t, "jane", v.CreatedBy)
+		assert.Equal(t, "test-client", v.UserAgent)
+		assert.Empty(t, v.RollbackOf)
+		assert.False(t, v.CreatedAt.IsZero())
+	}
+
+	// The versions are sorted most recent first.
+	first := versions[1]
+	status, body := do(http.MethodGet, "/api/v1/alerts/versions/"+first.ID, "", first.ID, am.GetUserConfigVersion)
+	require.Equal(t, http.StatusOK, status, body)
+
+	version := UserConfigVersionResponse{}
+	require.NoError(t, yaml.Unmarshal([]byte(body), &version))
+	assert.Equal(t, first, version.UserConfigVersion)
+	assert.Equal(t, cfg1, version.AlertmanagerConfig)
+
+	// Rollback to the first version.
+	status, body = do(http.MethodPost, "/api/v1/alerts/versions/"+first.ID+"/rollback", "", first.ID, am.RollbackUserConfig)
+	require.Equal(t, http.StatusOK, status, body)
+
+	rollback := UserConfigVersion{}
+	require.NoError(t, yaml.Unmarshal([]byte(body), &rollback))
+	assert.Equal(t, first.ID, rollback.RollbackOf)
+
+	cfg, err := store.GetAlertConfig(context.Background(), "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, alertspb.AlertConfigDesc{User: "user-1", RawConfig: cfg1}, cfg)
+
+	versions = listVersions()
+	require.Len(t, versions, 3)
+	assert.Equal(t, rollback, versions[0])
+
+	// A deleted config can be restored by rolling back to one of its versions.
+	status, body = do(http.MethodDelete, "/api/v1/alerts", "", "", am.DeleteUserConfig)
+	require.Equal(t, http.StatusOK, status, body)
+
+	_, err = store.GetAlertConfig(context.Background(), "user-1")
+	require.Equal(t, alertspb.ErrNotFound, err)
+	require.Len(t, listVersions(), 3)
+
+	second := versions[1]
+	status, body = do(http.MethodPost, "/api/v1/alerts/versions/"+second.ID+"/rollback", "", second.ID, am.RollbackUserConfig)
+	require.Equal(t, http.StatusOK, status, body)
+
+	cfg, err = store.GetAlertConfig(context.Background(), "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, alertspb.AlertConfigDesc{User: "user-1", RawConfig: cfg2}, cfg)
+
+	// Unknown and invalid versions.
+	status, _ = do(http.MethodGet, "/api/v1/alerts/versions/01F0000000000000000000000A", "", "01F0000000000000000000000A", am.GetUserConfigVersion)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, _ = do(http.MethodPost, "/api/v1/alerts/versions/invalid/rollback", "", "invalid", am.RollbackUserConfig)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestMultitenantAlertmanager_ConfigVersionsNotSupported(t *testing.T) {
+	store, err := local.NewStore(local.StoreConfig{Path: t.TempDir()})
+	require.NoError(t, err)
+
+	am := &MultitenantAlertmanager{
+		store:  store,
+		logger: util_log.Logger,
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/alerts/versions", nil)
+	w := httptest.NewRecorder()
+	am.ListUserConfigVersions(w, req.WithContext(user.InjectOrgID(req.Context(), "user-1")))
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+}
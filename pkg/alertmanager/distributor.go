@@ -77,7 +77,9 @@ func (d *Distributor) isQuorumWritePath(p string) bool {
 }
 
 func (d *Distributor) isUnaryWritePath(p string) bool {
-	return strings.HasSuffix(p, "/silences")
+	return strings.HasSuffix(p, "/silences") ||
+		strings.HasSuffix(p, "/silences/import") ||
+		strings.HasSuffix(p, "/silences/expire")
 }
 
 func (d *Distributor) isUnaryDeletePath(p string) bool {
@@ -106,6 +108,12 @@ func (d *Distributor) isQuorumReadPath(p string) (bool, merger.Merger) {
 	if strings.HasSuffix(path.Dir(p), "/v2/silence") {
 		return true, merger.V2SilenceID{}
 	}
+	if strings.HasSuffix(p, "/v1/silences/export") {
+		return true, merger.V2Silences{}
+	}
+	if strings.HasSuffix(p, "/v1/notifications") {
+		return true, merger.V1Notifications{}
+	}
 	return false, nil
 }
 
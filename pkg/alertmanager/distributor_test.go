@@ -221,6 +221,42 @@ func TestDistributor_DistributeRequest(t *testing.T) {
 			expectedTotalCalls:  0,
 			headersNotPreserved: true,
 			route:               "/receivers",
+		}, {
+			name:               "Read /v1/notifications is sent to 3 AMs",
+			numAM:              5,
+			numHappyAM:         5,
+			replicationFactor:  3,
+			isRead:             true,
+			expStatusCode:      http.StatusOK,
+			expectedTotalCalls: 3,
+			route:              "/v1/notifications",
+			responseBody:       []byte(`{"status":"success","data":[]}`),
+		}, {
+			name:               "Read /silences/export is sent to 3 AMs",
+			numAM:              5,
+			numHappyAM:         5,
+			replicationFactor:  3,
+			isRead:             true,
+			expStatusCode:      http.StatusOK,
+			expectedTotalCalls: 3,
+			route:              "/silences/export",
+			responseBody:       []byte(`[]`),
+		}, {
+			name:               "Write /silences/import is sent to only 1 AM",
+			numAM:              5,
+			numHappyAM:         5,
+			replicationFactor:  3,
+			expStatusCode:      http.StatusOK,
+			expectedTotalCalls: 1,
+			route:              "/silences/import",
+		}, {
+			name:               "Write /silences/expire is sent to only 1 AM",
+			numAM:              5,
+			numHappyAM:         5,
+			replicationFactor:  3,
+			expStatusCode:      http.StatusOK,
+			expectedTotalCalls: 1,
+			route:              "/silences/expire",
 		},
 	}
 
@@ -283,6 +319,10 @@ func TestDistributor_IsPathSupported(t *testing.T) {
 		"/alertmanager/api/v1/alerts":           true,
 		"/alertmanager/api/v1/alerts/groups":    false,
 		"/alertmanager/api/v1/silences":         true,
+		"/alertmanager/api/v1/silences/export":  true,
+		"/alertmanager/api/v1/silences/import":  true,
+		"/alertmanager/api/v1/silences/expire":  true,
+		"/alertmanager/api/v1/silences/other":   false,
 		"/alertmanager/api/v1/silence/id":       true,
 		"/alertmanager/api/v1/silence/anything": true,
 		"/alertmanager/api/v1/silence/really":   true,
@@ -0,0 +1,72 @@
+package merger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// V1Notifications implements the Merger interface for GET /v1/notifications. It returns the union
+// of the notification attempts over all the responses, deduplicated by ID and sorted by timestamp,
+// most recent first. The notification attempts are kept as-is, only the fields required to merge
+// them are parsed.
+type V1Notifications struct{}
+
+func (V1Notifications) MergeResponses(in [][]byte) ([]byte, error) {
+	type bodyType struct {
+		Status string            `json:"status"`
+		Data   []json.RawMessage `json:"data"`
+	}
+
+	type notification struct {
+		ID        string    `json:"id"`
+		Timestamp time.Time `json:"timestamp"`
+
+		raw json.RawMessage
+	}
+
+	notifications := make(map[string]notification)
+	for _, body := range in {
+		parsed := bodyType{}
+		if err := json.Unmarshal(body, &parsed); err != nil {
+			return nil, err
+		}
+		if parsed.Status != statusSuccess {
+			return nil, fmt.Errorf("unable to merge response of status: %s", parsed.Status)
+		}
+
+		for _, raw := range parsed.Data {
+			n := notification{raw: raw}
+			if err := json.Unmarshal(raw, &n); err != nil {
+				return nil, err
+			}
+			if n.ID == "" {
+				return nil, errors.New("unexpected empty id")
+			}
+			notifications[n.ID] = n
+		}
+	}
+
+	sorted := make([]notification, 0, len(notifications))
+	for _, n := range notifications {
+		sorted = append(sorted, n)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if !sorted[i].Timestamp.Equal(sorted[j].Timestamp) {
+			return sorted[i].Timestamp.After(sorted[j].Timestamp)
+		}
+		return sorted[i].ID > sorted[j].ID
+	})
+
+	merged := bodyType{
+		Status: statusSuccess,
+		Data:   make([]json.RawMessage, 0, len(sorted)),
+	}
+	for _, n := range sorted {
+		merged.Data = append(merged.Data, n.raw)
+	}
+
+	return json.Marshal(merged)
+}
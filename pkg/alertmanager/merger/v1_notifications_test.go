@@ -0,0 +1,46 @@
+package merger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestV1Notifications(t *testing.T) {
+	in := [][]byte{
+		[]byte(`{"status":"success","data":[` +
+			`{"id":"01F8MECHZX3TBDSZ7XRADM79XE","receiver":"team-a","integration":"webhook","timestamp":"2021-06-21T10:00:00Z","status":"success"},` +
+			`{"id":"01F8MECHZX3TBDSZ7XRADM79XA","receiver":"team-a","integration":"webhook","timestamp":"2021-06-21T09:00:00Z","status":"failed","error":"unexpected status code 500"}` +
+			`]}`),
+		[]byte(`{"status":"success","data":[` +
+			`{"id":"01F8MECHZX3TBDSZ7XRADM79XF","receiver":"team-b","integration":"slack","timestamp":"2021-06-21T11:00:00Z","status":"success"},` +
+			`{"id":"01F8MECHZX3TBDSZ7XRADM79XE","receiver":"team-a","integration":"webhook","timestamp":"2021-06-21T10:00:00Z","status":"success"}` +
+			`]}`),
+	}
+
+	expected := []byte(`{"status":"success","data":[` +
+		`{"id":"01F8MECHZX3TBDSZ7XRADM79XF","receiver":"team-b","integration":"slack","timestamp":"2021-06-21T11:00:00Z","status":"success"},` +
+		`{"id":"01F8MECHZX3TBDSZ7XRADM79XE","receiver":"team-a","integration":"webhook","timestamp":"2021-06-21T10:00:00Z","status":"success"},` +
+		`{"id":"01F8MECHZX3TBDSZ7XRADM79XA","receiver":"team-a","integration":"webhook","timestamp":"2021-06-21T09:00:00Z","status":"failed","error":"unexpected status code 500"}` +
+		`]}`)
+
+	out, err := V1Notifications{}.MergeResponses(in)
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(out))
+}
+
+func TestV1Notifications_Empty(t *testing.T) {
+	out, err := V1Notifications{}.MergeResponses([][]byte{
+		[]byte(`{"status":"success","data":[]}`),
+		[]byte(`{"status":"success","data":[]}`),
+	})
+	require.NoError(t, err)
+	require.Equal(t, `{"status":"success","data":[]}`, string(out))
+}
+
+func TestV1Notifications_Error(t *testing.T) {
+	_, err := V1Notifications{}.MergeResponses([][]byte{
+		[]byte(`{"status":"error","data":[]}`),
+	})
+	require.EqualError(t, err, "unable to merge response of status: error")
+}
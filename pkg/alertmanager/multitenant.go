@@ -83,6 +83,8 @@ type MultitenantAlertmanagerConfig struct {
 
 	EnableAPI bool `yaml:"enable_api"`
 
+	NotificationHistorySize int `yaml:"notification_history_size"`
+
 	// For distributor.
 	AlertmanagerClient ClientConfig `yaml:"alertmanager_client"`
 
@@ -117,6 +119,7 @@ func (cfg *MultitenantAlertmanagerConfig) RegisterFlags(f *flag.FlagSet) {
 	f.DurationVar(&cfg.PollInterval, "alertmanager.configs.poll-interval", 15*time.Second, "How frequently to poll Cortex configs")
 
 	f.BoolVar(&cfg.EnableAPI, "experimental.alertmanager.enable-api", false, "Enable the experimental alertmanager config api.")
+	f.IntVar(&cfg.NotificationHistorySize, "alertmanager.notification-history-size", 0, "Max number of notification attempts kept in the per-tenant notification history, replicated like the notification log and exposed by the notification history API. 0 to disable.")
 
 	f.BoolVar(&cfg.ShardingEnabled, "alertmanager.sharding-enabled", false, "Shard tenants across multiple alertmanager instances.")
 
@@ -161,6 +164,9 @@ func (cfg *MultitenantAlertmanagerConfig) Validate(storageCfg alertstore.Config)
 		if cfg.ShardingRing.ZoneAwarenessEnabled && cfg.ShardingRing.InstanceZone == "" {
 			return errZoneAwarenessEnabledWithoutZoneInfo
 		}
+		if err := cfg.ShardingRing.TokenGenerator.Validate(); err != nil {
+			return errors.Wrap(err, "invalid sharding ring config")
+		}
 	}
 
 	return nil
@@ -686,6 +692,13 @@ func (am *MultitenantAlertmanager) loadAndSyncConfigs(ctx context.Context, syncR
 		am.deleteUnusedRemoteUserState(ctx, allUsers)
 	}
 
+	// The config versions are stored regardless of sharding.
+	if store, ok := am.store.(alertstore.AlertConfigVersionStore); ok {
+		if err := store.DeleteUnusedAlertConfigVersions(ctx, allUsers); err != nil {
+			level.Warn(am.logger).Log("msg", "failed to delete alertmanager config versions of deleted users", "err", err)
+		}
+	}
+
 	return nil
 }
 
@@ -933,6 +946,8 @@ func (am *MultitenantAlertmanager) newAlertmanager(userID string, amConfig *amco
 		Store:             am.store,
 		PersisterConfig:   am.cfg.Persister,
 		Limits:            am.limits,
+
+		NotificationHistorySize: am.cfg.NotificationHistorySize,
 	}, reg)
 	if err != nil {
 		return nil, fmt.Errorf("unable to start Alertmanager for user %v: %v", userID, err)
@@ -0,0 +1,350 @@
+package alertmanager
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/oklog/ulid"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/cortexproject/cortex/pkg/alertmanager/alertspb"
+	"github.com/cortexproject/cortex/pkg/util"
+)
+
+const (
+	notificationStatusSuccess = "success"
+	notificationStatusFailed  = "failed"
+)
+
+// notificationHistory is a bounded log of the notification attempts of a tenant's Alertmanager.
+// It implements cluster.State, so that the attempts are replicated like the notification log.
+type notificationHistory struct {
+	size      int
+	retention time.Duration
+	logger    log.Logger
+
+	mtx     sync.Mutex
+	records []*alertspb.NotificationRecordDesc // Sorted by timestamp.
+	ids     map[string]struct{}
+
+	broadcast func([]byte)
+}
+
+func newNotificationHistory(size int, retention time.Duration, logger log.Logger) *notificationHistory {
+	return &notificationHistory{
+		size:      size,
+		retention: retention,
+		logger:    logger,
+		ids:       map[string]struct{}{},
+		broadcast: func([]byte) {},
+	}
+}
+
+// SetBroadcast sets the function used to replicate the new notification attempts.
+func (h *notificationHistory) SetBroadcast(f func([]byte)) {
+	h.mtx.Lock()
+	h.broadcast = f
+	h.mtx.Unlock()
+}
+
+// MarshalBinary implements cluster.State.
+func (h *notificationHistory) MarshalBinary() ([]byte, error) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	return (&alertspb.NotificationHistoryDesc{Records: h.records}).Marshal()
+}
+
+// Merge implements cluster.State.
+func (h *notificationHistory) Merge(b []byte) error {
+	desc := alertspb.NotificationHistoryDesc{}
+	if err := desc.Unmarshal(b); err != nil {
+		return err
+	}
+
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	h.add(desc.Records...)
+	return nil
+}
+
+// record adds a notification attempt to the history and replicates it.
+func (h *notificationHistory) record(r *alertspb.NotificationRecordDesc) {
+	b, err := (&alertspb.NotificationHistoryDesc{Records: []*alertspb.NotificationRecordDesc{r}}).Marshal()
+	if err != nil {
+		level.Warn(h.logger).Log("msg", "failed to encode the notification record", "err", err)
+		return
+	}
+
+	h.mtx.Lock()
+	h.add(r)
+	broadcast := h.broadcast
+	h.mtx.Unlock()
+
+	broadcast(b)
+}
+
+// add must be called with the lock held.
+func (h *notificationHistory) add(records ...*alertspb.NotificationRecordDesc) {
+	added := false
+	for _, r := range records {
+		if _, ok := h.ids[r.Id]; ok {
+			continue
+		}
+		h.ids[r.Id] = struct{}{}
+		h.records = append(h.records, r)
+		added = true
+	}
+
+	if !added {
+		return
+	}
+
+	sort.SliceStable(h.records, func(i, j int) bool {
+		return h.records[i].Timestamp.Before(h.records[j].Timestamp)
+	})
+
+	// Drop the records past the retention, and the oldest ones over the max size.
+	drop := len(h.records) - h.size
+	if drop < 0 {
+		drop = 0
+	}
+	if h.retention > 0 {
+		cutoff := time.Now().Add(-h.retention)
+		for drop < len(h.records) && h.records[drop].Timestamp.Before(cutoff) {
+			drop++
+		}
+	}
+
+	for _, r := range h.records[:drop] {
+		delete(h.ids, r.Id)
+	}
+	h.records = h.records[drop:]
+}
+
+// query returns the notification attempts matching the filter, most recent first.
+func (h *notificationHistory) query(f notificationFilter) []*alertspb.NotificationRecordDesc {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	result := []*alertspb.NotificationRecordDesc{}
+	for i := len(h.records) - 1; i >= 0; i-- {
+		if f.limit > 0 && len(result) >= f.limit {
+			break
+		}
+		if f.matches(h.records[i]) {
+			result = append(result, h.records[i])
+		}
+	}
+	return result
+}
+
+// notificationFilter filters the notification attempts returned by the notification history API.
+type notificationFilter struct {
+	receiver    string
+	integration string
+	status      string
+	fingerprint *model.Fingerprint
+	since       time.Time
+	until       time.Time
+	limit       int
+}
+
+func parseNotificationFilter(r *http.Request) (notificationFilter, error) {
+	var (
+		f   notificationFilter
+		err error
+	)
+
+	f.receiver = r.FormValue("receiver")
+	f.integration = r.FormValue("integration")
+
+	f.status = r.FormValue("status")
+	if f.status != "" && f.status != notificationStatusSuccess && f.status != notificationStatusFailed {
+		return f, fmt.Errorf("invalid status %q, supported values are %q and %q", f.status, notificationStatusSuccess, notificationStatusFailed)
+	}
+
+	if v := r.FormValue("fingerprint"); v != "" {
+		fp, err := model.ParseFingerprint(v)
+		if err != nil {
+			return f, fmt.Errorf("invalid fingerprint %q: %v", v, err)
+		}
+		f.fingerprint = &fp
+	}
+
+	if v := r.FormValue("since"); v != "" {
+		if f.since, err = time.Parse(time.RFC3339, v); err != nil {
+			return f, fmt.Errorf("invalid since %q: %v", v, err)
+		}
+	}
+
+	if v := r.FormValue("until"); v != "" {
+		if f.until, err = time.Parse(time.RFC3339, v); err != nil {
+			return f, fmt.Errorf("invalid until %q: %v", v, err)
+		}
+	}
+
+	if v := r.FormValue("limit"); v != "" {
+		if f.limit, err = strconv.Atoi(v); err != nil || f.limit < 0 {
+			return f, fmt.Errorf("invalid limit %q", v)
+		}
+	}
+
+	return f, nil
+}
+
+func (f notificationFilter) matches(r *alertspb.NotificationRecordDesc) bool {
+	if f.receiver != "" && r.Receiver != f.receiver {
+		return false
+	}
+	if f.integration != "" && r.Integration != f.integration {
+		return false
+	}
+	if f.status != "" && notificationStatus(r) != f.status {
+		return false
+	}
+	if !f.since.IsZero() && r.Timestamp.Before(f.since) {
+		return false
+	}
+	if !f.until.IsZero() && r.Timestamp.After(f.until) {
+		return false
+	}
+	if f.fingerprint != nil && !containsFingerprint(r.FiringAlerts, *f.fingerprint) && !containsFingerprint(r.ResolvedAlerts, *f.fingerprint) {
+		return false
+	}
+	return true
+}
+
+func containsFingerprint(fps []uint64, fp model.Fingerprint) bool {
+	for _, f := range fps {
+		if f == uint64(fp) {
+			return true
+		}
+	}
+	return false
+}
+
+func notificationStatus(r *alertspb.NotificationRecordDesc) string {
+	if r.Error != "" {
+		return notificationStatusFailed
+	}
+	return notificationStatusSuccess
+}
+
+// historyNotifier records the notification attempts of the wrapped notifier in the history.
+type historyNotifier struct {
+	upstream    notify.Notifier
+	history     *notificationHistory
+	integration string
+	index       int
+}
+
+func newHistoryNotifier(upstream notify.Notifier, history *notificationHistory, integration string, index int) *historyNotifier {
+	return &historyNotifier{
+		upstream:    upstream,
+		history:     history,
+		integration: integration,
+		index:       index,
+	}
+}
+
+// Notify implements notify.Notifier.
+func (n *historyNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	start := time.Now()
+	retry, err := n.upstream.Notify(ctx, alerts...)
+
+	r := &alertspb.NotificationRecordDesc{
+		Id:               ulid.MustNew(ulid.Timestamp(start), rand.Reader).String(),
+		Integration:      n.integration,
+		IntegrationIndex: int32(n.index),
+		Timestamp:        start,
+		Duration:         time.Since(start),
+	}
+	r.Receiver, _ = notify.ReceiverName(ctx)
+	if key, err := notify.ExtractGroupKey(ctx); err == nil {
+		r.GroupKey = key.String()
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+
+	for _, a := range alerts {
+		if a.ResolvedAt(start) {
+			r.ResolvedAlerts = append(r.ResolvedAlerts, uint64(a.Fingerprint()))
+		} else {
+			r.FiringAlerts = append(r.FiringAlerts, uint64(a.Fingerprint()))
+		}
+	}
+
+	n.history.record(r)
+	return retry, err
+}
+
+// NotificationRecord is a notification attempt returned by the notification history API.
+type NotificationRecord struct {
+	ID               string    `json:"id"`
+	Receiver         string    `json:"receiver"`
+	Integration      string    `json:"integration"`
+	IntegrationIndex int       `json:"integrationIndex"`
+	GroupKey         string    `json:"groupKey"`
+	FiringAlerts     []string  `json:"firingAlerts"`
+	ResolvedAlerts   []string  `json:"resolvedAlerts"`
+	Timestamp        time.Time `json:"timestamp"`
+	Duration         float64   `json:"duration"`
+	Status           string    `json:"status"`
+	Error            string    `json:"error,omitempty"`
+}
+
+func fingerprintsToStrings(fps []uint64) []string {
+	result := make([]string, 0, len(fps))
+	for _, fp := range fps {
+		result = append(result, model.Fingerprint(fp).String())
+	}
+	return result
+}
+
+// notificationHistoryHandler serves the notification attempts matching the request filters.
+func (am *Alertmanager) notificationHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	f, err := parseNotificationFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	records := am.notificationHistory.query(f)
+	data := make([]NotificationRecord, 0, len(records))
+	for _, r := range records {
+		data = append(data, NotificationRecord{
+			ID:               r.Id,
+			Receiver:         r.Receiver,
+			Integration:      r.Integration,
+			IntegrationIndex: int(r.IntegrationIndex),
+			GroupKey:         r.GroupKey,
+			FiringAlerts:     fingerprintsToStrings(r.FiringAlerts),
+			ResolvedAlerts:   fingerprintsToStrings(r.ResolvedAlerts),
+			Timestamp:        r.Timestamp,
+			Duration:         r.Duration.Seconds(),
+			Status:           notificationStatus(r),
+			Error:            r.Error,
+		})
+	}
+
+	util.WriteJSONResponse(w, struct {
+		Status string               `json:"status"`
+		Data   []NotificationRecord `json:"data"`
+	}{
+		Status: "success",
+		Data:   data,
+	})
+}
@@ -0,0 +1,243 @@
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	"github.com/cortexproject/cortex/pkg/alertmanager/alertspb"
+	"github.com/cortexproject/cortex/pkg/util/test"
+)
+
+func TestNotificationHistory_ShouldKeepTheMostRecentRecords(t *testing.T) {
+	now := time.Now()
+	h := newNotificationHistory(3, time.Hour, log.NewNopLogger())
+
+	var broadcasted []*alertspb.NotificationRecordDesc
+	h.SetBroadcast(func(b []byte) {
+		desc := alertspb.NotificationHistoryDesc{}
+		require.NoError(t, desc.Unmarshal(b))
+		broadcasted = append(broadcasted, desc.Records...)
+	})
+
+	h.record(&alertspb.NotificationRecordDesc{Id: "1", Timestamp: now.Add(-2 * time.Hour)})
+	h.record(&alertspb.NotificationRecordDesc{Id: "3", Timestamp: now.Add(-2 * time.Minute)})
+	h.record(&alertspb.NotificationRecordDesc{Id: "2", Timestamp: now.Add(-3 * time.Minute)})
+
+	// Records past the retention are dropped, and each record is broadcasted.
+	assert.Equal(t, []string{"3", "2"}, recordIDs(h.query(notificationFilter{})))
+	assert.Equal(t, []string{"1", "3", "2"}, recordIDs(broadcasted))
+
+	// Merge the state of another replica, with duplicates.
+	other := newNotificationHistory(3, time.Hour, log.NewNopLogger())
+	other.record(&alertspb.NotificationRecordDesc{Id: "3", Timestamp: now.Add(-2 * time.Minute)})
+	other.record(&alertspb.NotificationRecordDesc{Id: "4", Timestamp: now.Add(-time.Minute)})
+	other.record(&alertspb.NotificationRecordDesc{Id: "5", Timestamp: now})
+
+	b, err := other.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, h.Merge(b))
+
+	// Only the most recent records are kept.
+	assert.Equal(t, []string{"5", "4", "3"}, recordIDs(h.query(notificationFilter{})))
+	assert.Equal(t, []string{"5"}, recordIDs(h.query(notificationFilter{limit: 1})))
+
+	// Merging doesn't broadcast.
+	assert.Len(t, broadcasted, 3)
+}
+
+func TestNotificationHistory_Query(t *testing.T) {
+	now := time.Now()
+	h := newNotificationHistory(10, 0, log.NewNopLogger())
+	h.record(&alertspb.NotificationRecordDesc{Id: "1", Receiver: "team-a", Integration: "webhook", FiringAlerts: []uint64{1}, Timestamp: now.Add(-3 * time.Minute)})
+	h.record(&alertspb.NotificationRecordDesc{Id: "2", Receiver: "team-a", Integration: "slack", FiringAlerts: []uint64{1, 2}, Timestamp: now.Add(-2 * time.Minute), Error: "failed"})
+	h.record(&alertspb.NotificationRecordDesc{Id: "3", Receiver: "team-b", Integration: "webhook", ResolvedAlerts: []uint64{2}, Timestamp: now.Add(-time.Minute)})
+
+	for name, tc := range map[string]struct {
+		query    string
+		expected []string
+		err      string
+	}{
+		"no filter": {
+			expected: []string{"3", "2", "1"},
+		},
+		"by receiver": {
+			query:    "receiver=team-a",
+			expected: []string{"2", "1"},
+		},
+		"by integration": {
+			query:    "integration=webhook",
+			expected: []string{"3", "1"},
+		},
+		"by status": {
+			query:    "status=failed",
+			expected: []string{"2"},
+		},
+		"by fingerprint": {
+			query:    "fingerprint=" + model.Fingerprint(2).String(),
+			expected: []string{"3", "2"},
+		},
+		"by time range": {
+			query:    fmt.Sprintf("since=%s&until=%s", url.QueryEscape(now.Add(-150*time.Second).Format(time.RFC3339)), url.QueryEscape(now.Add(-90*time.Second).Format(time.RFC3339))),
+			expected: []string{"2"},
+		},
+		"with limit": {
+			query:    "limit=2",
+			expected: []string{"3", "2"},
+		},
+		"invalid status": {
+			query: "status=unknown",
+			err:   `invalid status "unknown", supported values are "success" and "failed"`,
+		},
+		"invalid limit": {
+			query: "limit=-1",
+			err:   `invalid limit "-1"`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			f, err := parseNotificationFilter(httptest.NewRequest(http.MethodGet, "/api/v1/notifications?"+tc.query, nil))
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, recordIDs(h.query(f)))
+		})
+	}
+}
+
+type failingNotifier struct {
+	err error
+}
+
+func (n *failingNotifier) Notify(context.Context, ...*types.Alert) (bool, error) {
+	return true, n.err
+}
+
+func TestHistoryNotifier(t *testing.T) {
+	now := time.Now()
+	firing := &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "firing"}, StartsAt: now, EndsAt: now.Add(time.Hour)}}
+	resolved := &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "resolved"}, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(-time.Minute)}}
+
+	ctx := notify.WithReceiverName(context.Background(), "team-a")
+	ctx = notify.WithGroupKey(ctx, "{}:{}")
+
+	h := newNotificationHistory(10, 0, log.NewNopLogger())
+
+	retry, err := newHistoryNotifier(&mockNotifier{}, h, "webhook", 1).Notify(ctx, firing, resolved)
+	require.NoError(t, err)
+	require.False(t, retry)
+
+	retry, err = newHistoryNotifier(&failingNotifier{err: errors.New("unexpected status code 500")}, h, "slack", 0).Notify(ctx, firing)
+	require.Error(t, err)
+	require.True(t, retry)
+
+	records := h.query(notificationFilter{})
+	require.Len(t, records, 2)
+
+	assert.Equal(t, "slack", records[0].Integration)
+	assert.Equal(t, "unexpected status code 500", records[0].Error)
+	assert.Equal(t, notificationStatusFailed, notificationStatus(records[0]))
+
+	assert.NotEmpty(t, records[1].Id)
+	assert.Equal(t, "team-a", records[1].Receiver)
+	assert.Equal(t, "webhook", records[1].Integration)
+	assert.Equal(t, int32(1), records[1].IntegrationIndex)
+	assert.Equal(t, "{}:{}", records[1].GroupKey)
+	assert.Equal(t, []uint64{uint64(firing.Fingerprint())}, records[1].FiringAlerts)
+	assert.Equal(t, []uint64{uint64(resolved.Fingerprint())}, records[1].ResolvedAlerts)
+	assert.Equal(t, notificationStatusSuccess, notificationStatus(records[1]))
+}
+
+func TestAlertmanager_NotificationHistoryAPI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	user := "test"
+	am, err := New(&Config{
+		UserID:                  user,
+		Logger:                  log.NewNopLogger(),
+		Limits:                  &mockAlertManagerLimits{emailNotificationRateLimit: rate.Inf},
+		TenantDataDir:           t.TempDir(),
+		ExternalURL:             &url.URL{Path: "/am"},
+		Retention:               time.Hour,
+		NotificationHistorySize: 10,
+	}, prometheus.NewPedanticRegistry())
+	require.NoError(t, err)
+	defer am.StopAndWait()
+
+	cfgRaw := fmt.Sprintf(`receivers:
+- name: 'prod'
+  webhook_configs:
+  - url: '%s'
+
+route:
+  group_by: ['alertname']
+  group_wait: 10ms
+  group_interval: 10ms
+  receiver: 'prod'`, server.URL)
+
+	cfg, err := config.Load(cfgRaw)
+	require.NoError(t, err)
+	require.NoError(t, am.ApplyConfig(user, cfg, cfgRaw))
+
+	now := time.Now()
+	alert := &types.Alert{
+		Alert: model.Alert{
+			Labels:   model.LabelSet{"alertname": "test"},
+			StartsAt: now,
+			EndsAt:   now.Add(5 * time.Minute),
+		},
+		UpdatedAt: now,
+	}
+	require.NoError(t, am.alerts.Put(alert))
+
+	// Alerts are sent to the dispatcher asynchronously.
+	test.Poll(t, 3*time.Second, 1, func() interface{} {
+		return len(am.notificationHistory.query(notificationFilter{}))
+	})
+
+	w := httptest.NewRecorder()
+	am.mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/am/api/v1/notifications?receiver=prod", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	resp := struct {
+		Status string               `json:"status"`
+		Data   []NotificationRecord `json:"data"`
+	}{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, "success", resp.Status)
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, "prod", resp.Data[0].Receiver)
+	assert.Equal(t, "webhook", resp.Data[0].Integration)
+	assert.Equal(t, notificationStatusSuccess, resp.Data[0].Status)
+	assert.Equal(t, []string{alert.Fingerprint().String()}, resp.Data[0].FiringAlerts)
+	assert.Empty(t, resp.Data[0].ResolvedAlerts)
+}
+
+func recordIDs(records []*alertspb.NotificationRecordDesc) []string {
+	ids := make([]string, 0, len(records))
+	for _, r := range records {
+		ids = append(ids, r.Id)
+	}
+	return ids
+}
@@ -0,0 +1,264 @@
+package alertmanager
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	v2 "github.com/prometheus/alertmanager/api/v2"
+	v2_models "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/silence"
+	"github.com/prometheus/alertmanager/silence/silencepb"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/cortexproject/cortex/pkg/util"
+	util_log "github.com/cortexproject/cortex/pkg/util/log"
+)
+
+// SilencesImportResult is the result of a silences import.
+type SilencesImportResult struct {
+	Imported int               `json:"imported"`
+	Skipped  int               `json:"skipped"`
+	Errors   []string          `json:"errors"`
+	IDs      map[string]string `json:"ids"`
+}
+
+// SilencesExpireResult is the result of a bulk silences expiration.
+type SilencesExpireResult struct {
+	Expired []string `json:"expired"`
+}
+
+// silencesExportHandler serves all the silences of the tenant, including the expired ones
+// still in the retention, in the same format as GET /api/v2/silences.
+func (am *Alertmanager) silencesExportHandler(w http.ResponseWriter, r *http.Request) {
+	sils, _, err := am.silences.Query()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := make(v2_models.GettableSilences, 0, len(sils))
+	for _, s := range sils {
+		sil, err := v2.GettableSilenceFromProto(s)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		result = append(result, &sil)
+	}
+	v2.SortSilences(result)
+
+	b, err := swag.WriteJSON(result)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(b); err != nil {
+		level.Error(util_log.WithContext(r.Context(), am.logger)).Log("msg", "error writing response", "err", err)
+	}
+}
+
+// silencesImportHandler imports a batch of silences, in the format returned by the export. Silences
+// already expired are skipped. If preserve_ids is true, the silences keep their ID and replace any
+// existing silence with the same ID, otherwise they're created with a new ID.
+func (am *Alertmanager) silencesImportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	preserveIDs := false
+	if v := r.URL.Query().Get("preserve_ids"); v != "" {
+		var err error
+		if preserveIDs, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, fmt.Sprintf("invalid preserve_ids %q", v), http.StatusBadRequest)
+			return
+		}
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error reading request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	sils := []*v2_models.PostableSilence{}
+	if err := swag.ReadJSON(body, &sils); err != nil {
+		http.Error(w, fmt.Sprintf("error parsing silences: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	result := SilencesImportResult{Errors: []string{}, IDs: map[string]string{}}
+	now := time.Now()
+
+	for i, s := range sils {
+		id, skipped, err := am.importSilence(s, preserveIDs, now)
+		switch {
+		case err != nil:
+			result.Errors = append(result.Errors, fmt.Sprintf("silence %d: %v", i, err))
+		case skipped:
+			result.Skipped++
+		default:
+			result.Imported++
+			if s.ID != "" {
+				result.IDs[s.ID] = id
+			}
+		}
+	}
+
+	util.WriteJSONResponse(w, struct {
+		Status string               `json:"status"`
+		Data   SilencesImportResult `json:"data"`
+	}{
+		Status: "success",
+		Data:   result,
+	})
+}
+
+func (am *Alertmanager) importSilence(s *v2_models.PostableSilence, preserveID bool, now time.Time) (string, bool, error) {
+	if s == nil {
+		return "", false, fmt.Errorf("empty silence")
+	}
+	if err := s.Validate(strfmt.Default); err != nil {
+		return "", false, err
+	}
+
+	sil, err := v2.PostableSilenceToProto(s)
+	if err != nil {
+		return "", false, err
+	}
+	if !sil.EndsAt.After(now) {
+		return "", true, nil
+	}
+	if sil.EndsAt.Before(sil.StartsAt) {
+		return "", false, fmt.Errorf("end time must not be before start time")
+	}
+
+	if !preserveID || sil.Id == "" {
+		sil.Id = ""
+		id, err := am.silences.Set(sil)
+		return id, false, err
+	}
+
+	if err := validateSilenceMatchers(sil.Matchers); err != nil {
+		return "", false, err
+	}
+
+	// Merging the silence, rather than setting it, keeps its ID. The silences
+	// broadcast it to the other replicas once merged.
+	sil.UpdatedAt = now
+	b, err := (&silencepb.MeshSilence{
+		Silence:   sil,
+		ExpiresAt: sil.EndsAt.Add(am.cfg.Retention),
+	}).Marshal()
+	if err != nil {
+		return "", false, err
+	}
+
+	// The silences state is made of length-delimited entries.
+	buf := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+len(b))
+	buf = append(buf[:binary.PutUvarint(buf, uint64(len(b)))], b...)
+	return sil.Id, false, am.silences.Merge(buf)
+}
+
+func validateSilenceMatchers(matchers []*silencepb.Matcher) error {
+	if len(matchers) == 0 {
+		return fmt.Errorf("at least one matcher required")
+	}
+
+	allMatchEmpty := true
+	for i, m := range matchers {
+		if !model.LabelName(m.Name).IsValid() {
+			return fmt.Errorf("invalid label matcher %d: invalid label name %q", i, m.Name)
+		}
+
+		var t labels.MatchType
+		switch m.Type {
+		case silencepb.Matcher_EQUAL:
+			t = labels.MatchEqual
+		case silencepb.Matcher_NOT_EQUAL:
+			t = labels.MatchNotEqual
+		case silencepb.Matcher_REGEXP:
+			t = labels.MatchRegexp
+		case silencepb.Matcher_NOT_REGEXP:
+			t = labels.MatchNotRegexp
+		}
+
+		matcher, err := labels.NewMatcher(t, m.Name, m.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid label matcher %d: %v", i, err)
+		}
+		allMatchEmpty = allMatchEmpty && matcher.Matches("")
+	}
+	if allMatchEmpty {
+		return fmt.Errorf("at least one matcher must not match the empty string")
+	}
+	return nil
+}
+
+// silencesExpireHandler expires all the active and pending silences matching the filter matchers,
+// which follow the same semantics as the filter of GET /api/v2/silences.
+func (am *Alertmanager) silencesExpireHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter := r.Form["filter"]
+	if len(filter) == 0 {
+		http.Error(w, "at least one filter matcher is required", http.StatusBadRequest)
+		return
+	}
+
+	matchers := make([]*labels.Matcher, 0, len(filter))
+	for _, f := range filter {
+		m, err := labels.ParseMatcher(f)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid filter %q: %v", f, err), http.StatusBadRequest)
+			return
+		}
+		matchers = append(matchers, m)
+	}
+
+	sils, _, err := am.silences.Query(silence.QState(types.SilenceStateActive, types.SilenceStatePending))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := SilencesExpireResult{Expired: []string{}}
+	for _, s := range sils {
+		if !v2.CheckSilenceMatchesFilterLabels(s, matchers) {
+			continue
+		}
+		// Expire broadcasts the expired silence to the other replicas.
+		if err := am.silences.Expire(s.Id); err != nil {
+			http.Error(w, fmt.Sprintf("failed to expire silence %s: %v", s.Id, err), http.StatusInternalServerError)
+			return
+		}
+		result.Expired = append(result.Expired, s.Id)
+	}
+
+	util.WriteJSONResponse(w, struct {
+		Status string               `json:"status"`
+		Data   SilencesExpireResult `json:"data"`
+	}{
+		Status: "success",
+		Data:   result,
+	})
+}
@@ -0,0 +1,215 @@
+package alertmanager
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-openapi/swag"
+	v2_models "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/silence"
+	"github.com/prometheus/alertmanager/silence/silencepb"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func newTestSilencesAlertmanager(t *testing.T) *Alertmanager {
+	am, err := New(&Config{
+		UserID:        "test",
+		Logger:        log.NewNopLogger(),
+		Limits:        &mockAlertManagerLimits{emailNotificationRateLimit: rate.Inf},
+		TenantDataDir: t.TempDir(),
+		ExternalURL:   &url.URL{Path: "/am"},
+		Retention:     time.Hour,
+	}, prometheus.NewPedanticRegistry())
+	require.NoError(t, err)
+	t.Cleanup(am.StopAndWait)
+	return am
+}
+
+func TestAlertmanager_SilencesExportAndImport(t *testing.T) {
+	now := time.Now()
+	source := newTestSilencesAlertmanager(t)
+
+	activeID, err := source.silences.Set(&silencepb.Silence{
+		Matchers:  []*silencepb.Matcher{{Name: "alertname", Pattern: "foo", Type: silencepb.Matcher_EQUAL}},
+		StartsAt:  now,
+		EndsAt:    now.Add(time.Hour),
+		CreatedBy: "user",
+		Comment:   "active",
+	})
+	require.NoError(t, err)
+
+	pendingID, err := source.silences.Set(&silencepb.Silence{
+		Matchers:  []*silencepb.Matcher{{Name: "cluster", Pattern: "prod-.*", Type: silencepb.Matcher_REGEXP}},
+		StartsAt:  now.Add(time.Hour),
+		EndsAt:    now.Add(2 * time.Hour),
+		CreatedBy: "user",
+		Comment:   "pending",
+	})
+	require.NoError(t, err)
+
+	expiredID, err := source.silences.Set(&silencepb.Silence{
+		Matchers:  []*silencepb.Matcher{{Name: "alertname", Pattern: "bar", Type: silencepb.Matcher_EQUAL}},
+		StartsAt:  now,
+		EndsAt:    now.Add(time.Hour),
+		CreatedBy: "user",
+		Comment:   "expired",
+	})
+	require.NoError(t, err)
+	require.NoError(t, source.silences.Expire(expiredID))
+
+	// Export all the silences, expired ones included.
+	w := httptest.NewRecorder()
+	source.mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/am/api/v1/silences/export", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	exported := v2_models.GettableSilences{}
+	require.NoError(t, swag.ReadJSON(w.Body.Bytes(), &exported))
+	require.Len(t, exported, 3)
+	exportedBody := w.Body.String()
+
+	t.Run("should import the silences preserving their IDs", func(t *testing.T) {
+		target := newTestSilencesAlertmanager(t)
+		result := importSilences(t, target, "?preserve_ids=true", exportedBody)
+
+		assert.Equal(t, SilencesImportResult{
+			Imported: 2,
+			Skipped:  1,
+			Errors:   []string{},
+			IDs:      map[string]string{activeID: activeID, pendingID: pendingID},
+		}, result)
+
+		sils, _, err := target.silences.Query(silence.QIDs(activeID, pendingID))
+		require.NoError(t, err)
+		require.Len(t, sils, 2)
+
+		active, _, err := target.silences.Query(silence.QIDs(activeID), silence.QState(types.SilenceStateActive))
+		require.NoError(t, err)
+		require.Len(t, active, 1)
+		assert.Equal(t, "active", active[0].Comment)
+
+		// Importing again replaces the silences with the same ID.
+		result = importSilences(t, target, "?preserve_ids=true", exportedBody)
+		assert.Equal(t, 2, result.Imported)
+
+		sils, _, err = target.silences.Query()
+		require.NoError(t, err)
+		assert.Len(t, sils, 2)
+	})
+
+	t.Run("should import the silences with new IDs", func(t *testing.T) {
+		target := newTestSilencesAlertmanager(t)
+		result := importSilences(t, target, "", exportedBody)
+
+		assert.Equal(t, 2, result.Imported)
+		assert.Equal(t, 1, result.Skipped)
+		assert.Empty(t, result.Errors)
+		require.Len(t, result.IDs, 2)
+		assert.NotEqual(t, activeID, result.IDs[activeID])
+		assert.NotEqual(t, pendingID, result.IDs[pendingID])
+
+		sils, _, err := target.silences.Query(silence.QIDs(result.IDs[activeID], result.IDs[pendingID]))
+		require.NoError(t, err)
+		assert.Len(t, sils, 2)
+	})
+
+	t.Run("should report the invalid silences", func(t *testing.T) {
+		target := newTestSilencesAlertmanager(t)
+		body := `[
+			{"id": "invalid", "matchers": [{"name": "alertname", "value": ".*", "isRegex": true}], "startsAt": "` + now.Format(time.RFC3339) + `", "endsAt": "` + now.Add(time.Hour).Format(time.RFC3339) + `", "createdBy": "user", "comment": "matches everything"},
+			{"matchers": [{"name": "alertname", "value": "foo", "isRegex": false}], "startsAt": "` + now.Format(time.RFC3339) + `", "endsAt": "` + now.Add(time.Hour).Format(time.RFC3339) + `", "createdBy": "user", "comment": "valid"}
+		]`
+		result := importSilences(t, target, "?preserve_ids=true", body)
+
+		assert.Equal(t, 1, result.Imported)
+		assert.Equal(t, []string{"silence 0: at least one matcher must not match the empty string"}, result.Errors)
+	})
+
+	t.Run("should reject an invalid preserve_ids", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		source.mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/am/api/v1/silences/import?preserve_ids=maybe", strings.NewReader("[]")))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestAlertmanager_SilencesExpire(t *testing.T) {
+	now := time.Now()
+	am := newTestSilencesAlertmanager(t)
+
+	ids := map[string]string{}
+	for name, matchers := range map[string][]*silencepb.Matcher{
+		"team-a": {{Name: "team", Pattern: "a", Type: silencepb.Matcher_EQUAL}},
+		"team-a-prod": {
+			{Name: "team", Pattern: "a", Type: silencepb.Matcher_EQUAL},
+			{Name: "cluster", Pattern: "prod", Type: silencepb.Matcher_EQUAL},
+		},
+		"team-b": {{Name: "team", Pattern: "b", Type: silencepb.Matcher_EQUAL}},
+	} {
+		id, err := am.silences.Set(&silencepb.Silence{
+			Matchers:  matchers,
+			StartsAt:  now,
+			EndsAt:    now.Add(time.Hour),
+			CreatedBy: "user",
+			Comment:   name,
+		})
+		require.NoError(t, err)
+		ids[name] = id
+	}
+
+	expire := func(query string) (int, SilencesExpireResult) {
+		w := httptest.NewRecorder()
+		am.mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/am/api/v1/silences/expire"+query, nil))
+
+		resp := struct {
+			Status string               `json:"status"`
+			Data   SilencesExpireResult `json:"data"`
+		}{}
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		}
+		return w.Code, resp.Data
+	}
+
+	status, _ := expire("")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = expire("?filter=" + url.QueryEscape(`team=~"("`))
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, result := expire("?filter=" + url.QueryEscape(`team="a"`))
+	require.Equal(t, http.StatusOK, status)
+	assert.ElementsMatch(t, []string{ids["team-a"], ids["team-a-prod"]}, result.Expired)
+
+	// Already expired silences are not expired again.
+	status, result = expire("?filter=" + url.QueryEscape(`team="a"`))
+	require.Equal(t, http.StatusOK, status)
+	assert.Empty(t, result.Expired)
+
+	active, _, err := am.silences.Query(silence.QState(types.SilenceStateActive))
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	assert.Equal(t, ids["team-b"], active[0].Id)
+}
+
+func importSilences(t *testing.T, am *Alertmanager, query, body string) SilencesImportResult {
+	w := httptest.NewRecorder()
+	am.mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/am/api/v1/silences/import"+query, strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	resp := struct {
+		Status string               `json:"status"`
+		Data   SilencesImportResult `json:"data"`
+	}{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, "success", resp.Status)
+	return resp.Data
+}
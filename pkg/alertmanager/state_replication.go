@@ -71,7 +71,7 @@ func newReplicatedStates(userID string, rf int, re Replicator, st alertstore.Ale
 		replicationFactor: rf,
 		replicator:        re,
 		store:             st,
-		states:            make(map[string]cluster.State, 2), // we use two, one for the notifications and one for silences.
+		states:            make(map[string]cluster.State, 3), // we use three, one for the notifications, one for silences and one for the notification history (if enabled).
 		msgc:              make(chan *clusterpb.Part),
 		reg:               r,
 		settleReadTimeout: defaultSettleReadTimeout,
@@ -91,6 +91,8 @@ type Config struct {
 	NumWorkers                int           `yaml:"num_workers"`
 	ObjectStoreType           string        `yaml:"object_store_type"`
 	DeleteRequestCancelPeriod time.Duration `yaml:"delete_request_cancel_period"`
+
+	RetentionEnforcementInterval time.Duration `yaml:"retention_enforcement_interval"`
 }
 
 // RegisterFlags registers CLI flags for Config
@@ -99,6 +101,7 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&cfg.NumWorkers, "purger.num-workers", 2, "Number of workers executing delete plans in parallel")
 	f.StringVar(&cfg.ObjectStoreType, "purger.object-store-type", "", "Name of the object store to use for storing delete plans")
 	f.DurationVar(&cfg.DeleteRequestCancelPeriod, "purger.delete-request-cancel-period", 24*time.Hour, "Allow cancellation of delete request until duration after they are created. Data would be deleted only after delete requests have been older than this duration. Ideally this should be set to at least 24h.")
+	f.DurationVar(&cfg.RetentionEnforcementInterval, "purger.retention-enforcement-interval", 24*time.Hour, "How frequently delete requests are created to delete the chunks older than the per-tenant chunks retention period.")
 }
 
 type workerJob struct {
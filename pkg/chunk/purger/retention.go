@@ -0,0 +1,302 @@
+package purger
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/model"
+	"github.com/weaveworks/common/user"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/tenant"
+	"github.com/cortexproject/cortex/pkg/util/services"
+)
+
+// chunkKeyDelim separates the user ID from the rest of the chunk external key.
+const chunkKeyDelim = "/"
+
+// RetentionLimits is the interface used by the RetentionEnforcer to look up
+// per-tenant retention overrides.
+type RetentionLimits interface {
+	ChunksRetentionPeriod(userID string) time.Duration
+}
+
+// RetentionEnforcer periodically creates delete requests for the chunks older than the
+// per-tenant retention period. The delete requests are then processed by the Purger,
+// which takes care of removing both index entries and chunks.
+//
+// The chunks index can only be looked up by metric name, so the enforcer discovers tenants
+// and expired chunks by listing the object stores holding the chunks. The metric name of each
+// expired series is read from one of its chunks, and a selector matching it is added to the
+// delete request.
+type RetentionEnforcer struct {
+	services.Service
+
+	cfg          Config
+	deleteStore  *DeleteStore
+	chunkStore   chunk.Store
+	chunkClients []chunk.ObjectClient
+	limits       RetentionLimits
+	logger       log.Logger
+
+	runsStarted           prometheus.Counter
+	runsCompleted         prometheus.Counter
+	runsFailed            prometheus.Counter
+	deleteRequestsCreated prometheus.Counter
+	lastRunSuccessfulTime prometheus.Gauge
+	expiredChunksFound    prometheus.Counter
+}
+
+// NewRetentionEnforcer creates a new RetentionEnforcer. The chunkClients are the object stores
+// where chunks are stored, each one listed to discover expired chunks.
+func NewRetentionEnforcer(cfg Config, deleteStore *DeleteStore, chunkStore chunk.Store, chunkClients []chunk.ObjectClient, limits RetentionLimits, logger log.Logger, reg prometheus.Registerer) *RetentionEnforcer {
+	r := &RetentionEnforcer{
+		cfg:          cfg,
+		deleteStore:  deleteStore,
+		chunkStore:   chunkStore,
+		chunkClients: chunkClients,
+		limits:       limits,
+		logger:       log.With(logger, "component", "retention-enforcer"),
+
+		runsStarted: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: "cortex",
+			Name:      "purger_retention_runs_started_total",
+			Help:      "Total number of chunks retention enforcement runs started.",
+		}),
+		runsCompleted: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: "cortex",
+			Name:      "purger_retention_runs_completed_total",
+			Help:      "Total number of chunks retention enforcement runs successfully completed.",
+		}),
+		runsFailed: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: "cortex",
+			Name:      "purger_retention_runs_failed_total",
+			Help:      "Total number of chunks retention enforcement runs failed.",
+		}),
+		lastRunSuccessfulTime: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: "cortex",
+			Name:      "purger_retention_last_successful_run_timestamp_seconds",
+			Help:      "Unix timestamp of the last successful chunks retention enforcement run.",
+		}),
+		deleteRequestsCreated: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: "cortex",
+			Name:      "purger_retention_delete_requests_created_total",
+			Help:      "Total number of delete requests created to enforce the chunks retention.",
+		}),
+		expiredChunksFound: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace: "cortex",
+			Name:      "purger_retention_expired_chunks_total",
+			Help:      "Total number of expired chunks found while enforcing the chunks retention.",
+		}),
+	}
+
+	r.Service = services.NewTimerService(cfg.RetentionEnforcementInterval, nil, r.iteration, nil)
+	return r
+}
+
+func (r *RetentionEnforcer) iteration(ctx context.Context) error {
+	r.runsStarted.Inc()
+
+	if err := r.enforceRetention(ctx); err != nil {
+		r.runsFailed.Inc()
+		level.Error(r.logger).Log("msg", "failed to enforce chunks retention", "err", err)
+
+		// Do not return the error, otherwise the service would stop.
+		return nil
+	}
+
+	r.runsCompleted.Inc()
+	r.lastRunSuccessfulTime.SetToCurrentTime()
+	return nil
+}
+
+func (r *RetentionEnforcer) enforceRetention(ctx context.Context) error {
+	userIDs, err := r.listUsers(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list users")
+	}
+
+	// Continue with the other users if one fails, but report the failure at the end.
+	var lastErr error
+	for _, userID := range userIDs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		retention := r.limits.ChunksRetentionPeriod(userID)
+		if retention <= 0 {
+			continue
+		}
+
+		if err := r.enforceUserRetention(ctx, userID, retention); err != nil {
+			level.Error(r.logger).Log("msg", "failed to enforce chunks retention for user", "user", userID, "err", err)
+			lastErr = err
+		}
+	}
+
+	return lastErr
+}
+
+// listUsers returns the users having chunks in any of the chunk object stores.
+func (r *RetentionEnforcer) listUsers(ctx context.Context) ([]string, error) {
+	users := map[string]struct{}{}
+
+	for _, client := range r.chunkClients {
+		_, prefixes, err := client.List(ctx, "", chunkKeyDelim)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range prefixes {
+			userID := strings.TrimSuffix(string(p), chunkKeyDelim)
+
+			// Skip anything which is not a valid tenant, like the delete plans which
+			// may be stored in the same bucket.
+			if userID == "" || tenant.ValidTenantID(userID) != nil {
+				continue
+			}
+			users[userID] = struct{}{}
+		}
+	}
+
+	out := make([]string, 0, len(users))
+	for userID := range users {
+		out = append(out, userID)
+	}
+	sort.Strings(out)
+
+	return out, nil
+}
+
+func (r *RetentionEnforcer) enforceUserRetention(ctx context.Context, userID string, retention time.Duration) error {
+	logger := log.With(r.logger, "user", userID)
+	ctx = user.InjectOrgID(ctx, userID)
+
+	// The purger processes a single delete request per user at a time, so we wait
+	// until the pending ones (including the previous retention request) are done
+	// to avoid requesting the deletion of the same chunks twice.
+	pending, err := r.deleteStore.GetPendingDeleteRequestsForUser(ctx, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get pending delete requests")
+	}
+	if len(pending) > 0 {
+		level.Debug(logger).Log("msg", "skipping chunks retention enforcement because the user has pending delete requests", "pending", len(pending))
+		return nil
+	}
+
+	// Align the cutoff to the start of the day, so that chunks crossing it are
+	// partially deleted (and so re-written) at most once a day.
+	cutoff := model.Time((int64(model.Now().Add(-retention)) / millisecondPerDay) * millisecondPerDay)
+
+	expired, err := r.listExpiredChunks(ctx, userID, cutoff)
+	if err != nil {
+		return errors.Wrap(err, "failed to list expired chunks")
+	}
+	if len(expired) == 0 {
+		level.Debug(logger).Log("msg", "no expired chunks found")
+		return nil
+	}
+
+	metricNames, start, err := r.expiredMetricNames(ctx, expired)
+	if err != nil {
+		return errors.Wrap(err, "failed to read metric names of expired chunks")
+	}
+	if len(metricNames) == 0 {
+		return nil
+	}
+
+	selectors := make([]string, 0, len(metricNames))
+	for _, name := range metricNames {
+		selectors = append(selectors, fmt.Sprintf("{%s=%q}", model.MetricNameLabel, name))
+	}
+
+	end := cutoff - 1
+	if err := r.deleteStore.AddDeleteRequest(ctx, userID, start, end, selectors); err != nil {
+		return errors.Wrap(err, "failed to add delete request")
+	}
+
+	r.deleteRequestsCreated.Inc()
+	r.expiredChunksFound.Add(float64(len(expired)))
+	level.Info(logger).Log("msg", "created delete request to enforce chunks retention", "start", start, "end", end, "metrics", len(metricNames), "expired_chunks", len(expired))
+
+	return nil
+}
+
+// listExpiredChunks returns the chunks of the user containing samples older than cutoff,
+// with their external keys. The returned chunks are not loaded.
+func (r *RetentionEnforcer) listExpiredChunks(ctx context.Context, userID string, cutoff model.Time) (map[string]chunk.Chunk, error) {
+	expired := map[string]chunk.Chunk{}
+
+	for _, client := range r.chunkClients {
+		objects, _, err := client.List(ctx, userID+chunkKeyDelim, "")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, obj := range objects {
+			c, err := chunk.ParseExternalKey(userID, obj.Key)
+			if err != nil {
+				level.Warn(r.logger).Log("msg", "skipping object which is not a chunk", "key", obj.Key, "err", err)
+				continue
+			}
+
+			if c.From < cutoff {
+				expired[obj.Key] = c
+			}
+		}
+	}
+
+	return expired, nil
+}
+
+// expiredMetricNames fetches one chunk per series to read its metric name, and returns the
+// sorted metric names along with the minimum time of the expired chunks.
+func (r *RetentionEnforcer) expiredMetricNames(ctx context.Context, expired map[string]chunk.Chunk) ([]string, model.Time, error) {
+	start := model.Latest
+	perSeries := map[model.Fingerprint]string{}
+
+	for key, c := range expired {
+		if c.From < start {
+			start = c.From
+		}
+		if _, ok := perSeries[c.Fingerprint]; !ok {
+			perSeries[c.Fingerprint] = key
+		}
+	}
+
+	names := map[string]struct{}{}
+	for _, key := range perSeries {
+		c := expired[key]
+
+		fetched, err := r.chunkStore.GetChunkFetcher(c.Through).FetchChunks(ctx, []chunk.Chunk{c}, []string{key})
+		if err != nil {
+			if isMissingChunkErr(err) {
+				// The chunk has been deleted in the meanwhile.
+				continue
+			}
+			return nil, 0, err
+		}
+
+		for _, f := range fetched {
+			if name := f.Metric.Get(model.MetricNameLabel); name != "" {
+				names[name] = struct{}{}
+			}
+		}
+	}
+
+	out := make([]string, 0, len(names))
+	for name := range names {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+
+	return out, start, nil
+}
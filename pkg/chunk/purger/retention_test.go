@@ -0,0 +1,98 @@
+package purger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/chunk/testutils"
+	"github.com/cortexproject/cortex/pkg/util/flagext"
+	util_log "github.com/cortexproject/cortex/pkg/util/log"
+)
+
+type mockRetentionLimits map[string]time.Duration
+
+func (m mockRetentionLimits) ChunksRetentionPeriod(userID string) time.Duration {
+	return m[userID]
+}
+
+func TestRetentionEnforcer(t *testing.T) {
+	now := model.Now()
+	cutoff := model.Time((int64(now.Add(-48*time.Hour)) / millisecondPerDay) * millisecondPerDay)
+
+	for name, tc := range map[string]struct {
+		retention       time.Duration
+		pendingRequest  bool
+		expectedRequest bool
+	}{
+		"retention disabled": {
+			retention: 0,
+		},
+		"retention enabled": {
+			retention:       48 * time.Hour,
+			expectedRequest: true,
+		},
+		"retention enabled but no expired chunks": {
+			retention: 30 * 24 * time.Hour,
+		},
+		"retention enabled with a pending delete request": {
+			retention:      48 * time.Hour,
+			pendingRequest: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			deleteStore := setupTestDeleteStore(t)
+
+			storage := chunk.NewMockStorage()
+			chunkStore, err := testutils.SetupTestChunkStoreWithClients(storage, storage, storage)
+			require.NoError(t, err)
+
+			// Store chunks spanning from 5 days ago until now.
+			chunks, err := buildChunks(now.Add(-5*24*time.Hour), now, 1)
+			require.NoError(t, err)
+			require.NoError(t, chunkStore.Put(context.Background(), chunks))
+
+			if tc.pendingRequest {
+				require.NoError(t, deleteStore.AddDeleteRequest(context.Background(), userID, 0, now, []string{`{__name__="foo"}`}))
+			}
+
+			var cfg Config
+			flagext.DefaultValues(&cfg)
+
+			enforcer := NewRetentionEnforcer(cfg, deleteStore, chunkStore, []chunk.ObjectClient{storage}, mockRetentionLimits{userID: tc.retention}, util_log.Logger, prometheus.NewRegistry())
+			require.NoError(t, enforcer.enforceRetention(context.Background()))
+
+			requests, err := deleteStore.GetAllDeleteRequestsForUser(context.Background(), userID)
+			require.NoError(t, err)
+
+			if tc.pendingRequest {
+				require.Len(t, requests, 1)
+				return
+			}
+
+			if !tc.expectedRequest {
+				require.Len(t, requests, 0)
+				return
+			}
+
+			require.Len(t, requests, 1)
+			assert.Equal(t, StatusReceived, requests[0].Status)
+			assert.Equal(t, chunks[0].From, requests[0].StartTime)
+			assert.Equal(t, cutoff-1, requests[0].EndTime)
+			assert.Equal(t, []string{`{__name__="foo"}`}, requests[0].Selectors)
+
+			// Running the enforcer again should not create a new request while the previous one is pending.
+			require.NoError(t, enforcer.enforceRetention(context.Background()))
+
+			requests, err = deleteStore.GetAllDeleteRequestsForUser(context.Background(), userID)
+			require.NoError(t, err)
+			require.Len(t, requests, 1)
+		})
+	}
+}
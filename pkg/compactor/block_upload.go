@@ -0,0 +1,511 @@
+package compactor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/gorilla/mux"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/prometheus/prometheus/tsdb/index"
+	"github.com/thanos-io/thanos/pkg/block"
+	"github.com/thanos-io/thanos/pkg/block/metadata"
+	"github.com/thanos-io/thanos/pkg/objstore"
+
+	"github.com/cortexproject/cortex/pkg/storage/bucket"
+	cortex_tsdb "github.com/cortexproject/cortex/pkg/storage/tsdb"
+	"github.com/cortexproject/cortex/pkg/storage/tsdb/bucketindex"
+	"github.com/cortexproject/cortex/pkg/tenant"
+	"github.com/cortexproject/cortex/pkg/util"
+	util_log "github.com/cortexproject/cortex/pkg/util/log"
+	"github.com/cortexproject/cortex/pkg/util/services"
+)
+
+const (
+	// uploadingMetaFilename is the name of the meta.json stored while the block is being uploaded.
+	// The block becomes visible to other components only once the upload is finished and the
+	// real meta.json is written, because blocks without meta.json are considered partial.
+	uploadingMetaFilename = "uploading-" + block.MetaFilename
+
+	blockUploadRouteVar = "block"
+)
+
+var (
+	// Files which can be uploaded for a block, relative to the block directory.
+	uploadableBlockFile = regexp.MustCompile(`^(` + block.IndexFilename + `|` + block.ChunksDirname + `/\d{6})$`)
+
+	errBlockUploadDisabled = errors.New("block upload is disabled for the tenant")
+	errBlockUploadNotFound = errors.New("block upload has not been started")
+	errBlockAlreadyExists  = errors.New("block already exists")
+)
+
+// blockUploadError is an error caused by an invalid request, which is returned to the client.
+type blockUploadError struct {
+	status int
+	err    error
+}
+
+func (e blockUploadError) Error() string {
+	return e.err.Error()
+}
+
+func httpError(status int, format string, args ...interface{}) error {
+	return blockUploadError{status: status, err: fmt.Errorf(format, args...)}
+}
+
+// BlockUploadFileInfo describes a file of a block being uploaded.
+type BlockUploadFileInfo struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// BlockUploadStatus is the response of the block upload status endpoint. It can be used by
+// clients to resume an interrupted upload, skipping the files which have already been uploaded.
+type BlockUploadStatus struct {
+	BlockID string                `json:"block_id"`
+	State   string                `json:"state"`
+	Files   []BlockUploadFileInfo `json:"files"`
+}
+
+const (
+	blockUploadStateUploading = "uploading"
+	blockUploadStateComplete  = "complete"
+)
+
+// StartBlockUpload handles the request to start the upload of a block. The request body must
+// contain the block's meta.json, which is validated before accepting the upload.
+func (c *Compactor) StartBlockUpload(w http.ResponseWriter, r *http.Request) {
+	c.handleBlockUpload(w, r, "start", func(ctx context.Context, userID string, blockID ulid.ULID, userBkt objstore.Bucket, logger log.Logger) (interface{}, error) {
+		meta, err := metadata.Read(ioutil.NopCloser(r.Body))
+		if err != nil {
+			return nil, httpError(http.StatusBadRequest, "invalid meta.json: %s", err)
+		}
+
+		if err := c.validateBlockMeta(userID, blockID, meta, time.Now()); err != nil {
+			return nil, err
+		}
+
+		if err := checkBlockDoesNotExist(ctx, userBkt, blockID); err != nil {
+			return nil, err
+		}
+
+		// The tenant external label is the only one required by Cortex to correctly
+		// handle the block, so we add it if missing.
+		if meta.Thanos.Labels == nil {
+			meta.Thanos.Labels = map[string]string{}
+		}
+		meta.Thanos.Labels[cortex_tsdb.TenantIDExternalLabel] = userID
+
+		if err := uploadMeta(ctx, userBkt, blockID, uploadingMetaFilename, meta); err != nil {
+			return nil, err
+		}
+
+		level.Info(logger).Log("msg", "started block upload", "min_time", meta.MinTime, "max_time", meta.MaxTime)
+		return nil, nil
+	})
+}
+
+// UploadBlockFile handles the upload of a single file of a block. The file path, relative to the
+// block directory, is passed in the "path" query parameter. Files can be uploaded multiple times,
+// in which case the previously uploaded content is overwritten, until the upload is finished.
+func (c *Compactor) UploadBlockFile(w http.ResponseWriter, r *http.Request) {
+	c.handleBlockUpload(w, r, "upload_file", func(ctx context.Context, userID string, blockID ulid.ULID, userBkt objstore.Bucket, logger log.Logger) (interface{}, error) {
+		filePath := r.URL.Query().Get("path")
+		if !uploadableBlockFile.MatchString(filePath) {
+			return nil, httpError(http.StatusBadRequest, "invalid file path %q, only the index and chunks segment files can be uploaded", filePath)
+		}
+
+		if _, err := readUploadingMeta(ctx, userBkt, blockID); err != nil {
+			return nil, err
+		}
+
+		// The uploading meta.json may be left in place once the upload is finished (eg. if it
+		// failed to be deleted), but the files of a visible block must never be overwritten.
+		if err := checkBlockDoesNotExist(ctx, userBkt, blockID); err != nil {
+			return nil, err
+		}
+
+		if r.ContentLength == 0 {
+			return nil, httpError(http.StatusBadRequest, "file %s is empty", filePath)
+		}
+
+		if err := userBkt.Upload(ctx, path.Join(blockID.String(), filePath), r.Body); err != nil {
+			return nil, errors.Wrapf(err, "upload file %s", filePath)
+		}
+
+		level.Debug(logger).Log("msg", "uploaded block file", "path", filePath)
+		return nil, nil
+	})
+}
+
+// FinishBlockUpload handles the request to finish the upload of a block. The block is validated
+// and, if valid, made visible to the other components and added to the bucket index.
+func (c *Compactor) FinishBlockUpload(w http.ResponseWriter, r *http.Request) {
+	c.handleBlockUpload(w, r, "finish", func(ctx context.Context, userID string, blockID ulid.ULID, userBkt objstore.Bucket, logger log.Logger) (interface{}, error) {
+		meta, err := readUploadingMeta(ctx, userBkt, blockID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := c.validateBlockFiles(ctx, userBkt, blockID, meta, logger); err != nil {
+			c.blockUploadValidationFailures.Inc()
+			return nil, err
+		}
+
+		// Writing the meta.json is what makes the block visible to other components.
+		if err := uploadMeta(ctx, userBkt, blockID, block.MetaFilename, meta); err != nil {
+			return nil, err
+		}
+
+		// Update the bucket index, so that the block can be queried without waiting
+		// for the next periodic update. The uploading meta.json is kept until then,
+		// so that the request can be retried on failure.
+		if err := c.updateBucketIndex(ctx, userID, logger); err != nil {
+			return nil, errors.Wrap(err, "update bucket index")
+		}
+
+		if err := userBkt.Delete(ctx, path.Join(blockID.String(), uploadingMetaFilename)); err != nil {
+			level.Warn(logger).Log("msg", "failed to delete uploading meta.json", "err", err)
+		}
+
+		c.blockUploadsCompleted.Inc()
+		level.Info(logger).Log("msg", "finished block upload")
+		return nil, nil
+	})
+}
+
+// AbortBlockUpload handles the request to abort the upload of a block, deleting all files
+// uploaded so far. It's not possible to abort the upload of a block which has been finished.
+func (c *Compactor) AbortBlockUpload(w http.ResponseWriter, r *http.Request) {
+	c.handleBlockUpload(w, r, "abort", func(ctx context.Context, userID string, blockID ulid.ULID, userBkt objstore.Bucket, logger log.Logger) (interface{}, error) {
+		if _, err := readUploadingMeta(ctx, userBkt, blockID); err != nil {
+			return nil, err
+		}
+
+		if err := checkBlockDoesNotExist(ctx, userBkt, blockID); err != nil {
+			return nil, err
+		}
+
+		// Delete the uploading meta.json last, so that a failure can be retried.
+		err := userBkt.Iter(ctx, blockID.String(), func(name string) error {
+			if path.Base(name) == uploadingMetaFilename {
+				return nil
+			}
+			return userBkt.Delete(ctx, name)
+		}, objstore.WithRecursiveIter)
+		if err != nil {
+			return nil, errors.Wrap(err, "delete uploaded files")
+		}
+
+		if err := userBkt.Delete(ctx, path.Join(blockID.String(), uploadingMetaFilename)); err != nil {
+			return nil, errors.Wrap(err, "delete uploading meta.json")
+		}
+
+		level.Info(logger).Log("msg", "aborted block upload")
+		return nil, nil
+	})
+}
+
+// BlockUploadStatus handles the request to get the status of a block upload.
+func (c *Compactor) BlockUploadStatus(w http.ResponseWriter, r *http.Request) {
+	c.handleBlockUpload(w, r, "status", func(ctx context.Context, userID string, blockID ulid.ULID, userBkt objstore.Bucket, logger log.Logger) (interface{}, error) {
+		status := BlockUploadStatus{BlockID: blockID.String(), Files: []BlockUploadFileInfo{}}
+
+		if exists, err := userBkt.Exists(ctx, path.Join(blockID.String(), block.MetaFilename)); err != nil {
+			return nil, errors.Wrap(err, "check meta.json")
+		} else if exists {
+			status.State = blockUploadStateComplete
+			return status, nil
+		}
+
+		if _, err := readUploadingMeta(ctx, userBkt, blockID); err != nil {
+			return nil, err
+		}
+		status.State = blockUploadStateUploading
+
+		err := userBkt.Iter(ctx, blockID.String(), func(name string) error {
+			filePath := name[len(blockID.String())+1:]
+			if !uploadableBlockFile.MatchString(filePath) {
+				return nil
+			}
+
+			attrs, err := userBkt.Attributes(ctx, name)
+			if err != nil {
+				return err
+			}
+			status.Files = append(status.Files, BlockUploadFileInfo{Path: filePath, Size: attrs.Size})
+			return nil
+		}, objstore.WithRecursiveIter)
+		if err != nil {
+			return nil, errors.Wrap(err, "list uploaded files")
+		}
+
+		return status, nil
+	})
+}
+
+// handleBlockUpload runs the common checks for all block upload handlers and writes the response.
+func (c *Compactor) handleBlockUpload(w http.ResponseWriter, r *http.Request, op string, fn func(ctx context.Context, userID string, blockID ulid.ULID, userBkt objstore.Bucket, logger log.Logger) (interface{}, error)) {
+	if c.State() != services.Running {
+		http.Error(w, "compactor is not running", http.StatusServiceUnavailable)
+		return
+	}
+
+	userID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if !c.cfgProvider.CompactorBlockUploadEnabled(userID) {
+		http.Error(w, errBlockUploadDisabled.Error(), http.StatusForbidden)
+		return
+	}
+
+	blockID, err := ulid.Parse(mux.Vars(r)[blockUploadRouteVar])
+	if err != nil {
+		http.Error(w, "invalid block ID", http.StatusBadRequest)
+		return
+	}
+
+	logger := log.With(util_log.WithContext(r.Context(), c.logger), "user", userID, "block", blockID.String(), "op", op)
+	userBkt := bucket.NewUserBucketClient(userID, c.bucketClient, c.cfgProvider)
+
+	res, err := fn(r.Context(), userID, blockID, userBkt, logger)
+	if err != nil {
+		var uploadErr blockUploadError
+		if errors.As(err, &uploadErr) {
+			http.Error(w, uploadErr.Error(), uploadErr.status)
+			return
+		}
+
+		level.Error(logger).Log("msg", "block upload request failed", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if res == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	util.WriteJSONResponse(w, res)
+}
+
+// validateBlockMeta validates the meta.json of a block to upload.
+func (c *Compactor) validateBlockMeta(userID string, blockID ulid.ULID, meta *metadata.Meta, now time.Time) error {
+	if meta.ULID != blockID {
+		return httpError(http.StatusBadRequest, "block ID %s in meta.json doesn't match the block ID %s in the request", meta.ULID.String(), blockID.String())
+	}
+
+	if meta.MinTime < 0 || meta.MaxTime < 0 || meta.MinTime >= meta.MaxTime {
+		return httpError(http.StatusBadRequest, "invalid block time range [%d, %d)", meta.MinTime, meta.MaxTime)
+	}
+
+	if maxTime := util.TimeFromMillis(meta.MaxTime); maxTime.After(now) {
+		return httpError(http.StatusBadRequest, "block max time %s is in the future", maxTime.UTC().Format(time.RFC3339))
+	}
+
+	if len(c.compactorCfg.BlockRanges) > 0 {
+		maxRange := c.compactorCfg.BlockRanges[len(c.compactorCfg.BlockRanges)-1]
+		if rng := time.Duration(meta.MaxTime-meta.MinTime) * time.Millisecond; rng > maxRange {
+			return httpError(http.StatusBadRequest, "block time range %s is larger than the largest compaction block range %s", rng, maxRange)
+		}
+	}
+
+	if retention := c.cfgProvider.CompactorBlocksRetentionPeriod(userID); retention > 0 {
+		if maxTime := util.TimeFromMillis(meta.MaxTime); maxTime.Before(now.Add(-retention)) {
+			return httpError(http.StatusBadRequest, "block max time %s is older than the retention period %s", maxTime.UTC().Format(time.RFC3339), retention)
+		}
+	}
+
+	if meta.Thanos.Downsample.Resolution != 0 {
+		return httpError(http.StatusBadRequest, "downsampled blocks are not supported")
+	}
+
+	if tenantID, ok := meta.Thanos.Labels[cortex_tsdb.TenantIDExternalLabel]; ok && tenantID != userID {
+		return httpError(http.StatusBadRequest, "block external label %s=%q doesn't match the tenant", cortex_tsdb.TenantIDExternalLabel, tenantID)
+	}
+
+	return nil
+}
+
+// validateBlockFiles checks that all the block files have been uploaded, and verifies the index.
+func (c *Compactor) validateBlockFiles(ctx context.Context, userBkt objstore.Bucket, blockID ulid.ULID, meta *metadata.Meta, logger log.Logger) error {
+	indexPath := path.Join(blockID.String(), block.IndexFilename)
+	if exists, err := userBkt.Exists(ctx, indexPath); err != nil {
+		return errors.Wrap(err, "check index")
+	} else if !exists {
+		return httpError(http.StatusBadRequest, "block index has not been uploaded")
+	}
+
+	var chunkFiles []string
+	err := userBkt.Iter(ctx, path.Join(blockID.String(), block.ChunksDirname), func(name string) error {
+		chunkFiles = append(chunkFiles, name)
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "list chunks")
+	}
+
+	// Download the index locally to verify it.
+	dir, err := ioutil.TempDir(c.compactorCfg.DataDir, "upload-"+blockID.String())
+	if err != nil {
+		return errors.Wrap(err, "create temporary directory")
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			level.Warn(logger).Log("msg", "failed to delete temporary directory", "dir", dir, "err", err)
+		}
+	}()
+
+	localIndex := filepath.Join(dir, block.IndexFilename)
+	if err := objstore.DownloadFile(ctx, logger, userBkt, indexPath, localIndex); err != nil {
+		return errors.Wrap(err, "download index")
+	}
+
+	if err := block.VerifyIndex(logger, localIndex, meta.MinTime, meta.MaxTime); err != nil {
+		return httpError(http.StatusBadRequest, "invalid index: %s", err)
+	}
+
+	numSeries, err := validateIndexLabels(localIndex)
+	if err != nil {
+		return err
+	}
+
+	if numSeries > 0 && len(chunkFiles) == 0 {
+		return httpError(http.StatusBadRequest, "block chunks have not been uploaded")
+	}
+
+	return nil
+}
+
+// validateIndexLabels checks that the labels of all series in the index are valid, and returns
+// the number of series.
+func validateIndexLabels(indexPath string) (int, error) {
+	r, err := index.NewFileReader(indexPath)
+	if err != nil {
+		return 0, httpError(http.StatusBadRequest, "invalid index: %s", err)
+	}
+	defer r.Close()
+
+	p, err := r.Postings(index.AllPostingsKey())
+	if err != nil {
+		return 0, httpError(http.StatusBadRequest, "invalid index: %s", err)
+	}
+
+	var (
+		lset      labels.Labels
+		chks      []chunks.Meta
+		numSeries int
+	)
+	for p.Next() {
+		if err := r.Series(p.At(), &lset, &chks); err != nil {
+			return 0, httpError(http.StatusBadRequest, "invalid index: %s", err)
+		}
+
+		if err := validateSeriesLabels(lset); err != nil {
+			return 0, httpError(http.StatusBadRequest, "invalid series %s: %s", lset.String(), err)
+		}
+		numSeries++
+	}
+	if err := p.Err(); err != nil {
+		return 0, httpError(http.StatusBadRequest, "invalid index: %s", err)
+	}
+
+	return numSeries, nil
+}
+
+func validateSeriesLabels(lset labels.Labels) error {
+	if len(lset) == 0 {
+		return errors.New("series has no labels")
+	}
+
+	for i, l := range lset {
+		if !model.LabelName(l.Name).IsValid() {
+			return fmt.Errorf("invalid label name %q", l.Name)
+		}
+		if !utf8.ValidString(l.Value) {
+			return fmt.Errorf("invalid value of label %s", l.Name)
+		}
+		if i > 0 && lset[i-1].Name == l.Name {
+			return fmt.Errorf("duplicate label name %s", l.Name)
+		}
+	}
+
+	if name := lset.Get(model.MetricNameLabel); name != "" && !model.IsValidMetricName(model.LabelValue(name)) {
+		return fmt.Errorf("invalid metric name %q", name)
+	}
+
+	return nil
+}
+
+func (c *Compactor) updateBucketIndex(ctx context.Context, userID string, logger log.Logger) error {
+	idx, err := bucketindex.ReadIndex(ctx, c.bucketClient, userID, c.cfgProvider, logger)
+	if err != nil && !errors.Is(err, bucketindex.ErrIndexNotFound) {
+		level.Warn(logger).Log("msg", "failed to read bucket index, rebuilding it", "err", err)
+	}
+	if err != nil {
+		idx = nil
+	}
+
+	w := bucketindex.NewUpdater(c.bucketClient, userID, c.cfgProvider, logger)
+	idx, _, err = w.UpdateIndex(ctx, idx)
+	if err != nil {
+		return err
+	}
+
+	return bucketindex.WriteIndex(ctx, c.bucketClient, userID, c.cfgProvider, idx)
+}
+
+func checkBlockDoesNotExist(ctx context.Context, userBkt objstore.Bucket, blockID ulid.ULID) error {
+	exists, err := userBkt.Exists(ctx, path.Join(blockID.String(), block.MetaFilename))
+	if err != nil {
+		return errors.Wrap(err, "check meta.json")
+	}
+	if exists {
+		return blockUploadError{status: http.StatusConflict, err: errBlockAlreadyExists}
+	}
+	return nil
+}
+
+func readUploadingMeta(ctx context.Context, userBkt objstore.Bucket, blockID ulid.ULID) (*metadata.Meta, error) {
+	r, err := userBkt.Get(ctx, path.Join(blockID.String(), uploadingMetaFilename))
+	if userBkt.IsObjNotFoundErr(err) {
+		return nil, blockUploadError{status: http.StatusNotFound, err: errBlockUploadNotFound}
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "read uploading meta.json")
+	}
+
+	meta, err := metadata.Read(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "read uploading meta.json")
+	}
+	return meta, nil
+}
+
+func uploadMeta(ctx context.Context, userBkt objstore.Bucket, blockID ulid.ULID, name string, meta *metadata.Meta) error {
+	var buf bytes.Buffer
+	if err := meta.Write(&buf); err != nil {
+		return errors.Wrap(err, "encode meta.json")
+	}
+
+	if err := userBkt.Upload(ctx, path.Join(blockID.String(), name), &buf); err != nil {
+		return errors.Wrapf(err, "upload %s", name)
+	}
+	return nil
+}
@@ -0,0 +1,354 @@
+package compactor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/oklog/ulid"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/tsdbutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thanos-io/thanos/pkg/block"
+	"github.com/thanos-io/thanos/pkg/block/metadata"
+	"github.com/thanos-io/thanos/pkg/objstore"
+	"github.com/weaveworks/common/user"
+
+	"github.com/cortexproject/cortex/pkg/storage/bucket"
+	"github.com/cortexproject/cortex/pkg/storage/tsdb/bucketindex"
+	"github.com/cortexproject/cortex/pkg/util"
+	util_log "github.com/cortexproject/cortex/pkg/util/log"
+	"github.com/cortexproject/cortex/pkg/util/services"
+)
+
+type testSample struct {
+	t int64
+	v float64
+}
+
+func (s testSample) T() int64   { return s.t }
+func (s testSample) V() float64 { return s.v }
+
+func TestCompactor_BlockUpload(t *testing.T) {
+	const userID = "user-1"
+
+	now := time.Now()
+	minT := util.TimeToMillis(now.Add(-2 * time.Hour))
+	maxT := util.TimeToMillis(now.Add(-time.Hour))
+
+	blockDir := createLocalBlock(t, minT, maxT, []labels.Labels{
+		labels.FromStrings(labels.MetricName, "series_1"),
+		labels.FromStrings(labels.MetricName, "series_2", "job", "test"),
+	})
+	blockID := ulid.MustParse(filepath.Base(blockDir))
+
+	meta, err := metadata.ReadFromDir(blockDir)
+	require.NoError(t, err)
+	metaJSON := encodeMeta(t, meta)
+
+	c, bkt := prepareBlockUpload(t, userID)
+
+	// Start the upload.
+	res := doBlockUploadRequest(c.StartBlockUpload, http.MethodPost, userID, blockID.String(), "", metaJSON)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+
+	// Starting the upload again is allowed, until the block is finished.
+	res = doBlockUploadRequest(c.StartBlockUpload, http.MethodPost, userID, blockID.String(), "", metaJSON)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+
+	// The block isn't visible until the upload is finished.
+	exists, err := bkt.Exists(context.Background(), path.Join(userID, blockID.String(), block.MetaFilename))
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	// Finishing the upload fails if the index has not been uploaded.
+	res = doBlockUploadRequest(c.FinishBlockUpload, http.MethodPost, userID, blockID.String(), "", nil)
+	require.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Contains(t, res.Body.String(), "block index has not been uploaded")
+
+	// Uploading files not belonging to a block is not allowed.
+	for _, p := range []string{"meta.json", "../index", "chunks/../../other/index", "tombstones", ""} {
+		res = doBlockUploadRequest(c.UploadBlockFile, http.MethodPost, userID, blockID.String(), p, []byte("data"))
+		require.Equal(t, http.StatusBadRequest, res.Code, p)
+	}
+
+	// Upload block files.
+	files := []string{block.IndexFilename, path.Join(block.ChunksDirname, "000001")}
+	for _, f := range files {
+		data, err := ioutil.ReadFile(filepath.Join(blockDir, filepath.FromSlash(f)))
+		require.NoError(t, err)
+
+		res = doBlockUploadRequest(c.UploadBlockFile, http.MethodPost, userID, blockID.String(), f, data)
+		require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	}
+
+	// Status reports all uploaded files.
+	res = doBlockUploadRequest(c.BlockUploadStatus, http.MethodGet, userID, blockID.String(), "", nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+
+	status := BlockUploadStatus{}
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &status))
+	assert.Equal(t, blockUploadStateUploading, status.State)
+	require.Len(t, status.Files, 2)
+	assert.ElementsMatch(t, files, []string{status.Files[0].Path, status.Files[1].Path})
+
+	// Finish the upload.
+	res = doBlockUploadRequest(c.FinishBlockUpload, http.MethodPost, userID, blockID.String(), "", nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+
+	// The block is now visible, with the tenant external label, and added to the bucket index.
+	uploadedMeta, err := block.DownloadMeta(context.Background(), util_log.Logger, bucket.NewUserBucketClient(userID, bkt, nil), blockID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"__org_id__": userID}, uploadedMeta.Thanos.Labels)
+
+	exists, err = bkt.Exists(context.Background(), path.Join(userID, blockID.String(), uploadingMetaFilename))
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	idx, err := bucketindex.ReadIndex(context.Background(), bkt, userID, nil, util_log.Logger)
+	require.NoError(t, err)
+	assert.Equal(t, []ulid.ULID{blockID}, idx.Blocks.GetULIDs())
+
+	res = doBlockUploadRequest(c.BlockUploadStatus, http.MethodGet, userID, blockID.String(), "", nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &status))
+	assert.Equal(t, blockUploadStateComplete, status.State)
+
+	// The block can't be uploaded again.
+	res = doBlockUploadRequest(c.StartBlockUpload, http.MethodPost, userID, blockID.String(), "", metaJSON)
+	require.Equal(t, http.StatusConflict, res.Code)
+
+	// The files of the finished block can't be overwritten, even if the uploading meta.json
+	// has been left in place (eg. because it failed to be deleted).
+	require.NoError(t, bkt.Upload(context.Background(), path.Join(userID, blockID.String(), uploadingMetaFilename), bytes.NewReader(metaJSON)))
+	res = doBlockUploadRequest(c.UploadBlockFile, http.MethodPost, userID, blockID.String(), block.IndexFilename, []byte("data"))
+	require.Equal(t, http.StatusConflict, res.Code)
+}
+
+func TestCompactor_BlockUpload_ShouldRejectInvalidMeta(t *testing.T) {
+	const userID = "user-1"
+
+	now := time.Now()
+	blockID := ulid.MustNew(ulid.Now(), nil)
+
+	validMeta := func() *metadata.Meta {
+		return &metadata.Meta{
+			BlockMeta: tsdb.BlockMeta{
+				ULID:    blockID,
+				Version: metadata.TSDBVersion1,
+				MinTime: util.TimeToMillis(now.Add(-2 * time.Hour)),
+				MaxTime: util.TimeToMillis(now.Add(-time.Hour)),
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		blockID         string
+		meta            func(meta *metadata.Meta)
+		uploadDisabled  bool
+		retention       time.Duration
+		expectedStatus  int
+		expectedMessage string
+	}{
+		"valid meta": {
+			expectedStatus: http.StatusOK,
+		},
+		"upload disabled for the tenant": {
+			uploadDisabled:  true,
+			expectedStatus:  http.StatusForbidden,
+			expectedMessage: "block upload is disabled",
+		},
+		"invalid block ID": {
+			blockID:         "invalid",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "invalid block ID",
+		},
+		"block ID mismatch": {
+			meta:            func(meta *metadata.Meta) { meta.ULID = ulid.MustNew(1, nil) },
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "doesn't match the block ID",
+		},
+		"invalid time range": {
+			meta:            func(meta *metadata.Meta) { meta.MinTime, meta.MaxTime = meta.MaxTime, meta.MinTime },
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "invalid block time range",
+		},
+		"max time in the future": {
+			meta:            func(meta *metadata.Meta) { meta.MaxTime = util.TimeToMillis(now.Add(time.Hour)) },
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "is in the future",
+		},
+		"time range larger than the largest block range": {
+			meta:            func(meta *metadata.Meta) { meta.MinTime = util.TimeToMillis(now.Add(-48 * time.Hour)) },
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "is larger than the largest compaction block range",
+		},
+		"older than retention": {
+			retention:       30 * time.Minute,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "is older than the retention period",
+		},
+		"downsampled block": {
+			meta:            func(meta *metadata.Meta) { meta.Thanos.Downsample.Resolution = 300000 },
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "downsampled blocks are not supported",
+		},
+		"tenant external label mismatch": {
+			meta:            func(meta *metadata.Meta) { meta.Thanos.Labels = map[string]string{"__org_id__": "another"} },
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "doesn't match the tenant",
+		},
+		"invalid version": {
+			meta:            func(meta *metadata.Meta) { meta.Version = 2 },
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "invalid meta.json",
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			c, _ := prepareBlockUpload(t, userID)
+
+			cfgProvider := c.cfgProvider.(*mockConfigProvider)
+			cfgProvider.blockUploadEnabled[userID] = !testData.uploadDisabled
+			cfgProvider.userRetentionPeriods[userID] = testData.retention
+
+			meta := validMeta()
+			if testData.meta != nil {
+				testData.meta(meta)
+			}
+
+			id := blockID.String()
+			if testData.blockID != "" {
+				id = testData.blockID
+			}
+
+			res := doBlockUploadRequest(c.StartBlockUpload, http.MethodPost, userID, id, "", encodeMeta(t, meta))
+			assert.Equal(t, testData.expectedStatus, res.Code, res.Body.String())
+			assert.Contains(t, res.Body.String(), testData.expectedMessage)
+		})
+	}
+}
+
+func TestCompactor_BlockUpload_ShouldRejectInvalidIndex(t *testing.T) {
+	const userID = "user-1"
+
+	now := time.Now()
+	minT := util.TimeToMillis(now.Add(-2 * time.Hour))
+	maxT := util.TimeToMillis(now.Add(-time.Hour))
+
+	blockDir := createLocalBlock(t, minT, maxT, []labels.Labels{
+		labels.FromStrings(labels.MetricName, "series_1"),
+		labels.FromStrings(labels.MetricName, "series_2", "invalid-name", "test"),
+	})
+	blockID := ulid.MustParse(filepath.Base(blockDir))
+
+	meta, err := metadata.ReadFromDir(blockDir)
+	require.NoError(t, err)
+
+	c, bkt := prepareBlockUpload(t, userID)
+
+	res := doBlockUploadRequest(c.StartBlockUpload, http.MethodPost, userID, blockID.String(), "", encodeMeta(t, meta))
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+
+	for _, f := range []string{block.IndexFilename, path.Join(block.ChunksDirname, "000001")} {
+		data, err := ioutil.ReadFile(filepath.Join(blockDir, filepath.FromSlash(f)))
+		require.NoError(t, err)
+
+		res = doBlockUploadRequest(c.UploadBlockFile, http.MethodPost, userID, blockID.String(), f, data)
+		require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+	}
+
+	res = doBlockUploadRequest(c.FinishBlockUpload, http.MethodPost, userID, blockID.String(), "", nil)
+	require.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Contains(t, res.Body.String(), `invalid label name "invalid-name"`)
+
+	// The upload can be aborted.
+	res = doBlockUploadRequest(c.AbortBlockUpload, http.MethodDelete, userID, blockID.String(), "", nil)
+	require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+
+	var remaining []string
+	require.NoError(t, bkt.Iter(context.Background(), path.Join(userID, blockID.String()), func(name string) error {
+		remaining = append(remaining, name)
+		return nil
+	}, objstore.WithRecursiveIter))
+	assert.Empty(t, remaining)
+
+	res = doBlockUploadRequest(c.BlockUploadStatus, http.MethodGet, userID, blockID.String(), "", nil)
+	require.Equal(t, http.StatusNotFound, res.Code)
+}
+
+func prepareBlockUpload(t *testing.T, userID string) (*Compactor, objstore.Bucket) {
+	bkt := objstore.NewInMemBucket()
+
+	c, _, _, _, _ := prepare(t, prepareConfig(), bkt)
+
+	cfgProvider := newMockConfigProvider()
+	cfgProvider.blockUploadEnabled[userID] = true
+	c.cfgProvider = cfgProvider
+
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), c))
+	t.Cleanup(func() {
+		require.NoError(t, services.StopAndAwaitTerminated(context.Background(), c))
+	})
+
+	return c, bkt
+}
+
+func doBlockUploadRequest(handler http.HandlerFunc, method, userID, blockID, filePath string, body []byte) *httptest.ResponseRecorder {
+	url := "/api/v1/upload/block/" + blockID
+	if filePath != "" {
+		url += "?path=" + filePath
+	}
+
+	req := httptest.NewRequest(method, url, bytes.NewReader(body))
+	req = req.WithContext(user.InjectOrgID(req.Context(), userID))
+	req = mux.SetURLVars(req, map[string]string{blockUploadRouteVar: blockID})
+
+	res := httptest.NewRecorder()
+	handler(res, req)
+	return res
+}
+
+func encodeMeta(t *testing.T, meta *metadata.Meta) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, meta.Write(&buf))
+	return buf.Bytes()
+}
+
+// createLocalBlock creates a block with the input series, each having a sample at minT and maxT-1.
+func createLocalBlock(t *testing.T, minT, maxT int64, series []labels.Labels) string {
+	dir, err := ioutil.TempDir(os.TempDir(), "block-upload")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	var ss []storage.Series
+	for i, lbls := range series {
+		// Labels are passed as is, to allow creating series with invalid labels.
+		ss = append(ss, storage.NewListSeries(lbls, []tsdbutil.Sample{
+			testSample{t: minT, v: float64(i)},
+			testSample{t: maxT - 1, v: float64(i)},
+		}))
+	}
+
+	blockDir, err := tsdb.CreateBlock(ss, dir, 0, util_log.Logger)
+	require.NoError(t, err)
+
+	require.True(t, strings.HasPrefix(blockDir, dir))
+	return blockDir
+}
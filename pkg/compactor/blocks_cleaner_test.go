@@ -655,14 +655,20 @@ func (m *mockBucketFailure) Delete(ctx context.Context, name string) error {
 
 type mockConfigProvider struct {
 	userRetentionPeriods map[string]time.Duration
+	blockUploadEnabled   map[string]bool
 }
 
 func newMockConfigProvider() *mockConfigProvider {
 	return &mockConfigProvider{
 		userRetentionPeriods: make(map[string]time.Duration),
+		blockUploadEnabled:   make(map[string]bool),
 	}
 }
 
+func (m *mockConfigProvider) CompactorBlockUploadEnabled(user string) bool {
+	return m.blockUploadEnabled[user]
+}
+
 func (m *mockConfigProvider) CompactorBlocksRetentionPeriod(user string) time.Duration {
 	if result, ok := m.userRetentionPeriods[user]; ok {
 		return result
@@ -159,6 +159,12 @@ func (cfg *Config) Validate() error {
 		}
 	}
 
+	if cfg.ShardingEnabled {
+		if err := cfg.ShardingRing.TokenGenerator.Validate(); err != nil {
+			return errors.Wrap(err, "invalid sharding ring config")
+		}
+	}
+
 	return nil
 }
 
@@ -166,6 +172,7 @@ func (cfg *Config) Validate() error {
 type ConfigProvider interface {
 	bucket.TenantConfigProvider
 	CompactorBlocksRetentionPeriod(user string) time.Duration
+	CompactorBlockUploadEnabled(user string) bool
 }
 
 // Compactor is a multi-tenant TSDB blocks compactor based on Thanos.
@@ -217,6 +224,8 @@ type Compactor struct {
 	compactionRunInterval          prometheus.Gauge
 	blocksMarkedForDeletion        prometheus.Counter
 	garbageCollectedBlocks         prometheus.Counter
+	blockUploadsCompleted          prometheus.Counter
+	blockUploadValidationFailures  prometheus.Counter
 
 	// TSDB syncer metrics
 	syncerMetrics *syncerMetrics
@@ -314,6 +323,14 @@ func newCompactor(
 			Name: "cortex_compactor_garbage_collected_blocks_total",
 			Help: "Total number of blocks marked for deletion by compactor.",
 		}),
+		blockUploadsCompleted: promauto.With(registerer).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_compactor_block_uploads_completed_total",
+			Help: "Total number of blocks successfully uploaded through the block upload API.",
+		}),
+		blockUploadValidationFailures: promauto.With(registerer).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_compactor_block_upload_validation_failures_total",
+			Help: "Total number of blocks uploaded through the block upload API which failed validation.",
+		}),
 	}
 
 	if len(compactorCfg.EnabledTenants) > 0 {
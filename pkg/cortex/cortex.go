@@ -218,6 +218,9 @@ func (c *Config) Validate(log log.Logger) error {
 	if err := c.Querier.Validate(); err != nil {
 		return errors.Wrap(err, "invalid querier config")
 	}
+	if err := c.Ingester.Validate(c.Distributor.ShardByAllLabels || c.LimitsConfig.ShardByAllLabels); err != nil {
+		return errors.Wrap(err, "invalid ingester config")
+	}
 	if err := c.IngesterClient.Validate(log); err != nil {
 		return errors.Wrap(err, "invalid ingester_client config")
 	}
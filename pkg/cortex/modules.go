@@ -79,6 +79,7 @@ const (
 	StoreGateway             string = "store-gateway"
 	MemberlistKV             string = "memberlist-kv"
 	ChunksPurger             string = "chunks-purger"
+	ChunksRetention          string = "chunks-retention"
 	TenantDeletion           string = "tenant-deletion"
 	Purger                   string = "purger"
 	QueryScheduler           string = "query-scheduler"
@@ -662,8 +663,17 @@ func (t *Cortex) initRuler() (serv services.Service, err error) {
 	// TODO: Consider wrapping logger to differentiate from querier module logger
 	queryable, _, engine := querier.New(t.Cfg.Querier, t.Overrides, t.Distributor, t.StoreQueryables, t.TombstonesLoader, rulerRegisterer, util_log.Logger)
 
-	managerFactory := ruler.DefaultTenantManagerFactory(t.Cfg.Ruler, t.Distributor, queryable, engine, t.Overrides, prometheus.DefaultRegisterer)
-	manager, err := ruler.NewDefaultMultiTenantManager(t.Cfg.Ruler, managerFactory, prometheus.DefaultRegisterer, util_log.Logger)
+	var frontendClient *ruler.FrontendClient
+	if t.Cfg.Ruler.FrontendAddress != "" {
+		frontendClient, err = ruler.DialQueryFrontend(t.Cfg.Ruler, t.Cfg.API.PrometheusHTTPPrefix, prometheus.DefaultRegisterer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	managerFactory := ruler.DefaultTenantManagerFactory(t.Cfg.Ruler, t.Distributor, queryable, engine, frontendClient, t.Overrides, prometheus.DefaultRegisterer)
+	queryFuncFactory := ruler.DefaultQueryFuncFactory(engine, queryable, frontendClient, t.Overrides)
+	manager, err := ruler.NewDefaultMultiTenantManager(t.Cfg.Ruler, managerFactory, t.Overrides, prometheus.DefaultRegisterer, util_log.Logger)
 	if err != nil {
 		return nil, err
 	}
@@ -685,7 +695,7 @@ func (t *Cortex) initRuler() (serv services.Service, err error) {
 
 	// If the API is enabled, register the Ruler API
 	if t.Cfg.Ruler.EnableAPI {
-		t.API.RegisterRulerAPI(ruler.NewAPI(t.Ruler, t.RulerStorage, util_log.Logger))
+		t.API.RegisterRulerAPI(ruler.NewAPI(t.Ruler, t.RulerStorage, queryFuncFactory, util_log.Logger))
 	}
 
 	return t.Ruler, nil
@@ -766,6 +776,7 @@ func (t *Cortex) initMemberlistKV() (services.Service, error) {
 	t.Cfg.MemberlistKV.MetricsRegisterer = prometheus.DefaultRegisterer
 	t.Cfg.MemberlistKV.Codecs = []codec.Codec{
 		ring.GetCodec(),
+		ring.GetMaintenanceLeaseCodec(),
 	}
 	t.MemberlistKV = memberlist.NewKVInitService(&t.Cfg.MemberlistKV, util_log.Logger)
 	t.API.RegisterMemberlistKV(t.MemberlistKV)
@@ -801,6 +812,41 @@ func (t *Cortex) initChunksPurger() (services.Service, error) {
 	return t.Purger, nil
 }
 
+func (t *Cortex) initChunksRetention() (services.Service, error) {
+	if t.Cfg.Storage.Engine != storage.StorageEngineChunks || !t.Cfg.PurgerConfig.Enable {
+		return nil, nil
+	}
+
+	// The retention enforcer lists the chunks in the object stores used by the schema periods.
+	// Stores which are not object stores, or which encode the chunk keys, can't be listed by user.
+	var chunkClients []chunk.ObjectClient
+	seen := map[string]struct{}{}
+	for _, period := range t.Cfg.Schema.Configs {
+		objectStoreType := period.ObjectType
+		if objectStoreType == "" {
+			objectStoreType = period.IndexType
+		}
+		if _, ok := seen[objectStoreType]; ok {
+			continue
+		}
+		seen[objectStoreType] = struct{}{}
+
+		if objectStoreType == storage.StorageTypeFileSystem {
+			level.Warn(util_log.Logger).Log("msg", "chunks retention is not supported for the object store", "object_store", objectStoreType)
+			continue
+		}
+
+		client, err := storage.NewObjectClient(objectStoreType, t.Cfg.Storage)
+		if err != nil {
+			level.Warn(util_log.Logger).Log("msg", "chunks retention is not supported for the object store", "object_store", objectStoreType, "err", err)
+			continue
+		}
+		chunkClients = append(chunkClients, client)
+	}
+
+	return purger.NewRetentionEnforcer(t.Cfg.PurgerConfig, t.DeletesStore, t.Store, chunkClients, t.Overrides, util_log.Logger, prometheus.DefaultRegisterer), nil
+}
+
 func (t *Cortex) initTenantDeletionAPI() (services.Service, error) {
 	if t.Cfg.Storage.Engine != storage.StorageEngineBlocks {
 		return nil, nil
@@ -858,6 +904,7 @@ func (t *Cortex) setupModuleManager() error {
 	mm.RegisterModule(Compactor, t.initCompactor)
 	mm.RegisterModule(StoreGateway, t.initStoreGateway)
 	mm.RegisterModule(ChunksPurger, t.initChunksPurger, modules.UserInvisibleModule)
+	mm.RegisterModule(ChunksRetention, t.initChunksRetention, modules.UserInvisibleModule)
 	mm.RegisterModule(TenantDeletion, t.initTenantDeletionAPI, modules.UserInvisibleModule)
 	mm.RegisterModule(Purger, nil)
 	mm.RegisterModule(QueryScheduler, t.initQueryScheduler)
@@ -892,8 +939,9 @@ func (t *Cortex) setupModuleManager() error {
 		Compactor:                {API, MemberlistKV, Overrides},
 		StoreGateway:             {API, Overrides, MemberlistKV},
 		ChunksPurger:             {Store, DeleteRequestsStore, API},
+		ChunksRetention:          {Store, DeleteRequestsStore, Overrides},
 		TenantDeletion:           {Store, API, Overrides},
-		Purger:                   {ChunksPurger, TenantDeletion},
+		Purger:                   {ChunksPurger, ChunksRetention, TenantDeletion},
 		TenantFederation:         {Queryable},
 		All:                      {QueryFrontend, Querier, Ingester, Distributor, TableManager, Purger, StoreGateway, Ruler},
 	}
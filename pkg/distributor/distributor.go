@@ -414,8 +414,14 @@ func (d *Distributor) stopping(_ error) error {
 	return services.StopManagerAndAwaitStopped(context.Background(), d.subservices)
 }
 
+// shardByAllLabelsEnabled returns whether the tenant's series are sharded by all labels,
+// either because enabled for all tenants or through the tenant's overrides.
+func (d *Distributor) shardByAllLabelsEnabled(userID string) bool {
+	return d.cfg.ShardByAllLabels || d.limits.ShardByAllLabels(userID)
+}
+
 func (d *Distributor) tokenForLabels(userID string, labels []cortexpb.LabelAdapter) (uint32, error) {
-	if d.cfg.ShardByAllLabels {
+	if d.shardByAllLabelsEnabled(userID) {
 		return shardByAllLabels(userID, labels), nil
 	}
 
@@ -427,7 +433,7 @@ func (d *Distributor) tokenForLabels(userID string, labels []cortexpb.LabelAdapt
 }
 
 func (d *Distributor) tokenForMetadata(userID string, metricName string) uint32 {
-	if d.cfg.ShardByAllLabels {
+	if d.shardByAllLabelsEnabled(userID) {
 		return shardByMetricName(userID, metricName)
 	}
 
@@ -1079,6 +1085,109 @@ func (d *Distributor) AllUserStats(ctx context.Context) ([]UserIDStats, error) {
 	return response, nil
 }
 
+// SeriesStats models how the series of a tenant are distributed across ingesters.
+type SeriesStats struct {
+	// Approximate number of series of the tenant, before replication: the sum of the series
+	// in all the ingesters divided by the replication factor, which is inaccurate when the
+	// writes are replicated to additional ingesters (eg. READONLY or LEAVING ones).
+	NumSeries uint64 `json:"numSeries"`
+
+	// Ratio between the number of series in the ingester with the most series and the
+	// average number of series per ingester. 1 means the series are perfectly balanced.
+	ImbalanceScore float64 `json:"imbalanceScore"`
+
+	// Sorted by number of series in descending order.
+	Ingesters []IngesterSeriesStats `json:"ingesters"`
+}
+
+// IngesterSeriesStats models the series of a tenant in one ingester.
+type IngesterSeriesStats struct {
+	Addr               string             `json:"addr"`
+	Zone               string             `json:"zone"`
+	NumSeries          uint64             `json:"numSeries"`
+	TopMetricNames     []SeriesStatsEntry `json:"topMetricNames"`
+	TopLabelValuePairs []SeriesStatsEntry `json:"topLabelValuePairs"`
+}
+
+// SeriesStatsEntry models the number of series with a given metric name or label name/value pair.
+type SeriesStatsEntry struct {
+	Name      string `json:"name"`
+	NumSeries uint64 `json:"numSeries"`
+}
+
+// SeriesStats returns the number of series of the tenant in each ingester, along with the
+// metric names and label name/value pairs with the highest number of series, which can be
+// used to detect ingesters hot-spots.
+func (d *Distributor) SeriesStats(ctx context.Context) (*SeriesStats, error) {
+	replicationSet, err := d.GetIngestersForMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure we get a successful response from all of them.
+	replicationSet.MaxErrors = 0
+
+	req := &ingester_client.SeriesStatsRequest{}
+	resps, err := replicationSet.Do(ctx, d.cfg.ExtraQueryDelay, func(ctx context.Context, ing *ring.InstanceDesc) (interface{}, error) {
+		client, err := d.ingesterPool.GetClientFor(ing.Addr)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.(ingester_client.IngesterClient).SeriesStats(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		return IngesterSeriesStats{
+			Addr:               ing.Addr,
+			Zone:               ing.Zone,
+			NumSeries:          resp.NumSeries,
+			TopMetricNames:     toSeriesStatsEntries(resp.TopMetricNames),
+			TopLabelValuePairs: toSeriesStatsEntries(resp.TopLabelValuePairs),
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &SeriesStats{Ingesters: make([]IngesterSeriesStats, 0, len(resps))}
+	maxSeries := uint64(0)
+	for _, resp := range resps {
+		ing := resp.(IngesterSeriesStats)
+		stats.Ingesters = append(stats.Ingesters, ing)
+		stats.NumSeries += ing.NumSeries
+		if ing.NumSeries > maxSeries {
+			maxSeries = ing.NumSeries
+		}
+	}
+
+	sort.Slice(stats.Ingesters, func(i, j int) bool {
+		if stats.Ingesters[i].NumSeries != stats.Ingesters[j].NumSeries {
+			return stats.Ingesters[i].NumSeries > stats.Ingesters[j].NumSeries
+		}
+		return stats.Ingesters[i].Addr < stats.Ingesters[j].Addr
+	})
+
+	if stats.NumSeries > 0 {
+		avgSeries := float64(stats.NumSeries) / float64(len(stats.Ingesters))
+		stats.ImbalanceScore = float64(maxSeries) / avgSeries
+	}
+	// The ingesters don't know which series they own as primary, so the number of
+	// series is approximated assuming each series is stored in ReplicationFactor ingesters.
+	stats.NumSeries /= uint64(d.ingestersRing.ReplicationFactor())
+
+	return stats, nil
+}
+
+func toSeriesStatsEntries(entries []*ingester_client.SeriesStatsEntry) []SeriesStatsEntry {
+	result := make([]SeriesStatsEntry, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, SeriesStatsEntry{Name: e.Name, NumSeries: e.NumSeries})
+	}
+	return result
+}
+
 func (d *Distributor) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	if d.distributorsRing != nil {
 		d.distributorsRing.ServeHTTP(w, req)
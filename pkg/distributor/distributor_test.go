@@ -1867,6 +1867,153 @@ func TestDistributor_MetricsMetadata(t *testing.T) {
 	}
 }
 
+func TestDistributor_SeriesStats(t *testing.T) {
+	const (
+		numIngesters = 5
+		numSeries    = 100
+	)
+
+	tests := map[string]struct {
+		shardByAllLabelsOverride bool
+		expectedIngesters        int
+	}{
+		"should report the series of a tenant sharded by metric name": {
+			shardByAllLabelsOverride: false,
+			expectedIngesters:        3,
+		},
+		"should report the series of a tenant sharded by all labels through the overrides": {
+			shardByAllLabelsOverride: true,
+			expectedIngesters:        numIngesters,
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			limits := &validation.Limits{}
+			flagext.DefaultValues(limits)
+			limits.ShardByAllLabels = testData.shardByAllLabelsOverride
+
+			ds, ingesters, r, _ := prepare(t, prepConfig{
+				numIngesters:     numIngesters,
+				happyIngesters:   numIngesters,
+				numDistributors:  1,
+				shardByAllLabels: false,
+				limits:           limits,
+			})
+			defer stopAll(ds, r)
+
+			ctx := user.InjectOrgID(context.Background(), "test")
+			_, err := ds[0].Push(ctx, makeWriteRequest(0, numSeries, 0))
+			require.NoError(t, err)
+
+			// The distributor returns once the quorum is reached, so we wait until all replicas have been written.
+			test.Poll(t, time.Second, 3*numSeries, func() interface{} {
+				total := 0
+				for i := range ingesters {
+					total += len(ingesters[i].series())
+				}
+				return total
+			})
+
+			stats, err := ds[0].SeriesStats(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(numSeries), stats.NumSeries)
+			require.Len(t, stats.Ingesters, numIngesters)
+
+			maxSeries := stats.Ingesters[0].NumSeries
+			for idx, ing := range stats.Ingesters {
+				ingesterIdx, err := strconv.Atoi(ing.Addr)
+				require.NoError(t, err)
+				assert.Equal(t, uint64(len(ingesters[ingesterIdx].series())), ing.NumSeries)
+				assert.LessOrEqual(t, ing.NumSeries, maxSeries, "ingesters should be sorted by number of series")
+				maxSeries = ing.NumSeries
+
+				if idx < testData.expectedIngesters {
+					assert.Greater(t, ing.NumSeries, uint64(0))
+					assert.Equal(t, []SeriesStatsEntry{{Name: "foo", NumSeries: ing.NumSeries}}, ing.TopMetricNames)
+				} else {
+					assert.Equal(t, uint64(0), ing.NumSeries)
+				}
+			}
+
+			// The score is the ratio between the ingester with most series and the average.
+			expectedScore := float64(stats.Ingesters[0].NumSeries) / (float64(3*numSeries) / numIngesters)
+			assert.InDelta(t, expectedScore, stats.ImbalanceScore, 0.0001)
+			if !testData.shardByAllLabelsOverride {
+				assert.InDelta(t, float64(numIngesters)/3, stats.ImbalanceScore, 0.0001)
+			}
+
+			// Queries by metric name must be sent to all the ingesters holding the tenant's series.
+			replicationSet, err := ds[0].GetIngestersForQuery(ctx, mustNewMatcher(labels.MatchEqual, model.MetricNameLabel, "foo"))
+			require.NoError(t, err)
+			assert.Len(t, replicationSet.Instances, testData.expectedIngesters)
+		})
+	}
+}
+
+func TestDistributor_ShardByAllLabelsOverrideDisabled(t *testing.T) {
+	const (
+		numIngesters = 5
+		numSeries    = 100
+	)
+
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.ShardByAllLabels = true
+
+	ds, ingesters, r, _ := prepare(t, prepConfig{
+		numIngesters:     numIngesters,
+		happyIngesters:   numIngesters,
+		numDistributors:  1,
+		shardByAllLabels: false,
+		limits:           limits,
+	})
+	defer stopAll(ds, r)
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	_, err := ds[0].Push(ctx, makeWriteRequest(0, numSeries, 0))
+	require.NoError(t, err)
+
+	// The distributor returns once the quorum is reached, so we wait until all replicas have been written.
+	test.Poll(t, time.Second, 3*numSeries, func() interface{} {
+		total := 0
+		for i := range ingesters {
+			total += len(ingesters[i].series())
+		}
+		return total
+	})
+
+	// Disable the override.
+	limits.ShardByAllLabels = false
+	ds[0].limits, err = validation.NewOverrides(*limits, nil)
+	require.NoError(t, err)
+
+	// Queries by metric name are now only sent to the ingesters owning the metric name,
+	// so the series written while the override was enabled to the other ingesters are
+	// missing from the query results. This is why disabling the override isn't supported.
+	replicationSet, err := ds[0].GetIngestersForQuery(ctx, mustNewMatcher(labels.MatchEqual, model.MetricNameLabel, "foo"))
+	require.NoError(t, err)
+	require.Len(t, replicationSet.Instances, 3)
+
+	queried := map[string]bool{}
+	for _, ing := range replicationSet.Instances {
+		queried[ing.Addr] = true
+	}
+
+	missing := 0
+	for i := range ingesters {
+		if !queried[strconv.Itoa(i)] {
+			missing += len(ingesters[i].series())
+		}
+	}
+	assert.Greater(t, missing, 0)
+
+	// Queries without a metric name equality matcher are still sent to all ingesters.
+	replicationSet, err = ds[0].GetIngestersForQuery(ctx, mustNewMatcher(labels.MatchRegexp, model.MetricNameLabel, "fo.*"))
+	require.NoError(t, err)
+	assert.Len(t, replicationSet.Instances, numIngesters)
+}
+
 func mustNewMatcher(t labels.MatchType, n, v string) *labels.Matcher {
 	m, err := labels.NewMatcher(t, n, v)
 	if err != nil {
@@ -2366,6 +2513,32 @@ func (i *mockIngester) MetricsMetadata(ctx context.Context, req *client.MetricsM
 	return resp, nil
 }
 
+func (i *mockIngester) SeriesStats(ctx context.Context, req *client.SeriesStatsRequest, opts ...grpc.CallOption) (*client.SeriesStatsResponse, error) {
+	i.Lock()
+	defer i.Unlock()
+
+	i.trackCall("SeriesStats")
+
+	if !i.happy {
+		return nil, errFail
+	}
+
+	metricNames := map[string]uint64{}
+	for _, ts := range i.timeseries {
+		metricNames[cortexpb.FromLabelAdaptersToLabels(ts.Labels).Get(labels.MetricName)]++
+	}
+
+	resp := &client.SeriesStatsResponse{NumSeries: uint64(len(i.timeseries))}
+	for name, numSeries := range metricNames {
+		resp.TopMetricNames = append(resp.TopMetricNames, &client.SeriesStatsEntry{Name: name, NumSeries: numSeries})
+	}
+	sort.Slice(resp.TopMetricNames, func(x, y int) bool {
+		return resp.TopMetricNames[x].Name < resp.TopMetricNames[y].Name
+	})
+
+	return resp, nil
+}
+
 func (i *mockIngester) trackCall(name string) {
 	if i.calls == nil {
 		i.calls = map[string]int{}
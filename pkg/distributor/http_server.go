@@ -24,3 +24,14 @@ func (d *Distributor) UserStatsHandler(w http.ResponseWriter, r *http.Request) {
 
 	util.WriteJSONResponse(w, stats)
 }
+
+// SeriesStatsHandler reports how the series of the tenant are distributed across ingesters.
+func (d *Distributor) SeriesStatsHandler(w http.ResponseWriter, r *http.Request) {
+	stats, err := d.SeriesStats(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	util.WriteJSONResponse(w, stats)
+}
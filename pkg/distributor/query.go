@@ -123,7 +123,7 @@ func (d *Distributor) GetIngestersForQuery(ctx context.Context, matchers ...*lab
 	}
 
 	// If "shard by all labels" is disabled, we can get ingesters by metricName if exists.
-	if !d.cfg.ShardByAllLabels && len(matchers) > 0 {
+	if !d.shardByAllLabelsEnabled(userID) && len(matchers) > 0 {
 		metricNameMatcher, _, ok := extract.MetricNameMatcherFromMatchers(matchers)
 
 		if ok && metricNameMatcher.Type == labels.MatchEqual {
@@ -805,6 +805,151 @@ func (m *UsersStatsResponse) GetStats() []*UserIDStatsResponse {
 	return nil
 }
 
+type SeriesStatsRequest struct {
+}
+
+func (m *SeriesStatsRequest) Reset()      { *m = SeriesStatsRequest{} }
+func (*SeriesStatsRequest) ProtoMessage() {}
+func (*SeriesStatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60f6df4f3586b478, []int{15}
+}
+func (m *SeriesStatsRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SeriesStatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SeriesStatsRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SeriesStatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SeriesStatsRequest.Merge(m, src)
+}
+func (m *SeriesStatsRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *SeriesStatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SeriesStatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SeriesStatsRequest proto.InternalMessageInfo
+
+type SeriesStatsResponse struct {
+	NumSeries          uint64              `protobuf:"varint,1,opt,name=num_series,json=numSeries,proto3" json:"num_series,omitempty"`
+	TopMetricNames     []*SeriesStatsEntry `protobuf:"bytes,2,rep,name=top_metric_names,json=topMetricNames,proto3" json:"top_metric_names,omitempty"`
+	TopLabelValuePairs []*SeriesStatsEntry `protobuf:"bytes,3,rep,name=top_label_value_pairs,json=topLabelValuePairs,proto3" json:"top_label_value_pairs,omitempty"`
+}
+
+func (m *SeriesStatsResponse) Reset()      { *m = SeriesStatsResponse{} }
+func (*SeriesStatsResponse) ProtoMessage() {}
+func (*SeriesStatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60f6df4f3586b478, []int{16}
+}
+func (m *SeriesStatsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SeriesStatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SeriesStatsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SeriesStatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SeriesStatsResponse.Merge(m, src)
+}
+func (m *SeriesStatsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *SeriesStatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SeriesStatsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SeriesStatsResponse proto.InternalMessageInfo
+
+func (m *SeriesStatsResponse) GetNumSeries() uint64 {
+	if m != nil {
+		return m.NumSeries
+	}
+	return 0
+}
+
+func (m *SeriesStatsResponse) GetTopMetricNames() []*SeriesStatsEntry {
+	if m != nil {
+		return m.TopMetricNames
+	}
+	return nil
+}
+
+func (m *SeriesStatsResponse) GetTopLabelValuePairs() []*SeriesStatsEntry {
+	if m != nil {
+		return m.TopLabelValuePairs
+	}
+	return nil
+}
+
+type SeriesStatsEntry struct {
+	Name      string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	NumSeries uint64 `protobuf:"varint,2,opt,name=num_series,json=numSeries,proto3" json:"num_series,omitempty"`
+}
+
+func (m *SeriesStatsEntry) Reset()      { *m = SeriesStatsEntry{} }
+func (*SeriesStatsEntry) ProtoMessage() {}
+func (*SeriesStatsEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_60f6df4f3586b478, []int{17}
+}
+func (m *SeriesStatsEntry) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SeriesStatsEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SeriesStatsEntry.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SeriesStatsEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SeriesStatsEntry.Merge(m, src)
+}
+func (m *SeriesStatsEntry) XXX_Size() int {
+	return m.Size()
+}
+func (m *SeriesStatsEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_SeriesStatsEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SeriesStatsEntry proto.InternalMessageInfo
+
+func (m *SeriesStatsEntry) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SeriesStatsEntry) GetNumSeries() uint64 {
+	if m != nil {
+		return m.NumSeries
+	}
+	return 0
+}
+
 type MetricsForLabelMatchersRequest struct {
 	StartTimestampMs int64            `protobuf:"varint,1,opt,name=start_timestamp_ms,json=startTimestampMs,proto3" json:"start_timestamp_ms,omitempty"`
 	EndTimestampMs   int64            `protobuf:"varint,2,opt,name=end_timestamp_ms,json=endTimestampMs,proto3" json:"end_timestamp_ms,omitempty"`
@@ -814,7 +959,7 @@ type MetricsForLabelMatchersRequest struct {
 func (m *MetricsForLabelMatchersRequest) Reset()      { *m = MetricsForLabelMatchersRequest{} }
 func (*MetricsForLabelMatchersRequest) ProtoMessage() {}
 func (*MetricsForLabelMatchersRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{15}
+	return fileDescriptor_60f6df4f3586b478, []int{18}
 }
 func (m *MetricsForLabelMatchersRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -871,7 +1016,7 @@ type MetricsForLabelMatchersResponse struct {
 func (m *MetricsForLabelMatchersResponse) Reset()      { *m = MetricsForLabelMatchersResponse{} }
 func (*MetricsForLabelMatchersResponse) ProtoMessage() {}
 func (*MetricsForLabelMatchersResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{16}
+	return fileDescriptor_60f6df4f3586b478, []int{19}
 }
 func (m *MetricsForLabelMatchersResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -913,7 +1058,7 @@ type MetricsMetadataRequest struct {
 func (m *MetricsMetadataRequest) Reset()      { *m = MetricsMetadataRequest{} }
 func (*MetricsMetadataRequest) ProtoMessage() {}
 func (*MetricsMetadataRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{17}
+	return fileDescriptor_60f6df4f3586b478, []int{20}
 }
 func (m *MetricsMetadataRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -949,7 +1094,7 @@ type MetricsMetadataResponse struct {
 func (m *MetricsMetadataResponse) Reset()      { *m = MetricsMetadataResponse{} }
 func (*MetricsMetadataResponse) ProtoMessage() {}
 func (*MetricsMetadataResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{18}
+	return fileDescriptor_60f6df4f3586b478, []int{21}
 }
 func (m *MetricsMetadataResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -995,7 +1140,7 @@ type TimeSeriesChunk struct {
 func (m *TimeSeriesChunk) Reset()      { *m = TimeSeriesChunk{} }
 func (*TimeSeriesChunk) ProtoMessage() {}
 func (*TimeSeriesChunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{19}
+	return fileDescriptor_60f6df4f3586b478, []int{22}
 }
 func (m *TimeSeriesChunk) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1055,7 +1200,7 @@ type Chunk struct {
 func (m *Chunk) Reset()      { *m = Chunk{} }
 func (*Chunk) ProtoMessage() {}
 func (*Chunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{20}
+	return fileDescriptor_60f6df4f3586b478, []int{23}
 }
 func (m *Chunk) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1118,7 +1263,7 @@ type TransferChunksResponse struct {
 func (m *TransferChunksResponse) Reset()      { *m = TransferChunksResponse{} }
 func (*TransferChunksResponse) ProtoMessage() {}
 func (*TransferChunksResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{21}
+	return fileDescriptor_60f6df4f3586b478, []int{24}
 }
 func (m *TransferChunksResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1154,7 +1299,7 @@ type LabelMatchers struct {
 func (m *LabelMatchers) Reset()      { *m = LabelMatchers{} }
 func (*LabelMatchers) ProtoMessage() {}
 func (*LabelMatchers) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{22}
+	return fileDescriptor_60f6df4f3586b478, []int{25}
 }
 func (m *LabelMatchers) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1199,7 +1344,7 @@ type LabelMatcher struct {
 func (m *LabelMatcher) Reset()      { *m = LabelMatcher{} }
 func (*LabelMatcher) ProtoMessage() {}
 func (*LabelMatcher) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{23}
+	return fileDescriptor_60f6df4f3586b478, []int{26}
 }
 func (m *LabelMatcher) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1259,7 +1404,7 @@ type TimeSeriesFile struct {
 func (m *TimeSeriesFile) Reset()      { *m = TimeSeriesFile{} }
 func (*TimeSeriesFile) ProtoMessage() {}
 func (*TimeSeriesFile) Descriptor() ([]byte, []int) {
-	return fileDescriptor_60f6df4f3586b478, []int{24}
+	return fileDescriptor_60f6df4f3586b478, []int{27}
 }
 func (m *TimeSeriesFile) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1333,6 +1478,9 @@ func init() {
 	proto.RegisterType((*UserStatsResponse)(nil), "cortex.UserStatsResponse")
 	proto.RegisterType((*UserIDStatsResponse)(nil), "cortex.UserIDStatsResponse")
 	proto.RegisterType((*UsersStatsResponse)(nil), "cortex.UsersStatsResponse")
+	proto.RegisterType((*SeriesStatsRequest)(nil), "cortex.SeriesStatsRequest")
+	proto.RegisterType((*SeriesStatsResponse)(nil), "cortex.SeriesStatsResponse")
+	proto.RegisterType((*SeriesStatsEntry)(nil), "cortex.SeriesStatsEntry")
 	proto.RegisterType((*MetricsForLabelMatchersRequest)(nil), "cortex.MetricsForLabelMatchersRequest")
 	proto.RegisterType((*MetricsForLabelMatchersResponse)(nil), "cortex.MetricsForLabelMatchersResponse")
 	proto.RegisterType((*MetricsMetadataRequest)(nil), "cortex.MetricsMetadataRequest")
@@ -1348,86 +1496,92 @@ func init() {
 func init() { proto.RegisterFile("ingester.proto", fileDescriptor_60f6df4f3586b478) }
 
 var fileDescriptor_60f6df4f3586b478 = []byte{
-	// 1253 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x57, 0xcd, 0x6f, 0x13, 0x57,
-	0x10, 0xdf, 0x17, 0x7f, 0x10, 0x8f, 0x1d, 0xe3, 0xbc, 0x00, 0x31, 0x4b, 0xd9, 0xd0, 0x95, 0x68,
-	0xad, 0xb6, 0x38, 0x90, 0x7e, 0x08, 0xaa, 0x56, 0xc8, 0x81, 0x00, 0x29, 0x98, 0xc0, 0xc6, 0xb4,
-	0x55, 0xa5, 0x6a, 0xb5, 0xb6, 0x5f, 0x9c, 0x2d, 0xfb, 0xc5, 0xbe, 0xb7, 0x15, 0xdc, 0x2a, 0xf5,
-	0x0f, 0x68, 0xd5, 0x53, 0xaf, 0xbd, 0xf5, 0xdc, 0x4b, 0x6f, 0x3d, 0xf5, 0xc0, 0x91, 0x23, 0xea,
-	0x01, 0x15, 0x73, 0xe9, 0x91, 0xfe, 0x07, 0xd5, 0xbe, 0x7d, 0xbb, 0xde, 0xdd, 0xd8, 0x40, 0x24,
-	0xd2, 0x9b, 0x77, 0xe6, 0x37, 0xf3, 0x7e, 0x6f, 0x66, 0xde, 0xcc, 0x18, 0xea, 0xa6, 0x33, 0x22,
-	0x94, 0x11, 0xbf, 0xed, 0xf9, 0x2e, 0x73, 0x71, 0x79, 0xe0, 0xfa, 0x8c, 0xdc, 0x97, 0xcf, 0x8c,
-	0x4c, 0xb6, 0x1b, 0xf4, 0xdb, 0x03, 0xd7, 0x5e, 0x1d, 0xb9, 0x23, 0x77, 0x95, 0xab, 0xfb, 0xc1,
-	0x0e, 0xff, 0xe2, 0x1f, 0xfc, 0x57, 0x64, 0x26, 0x5f, 0x48, 0xc1, 0x23, 0x0f, 0x9e, 0xef, 0x7e,
-	0x43, 0x06, 0x4c, 0x7c, 0xad, 0x7a, 0x77, 0x47, 0xb1, 0xa2, 0x2f, 0x7e, 0x44, 0xa6, 0xea, 0xa7,
-	0x50, 0xd5, 0x88, 0x31, 0xd4, 0xc8, 0xbd, 0x80, 0x50, 0x86, 0xdb, 0x70, 0xe8, 0x5e, 0x40, 0x7c,
-	0x93, 0xd0, 0x26, 0x3a, 0x55, 0x68, 0x55, 0xd7, 0x8e, 0xb4, 0x05, 0xfc, 0x76, 0x40, 0xfc, 0x07,
-	0x02, 0xa6, 0xc5, 0x20, 0xf5, 0x22, 0xd4, 0x22, 0x73, 0xea, 0xb9, 0x0e, 0x25, 0x78, 0x15, 0x0e,
-	0xf9, 0x84, 0x06, 0x16, 0x8b, 0xed, 0x8f, 0xe6, 0xec, 0x23, 0x9c, 0x16, 0xa3, 0xd4, 0x9f, 0x11,
-	0xd4, 0xd2, 0xae, 0xf1, 0x7b, 0x80, 0x29, 0x33, 0x7c, 0xa6, 0x33, 0xd3, 0x26, 0x94, 0x19, 0xb6,
-	0xa7, 0xdb, 0xa1, 0x33, 0xd4, 0x2a, 0x68, 0x0d, 0xae, 0xe9, 0xc5, 0x8a, 0x2e, 0xc5, 0x2d, 0x68,
-	0x10, 0x67, 0x98, 0xc5, 0xce, 0x71, 0x6c, 0x9d, 0x38, 0xc3, 0x34, 0xf2, 0x2c, 0xcc, 0xdb, 0x06,
-	0x1b, 0xec, 0x12, 0x9f, 0x36, 0x0b, 0xd9, 0xab, 0xdd, 0x30, 0xfa, 0xc4, 0xea, 0x46, 0x4a, 0x2d,
-	0x41, 0xa9, 0xbf, 0x20, 0x38, 0xb2, 0x71, 0x9f, 0xd8, 0x9e, 0x65, 0xf8, 0xff, 0x0b, 0xc5, 0x73,
-	0x7b, 0x28, 0x1e, 0x9d, 0x46, 0x91, 0xa6, 0x38, 0x5e, 0x87, 0x85, 0x4c, 0x60, 0xf1, 0xc7, 0x00,
-	0xfc, 0xa4, 0x69, 0x39, 0xf4, 0xfa, 0xed, 0xf0, 0xb8, 0x6d, 0xae, 0x5b, 0x2f, 0x3e, 0x7c, 0xb2,
-	0x22, 0x69, 0x29, 0xb4, 0xfa, 0x13, 0x82, 0x25, 0xee, 0x6d, 0x9b, 0xf9, 0xc4, 0xb0, 0x13, 0x9f,
-	0x17, 0xa1, 0x3a, 0xd8, 0x0d, 0x9c, 0xbb, 0x19, 0xa7, 0xcb, 0x31, 0xb5, 0x89, 0xcb, 0x4b, 0x21,
-	0x48, 0xf8, 0x4d, 0x5b, 0xe4, 0x48, 0xcd, 0xed, 0x8b, 0xd4, 0x36, 0x1c, 0xcd, 0x25, 0xe1, 0x35,
-	0xdc, 0xf4, 0x0f, 0x04, 0x98, 0x87, 0xf4, 0x73, 0xc3, 0x0a, 0x08, 0x8d, 0x13, 0x7b, 0x12, 0xc0,
-	0x0a, 0xa5, 0xba, 0x63, 0xd8, 0x84, 0x27, 0xb4, 0xa2, 0x55, 0xb8, 0xe4, 0xa6, 0x61, 0x93, 0x19,
-	0x79, 0x9f, 0xdb, 0x47, 0xde, 0x0b, 0x2f, 0xcd, 0x7b, 0xf1, 0x14, 0x7a, 0x95, 0xbc, 0x9f, 0x87,
-	0xa5, 0x0c, 0x7f, 0x11, 0x93, 0x37, 0xa1, 0x16, 0x5d, 0xe0, 0x5b, 0x2e, 0xe7, 0x51, 0xa9, 0x68,
-	0x55, 0x6b, 0x02, 0x55, 0xef, 0xc2, 0xe2, 0x8d, 0xf8, 0x46, 0xf4, 0x80, 0x2b, 0x5a, 0xfd, 0x50,
-	0x84, 0x59, 0x1c, 0x26, 0x58, 0xae, 0x40, 0x75, 0x12, 0xe6, 0x98, 0x24, 0x24, 0x71, 0xa6, 0x2a,
-	0x86, 0xc6, 0x1d, 0x4a, 0xfc, 0x6d, 0x66, 0xb0, 0x98, 0xa2, 0xfa, 0x3b, 0x82, 0xc5, 0x94, 0x50,
-	0xb8, 0x3a, 0x1d, 0xb7, 0x50, 0xd3, 0x75, 0x74, 0xdf, 0x60, 0x51, 0xd6, 0x90, 0xb6, 0x90, 0x48,
-	0x35, 0x83, 0x91, 0x30, 0xb1, 0x4e, 0x60, 0xeb, 0x49, 0x01, 0xa2, 0x56, 0x51, 0xab, 0x38, 0x81,
-	0x1d, 0x15, 0x48, 0x78, 0x7d, 0xc3, 0x33, 0xf5, 0x9c, 0xa7, 0x02, 0xf7, 0xd4, 0x30, 0x3c, 0x73,
-	0x33, 0xe3, 0xac, 0x0d, 0x4b, 0x7e, 0x60, 0x91, 0x3c, 0xbc, 0xc8, 0xe1, 0x8b, 0xa1, 0x2a, 0x83,
-	0x57, 0xbf, 0x86, 0xa5, 0x90, 0xf8, 0xe6, 0xe5, 0x2c, 0xf5, 0x65, 0x38, 0x14, 0x50, 0xe2, 0xeb,
-	0xe6, 0x50, 0x54, 0x5a, 0x39, 0xfc, 0xdc, 0x1c, 0xe2, 0x33, 0x50, 0x1c, 0x1a, 0xcc, 0xe0, 0x34,
-	0xab, 0x6b, 0xc7, 0xe3, 0x52, 0xd8, 0x73, 0x79, 0x8d, 0xc3, 0xd4, 0xab, 0x80, 0x43, 0x15, 0xcd,
-	0x7a, 0x3f, 0x07, 0x25, 0x1a, 0x0a, 0xc4, 0xc3, 0x38, 0x91, 0xf6, 0x92, 0x63, 0xa2, 0x45, 0x48,
-	0xf5, 0x37, 0x04, 0x4a, 0x97, 0x30, 0xdf, 0x1c, 0xd0, 0x2b, 0xae, 0x9f, 0xad, 0xbc, 0x03, 0xee,
-	0x7c, 0xe7, 0xa1, 0x16, 0x97, 0xb6, 0x4e, 0x09, 0x7b, 0x71, 0xf7, 0xab, 0xc6, 0xd0, 0x6d, 0xc2,
-	0xd4, 0xeb, 0xb0, 0x32, 0x93, 0xb3, 0x08, 0x45, 0x0b, 0xca, 0x36, 0x87, 0x88, 0x58, 0x34, 0x26,
-	0x4d, 0x22, 0x32, 0xd5, 0x84, 0x5e, 0x6d, 0xc2, 0x31, 0xe1, 0xac, 0x4b, 0x98, 0x11, 0x46, 0x37,
-	0xae, 0xbe, 0x2d, 0x58, 0xde, 0xa3, 0x11, 0xee, 0x3f, 0x80, 0x79, 0x5b, 0xc8, 0xc4, 0x01, 0xcd,
-	0xfc, 0x01, 0x89, 0x4d, 0x82, 0x54, 0xff, 0x45, 0x70, 0x38, 0xd7, 0x39, 0xc3, 0x78, 0xed, 0xf8,
-	0xae, 0xad, 0xc7, 0x4b, 0xc1, 0xa4, 0x34, 0xea, 0xa1, 0x7c, 0x53, 0x88, 0x37, 0x87, 0xe9, 0xda,
-	0x99, 0xcb, 0xd4, 0x8e, 0x03, 0x65, 0xfe, 0x8e, 0xe2, 0x01, 0xb2, 0x34, 0xa1, 0xc2, 0x83, 0x73,
-	0xcb, 0x30, 0xfd, 0xf5, 0x4e, 0xd8, 0x0f, 0xff, 0x7a, 0xb2, 0xb2, 0xaf, 0xb5, 0x21, 0xb2, 0xef,
-	0x0c, 0x0d, 0x8f, 0x11, 0x5f, 0x13, 0xa7, 0xe0, 0x77, 0xa1, 0x1c, 0x35, 0xfa, 0x66, 0x91, 0x9f,
-	0xb7, 0x10, 0xa7, 0x2c, 0x3d, 0x0b, 0x04, 0x44, 0xfd, 0x01, 0x41, 0x29, 0xba, 0xe9, 0x41, 0xd5,
-	0x91, 0x0c, 0xf3, 0xc4, 0x19, 0xb8, 0x43, 0xd3, 0x19, 0xf1, 0xe7, 0x5b, 0xd2, 0x92, 0x6f, 0x8c,
-	0xc5, 0xb3, 0x0a, 0xdf, 0x69, 0x4d, 0xbc, 0x9d, 0x26, 0x1c, 0xeb, 0xf9, 0x86, 0x43, 0x77, 0x88,
-	0xcf, 0x89, 0x25, 0x45, 0xa3, 0x76, 0x60, 0x21, 0x53, 0x4d, 0x99, 0xfd, 0x01, 0xbd, 0xd2, 0xfe,
-	0xa0, 0x43, 0x2d, 0xad, 0xc1, 0xa7, 0xa1, 0xc8, 0x1e, 0x78, 0x51, 0x87, 0xaa, 0xaf, 0x2d, 0xc6,
-	0xd6, 0x5c, 0xdd, 0x7b, 0xe0, 0x11, 0x8d, 0xab, 0x43, 0x9e, 0x7c, 0xfc, 0x44, 0x89, 0xe5, 0xbf,
-	0xf1, 0x11, 0x28, 0xf1, 0x8e, 0xce, 0x2f, 0x55, 0xd1, 0xa2, 0x0f, 0xf5, 0x7b, 0x04, 0xf5, 0x49,
-	0x0d, 0x5d, 0x31, 0x2d, 0xf2, 0x3a, 0x4a, 0x48, 0x86, 0xf9, 0x1d, 0xd3, 0x22, 0x9c, 0x43, 0x74,
-	0x5c, 0xf2, 0x3d, 0x2d, 0x86, 0xef, 0x7c, 0x06, 0x95, 0xe4, 0x0a, 0xb8, 0x02, 0xa5, 0x8d, 0xdb,
-	0x77, 0x3a, 0x37, 0x1a, 0x12, 0x5e, 0x80, 0xca, 0xcd, 0xad, 0x9e, 0x1e, 0x7d, 0x22, 0x7c, 0x18,
-	0xaa, 0xda, 0xc6, 0xd5, 0x8d, 0x2f, 0xf5, 0x6e, 0xa7, 0x77, 0xe9, 0x5a, 0x63, 0x0e, 0x63, 0xa8,
-	0x47, 0x82, 0x9b, 0x5b, 0x42, 0x56, 0x58, 0xfb, 0xb3, 0x0c, 0xf3, 0x31, 0x47, 0x7c, 0x01, 0x8a,
-	0xb7, 0x02, 0xba, 0x8b, 0x8f, 0x4d, 0x6a, 0xf8, 0x0b, 0xdf, 0x64, 0x44, 0xbc, 0x49, 0x79, 0x79,
-	0x8f, 0x5c, 0xe4, 0x4e, 0xc2, 0x1f, 0x41, 0x89, 0x2f, 0x0b, 0x78, 0xea, 0xfa, 0x2a, 0x4f, 0x5f,
-	0x4a, 0x55, 0x09, 0x5f, 0x86, 0x6a, 0x6a, 0x01, 0x9a, 0x61, 0x7d, 0x22, 0x23, 0xcd, 0xee, 0x4a,
-	0xaa, 0x74, 0x16, 0xe1, 0x2d, 0xa8, 0x73, 0x55, 0xbc, 0xb7, 0x50, 0xfc, 0x46, 0x6c, 0x32, 0x6d,
-	0x9f, 0x94, 0x4f, 0xce, 0xd0, 0x26, 0xb4, 0xae, 0x41, 0x35, 0x35, 0xed, 0xb1, 0x9c, 0x29, 0xbc,
-	0xcc, 0x0a, 0x33, 0x21, 0x37, 0x65, 0x3d, 0x50, 0x25, 0xbc, 0x01, 0x30, 0x19, 0xc8, 0xf8, 0x78,
-	0x06, 0x9c, 0xde, 0x08, 0x64, 0x79, 0x9a, 0x2a, 0x71, 0xb3, 0x0e, 0x95, 0x64, 0x1c, 0xe1, 0xe6,
-	0x94, 0x09, 0x15, 0x39, 0x99, 0x3d, 0xbb, 0x54, 0x09, 0x5f, 0x81, 0x5a, 0xc7, 0xb2, 0x5e, 0xc5,
-	0x8d, 0x9c, 0xd6, 0xd0, 0xbc, 0x1f, 0x2b, 0x69, 0xcd, 0xf9, 0x09, 0x80, 0xdf, 0x4a, 0xde, 0xd8,
-	0x0b, 0xc7, 0x9a, 0xfc, 0xf6, 0x4b, 0x71, 0xc9, 0x69, 0x3d, 0x38, 0x9c, 0x1b, 0x04, 0x58, 0xc9,
-	0x59, 0xe7, 0x66, 0x87, 0xbc, 0x32, 0x53, 0x9f, 0x78, 0xed, 0x42, 0x3d, 0xdb, 0x87, 0xf0, 0xac,
-	0xf5, 0x5a, 0x4e, 0x4e, 0x9b, 0xd1, 0xb8, 0xa4, 0x16, 0x5a, 0xff, 0xe4, 0xd1, 0x53, 0x45, 0x7a,
-	0xfc, 0x54, 0x91, 0x9e, 0x3f, 0x55, 0xd0, 0x77, 0x63, 0x05, 0xfd, 0x3a, 0x56, 0xd0, 0xc3, 0xb1,
-	0x82, 0x1e, 0x8d, 0x15, 0xf4, 0xf7, 0x58, 0x41, 0xff, 0x8c, 0x15, 0xe9, 0xf9, 0x58, 0x41, 0x3f,
-	0x3e, 0x53, 0xa4, 0x47, 0xcf, 0x14, 0xe9, 0xf1, 0x33, 0x45, 0xfa, 0xaa, 0x3c, 0xb0, 0x4c, 0xe2,
-	0xb0, 0x7e, 0x99, 0xff, 0x33, 0x7c, 0xff, 0xbf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x0e, 0x4f, 0x5c,
-	0xe0, 0x9d, 0x0e, 0x00, 0x00,
+	// 1348 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x57, 0xcf, 0x6f, 0x1b, 0xc5,
+	0x17, 0xdf, 0x49, 0x1c, 0x27, 0x7e, 0x76, 0x5c, 0x67, 0x92, 0x34, 0xee, 0xe6, 0xdb, 0x4d, 0xbf,
+	0x2b, 0x15, 0x22, 0xa0, 0x49, 0x1b, 0x7e, 0xa8, 0x45, 0xa0, 0x2a, 0x69, 0xdd, 0x36, 0xb4, 0x69,
+	0xda, 0x4d, 0x0a, 0x08, 0x09, 0xad, 0xd6, 0xf6, 0xc4, 0x59, 0xba, 0xbf, 0xba, 0x33, 0x8b, 0x9a,
+	0x1b, 0x12, 0x77, 0x40, 0x9c, 0xb8, 0x72, 0xe3, 0xcc, 0x85, 0x1b, 0x37, 0xa4, 0x1e, 0x7b, 0xac,
+	0x38, 0x54, 0xd4, 0xbd, 0x70, 0x2c, 0xff, 0x01, 0xda, 0x99, 0xd9, 0xf5, 0xee, 0xc6, 0xee, 0x0f,
+	0xa9, 0xe1, 0xe6, 0x7d, 0xef, 0xf3, 0x3e, 0xf3, 0xe6, 0xfd, 0x98, 0xf7, 0x0c, 0x75, 0xdb, 0xeb,
+	0x11, 0xca, 0x48, 0xb8, 0x12, 0x84, 0x3e, 0xf3, 0x71, 0xb9, 0xe3, 0x87, 0x8c, 0xdc, 0x57, 0xcf,
+	0xf4, 0x6c, 0xb6, 0x1f, 0xb5, 0x57, 0x3a, 0xbe, 0xbb, 0xda, 0xf3, 0x7b, 0xfe, 0x2a, 0x57, 0xb7,
+	0xa3, 0x3d, 0xfe, 0xc5, 0x3f, 0xf8, 0x2f, 0x61, 0xa6, 0x5e, 0xc8, 0xc0, 0x05, 0x43, 0x10, 0xfa,
+	0x5f, 0x91, 0x0e, 0x93, 0x5f, 0xab, 0xc1, 0xdd, 0x5e, 0xa2, 0x68, 0xcb, 0x1f, 0xc2, 0x54, 0xff,
+	0x18, 0xaa, 0x06, 0xb1, 0xba, 0x06, 0xb9, 0x17, 0x11, 0xca, 0xf0, 0x0a, 0x4c, 0xde, 0x8b, 0x48,
+	0x68, 0x13, 0xda, 0x44, 0xa7, 0xc6, 0x97, 0xab, 0x6b, 0x73, 0x2b, 0x12, 0x7e, 0x3b, 0x22, 0xe1,
+	0x81, 0x84, 0x19, 0x09, 0x48, 0xbf, 0x08, 0x35, 0x61, 0x4e, 0x03, 0xdf, 0xa3, 0x04, 0xaf, 0xc2,
+	0x64, 0x48, 0x68, 0xe4, 0xb0, 0xc4, 0x7e, 0xbe, 0x60, 0x2f, 0x70, 0x46, 0x82, 0xd2, 0x7f, 0x42,
+	0x50, 0xcb, 0x52, 0xe3, 0x77, 0x00, 0x53, 0x66, 0x85, 0xcc, 0x64, 0xb6, 0x4b, 0x28, 0xb3, 0xdc,
+	0xc0, 0x74, 0x63, 0x32, 0xb4, 0x3c, 0x6e, 0x34, 0xb8, 0x66, 0x37, 0x51, 0x6c, 0x51, 0xbc, 0x0c,
+	0x0d, 0xe2, 0x75, 0xf3, 0xd8, 0x31, 0x8e, 0xad, 0x13, 0xaf, 0x9b, 0x45, 0x9e, 0x85, 0x29, 0xd7,
+	0x62, 0x9d, 0x7d, 0x12, 0xd2, 0xe6, 0x78, 0xfe, 0x6a, 0x37, 0xac, 0x36, 0x71, 0xb6, 0x84, 0xd2,
+	0x48, 0x51, 0xfa, 0xcf, 0x08, 0xe6, 0x5a, 0xf7, 0x89, 0x1b, 0x38, 0x56, 0xf8, 0x9f, 0xb8, 0x78,
+	0xee, 0x90, 0x8b, 0xf3, 0xc3, 0x5c, 0xa4, 0x19, 0x1f, 0xaf, 0xc3, 0x74, 0x2e, 0xb0, 0xf8, 0x43,
+	0x00, 0x7e, 0xd2, 0xb0, 0x1c, 0x06, 0xed, 0x95, 0xf8, 0xb8, 0x1d, 0xae, 0xdb, 0x28, 0x3d, 0x78,
+	0xbc, 0xa4, 0x18, 0x19, 0xb4, 0xfe, 0x23, 0x82, 0x59, 0xce, 0xb6, 0xc3, 0x42, 0x62, 0xb9, 0x29,
+	0xe7, 0x45, 0xa8, 0x76, 0xf6, 0x23, 0xef, 0x6e, 0x8e, 0x74, 0x21, 0x71, 0x6d, 0x40, 0x79, 0x29,
+	0x06, 0x49, 0xde, 0xac, 0x45, 0xc1, 0xa9, 0xb1, 0x57, 0x72, 0x6a, 0x07, 0xe6, 0x0b, 0x49, 0x78,
+	0x0d, 0x37, 0xfd, 0x1d, 0x01, 0xe6, 0x21, 0xfd, 0xd4, 0x72, 0x22, 0x42, 0x93, 0xc4, 0x9e, 0x04,
+	0x70, 0x62, 0xa9, 0xe9, 0x59, 0x2e, 0xe1, 0x09, 0xad, 0x18, 0x15, 0x2e, 0xb9, 0x69, 0xb9, 0x64,
+	0x44, 0xde, 0xc7, 0x5e, 0x21, 0xef, 0xe3, 0x2f, 0xcc, 0x7b, 0xe9, 0x14, 0x7a, 0x99, 0xbc, 0x9f,
+	0x87, 0xd9, 0x9c, 0xff, 0x32, 0x26, 0xff, 0x87, 0x9a, 0xb8, 0xc0, 0xd7, 0x5c, 0xce, 0xa3, 0x52,
+	0x31, 0xaa, 0xce, 0x00, 0xaa, 0xdf, 0x85, 0x99, 0x1b, 0xc9, 0x8d, 0xe8, 0x11, 0x57, 0xb4, 0xfe,
+	0x3e, 0xe0, 0xec, 0x61, 0xd2, 0xcb, 0x25, 0xa8, 0x0e, 0xc2, 0x9c, 0x38, 0x09, 0x69, 0x9c, 0xa9,
+	0x8e, 0xa1, 0x71, 0x87, 0x92, 0x70, 0x87, 0x59, 0x2c, 0x71, 0x51, 0xff, 0x0d, 0xc1, 0x4c, 0x46,
+	0x28, 0xa9, 0x4e, 0x27, 0x4f, 0xa8, 0xed, 0x7b, 0x66, 0x68, 0x31, 0x91, 0x35, 0x64, 0x4c, 0xa7,
+	0x52, 0xc3, 0x62, 0x24, 0x4e, 0xac, 0x17, 0xb9, 0x66, 0x5a, 0x80, 0x68, 0xb9, 0x64, 0x54, 0xbc,
+	0xc8, 0x15, 0x05, 0x12, 0x5f, 0xdf, 0x0a, 0x6c, 0xb3, 0xc0, 0x34, 0xce, 0x99, 0x1a, 0x56, 0x60,
+	0x6f, 0xe6, 0xc8, 0x56, 0x60, 0x36, 0x8c, 0x1c, 0x52, 0x84, 0x97, 0x38, 0x7c, 0x26, 0x56, 0xe5,
+	0xf0, 0xfa, 0x97, 0x30, 0x1b, 0x3b, 0xbe, 0x79, 0x39, 0xef, 0xfa, 0x02, 0x4c, 0x46, 0x94, 0x84,
+	0xa6, 0xdd, 0x95, 0x95, 0x56, 0x8e, 0x3f, 0x37, 0xbb, 0xf8, 0x0c, 0x94, 0xba, 0x16, 0xb3, 0xb8,
+	0x9b, 0xd5, 0xb5, 0x13, 0x49, 0x29, 0x1c, 0xba, 0xbc, 0xc1, 0x61, 0xfa, 0x55, 0xc0, 0xb1, 0x8a,
+	0xe6, 0xd9, 0xcf, 0xc1, 0x04, 0x8d, 0x05, 0xb2, 0x31, 0x16, 0xb3, 0x2c, 0x05, 0x4f, 0x0c, 0x81,
+	0xd4, 0xe7, 0x00, 0x8b, 0x78, 0xe4, 0xe2, 0xfe, 0x07, 0x82, 0xd9, 0x9c, 0x58, 0x1e, 0x90, 0x0f,
+	0x29, 0x2a, 0x86, 0x74, 0x03, 0x1a, 0xcc, 0x0f, 0x4c, 0x97, 0xb0, 0xd0, 0xee, 0xc8, 0x44, 0x8b,
+	0xc6, 0x6f, 0x26, 0xae, 0x64, 0x58, 0x5b, 0x1e, 0x0b, 0x0f, 0x8c, 0x3a, 0xf3, 0x83, 0x2d, 0x6e,
+	0xc0, 0xcb, 0x00, 0x5f, 0x87, 0xf9, 0x98, 0x23, 0x53, 0xd1, 0x66, 0x60, 0xd9, 0xe9, 0xe3, 0x38,
+	0x9a, 0x08, 0x33, 0x3f, 0x18, 0xb4, 0xc7, 0xad, 0xd8, 0x46, 0x6f, 0x41, 0xa3, 0x88, 0xc3, 0x18,
+	0x4a, 0x99, 0x4e, 0xe7, 0xbf, 0x5f, 0x50, 0x2a, 0xfa, 0xaf, 0x08, 0x34, 0xe1, 0x23, 0xbd, 0xe2,
+	0x87, 0xf9, 0xf6, 0x3c, 0xe2, 0xf1, 0x70, 0x1e, 0x6a, 0x49, 0xff, 0x9b, 0x94, 0xb0, 0xe7, 0x8f,
+	0x88, 0x6a, 0x02, 0xdd, 0x21, 0x4c, 0xbf, 0x0e, 0x4b, 0x23, 0x7d, 0x96, 0xe9, 0x5c, 0x86, 0xb2,
+	0xc8, 0x95, 0x2c, 0x98, 0xc6, 0xe0, 0x25, 0x15, 0xa6, 0x86, 0xd4, 0xeb, 0x4d, 0x38, 0x2e, 0xc9,
+	0xb6, 0x08, 0xb3, 0xe2, 0x12, 0x4c, 0x4a, 0x65, 0x1b, 0x16, 0x0e, 0x69, 0x24, 0xfd, 0x7b, 0x30,
+	0xe5, 0x4a, 0x59, 0x13, 0xe5, 0xb2, 0x97, 0x1e, 0x90, 0xda, 0xa4, 0x48, 0xfd, 0x1f, 0x04, 0xc7,
+	0x0a, 0xe3, 0x25, 0x8e, 0xd7, 0x5e, 0xe8, 0xbb, 0x66, 0xb2, 0x39, 0x0d, 0xfa, 0xa7, 0x1e, 0xcb,
+	0x37, 0xa5, 0x78, 0xb3, 0x9b, 0x6d, 0xb0, 0xb1, 0x5c, 0x83, 0x79, 0x50, 0xe6, 0x35, 0x95, 0x14,
+	0xd2, 0xec, 0xc0, 0x15, 0x1e, 0x9c, 0xb8, 0x60, 0x36, 0xd6, 0xe3, 0xa1, 0xf1, 0xe7, 0xe3, 0xa5,
+	0x57, 0xda, 0xad, 0x84, 0xfd, 0x7a, 0xd7, 0x0a, 0x18, 0x09, 0x0d, 0x79, 0x0a, 0x7e, 0x1b, 0xca,
+	0x62, 0x1a, 0x36, 0x4b, 0xfc, 0xbc, 0xe9, 0x24, 0x65, 0xd9, 0x81, 0x29, 0x21, 0xfa, 0xf7, 0x08,
+	0x26, 0xc4, 0x4d, 0x8f, 0xaa, 0x8e, 0x54, 0x98, 0x22, 0x5e, 0xc7, 0xef, 0xda, 0x5e, 0x8f, 0xbf,
+	0x71, 0x13, 0x46, 0xfa, 0x1d, 0x77, 0x04, 0xcf, 0x51, 0xfc, 0x98, 0xd5, 0xe4, 0x03, 0xd3, 0x84,
+	0xe3, 0xbb, 0xa1, 0xe5, 0xd1, 0x3d, 0x12, 0x72, 0xc7, 0xd2, 0xa2, 0xd1, 0xd7, 0x61, 0x3a, 0x57,
+	0x4d, 0xb9, 0x25, 0x0b, 0xbd, 0xd4, 0x92, 0x65, 0x42, 0x2d, 0xab, 0xc1, 0xa7, 0xa1, 0xc4, 0x0e,
+	0x02, 0xd1, 0x92, 0xf5, 0xb5, 0x99, 0xc4, 0x9a, 0xab, 0x77, 0x0f, 0x02, 0x62, 0x70, 0x75, 0xda,
+	0xb9, 0x63, 0x99, 0xce, 0x9d, 0x83, 0x09, 0xfe, 0x48, 0xf0, 0x4b, 0x55, 0x0c, 0xf1, 0xa1, 0x7f,
+	0x8b, 0xa0, 0x3e, 0xa8, 0xa1, 0x2b, 0xb6, 0x43, 0x5e, 0x47, 0x09, 0xa9, 0x30, 0xb5, 0x67, 0x3b,
+	0x84, 0xfb, 0x20, 0x8e, 0x4b, 0xbf, 0x87, 0xc5, 0xf0, 0xad, 0x4f, 0xa0, 0x92, 0x5e, 0x01, 0x57,
+	0x60, 0xa2, 0x75, 0xfb, 0xce, 0xfa, 0x8d, 0x86, 0x82, 0xa7, 0xa1, 0x72, 0x73, 0x7b, 0xd7, 0x14,
+	0x9f, 0x08, 0x1f, 0x83, 0xaa, 0xd1, 0xba, 0xda, 0xfa, 0xdc, 0xdc, 0x5a, 0xdf, 0xbd, 0x74, 0xad,
+	0x31, 0x86, 0x31, 0xd4, 0x85, 0xe0, 0xe6, 0xb6, 0x94, 0x8d, 0xaf, 0x7d, 0x37, 0x09, 0x53, 0x89,
+	0x8f, 0xf8, 0x02, 0x94, 0x6e, 0x45, 0x74, 0x1f, 0x1f, 0x1f, 0xd4, 0xf0, 0x67, 0xa1, 0xcd, 0x88,
+	0xec, 0x49, 0x75, 0xe1, 0x90, 0x5c, 0xe6, 0x4e, 0xc1, 0x1f, 0xc0, 0x04, 0xdf, 0xa8, 0xf0, 0xd0,
+	0x1d, 0x5f, 0x1d, 0xbe, 0xb9, 0xeb, 0x0a, 0xbe, 0x0c, 0xd5, 0xcc, 0x96, 0x38, 0xc2, 0x7a, 0x31,
+	0x27, 0xcd, 0x2f, 0x94, 0xba, 0x72, 0x16, 0xe1, 0x6d, 0xa8, 0x73, 0x55, 0xb2, 0xdc, 0x51, 0xfc,
+	0xbf, 0xc4, 0x64, 0xd8, 0xd2, 0xad, 0x9e, 0x1c, 0xa1, 0x4d, 0xdd, 0xba, 0x06, 0xd5, 0xcc, 0x4a,
+	0x84, 0xd5, 0x5c, 0xe1, 0xe5, 0xf6, 0x3c, 0x75, 0x71, 0xa8, 0x2e, 0x65, 0x6a, 0x01, 0x0c, 0xb6,
+	0x16, 0x7c, 0x22, 0x07, 0xce, 0xae, 0x4d, 0xaa, 0x3a, 0x4c, 0x95, 0xd2, 0x6c, 0x40, 0x25, 0x9d,
+	0xd9, 0xb8, 0x39, 0x64, 0x8c, 0x0b, 0x92, 0xd1, 0x03, 0x5e, 0x57, 0xf0, 0x15, 0xa8, 0xad, 0x3b,
+	0xce, 0xcb, 0xd0, 0xa8, 0x59, 0x0d, 0x2d, 0xf2, 0x5c, 0x83, 0x6a, 0x66, 0xfa, 0x0d, 0x82, 0x73,
+	0x78, 0xe0, 0xab, 0x8b, 0x43, 0x75, 0x29, 0x93, 0x03, 0x0b, 0x23, 0x66, 0x09, 0x7e, 0x23, 0xed,
+	0xd6, 0xe7, 0x0e, 0x48, 0xf5, 0xcd, 0x17, 0xe2, 0xd2, 0xd3, 0x76, 0xe1, 0x58, 0x61, 0xa4, 0x60,
+	0xad, 0x60, 0x5d, 0x98, 0x42, 0xea, 0xd2, 0x48, 0x7d, 0xca, 0xba, 0x05, 0xf5, 0xfc, 0x8b, 0x86,
+	0x47, 0xfd, 0x9b, 0x51, 0xd3, 0xd3, 0x46, 0x3c, 0x81, 0xca, 0x32, 0xda, 0xf8, 0xe8, 0xe1, 0x13,
+	0x4d, 0x79, 0xf4, 0x44, 0x53, 0x9e, 0x3d, 0xd1, 0xd0, 0x37, 0x7d, 0x0d, 0xfd, 0xd2, 0xd7, 0xd0,
+	0x83, 0xbe, 0x86, 0x1e, 0xf6, 0x35, 0xf4, 0x57, 0x5f, 0x43, 0x7f, 0xf7, 0x35, 0xe5, 0x59, 0x5f,
+	0x43, 0x3f, 0x3c, 0xd5, 0x94, 0x87, 0x4f, 0x35, 0xe5, 0xd1, 0x53, 0x4d, 0xf9, 0xa2, 0xdc, 0x71,
+	0x6c, 0xe2, 0xb1, 0x76, 0x99, 0xff, 0x11, 0x7f, 0xf7, 0xdf, 0x01, 0x00, 0x6d, 0x91, 0x8c, 0x82,
+	0x0c, 0x10, 0x00, 0x00,
 }
 
 func (x MatchType) String() string {
@@ -1888,6 +2042,94 @@ func (this *UsersStatsResponse) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *SeriesStatsRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*SeriesStatsRequest)
+	if !ok {
+		that2, ok := that.(SeriesStatsRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
+func (this *SeriesStatsResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*SeriesStatsResponse)
+	if !ok {
+		that2, ok := that.(SeriesStatsResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.NumSeries != that1.NumSeries {
+		return false
+	}
+	if len(this.TopMetricNames) != len(that1.TopMetricNames) {
+		return false
+	}
+	for i := range this.TopMetricNames {
+		if !this.TopMetricNames[i].Equal(that1.TopMetricNames[i]) {
+			return false
+		}
+	}
+	if len(this.TopLabelValuePairs) != len(that1.TopLabelValuePairs) {
+		return false
+	}
+	for i := range this.TopLabelValuePairs {
+		if !this.TopLabelValuePairs[i].Equal(that1.TopLabelValuePairs[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *SeriesStatsEntry) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*SeriesStatsEntry)
+	if !ok {
+		that2, ok := that.(SeriesStatsEntry)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Name != that1.Name {
+		return false
+	}
+	if this.NumSeries != that1.NumSeries {
+		return false
+	}
+	return true
+}
 func (this *MetricsForLabelMatchersRequest) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -2391,6 +2633,42 @@ func (this *UsersStatsResponse) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *SeriesStatsRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&client.SeriesStatsRequest{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *SeriesStatsResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&client.SeriesStatsResponse{")
+	s = append(s, "NumSeries: "+fmt.Sprintf("%#v", this.NumSeries)+",\n")
+	if this.TopMetricNames != nil {
+		s = append(s, "TopMetricNames: "+fmt.Sprintf("%#v", this.TopMetricNames)+",\n")
+	}
+	if this.TopLabelValuePairs != nil {
+		s = append(s, "TopLabelValuePairs: "+fmt.Sprintf("%#v", this.TopLabelValuePairs)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *SeriesStatsEntry) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&client.SeriesStatsEntry{")
+	s = append(s, "Name: "+fmt.Sprintf("%#v", this.Name)+",\n")
+	s = append(s, "NumSeries: "+fmt.Sprintf("%#v", this.NumSeries)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func (this *MetricsForLabelMatchersRequest) GoString() string {
 	if this == nil {
 		return "nil"
@@ -2545,6 +2823,8 @@ type IngesterClient interface {
 	LabelNames(ctx context.Context, in *LabelNamesRequest, opts ...grpc.CallOption) (*LabelNamesResponse, error)
 	UserStats(ctx context.Context, in *UserStatsRequest, opts ...grpc.CallOption) (*UserStatsResponse, error)
 	AllUserStats(ctx context.Context, in *UserStatsRequest, opts ...grpc.CallOption) (*UsersStatsResponse, error)
+	// SeriesStats returns the number of series of the tenant, along with the metric names and label name/value pairs with the highest number of series.
+	SeriesStats(ctx context.Context, in *SeriesStatsRequest, opts ...grpc.CallOption) (*SeriesStatsResponse, error)
 	MetricsForLabelMatchers(ctx context.Context, in *MetricsForLabelMatchersRequest, opts ...grpc.CallOption) (*MetricsForLabelMatchersResponse, error)
 	MetricsMetadata(ctx context.Context, in *MetricsMetadataRequest, opts ...grpc.CallOption) (*MetricsMetadataResponse, error)
 	// TransferChunks allows leaving ingester (client) to stream chunks directly to joining ingesters (server).
@@ -2654,6 +2934,15 @@ func (c *ingesterClient) AllUserStats(ctx context.Context, in *UserStatsRequest,
 	return out, nil
 }
 
+func (c *ingesterClient) SeriesStats(ctx context.Context, in *SeriesStatsRequest, opts ...grpc.CallOption) (*SeriesStatsResponse, error) {
+	out := new(SeriesStatsResponse)
+	err := c.cc.Invoke(ctx, "/cortex.Ingester/SeriesStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *ingesterClient) MetricsForLabelMatchers(ctx context.Context, in *MetricsForLabelMatchersRequest, opts ...grpc.CallOption) (*MetricsForLabelMatchersResponse, error) {
 	out := new(MetricsForLabelMatchersResponse)
 	err := c.cc.Invoke(ctx, "/cortex.Ingester/MetricsForLabelMatchers", in, out, opts...)
@@ -2716,6 +3005,8 @@ type IngesterServer interface {
 	LabelNames(context.Context, *LabelNamesRequest) (*LabelNamesResponse, error)
 	UserStats(context.Context, *UserStatsRequest) (*UserStatsResponse, error)
 	AllUserStats(context.Context, *UserStatsRequest) (*UsersStatsResponse, error)
+	// SeriesStats returns the number of series of the tenant, along with the metric names and label name/value pairs with the highest number of series.
+	SeriesStats(context.Context, *SeriesStatsRequest) (*SeriesStatsResponse, error)
 	MetricsForLabelMatchers(context.Context, *MetricsForLabelMatchersRequest) (*MetricsForLabelMatchersResponse, error)
 	MetricsMetadata(context.Context, *MetricsMetadataRequest) (*MetricsMetadataResponse, error)
 	// TransferChunks allows leaving ingester (client) to stream chunks directly to joining ingesters (server).
@@ -2750,6 +3041,9 @@ func (*UnimplementedIngesterServer) UserStats(ctx context.Context, req *UserStat
 func (*UnimplementedIngesterServer) AllUserStats(ctx context.Context, req *UserStatsRequest) (*UsersStatsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AllUserStats not implemented")
 }
+func (*UnimplementedIngesterServer) SeriesStats(ctx context.Context, req *SeriesStatsRequest) (*SeriesStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SeriesStats not implemented")
+}
 func (*UnimplementedIngesterServer) MetricsForLabelMatchers(ctx context.Context, req *MetricsForLabelMatchersRequest) (*MetricsForLabelMatchersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method MetricsForLabelMatchers not implemented")
 }
@@ -2911,6 +3205,24 @@ func _Ingester_AllUserStats_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Ingester_SeriesStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SeriesStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IngesterServer).SeriesStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cortex.Ingester/SeriesStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IngesterServer).SeriesStats(ctx, req.(*SeriesStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Ingester_MetricsForLabelMatchers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(MetricsForLabelMatchersRequest)
 	if err := dec(in); err != nil {
@@ -3005,6 +3317,10 @@ var _Ingester_serviceDesc = grpc.ServiceDesc{
 			MethodName: "AllUserStats",
 			Handler:    _Ingester_AllUserStats_Handler,
 		},
+		{
+			MethodName: "SeriesStats",
+			Handler:    _Ingester_SeriesStats_Handler,
+		},
 		{
 			MethodName: "MetricsForLabelMatchers",
 			Handler:    _Ingester_MetricsForLabelMatchers_Handler,
@@ -3619,7 +3935,7 @@ func (m *UsersStatsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *MetricsForLabelMatchersRequest) Marshal() (dAtA []byte, err error) {
+func (m *SeriesStatsRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -3629,29 +3945,143 @@ func (m *MetricsForLabelMatchersRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *MetricsForLabelMatchersRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *SeriesStatsRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *MetricsForLabelMatchersRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *SeriesStatsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.MatchersSet) > 0 {
-		for iNdEx := len(m.MatchersSet) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.MatchersSet[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintIngester(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x1a
-		}
+	return len(dAtA) - i, nil
+}
+
+func (m *SeriesStatsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SeriesStatsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SeriesStatsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.TopLabelValuePairs) > 0 {
+		for iNdEx := len(m.TopLabelValuePairs) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.TopLabelValuePairs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintIngester(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if len(m.TopMetricNames) > 0 {
+		for iNdEx := len(m.TopMetricNames) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.TopMetricNames[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintIngester(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.NumSeries != 0 {
+		i = encodeVarintIngester(dAtA, i, uint64(m.NumSeries))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *SeriesStatsEntry) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SeriesStatsEntry) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SeriesStatsEntry) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.NumSeries != 0 {
+		i = encodeVarintIngester(dAtA, i, uint64(m.NumSeries))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintIngester(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *MetricsForLabelMatchersRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MetricsForLabelMatchersRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MetricsForLabelMatchersRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.MatchersSet) > 0 {
+		for iNdEx := len(m.MatchersSet) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.MatchersSet[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintIngester(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
 	}
 	if m.EndTimestampMs != 0 {
 		i = encodeVarintIngester(dAtA, i, uint64(m.EndTimestampMs))
@@ -4290,6 +4720,55 @@ func (m *UsersStatsResponse) Size() (n int) {
 	return n
 }
 
+func (m *SeriesStatsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *SeriesStatsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.NumSeries != 0 {
+		n += 1 + sovIngester(uint64(m.NumSeries))
+	}
+	if len(m.TopMetricNames) > 0 {
+		for _, e := range m.TopMetricNames {
+			l = e.Size()
+			n += 1 + l + sovIngester(uint64(l))
+		}
+	}
+	if len(m.TopLabelValuePairs) > 0 {
+		for _, e := range m.TopLabelValuePairs {
+			l = e.Size()
+			n += 1 + l + sovIngester(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SeriesStatsEntry) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovIngester(uint64(l))
+	}
+	if m.NumSeries != 0 {
+		n += 1 + sovIngester(uint64(m.NumSeries))
+	}
+	return n
+}
+
 func (m *MetricsForLabelMatchersRequest) Size() (n int) {
 	if m == nil {
 		return 0
@@ -4683,6 +5162,48 @@ func (this *UsersStatsResponse) String() string {
 	}, "")
 	return s
 }
+func (this *SeriesStatsRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SeriesStatsRequest{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SeriesStatsResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForTopMetricNames := "[]*SeriesStatsEntry{"
+	for _, f := range this.TopMetricNames {
+		repeatedStringForTopMetricNames += strings.Replace(f.String(), "SeriesStatsEntry", "SeriesStatsEntry", 1) + ","
+	}
+	repeatedStringForTopMetricNames += "}"
+	repeatedStringForTopLabelValuePairs := "[]*SeriesStatsEntry{"
+	for _, f := range this.TopLabelValuePairs {
+		repeatedStringForTopLabelValuePairs += strings.Replace(f.String(), "SeriesStatsEntry", "SeriesStatsEntry", 1) + ","
+	}
+	repeatedStringForTopLabelValuePairs += "}"
+	s := strings.Join([]string{`&SeriesStatsResponse{`,
+		`NumSeries:` + fmt.Sprintf("%v", this.NumSeries) + `,`,
+		`TopMetricNames:` + repeatedStringForTopMetricNames + `,`,
+		`TopLabelValuePairs:` + repeatedStringForTopLabelValuePairs + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SeriesStatsEntry) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SeriesStatsEntry{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`NumSeries:` + fmt.Sprintf("%v", this.NumSeries) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *MetricsForLabelMatchersRequest) String() string {
 	if this == nil {
 		return "nil"
@@ -6332,6 +6853,303 @@ func (m *UsersStatsResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *SeriesStatsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowIngester
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SeriesStatsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SeriesStatsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipIngester(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SeriesStatsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowIngester
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SeriesStatsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SeriesStatsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NumSeries", wireType)
+			}
+			m.NumSeries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.NumSeries |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TopMetricNames", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthIngester
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TopMetricNames = append(m.TopMetricNames, &SeriesStatsEntry{})
+			if err := m.TopMetricNames[len(m.TopMetricNames)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TopLabelValuePairs", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthIngester
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TopLabelValuePairs = append(m.TopLabelValuePairs, &SeriesStatsEntry{})
+			if err := m.TopLabelValuePairs[len(m.TopLabelValuePairs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipIngester(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SeriesStatsEntry) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowIngester
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SeriesStatsEntry: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SeriesStatsEntry: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthIngester
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NumSeries", wireType)
+			}
+			m.NumSeries = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowIngester
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.NumSeries |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipIngester(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthIngester
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *MetricsForLabelMatchersRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -27,6 +28,8 @@ import (
 	"github.com/cortexproject/cortex/pkg/cortexpb"
 	"github.com/cortexproject/cortex/pkg/ingester/client"
 	"github.com/cortexproject/cortex/pkg/ring"
+	"github.com/cortexproject/cortex/pkg/ring/kv"
+	"github.com/cortexproject/cortex/pkg/ring/kv/consul"
 	"github.com/cortexproject/cortex/pkg/storage/tsdb"
 	"github.com/cortexproject/cortex/pkg/tenant"
 	"github.com/cortexproject/cortex/pkg/util"
@@ -47,6 +50,13 @@ const (
 
 	// Period at which to attempt purging metadata from memory.
 	metadataPurgePeriod = 5 * time.Minute
+
+	// Max number of metric names and label name/value pairs returned by SeriesStats,
+	// matching the number of entries computed by the TSDB head.
+	seriesStatsMaxEntries = 10
+
+	// How long the SeriesStats of a tenant are cached, matching the TSDB head.
+	seriesStatsCacheTTL = 30 * time.Second
 )
 
 var (
@@ -54,6 +64,8 @@ var (
 	recordPool sync.Pool
 
 	errIngesterStopping = errors.New("ingester stopping")
+
+	errOwnershipAwareLimitsValidation = errors.New("the ingester.ownership-aware-limits-enabled option is unsupported if distributor.shard-by-all-labels is disabled")
 )
 
 // Config for an Ingester.
@@ -101,6 +113,12 @@ type Config struct {
 
 	IgnoreSeriesLimitForMetricNames string `yaml:"ignore_series_limit_for_metric_names"`
 
+	OwnershipAwareLimitsEnabled bool `yaml:"ownership_aware_limits_enabled"`
+
+	ShardByAllLabelsGracePeriod time.Duration `yaml:"shard_by_all_labels_grace_period"`
+
+	MaintenanceLeaseTTL time.Duration `yaml:"maintenance_lease_ttl"`
+
 	// For testing, you can override the address and ID of this ingester.
 	ingesterClientFactory func(addr string, cfg client.Config) (client.HealthAndIngesterClient, error)
 }
@@ -136,6 +154,27 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.Int64Var(&cfg.DefaultLimits.MaxInflightPushRequests, "ingester.instance-limits.max-inflight-push-requests", 0, "Max inflight push requests that this ingester can handle (across all tenants). Additional requests will be rejected. 0 = unlimited.")
 
 	f.StringVar(&cfg.IgnoreSeriesLimitForMetricNames, "ingester.ignore-series-limit-for-metric-names", "", "Comma-separated list of metric names, for which -ingester.max-series-per-metric and -ingester.max-global-series-per-metric limits will be ignored. Does not affect max-series-per-user or max-global-series-per-metric limits.")
+	f.BoolVar(&cfg.OwnershipAwareLimitsEnabled, "ingester.ownership-aware-limits-enabled", false, "When enabled, global limits are converted to local limits based on the fraction of the tenant's token ranges owned by the ingester in the tenant's shard, including the ranges received because the write replication set is extended past instances which are not ACTIVE. The local limit is never lower than the one assuming an even distribution of series across ingesters. The ownership is recomputed when the ring topology or the instances state change. This option requires -distributor.shard-by-all-labels.")
+
+	f.DurationVar(&cfg.ShardByAllLabelsGracePeriod, "ingester.shard-by-all-labels-grace-period", 3*time.Hour, "How long after a tenant's shard_by_all_labels override is switched from disabled to enabled the ingester keeps applying the limits as if the tenant's series were sharded by metric name, so that the series previously sharded by metric name aren't counted against the lower per-ingester limits. It should cover the time the ingester keeps in memory the series which don't receive samples anymore. The switch is tracked in memory, so the grace period doesn't apply after an ingester restart. 0 to disable.")
+
+	f.DurationVar(&cfg.MaintenanceLeaseTTL, "ingester.maintenance-lease-ttl", 15*time.Minute, "How long the zone maintenance lease acquired via the /ingester/maintenance-lease endpoint is held, unless renewed or released.")
+}
+
+// Validate the config. The shardByAllLabels is whether series are sharded by all labels,
+// either for all tenants or by default through the limits.
+func (cfg *Config) Validate(shardByAllLabels bool) error {
+	if err := cfg.LifecyclerConfig.Validate(); err != nil {
+		return err
+	}
+
+	// Converting global limits based on the ownership assumes series are evenly
+	// distributed across the token ranges, which requires sharding by all labels.
+	if cfg.OwnershipAwareLimitsEnabled && !shardByAllLabels {
+		return errOwnershipAwareLimitsValidation
+	}
+
+	return nil
 }
 
 func (cfg *Config) getIgnoreSeriesLimitForMetricNamesMap() map[string]struct{} {
@@ -172,6 +211,7 @@ type Ingester struct {
 
 	chunkStore         ChunkStore
 	lifecycler         *ring.Lifecycler
+	maintenanceLease   *ring.MaintenanceLeaseClient
 	limits             *validation.Overrides
 	limiter            *Limiter
 	subservicesWatcher *services.FailureWatcher
@@ -270,12 +310,18 @@ func New(cfg Config, clientConfig client.Config, limits *validation.Overrides, c
 	if err != nil {
 		return nil, err
 	}
+	i.maintenanceLease, err = newMaintenanceLeaseClient(cfg, i.lifecycler, registerer)
+	if err != nil {
+		return nil, err
+	}
 
 	i.limiter = NewLimiter(
 		limits,
 		i.lifecycler,
+		ringOwnership(cfg, i.lifecycler),
 		cfg.DistributorShardingStrategy,
 		cfg.DistributorShardByAllLabels,
+		cfg.ShardByAllLabelsGracePeriod,
 		cfg.LifecyclerConfig.RingConfig.ReplicationFactor,
 		cfg.LifecyclerConfig.RingConfig.ZoneAwarenessEnabled)
 
@@ -462,6 +508,120 @@ func (i *Ingester) ShutdownHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// ReadOnlyHandler sets (POST) or unsets (DELETE) the ingester READONLY state in the ring, and returns
+// the current state (GET). A READONLY ingester doesn't receive writes anymore, but it's still queried.
+func (i *Ingester) ReadOnlyHandler(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	// Requesting the state the ingester is already in is a no-op, so that the calls can be safely retried.
+	switch r.Method {
+	case http.MethodPost:
+		if i.lifecycler.GetState() != ring.READONLY {
+			err = i.lifecycler.ChangeState(r.Context(), ring.READONLY)
+		}
+	case http.MethodDelete:
+		if i.lifecycler.GetState() != ring.ACTIVE {
+			err = i.lifecycler.ChangeState(r.Context(), ring.ACTIVE)
+		}
+	}
+
+	if err != nil {
+		level.Warn(i.logger).Log("msg", "failed to change the ingester read-only state", "err", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	state := i.lifecycler.GetState()
+	util.WriteJSONResponse(w, readOnlyResponse{
+		State:    state.String(),
+		ReadOnly: state == ring.READONLY,
+	})
+}
+
+type readOnlyResponse struct {
+	State    string `json:"state"`
+	ReadOnly bool   `json:"read_only"`
+}
+
+// MaintenanceLeaseHandler acquires (POST) or releases (DELETE) the maintenance lease on behalf of the ingester
+// zone, and returns the current lease (GET). It's meant to be called by the rollout tooling before
+// restarting the ingester, so that ingesters of different zones are never restarted concurrently.
+func (i *Ingester) MaintenanceLeaseHandler(w http.ResponseWriter, r *http.Request) {
+	var err error
+
+	switch r.Method {
+	case http.MethodPost:
+		_, err = i.maintenanceLease.Acquire(r.Context(), i.lifecycler.Zone, i.lifecycler.ID, i.cfg.MaintenanceLeaseTTL)
+	case http.MethodDelete:
+		err = i.maintenanceLease.Release(r.Context(), i.lifecycler.Zone, i.lifecycler.ID)
+	}
+
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, ring.ErrMaintenanceLeaseHeld) || errors.Is(err, ring.ErrMaintenanceNotAllowed) {
+			status = http.StatusConflict
+		}
+
+		level.Warn(i.logger).Log("msg", "failed to change the maintenance lease", "err", err)
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	lease, err := i.maintenanceLease.Get(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	res := maintenanceLeaseResponse{Held: lease != nil}
+	if lease != nil {
+		res.Zone = lease.Zone
+		res.Holder = lease.Holder
+		res.Holders = lease.Holders
+		acquiredAt, expiresAt := time.Unix(lease.AcquiredAt, 0).UTC(), time.Unix(lease.ExpiresAt, 0).UTC()
+		res.AcquiredAt = &acquiredAt
+		res.ExpiresAt = &expiresAt
+	}
+	util.WriteJSONResponse(w, res)
+}
+
+type maintenanceLeaseResponse struct {
+	Held       bool       `json:"held"`
+	Zone       string     `json:"zone,omitempty"`
+	Holder     string     `json:"holder,omitempty"`
+	Holders    []string   `json:"holders,omitempty"`
+	AcquiredAt *time.Time `json:"acquired_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+}
+
+// The in-memory KV store returned by kv.NewClient() is a singleton using the codec of the first client, so
+// the maintenance lease is stored in a dedicated in-memory KV store, shared by the ingesters of the process.
+var (
+	inmemoryMaintenanceLeaseStoreInit sync.Once
+	inmemoryMaintenanceLeaseStore     kv.Client
+)
+
+func newMaintenanceLeaseClient(cfg Config, lifecycler *ring.Lifecycler, registerer prometheus.Registerer) (*ring.MaintenanceLeaseClient, error) {
+	kvCfg := cfg.LifecyclerConfig.RingConfig.KVStore
+	if kvCfg.Store == "inmemory" && kvCfg.Mock == nil {
+		inmemoryMaintenanceLeaseStoreInit.Do(func() {
+			inmemoryMaintenanceLeaseStore = consul.NewInMemoryClient(ring.GetMaintenanceLeaseCodec())
+		})
+		kvCfg.Mock = inmemoryMaintenanceLeaseStore
+	}
+
+	leaseKV, err := kv.NewClient(
+		kvCfg,
+		ring.GetMaintenanceLeaseCodec(),
+		kv.RegistererWithKVName(registerer, "ingester-maintenance-lease"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ring.NewMaintenanceLeaseClient(leaseKV, lifecycler.KVStore, ring.IngesterRingKey, cfg.LifecyclerConfig.RingConfig.HeartbeatTimeout), nil
+}
+
 // stopIncomingRequests is called during the shutdown process.
 func (i *Ingester) stopIncomingRequests() {
 	i.userStatesMtx.Lock()
@@ -1106,6 +1266,50 @@ func (i *Ingester) AllUserStats(ctx context.Context, req *client.UserStatsReques
 	return response, nil
 }
 
+// SeriesStats returns the number of series of the tenant, along with the metric names
+// and label name/value pairs with the highest number of series.
+func (i *Ingester) SeriesStats(ctx context.Context, req *client.SeriesStatsRequest) (*client.SeriesStatsResponse, error) {
+	if err := i.checkRunningOrStopping(); err != nil {
+		return nil, err
+	}
+
+	if i.cfg.BlocksStorageEnabled {
+		return i.v2SeriesStats(ctx, req)
+	}
+
+	i.userStatesMtx.RLock()
+	state, ok, err := i.userStates.getViaContext(ctx)
+	i.userStatesMtx.RUnlock()
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return &client.SeriesStatsResponse{}, nil
+	}
+
+	return state.getSeriesStats(), nil
+}
+
+// topSeriesStatsEntries returns the limit entries with the highest number of series,
+// sorted by number of series in descending order.
+func topSeriesStatsEntries(numSeries map[string]uint64, limit int) []*client.SeriesStatsEntry {
+	entries := make([]*client.SeriesStatsEntry, 0, len(numSeries))
+	for name, n := range numSeries {
+		entries = append(entries, &client.SeriesStatsEntry{Name: name, NumSeries: n})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].NumSeries != entries[j].NumSeries {
+			return entries[i].NumSeries > entries[j].NumSeries
+		}
+		return entries[i].Name < entries[j].Name
+	})
+
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
+}
+
 // CheckReady is the readiness handler used to indicate to k8s when the ingesters
 // are ready for the addition or removal of another ingester.
 func (i *Ingester) CheckReady(ctx context.Context) error {
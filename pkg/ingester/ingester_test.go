@@ -233,6 +233,39 @@ func retrieveTestSamples(t *testing.T, ing *Ingester, userIDs []string, testData
 	}
 }
 
+func TestConfig_Validate(t *testing.T) {
+	tests := map[string]struct {
+		ownershipAwareLimitsEnabled bool
+		shardByAllLabels            bool
+		expected                    error
+	}{
+		"should pass with ownership-aware limits disabled": {
+			ownershipAwareLimitsEnabled: false,
+			shardByAllLabels:            false,
+		},
+		"should pass with ownership-aware limits enabled and shard by all labels": {
+			ownershipAwareLimitsEnabled: true,
+			shardByAllLabels:            true,
+		},
+		"should fail with ownership-aware limits enabled and shard by all labels disabled": {
+			ownershipAwareLimitsEnabled: true,
+			shardByAllLabels:            false,
+			expected:                    errOwnershipAwareLimitsValidation,
+		},
+	}
+
+	for testName, testData := range tests {
+		testData := testData
+
+		t.Run(testName, func(t *testing.T) {
+			cfg := defaultIngesterTestConfig()
+			cfg.OwnershipAwareLimitsEnabled = testData.ownershipAwareLimitsEnabled
+
+			assert.Equal(t, testData.expected, cfg.Validate(testData.shardByAllLabels))
+		})
+	}
+}
+
 func TestIngesterAppend(t *testing.T) {
 	store, ing := newDefaultTestStore(t)
 	userIDs, testData := pushTestSamples(t, ing, 10, 1000, 0)
@@ -1045,6 +1078,62 @@ func TestIngesterActiveSeries(t *testing.T) {
 	}
 }
 
+func TestIngesterSeriesStats(t *testing.T) {
+	_, ing := newDefaultTestStore(t)
+	defer services.StopAndAwaitTerminated(context.Background(), ing) //nolint:errcheck
+
+	ctx := user.InjectOrgID(context.Background(), userID)
+
+	// A tenant without series has no stats.
+	res, err := ing.SeriesStats(ctx, &client.SeriesStatsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, &client.SeriesStatsResponse{}, res)
+
+	for _, lp := range []labelPairs{
+		{{Name: model.MetricNameLabel, Value: "test_1"}, {Name: "status", Value: "200"}, {Name: "route", Value: "get_user"}},
+		{{Name: model.MetricNameLabel, Value: "test_1"}, {Name: "status", Value: "500"}, {Name: "route", Value: "get_user"}},
+		{{Name: model.MetricNameLabel, Value: "test_2"}, {Name: "status", Value: "200"}},
+	} {
+		require.NoError(t, ing.append(ctx, userID, lp, 1, 0, cortexpb.API, nil))
+	}
+
+	res, err = ing.SeriesStats(ctx, &client.SeriesStatsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, &client.SeriesStatsResponse{
+		NumSeries: 3,
+		TopMetricNames: []*client.SeriesStatsEntry{
+			{Name: "test_1", NumSeries: 2},
+			{Name: "test_2", NumSeries: 1},
+		},
+		TopLabelValuePairs: []*client.SeriesStatsEntry{
+			{Name: "__name__=test_1", NumSeries: 2},
+			{Name: "route=get_user", NumSeries: 2},
+			{Name: "status=200", NumSeries: 2},
+			{Name: "__name__=test_2", NumSeries: 1},
+			{Name: "status=500", NumSeries: 1},
+		},
+	}, res)
+
+	// The stats are cached.
+	require.NoError(t, ing.append(ctx, userID, labelPairs{{Name: model.MetricNameLabel, Value: "test_3"}}, 1, 0, cortexpb.API, nil))
+
+	cached, err := ing.SeriesStats(ctx, &client.SeriesStatsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, res, cached)
+
+	// The stats are computed again once the cache is expired.
+	state, ok := ing.userStates.get(userID)
+	require.True(t, ok)
+	state.seriesStatsMtx.Lock()
+	state.seriesStatsAt = time.Now().Add(-seriesStatsCacheTTL)
+	state.seriesStatsMtx.Unlock()
+
+	res, err = ing.SeriesStats(ctx, &client.SeriesStatsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), res.NumSeries)
+	assert.Contains(t, res.TopMetricNames, &client.SeriesStatsEntry{Name: "test_3", NumSeries: 1})
+}
+
 func TestGetIgnoreSeriesLimitForMetricNamesMap(t *testing.T) {
 	cfg := Config{}
 
@@ -23,6 +23,7 @@ import (
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/tsdb"
 	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/prometheus/prometheus/tsdb/index"
 	"github.com/thanos-io/thanos/pkg/block/metadata"
 	"github.com/thanos-io/thanos/pkg/objstore"
 	"github.com/thanos-io/thanos/pkg/shipper"
@@ -515,6 +516,10 @@ func NewV2(cfg Config, clientConfig client.Config, limits *validation.Overrides,
 	if err != nil {
 		return nil, err
 	}
+	i.maintenanceLease, err = newMaintenanceLeaseClient(cfg, i.lifecycler, registerer)
+	if err != nil {
+		return nil, err
+	}
 	i.subservicesWatcher = services.NewFailureWatcher()
 	i.subservicesWatcher.WatchService(i.lifecycler)
 
@@ -522,8 +527,10 @@ func NewV2(cfg Config, clientConfig client.Config, limits *validation.Overrides,
 	i.limiter = NewLimiter(
 		limits,
 		i.lifecycler,
+		ringOwnership(cfg, i.lifecycler),
 		cfg.DistributorShardingStrategy,
 		cfg.DistributorShardByAllLabels,
+		cfg.ShardByAllLabelsGracePeriod,
 		cfg.LifecyclerConfig.RingConfig.ReplicationFactor,
 		cfg.LifecyclerConfig.RingConfig.ZoneAwarenessEnabled)
 
@@ -1250,6 +1257,35 @@ func (i *Ingester) v2AllUserStats(ctx context.Context, req *client.UserStatsRequ
 	return response, nil
 }
 
+func (i *Ingester) v2SeriesStats(ctx context.Context, req *client.SeriesStatsRequest) (*client.SeriesStatsResponse, error) {
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := i.getTSDB(userID)
+	if db == nil {
+		return &client.SeriesStatsResponse{}, nil
+	}
+
+	// The TSDB head computes the top 10 entries, caching them for a short period of time.
+	stats := db.Head().PostingsCardinalityStats(labels.MetricName)
+
+	return &client.SeriesStatsResponse{
+		NumSeries:          db.Head().NumSeries(),
+		TopMetricNames:     toSeriesStatsEntries(stats.CardinalityMetricsStats),
+		TopLabelValuePairs: toSeriesStatsEntries(stats.LabelValuePairsStats),
+	}, nil
+}
+
+func toSeriesStatsEntries(stats []index.Stat) []*client.SeriesStatsEntry {
+	entries := make([]*client.SeriesStatsEntry, 0, len(stats))
+	for _, s := range stats {
+		entries = append(entries, &client.SeriesStatsEntry{Name: s.Name, NumSeries: s.Count})
+	}
+	return entries
+}
+
 func createUserStats(db *userTSDB) *client.UserStatsResponse {
 	apiRate := db.ingestedAPISamples.Rate()
 	ruleRate := db.ingestedRuleSamples.Rate()
@@ -2065,6 +2101,9 @@ func (i *Ingester) closeAndDeleteUserTSDBIfIdle(userID string) tsdbCloseCheckRes
 
 	i.deleteUserMetadata(userID)
 	i.metrics.deletePerUserMetrics(userID)
+	if i.limiter != nil {
+		i.limiter.RemoveUser(userID)
+	}
 
 	validation.DeletePerUserValidationMetrics(userID, i.logger)
 
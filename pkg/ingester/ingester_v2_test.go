@@ -3070,6 +3070,55 @@ func Test_Ingester_v2AllUserStats(t *testing.T) {
 	assert.ElementsMatch(t, expect, res.Stats)
 }
 
+func Test_Ingester_v2SeriesStats(t *testing.T) {
+	series := []labels.Labels{
+		{{Name: labels.MetricName, Value: "test_1"}, {Name: "status", Value: "200"}, {Name: "route", Value: "get_user"}},
+		{{Name: labels.MetricName, Value: "test_1"}, {Name: "status", Value: "500"}, {Name: "route", Value: "get_user"}},
+		{{Name: labels.MetricName, Value: "test_2"}, {Name: "status", Value: "200"}},
+	}
+
+	// Create ingester
+	i, err := prepareIngesterWithBlocksStorage(t, defaultIngesterTestConfig(), nil)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), i))
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+
+	// Wait until it's ACTIVE
+	test.Poll(t, 1*time.Second, ring.ACTIVE, func() interface{} {
+		return i.lifecycler.GetState()
+	})
+
+	// A tenant without series has no stats.
+	res, err := i.SeriesStats(user.InjectOrgID(context.Background(), "test"), &client.SeriesStatsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, &client.SeriesStatsResponse{}, res)
+
+	// Push series
+	ctx := user.InjectOrgID(context.Background(), "test")
+
+	for _, lbls := range series {
+		req, _, _, _ := mockWriteRequest(t, lbls, 1, 100000)
+		_, err := i.v2Push(ctx, req)
+		require.NoError(t, err)
+	}
+
+	res, err = i.SeriesStats(ctx, &client.SeriesStatsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), res.NumSeries)
+	assert.Equal(t, []*client.SeriesStatsEntry{
+		{Name: "test_1", NumSeries: 2},
+		{Name: "test_2", NumSeries: 1},
+	}, res.TopMetricNames)
+	// The TSDB head doesn't guarantee the order of the entries with the same number of series.
+	assert.ElementsMatch(t, []*client.SeriesStatsEntry{
+		{Name: "__name__=test_1", NumSeries: 2},
+		{Name: "route=get_user", NumSeries: 2},
+		{Name: "status=200", NumSeries: 2},
+		{Name: "__name__=test_2", NumSeries: 1},
+		{Name: "status=500", NumSeries: 1},
+	}, res.TopLabelValuePairs)
+}
+
 func TestIngesterCompactIdleBlock(t *testing.T) {
 	cfg := defaultIngesterTestConfig()
 	cfg.LifecyclerConfig.JoinAfter = 0
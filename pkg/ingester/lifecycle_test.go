@@ -23,6 +23,7 @@ import (
 	"github.com/cortexproject/cortex/pkg/cortexpb"
 	"github.com/cortexproject/cortex/pkg/ingester/client"
 	"github.com/cortexproject/cortex/pkg/ring"
+	"github.com/cortexproject/cortex/pkg/ring/kv"
 	"github.com/cortexproject/cortex/pkg/ring/kv/consul"
 	"github.com/cortexproject/cortex/pkg/ring/testutils"
 	"github.com/cortexproject/cortex/pkg/util/flagext"
@@ -122,6 +123,115 @@ func TestIngester_ShutdownHandler(t *testing.T) {
 	}
 }
 
+func TestIngester_ReadOnlyHandler(t *testing.T) {
+	config := defaultIngesterTestConfig()
+	clientConfig := defaultClientTestConfig()
+	limits := defaultLimitsTestConfig()
+	_, ingester := newTestStore(t, config, clientConfig, limits, nil)
+
+	test.Poll(t, time.Second, ring.ACTIVE, func() interface{} {
+		return ingester.lifecycler.GetState()
+	})
+
+	getState := func() interface{} {
+		d, err := config.LifecyclerConfig.RingConfig.KVStore.Mock.Get(context.Background(), ring.IngesterRingKey)
+		require.NoError(t, err)
+		return d.(*ring.Desc).Ingesters["localhost"].State
+	}
+
+	recorder := httptest.NewRecorder()
+	ingester.ReadOnlyHandler(recorder, httptest.NewRequest(http.MethodPost, "/ingester/readonly", nil))
+	require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+	assert.JSONEq(t, `{"state":"READONLY","read_only":true}`, recorder.Body.String())
+	test.Poll(t, time.Second, ring.READONLY, getState)
+
+	// Setting the read-only state twice is a no-op.
+	recorder = httptest.NewRecorder()
+	ingester.ReadOnlyHandler(recorder, httptest.NewRequest(http.MethodPost, "/ingester/readonly", nil))
+	require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+	assert.JSONEq(t, `{"state":"READONLY","read_only":true}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	ingester.ReadOnlyHandler(recorder, httptest.NewRequest(http.MethodGet, "/ingester/readonly", nil))
+	require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+	assert.JSONEq(t, `{"state":"READONLY","read_only":true}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	ingester.ReadOnlyHandler(recorder, httptest.NewRequest(http.MethodDelete, "/ingester/readonly", nil))
+	require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+	assert.JSONEq(t, `{"state":"ACTIVE","read_only":false}`, recorder.Body.String())
+	test.Poll(t, time.Second, ring.ACTIVE, getState)
+
+	// Leaving the read-only state twice is a no-op too.
+	recorder = httptest.NewRecorder()
+	ingester.ReadOnlyHandler(recorder, httptest.NewRequest(http.MethodDelete, "/ingester/readonly", nil))
+	require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+	assert.JSONEq(t, `{"state":"ACTIVE","read_only":false}`, recorder.Body.String())
+}
+
+func TestIngester_MaintenanceLeaseHandler(t *testing.T) {
+	config := defaultIngesterTestConfig()
+	config.LifecyclerConfig.Zone = "zone-a"
+	clientConfig := defaultClientTestConfig()
+	limits := defaultLimitsTestConfig()
+	_, ingester := newTestStore(t, config, clientConfig, limits, nil)
+
+	test.Poll(t, time.Second, ring.ACTIVE, func() interface{} {
+		return ingester.lifecycler.GetState()
+	})
+
+	// The lease is stored in a dedicated in-memory KV store, because the mocked one uses the ring codec.
+	lease := ring.NewMaintenanceLeaseClient(consul.NewInMemoryClient(ring.GetMaintenanceLeaseCodec()), ingester.lifecycler.KVStore, ring.IngesterRingKey, time.Minute)
+	ingester.maintenanceLease = lease
+
+	recorder := httptest.NewRecorder()
+	ingester.MaintenanceLeaseHandler(recorder, httptest.NewRequest(http.MethodGet, "/ingester/maintenance-lease", nil))
+	require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+	assert.JSONEq(t, `{"held":false}`, recorder.Body.String())
+
+	recorder = httptest.NewRecorder()
+	ingester.MaintenanceLeaseHandler(recorder, httptest.NewRequest(http.MethodPost, "/ingester/maintenance-lease", nil))
+	require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+	assert.Contains(t, recorder.Body.String(), `"held":true,"zone":"zone-a","holder":"localhost"`)
+
+	recorder = httptest.NewRecorder()
+	ingester.MaintenanceLeaseHandler(recorder, httptest.NewRequest(http.MethodDelete, "/ingester/maintenance-lease", nil))
+	require.Equal(t, http.StatusOK, recorder.Result().StatusCode)
+	assert.JSONEq(t, `{"held":false}`, recorder.Body.String())
+
+	// The lease can't be acquired while held by another zone.
+	_, err := lease.Acquire(context.Background(), "zone-b", "ingester-zone-b-0", time.Minute)
+	require.NoError(t, err)
+
+	recorder = httptest.NewRecorder()
+	ingester.MaintenanceLeaseHandler(recorder, httptest.NewRequest(http.MethodPost, "/ingester/maintenance-lease", nil))
+	require.Equal(t, http.StatusConflict, recorder.Result().StatusCode)
+}
+
+func TestIngester_MaintenanceLeaseWithInMemoryStore(t *testing.T) {
+	config := defaultIngesterTestConfig()
+	config.LifecyclerConfig.Zone = "zone-a"
+	clientConfig := defaultClientTestConfig()
+	limits := defaultLimitsTestConfig()
+	_, ingester := newTestStore(t, config, clientConfig, limits, nil)
+
+	test.Poll(t, time.Second, ring.ACTIVE, func() interface{} {
+		return ingester.lifecycler.GetState()
+	})
+
+	// The lease must not be stored in the in-memory KV store of the ring, which uses the ring codec.
+	config.LifecyclerConfig.RingConfig.KVStore = kv.Config{Store: "inmemory"}
+	lease, err := newMaintenanceLeaseClient(config, ingester.lifecycler, nil)
+	require.NoError(t, err)
+
+	acquired, err := lease.Acquire(context.Background(), "zone-a", "localhost", time.Minute)
+	require.NoError(t, err)
+
+	current, err := lease.Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, acquired, current)
+}
+
 func TestIngesterChunksTransfer(t *testing.T) {
 	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
 	require.NoError(t, err)
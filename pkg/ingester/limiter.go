@@ -3,9 +3,12 @@ package ingester
 import (
 	"fmt"
 	"math"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 
+	"github.com/cortexproject/cortex/pkg/ring"
 	"github.com/cortexproject/cortex/pkg/util"
 	util_math "github.com/cortexproject/cortex/pkg/util/math"
 	"github.com/cortexproject/cortex/pkg/util/validation"
@@ -25,33 +28,61 @@ type RingCount interface {
 	ZonesCount() int
 }
 
+// RingOwnership is the interface exposed by a ring implementation which allows
+// to get the fraction of a tenant's series the ingester is expected to receive.
+type RingOwnership interface {
+	InstanceOwnership(identifier string, shardSize int) float64
+}
+
+// ringOwnership returns the RingOwnership used by the limiter, or nil
+// if ownership-aware limits are disabled.
+func ringOwnership(cfg Config, lifecycler *ring.Lifecycler) RingOwnership {
+	if !cfg.OwnershipAwareLimitsEnabled {
+		return nil
+	}
+	return lifecycler
+}
+
 // Limiter implements primitives to get the maximum number of series
 // an ingester can handle for a specific tenant
 type Limiter struct {
 	limits                 *validation.Overrides
 	ring                   RingCount
+	ownership              RingOwnership
 	replicationFactor      int
 	shuffleShardingEnabled bool
 	shardByAllLabels       bool
 	zoneAwarenessEnabled   bool
+
+	// How long the global limits are not converted to local limits after the tenant's
+	// shard_by_all_labels override is switched to enabled, and when it has been switched
+	// for the tenants whose override has been seen disabled.
+	shardByAllLabelsGracePeriod time.Duration
+	shardByAllLabelsMtx         sync.Mutex
+	shardByAllLabelsSince       map[string]time.Time
 }
 
 // NewLimiter makes a new in-memory series limiter
 func NewLimiter(
 	limits *validation.Overrides,
 	ring RingCount,
+	ownership RingOwnership,
 	shardingStrategy string,
 	shardByAllLabels bool,
+	shardByAllLabelsGracePeriod time.Duration,
 	replicationFactor int,
 	zoneAwarenessEnabled bool,
 ) *Limiter {
 	return &Limiter{
-		limits:                 limits,
-		ring:                   ring,
-		replicationFactor:      replicationFactor,
-		shuffleShardingEnabled: shardingStrategy == util.ShardingStrategyShuffle,
-		shardByAllLabels:       shardByAllLabels,
-		zoneAwarenessEnabled:   zoneAwarenessEnabled,
+		limits:                      limits,
+		ring:                        ring,
+		ownership:                   ownership,
+		replicationFactor:           replicationFactor,
+		shuffleShardingEnabled:      shardingStrategy == util.ShardingStrategyShuffle,
+		shardByAllLabels:            shardByAllLabels,
+		zoneAwarenessEnabled:        zoneAwarenessEnabled,
+		shardByAllLabelsGracePeriod: shardByAllLabelsGracePeriod,
+		shardByAllLabelsSince:       map[string]time.Time{},
 	}
 }
 
@@ -158,7 +189,7 @@ func (l *Limiter) maxSeriesPerMetric(userID string) int {
 	globalLimit := l.limits.MaxGlobalSeriesPerMetric(userID)
 
 	if globalLimit > 0 {
-		if l.shardByAllLabels {
+		if l.shardByAllLabelsEnabled(userID) {
 			// We can assume that series are evenly distributed across ingesters
 			// so we do convert the global limit into a local limit
 			localLimit = minNonZero(localLimit, l.convertGlobalToLocalLimit(userID, globalLimit))
@@ -184,7 +215,7 @@ func (l *Limiter) maxMetadataPerMetric(userID string) int {
 	globalLimit := l.limits.MaxGlobalMetadataPerMetric(userID)
 
 	if globalLimit > 0 {
-		if l.shardByAllLabels {
+		if l.shardByAllLabelsEnabled(userID) {
 			localLimit = minNonZero(localLimit, l.convertGlobalToLocalLimit(userID, globalLimit))
 		} else {
 			localLimit = minNonZero(localLimit, globalLimit)
@@ -220,7 +251,7 @@ func (l *Limiter) maxByLocalAndGlobal(userID string, localLimitFn, globalLimitFn
 	// The global limit is supported only when shard-by-all-labels is enabled,
 	// otherwise we wouldn't get an even split of series/metadata across ingesters and
 	// can't take a "local decision" without any centralized coordination.
-	if l.shardByAllLabels {
+	if l.shardByAllLabelsEnabled(userID) {
 		// We can assume that series/metadata are evenly distributed across ingesters
 		// so we do convert the global limit into a local limit
 		globalLimit := globalLimitFn(userID)
@@ -261,7 +292,71 @@ func (l *Limiter) convertGlobalToLocalLimit(userID string, globalLimit int) int
 		numIngesters = util_math.Min(numIngesters, util.ShuffleShardExpectedInstances(shardSize, l.getNumZones()))
 	}
 
-	return int((float64(globalLimit) / float64(numIngesters)) * float64(l.replicationFactor))
+	localLimit := int((float64(globalLimit) / float64(numIngesters)) * float64(l.replicationFactor))
+
+	// If enabled, we use the fraction of the tenant's series this ingester is
+	// expected to receive, based on the token ranges it owns in the tenant's shard.
+	// The ownership is 0 if the ingester doesn't own any token in the shard (ie.
+	// while joining the ring). The ownership is only updated at every heartbeat,
+	// so to keep erring in favor of the tenant (ie. while the ring changes during
+	// a rollout) we never go below the limit assuming an even distribution.
+	if l.ownership != nil {
+		if owned := l.ownership.InstanceOwnership(userID, l.getShardSize(userID)); owned > 0 {
+			localLimit = util_math.Max(localLimit, int(float64(globalLimit)*owned))
+		}
+	}
+
+	return localLimit
+}
+
+// shardByAllLabelsEnabled returns whether the tenant's series are sharded by all labels,
+// either because enabled for all tenants or through the tenant's overrides. When the
+// override is switched from disabled to enabled, the ingester still holds the series
+// previously sharded by metric name (eg. many series of the same metric), so it's
+// considered enabled only once the grace period has elapsed since the switch.
+func (l *Limiter) shardByAllLabelsEnabled(userID string) bool {
+	if l.shardByAllLabels {
+		return true
+	}
+
+	enabled := l.limits.ShardByAllLabels(userID)
+	if l.shardByAllLabelsGracePeriod <= 0 {
+		return enabled
+	}
+
+	l.shardByAllLabelsMtx.Lock()
+	defer l.shardByAllLabelsMtx.Unlock()
+
+	// A zero time means the override has been seen disabled.
+	switchedAt, seen := l.shardByAllLabelsSince[userID]
+	if !enabled {
+		l.shardByAllLabelsSince[userID] = time.Time{}
+		return false
+	}
+	if !seen {
+		// The override was already enabled when the ingester started.
+		return true
+	}
+
+	if switchedAt.IsZero() {
+		switchedAt = time.Now()
+		l.shardByAllLabelsSince[userID] = switchedAt
+	}
+	if time.Since(switchedAt) < l.shardByAllLabelsGracePeriod {
+		return false
+	}
+
+	delete(l.shardByAllLabelsSince, userID)
+	return true
+}
+
+// RemoveUser forgets the shard-by-all-labels override state of the tenant, once its series have been removed
+// from the ingester: new series are sharded according to the current override, so no grace period is needed.
+func (l *Limiter) RemoveUser(userID string) {
+	l.shardByAllLabelsMtx.Lock()
+	defer l.shardByAllLabelsMtx.Unlock()
+
+	delete(l.shardByAllLabelsSince, userID)
 }
 
 func (l *Limiter) getShardSize(userID string) int {
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"math"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -223,12 +224,12 @@ func runLimiterMaxFunctionTest(
 			require.NoError(t, err)
 
 			// Assert on default sharding strategy.
-			limiter := NewLimiter(overrides, ring, util.ShardingStrategyDefault, testData.shardByAllLabels, testData.ringReplicationFactor, testData.ringZoneAwarenessEnabled)
+			limiter := NewLimiter(overrides, ring, nil, util.ShardingStrategyDefault, testData.shardByAllLabels, 0, testData.ringReplicationFactor, testData.ringZoneAwarenessEnabled)
 			actual := runMaxFn(limiter)
 			assert.Equal(t, testData.expectedDefaultSharding, actual)
 
 			// Assert on shuffle sharding strategy.
-			limiter = NewLimiter(overrides, ring, util.ShardingStrategyShuffle, testData.shardByAllLabels, testData.ringReplicationFactor, testData.ringZoneAwarenessEnabled)
+			limiter = NewLimiter(overrides, ring, nil, util.ShardingStrategyShuffle, testData.shardByAllLabels, 0, testData.ringReplicationFactor, testData.ringZoneAwarenessEnabled)
 			actual = runMaxFn(limiter)
 			assert.Equal(t, testData.expectedShuffleSharding, actual)
 		})
@@ -290,7 +291,7 @@ func TestLimiter_AssertMaxSeriesPerMetric(t *testing.T) {
 			}, nil)
 			require.NoError(t, err)
 
-			limiter := NewLimiter(limits, ring, util.ShardingStrategyDefault, testData.shardByAllLabels, testData.ringReplicationFactor, false)
+			limiter := NewLimiter(limits, ring, nil, util.ShardingStrategyDefault, testData.shardByAllLabels, 0, testData.ringReplicationFactor, false)
 			actual := limiter.AssertMaxSeriesPerMetric("test", testData.series)
 
 			assert.Equal(t, testData.expected, actual)
@@ -352,7 +353,7 @@ func TestLimiter_AssertMaxMetadataPerMetric(t *testing.T) {
 			}, nil)
 			require.NoError(t, err)
 
-			limiter := NewLimiter(limits, ring, util.ShardingStrategyDefault, testData.shardByAllLabels, testData.ringReplicationFactor, false)
+			limiter := NewLimiter(limits, ring, nil, util.ShardingStrategyDefault, testData.shardByAllLabels, 0, testData.ringReplicationFactor, false)
 			actual := limiter.AssertMaxMetadataPerMetric("test", testData.metadata)
 
 			assert.Equal(t, testData.expected, actual)
@@ -415,7 +416,7 @@ func TestLimiter_AssertMaxSeriesPerUser(t *testing.T) {
 			}, nil)
 			require.NoError(t, err)
 
-			limiter := NewLimiter(limits, ring, util.ShardingStrategyDefault, testData.shardByAllLabels, testData.ringReplicationFactor, false)
+			limiter := NewLimiter(limits, ring, nil, util.ShardingStrategyDefault, testData.shardByAllLabels, 0, testData.ringReplicationFactor, false)
 			actual := limiter.AssertMaxSeriesPerUser("test", testData.series)
 
 			assert.Equal(t, testData.expected, actual)
@@ -478,7 +479,7 @@ func TestLimiter_AssertMaxMetricsWithMetadataPerUser(t *testing.T) {
 			}, nil)
 			require.NoError(t, err)
 
-			limiter := NewLimiter(limits, ring, util.ShardingStrategyDefault, testData.shardByAllLabels, testData.ringReplicationFactor, false)
+			limiter := NewLimiter(limits, ring, nil, util.ShardingStrategyDefault, testData.shardByAllLabels, 0, testData.ringReplicationFactor, false)
 			actual := limiter.AssertMaxMetricsWithMetadataPerUser("test", testData.metadata)
 
 			assert.Equal(t, testData.expected, actual)
@@ -501,7 +502,7 @@ func TestLimiter_FormatError(t *testing.T) {
 	}, nil)
 	require.NoError(t, err)
 
-	limiter := NewLimiter(limits, ring, util.ShardingStrategyDefault, true, 3, false)
+	limiter := NewLimiter(limits, ring, nil, util.ShardingStrategyDefault, true, 0, 3, false)
 
 	actual := limiter.FormatError("user-1", errMaxSeriesPerUserLimitExceeded)
 	assert.EqualError(t, actual, "per-user series limit of 100 exceeded, please contact administrator to raise it (local limit: 0 global limit: 100 actual local limit: 100)")
@@ -520,6 +521,141 @@ func TestLimiter_FormatError(t *testing.T) {
 	assert.Equal(t, input, actual)
 }
 
+func TestLimiter_OwnershipAwareLimits(t *testing.T) {
+	tests := map[string]struct {
+		shardingStrategy  string
+		shardSize         int
+		ownership         float64
+		expectedShardSize int
+		expected          int
+	}{
+		"should use the ownership if known": {
+			shardingStrategy:  util.ShardingStrategyDefault,
+			ownership:         0.45,
+			expectedShardSize: 0,
+			expected:          450,
+		},
+		"should not use a lower limit than the even distribution": {
+			shardingStrategy:  util.ShardingStrategyDefault,
+			ownership:         0.25,
+			expectedShardSize: 0,
+			expected:          300, // (1000 / 10) * 3
+		},
+		"should use the ownership in the tenant's shard if shuffle sharding is enabled": {
+			shardingStrategy:  util.ShardingStrategyShuffle,
+			shardSize:         6,
+			ownership:         0.6,
+			expectedShardSize: 6,
+			expected:          600,
+		},
+		"should fallback to the even distribution if the ownership is unknown": {
+			shardingStrategy:  util.ShardingStrategyDefault,
+			ownership:         0,
+			expectedShardSize: 0,
+			expected:          300, // (1000 / 10) * 3
+		},
+	}
+
+	for testName, testData := range tests {
+		testData := testData
+
+		t.Run(testName, func(t *testing.T) {
+			ring := &ringCountMock{}
+			ring.On("HealthyInstancesCount").Return(10)
+			ring.On("ZonesCount").Return(1)
+
+			ownership := &ringOwnershipMock{}
+			ownership.On("InstanceOwnership", "test", testData.expectedShardSize).Return(testData.ownership)
+
+			limits, err := validation.NewOverrides(validation.Limits{
+				MaxGlobalSeriesPerUser:   1000,
+				IngestionTenantShardSize: testData.shardSize,
+			}, nil)
+			require.NoError(t, err)
+
+			limiter := NewLimiter(limits, ring, ownership, testData.shardingStrategy, true, 0, 3, false)
+			assert.Equal(t, testData.expected, limiter.maxSeriesPerUser("test"))
+			ownership.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLimiter_ShardByAllLabelsOverride(t *testing.T) {
+	ring := &ringCountMock{}
+	ring.On("HealthyInstancesCount").Return(10)
+	ring.On("ZonesCount").Return(1)
+
+	for _, overridden := range []bool{false, true} {
+		limits, err := validation.NewOverrides(validation.Limits{
+			MaxGlobalSeriesPerUser:   1000,
+			MaxGlobalSeriesPerMetric: 100,
+			ShardByAllLabels:         overridden,
+		}, nil)
+		require.NoError(t, err)
+
+		// The global limits are converted to local limits only if the tenant is sharded by all labels.
+		limiter := NewLimiter(limits, ring, nil, util.ShardingStrategyDefault, false, 0, 3, false)
+		if overridden {
+			assert.Equal(t, 300, limiter.maxSeriesPerUser("test"))
+			assert.Equal(t, 30, limiter.maxSeriesPerMetric("test"))
+		} else {
+			assert.Equal(t, math.MaxInt32, limiter.maxSeriesPerUser("test"))
+			assert.Equal(t, 100, limiter.maxSeriesPerMetric("test"))
+		}
+	}
+}
+
+func TestLimiter_ShardByAllLabelsOverrideGracePeriod(t *testing.T) {
+	ring := &ringCountMock{}
+	ring.On("HealthyInstancesCount").Return(10)
+	ring.On("ZonesCount").Return(1)
+
+	newOverrides := func(shardByAllLabels bool) *validation.Overrides {
+		limits, err := validation.NewOverrides(validation.Limits{
+			MaxGlobalSeriesPerUser:   1000,
+			MaxGlobalSeriesPerMetric: 100,
+			ShardByAllLabels:         shardByAllLabels,
+		}, nil)
+		require.NoError(t, err)
+		return limits
+	}
+
+	// The override already enabled when the ingester starts is applied immediately.
+	limiter := NewLimiter(newOverrides(true), ring, nil, util.ShardingStrategyDefault, false, time.Hour, 3, false)
+	assert.Equal(t, 300, limiter.maxSeriesPerUser("test"))
+	assert.Equal(t, 30, limiter.maxSeriesPerMetric("test"))
+
+	// The override switched from disabled to enabled is applied after the grace period.
+	limiter = NewLimiter(newOverrides(false), ring, nil, util.ShardingStrategyDefault, false, time.Hour, 3, false)
+	assert.Equal(t, math.MaxInt32, limiter.maxSeriesPerUser("test"))
+	assert.Equal(t, 100, limiter.maxSeriesPerMetric("test"))
+
+	limiter.limits = newOverrides(true)
+	assert.Equal(t, math.MaxInt32, limiter.maxSeriesPerUser("test"))
+	assert.Equal(t, 100, limiter.maxSeriesPerMetric("test"))
+
+	limiter.shardByAllLabelsMtx.Lock()
+	limiter.shardByAllLabelsSince["test"] = time.Now().Add(-time.Hour)
+	limiter.shardByAllLabelsMtx.Unlock()
+	assert.Equal(t, 300, limiter.maxSeriesPerUser("test"))
+	assert.Equal(t, 30, limiter.maxSeriesPerMetric("test"))
+
+	// The override switched back to disabled is applied immediately.
+	limiter.limits = newOverrides(false)
+	assert.Equal(t, math.MaxInt32, limiter.maxSeriesPerUser("test"))
+	assert.Equal(t, 100, limiter.maxSeriesPerMetric("test"))
+
+	// The state of a removed tenant is forgotten.
+	limiter.RemoveUser("test")
+	limiter.shardByAllLabelsMtx.Lock()
+	assert.Empty(t, limiter.shardByAllLabelsSince)
+	limiter.shardByAllLabelsMtx.Unlock()
+
+	limiter.limits = newOverrides(true)
+	assert.Equal(t, 300, limiter.maxSeriesPerUser("test"))
+	assert.Equal(t, 30, limiter.maxSeriesPerMetric("test"))
+}
+
 func TestLimiter_minNonZero(t *testing.T) {
 	t.Parallel()
 
@@ -577,3 +713,12 @@ func (m *ringCountMock) ZonesCount() int {
 	args := m.Called()
 	return args.Int(0)
 }
+
+type ringOwnershipMock struct {
+	mock.Mock
+}
+
+func (m *ringOwnershipMock) InstanceOwnership(identifier string, shardSize int) float64 {
+	args := m.Called(identifier, shardSize)
+	return args.Get(0).(float64)
+}
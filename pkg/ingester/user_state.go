@@ -50,6 +50,11 @@ type userState struct {
 
 	seriesInMetric *metricCounter
 
+	// Series stats, cached because computing them requires to walk all the series.
+	seriesStatsMtx sync.Mutex
+	seriesStats    *client.SeriesStatsResponse
+	seriesStatsAt  time.Time
+
 	// Series metrics.
 	memSeries             prometheus.Gauge
 	memSeriesCreatedTotal prometheus.Counter
@@ -287,6 +292,40 @@ func (u *userState) removeSeries(fp model.Fingerprint, metric labels.Labels) {
 	u.memSeries.Dec()
 }
 
+// getSeriesStats returns the number of series, along with the metric names and label
+// name/value pairs with the highest number of series. Computing them requires to walk
+// all the series, so they're cached for seriesStatsCacheTTL and computed by a single
+// request at a time.
+func (u *userState) getSeriesStats() *client.SeriesStatsResponse {
+	u.seriesStatsMtx.Lock()
+	defer u.seriesStatsMtx.Unlock()
+
+	if u.seriesStats != nil && time.Since(u.seriesStatsAt) < seriesStatsCacheTTL {
+		return u.seriesStats
+	}
+
+	// The series labels never change, so there's no need to lock them.
+	metricNames := map[string]uint64{}
+	labelValuePairs := map[string]uint64{}
+	for pair := range u.fpToSeries.iter() {
+		for _, l := range pair.series.metric {
+			if l.Name == model.MetricNameLabel {
+				metricNames[l.Value]++
+			}
+			labelValuePairs[l.Name+"="+l.Value]++
+		}
+	}
+
+	u.seriesStats = &client.SeriesStatsResponse{
+		NumSeries:          uint64(u.fpToSeries.length()),
+		TopMetricNames:     topSeriesStatsEntries(metricNames, seriesStatsMaxEntries),
+		TopLabelValuePairs: topSeriesStatsEntries(labelValuePairs, seriesStatsMaxEntries),
+	}
+	u.seriesStatsAt = time.Now()
+
+	return u.seriesStats
+}
+
 // forSeriesMatching passes all series matching the given matchers to the
 // provided callback. Deals with locking and the quirks of zero-length matcher
 // values. There are 2 callbacks:
@@ -52,6 +52,10 @@ type BasicLifecyclerConfig struct {
 	HeartbeatPeriod     time.Duration
 	TokensObservePeriod time.Duration
 	NumTokens           int
+
+	// TokenGenerator generates the instance tokens. Random tokens
+	// are generated if nil.
+	TokenGenerator TokenGenerator
 }
 
 // BasicLifecycler is a basic ring lifecycler which allows to hook custom
@@ -134,6 +138,13 @@ func (l *BasicLifecycler) GetTokens() Tokens {
 	return l.currInstanceDesc.GetTokens()
 }
 
+// InstanceTokens returns the tokens the instance should own, keeping the existing tokens if allowed
+// by the configured token generator and generating the missing ones. It's expected to be called by
+// the delegate when the instance registers to the ring.
+func (l *BasicLifecycler) InstanceTokens(existing Tokens, takenTokens []uint32) Tokens {
+	return InstanceTokens(l.cfg.TokenGenerator, l.cfg.NumTokens, existing, takenTokens)
+}
+
 // GetRegisteredAt returns the timestamp when the instance has been registered to the ring
 // or a zero value if the lifecycler hasn't been started yet or was already registered and its
 // timestamp is unknown.
@@ -342,12 +353,7 @@ func (l *BasicLifecycler) verifyTokens(ctx context.Context) bool {
 		needTokens := l.cfg.NumTokens - len(actualTokens)
 
 		level.Info(l.logger).Log("msg", "generating new tokens", "count", needTokens, "ring", l.ringName)
-		newTokens := GenerateTokens(needTokens, takenTokens)
-
-		actualTokens = append(actualTokens, newTokens...)
-		sort.Sort(actualTokens)
-
-		i.Tokens = actualTokens
+		i.Tokens = l.InstanceTokens(actualTokens, takenTokens)
 		return true
 	})
 
@@ -42,7 +42,7 @@ const pageContent = `
 					</tr>
 				</thead>
 				<tbody>
-					{{ range $i, $ing := .Ingesters }}
+					{{ range $i, $ing := .Instances }}
 					{{ if mod $i 2 }}
 					<tr>
 					{{ else }}
@@ -50,10 +50,10 @@ const pageContent = `
 					{{ end }}
 						<td>{{ .ID }}</td>
 						<td>{{ .Zone }}</td>
-						<td>{{ .State }}</td>
+						<td>{{ if .Healthy }}{{ .State }}{{ else }}Unhealthy{{ end }}</td>
 						<td>{{ .Address }}</td>
 						<td>{{ .RegisteredTimestamp }}</td>
-						<td>{{ .HeartbeatTimestamp }}</td>
+						<td>{{ .HeartbeatTimestamp }} ({{ .HeartbeatAgeSeconds | printf "%.0f" }}s ago)</td>
 						<td>{{ .NumTokens }}</td>
 						<td>{{ .Ownership }}%</td>
 						<td><button name="forget" value="{{ .ID }}" type="submit">Forget</button></td>
@@ -69,7 +69,7 @@ const pageContent = `
 			{{ end }}
 
 			{{ if .ShowTokens }}
-				{{ range $i, $ing := .Ingesters }}
+				{{ range $i, $ing := .Instances }}
 					<h2>Instance: {{ .ID }}</h2>
 					<p>
 						Tokens:<br />
@@ -124,63 +124,136 @@ func (r *Ring) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	}
 
 	r.mtx.RLock()
-	defer r.mtx.RUnlock()
+	_, owned := r.countTokens()
+	status := newRingStatus(r.ringDesc, owned, r.cfg.HeartbeatTimeout, time.Now())
+	r.mtx.RUnlock()
+
+	// The status is served as JSON if requested via the Accept header or the format parameter.
+	if req.URL.Query().Get("format") == "json" {
+		util.WriteJSONResponse(w, status)
+		return
+	}
+
+	util.RenderHTTPResponse(w, struct {
+		RingStatus
+		ShowTokens bool `json:"-"`
+	}{
+		RingStatus: status,
+		ShowTokens: req.URL.Query().Get("tokens") == "true",
+	}, pageTemplate, req)
+}
+
+// RingStatus is the status of the ring, as served in JSON by the ring status page.
+type RingStatus struct {
+	Instances []InstanceStatus `json:"shards"`
+	Zones     []ZoneStatus     `json:"zones"`
+	Now       time.Time        `json:"now"`
+}
+
+// InstanceStatus is the status of an instance registered in the ring.
+type InstanceStatus struct {
+	ID      string `json:"id"`
+	Zone    string `json:"zone"`
+	State   string `json:"state"`
+	Healthy bool   `json:"healthy"`
+	Address string `json:"address"`
+
+	// HeartbeatTimestamp and RegisteredTimestamp are human readable, while
+	// the other timestamps are RFC3339 formatted.
+	HeartbeatTimestamp  string     `json:"timestamp"`
+	HeartbeatAt         time.Time  `json:"heartbeat_at"`
+	HeartbeatAgeSeconds float64    `json:"heartbeat_age_seconds"`
+	RegisteredTimestamp string     `json:"registered_timestamp"`
+	RegisteredAt        *time.Time `json:"registered_at,omitempty"`
+
+	Tokens    []uint32 `json:"tokens"`
+	NumTokens int      `json:"num_tokens"`
+
+	// Ownership is the percentage of the ring tokens owned by the instance.
+	Ownership float64 `json:"ownership"`
+}
+
+// ZoneStatus is the status of an availability zone of the ring.
+type ZoneStatus struct {
+	Zone             string `json:"zone"`
+	Instances        int    `json:"instances"`
+	HealthyInstances int    `json:"healthy_instances"`
+
+	// Ownership is the percentage of the ring tokens owned by the instances of the zone.
+	Ownership float64 `json:"ownership"`
+}
+
+// NewRingStatus returns the status of the ring described by ringDesc. An instance is reported
+// as healthy if its last heartbeat is within the heartbeatTimeout.
+func NewRingStatus(ringDesc *Desc, heartbeatTimeout time.Duration, now time.Time) RingStatus {
+	_, owned := countTokens(ringDesc, ringDesc.GetTokens(), ringDesc.getTokensInfo())
+	return newRingStatus(ringDesc, owned, heartbeatTimeout, now)
+}
+
+func newRingStatus(ringDesc *Desc, owned map[string]uint32, heartbeatTimeout time.Duration, now time.Time) RingStatus {
+	status := RingStatus{
+		Instances: []InstanceStatus{},
+		Zones:     []ZoneStatus{},
+		Now:       now,
+	}
+	if ringDesc == nil {
+		return status
+	}
 
 	ingesterIDs := []string{}
-	for id := range r.ringDesc.Ingesters {
+	for id := range ringDesc.Ingesters {
 		ingesterIDs = append(ingesterIDs, id)
 	}
 	sort.Strings(ingesterIDs)
 
-	now := time.Now()
-	ingesters := []interface{}{}
-	_, owned := r.countTokens()
+	zones := map[string]*ZoneStatus{}
 	for _, id := range ingesterIDs {
-		ing := r.ringDesc.Ingesters[id]
+		ing := ringDesc.Ingesters[id]
 		heartbeatTimestamp := time.Unix(ing.Timestamp, 0)
-		state := ing.State.String()
-		if !r.IsHealthy(&ing, Reporting, now) {
-			state = unhealthy
-		}
+		healthy := ing.IsHealthy(Reporting, heartbeatTimeout, now)
+		ownership := (float64(owned[id]) / float64(math.MaxUint32)) * 100
 
-		// Format the registered timestamp.
-		registeredTimestamp := ""
-		if ing.RegisteredTimestamp != 0 {
-			registeredTimestamp = ing.GetRegisteredAt().String()
-		}
-
-		ingesters = append(ingesters, struct {
-			ID                  string   `json:"id"`
-			State               string   `json:"state"`
-			Address             string   `json:"address"`
-			HeartbeatTimestamp  string   `json:"timestamp"`
-			RegisteredTimestamp string   `json:"registered_timestamp"`
-			Zone                string   `json:"zone"`
-			Tokens              []uint32 `json:"tokens"`
-			NumTokens           int      `json:"-"`
-			Ownership           float64  `json:"-"`
-		}{
+		instance := InstanceStatus{
 			ID:                  id,
-			State:               state,
+			Zone:                ing.Zone,
+			State:               ing.State.String(),
+			Healthy:             healthy,
 			Address:             ing.Addr,
 			HeartbeatTimestamp:  heartbeatTimestamp.String(),
-			RegisteredTimestamp: registeredTimestamp,
+			HeartbeatAt:         heartbeatTimestamp,
+			HeartbeatAgeSeconds: now.Sub(heartbeatTimestamp).Seconds(),
 			Tokens:              ing.Tokens,
-			Zone:                ing.Zone,
 			NumTokens:           len(ing.Tokens),
-			Ownership:           (float64(owned[id]) / float64(math.MaxUint32)) * 100,
-		})
+			Ownership:           ownership,
+		}
+
+		// Format the registered timestamp.
+		if ing.RegisteredTimestamp != 0 {
+			registeredAt := ing.GetRegisteredAt()
+			instance.RegisteredTimestamp = registeredAt.String()
+			instance.RegisteredAt = &registeredAt
+		}
+
+		status.Instances = append(status.Instances, instance)
+
+		zone, ok := zones[ing.Zone]
+		if !ok {
+			zone = &ZoneStatus{Zone: ing.Zone}
+			zones[ing.Zone] = zone
+		}
+		zone.Instances++
+		zone.Ownership += ownership
+		if healthy {
+			zone.HealthyInstances++
+		}
 	}
 
-	tokensParam := req.URL.Query().Get("tokens")
+	for _, zone := range zones {
+		status.Zones = append(status.Zones, *zone)
+	}
+	sort.Slice(status.Zones, func(i, j int) bool {
+		return status.Zones[i].Zone < status.Zones[j].Zone
+	})
 
-	util.RenderHTTPResponse(w, struct {
-		Ingesters  []interface{} `json:"shards"`
-		Now        time.Time     `json:"now"`
-		ShowTokens bool          `json:"-"`
-	}{
-		Ingesters:  ingesters,
-		Now:        now,
-		ShowTokens: tokensParam == "true",
-	}, pageTemplate, req)
+	return status
 }
@@ -0,0 +1,115 @@
+package ring
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRingStatus(t *testing.T) {
+	now := time.Now()
+	registeredAt := now.Add(-time.Hour).Truncate(time.Second)
+
+	desc := &Desc{Ingesters: map[string]InstanceDesc{
+		"instance-2": {Addr: "127.0.0.2", Zone: "zone-b", State: ACTIVE, Timestamp: now.Add(-10 * time.Minute).Unix(), Tokens: []uint32{math.MaxUint32 / 2}},
+		"instance-1": {Addr: "127.0.0.1", Zone: "zone-a", State: ACTIVE, Timestamp: now.Add(-10 * time.Second).Unix(), Tokens: []uint32{0}, RegisteredTimestamp: registeredAt.Unix()},
+		"instance-3": {Addr: "127.0.0.3", Zone: "zone-a", State: PENDING, Timestamp: now.Unix()},
+	}}
+
+	status := NewRingStatus(desc, time.Minute, now)
+	assert.Equal(t, now, status.Now)
+
+	require.Len(t, status.Instances, 3)
+	assert.Equal(t, "instance-1", status.Instances[0].ID)
+	assert.Equal(t, "instance-2", status.Instances[1].ID)
+	assert.Equal(t, "instance-3", status.Instances[2].ID)
+
+	first := status.Instances[0]
+	assert.Equal(t, "zone-a", first.Zone)
+	assert.Equal(t, "ACTIVE", first.State)
+	assert.True(t, first.Healthy)
+	assert.Equal(t, "127.0.0.1", first.Address)
+	assert.InDelta(t, 10, first.HeartbeatAgeSeconds, 1)
+	require.NotNil(t, first.RegisteredAt)
+	assert.True(t, registeredAt.Equal(*first.RegisteredAt))
+	assert.Equal(t, 1, first.NumTokens)
+	assert.InDelta(t, 50, first.Ownership, 0.01)
+
+	// The heartbeat is older than the timeout.
+	assert.False(t, status.Instances[1].Healthy)
+	assert.Nil(t, status.Instances[1].RegisteredAt)
+	assert.InDelta(t, 50, status.Instances[1].Ownership, 0.01)
+
+	// The instance is healthy, but it has no tokens yet.
+	assert.True(t, status.Instances[2].Healthy)
+	assert.Equal(t, float64(0), status.Instances[2].Ownership)
+
+	require.Len(t, status.Zones, 2)
+	assert.Equal(t, "zone-a", status.Zones[0].Zone)
+	assert.Equal(t, 2, status.Zones[0].Instances)
+	assert.Equal(t, 2, status.Zones[0].HealthyInstances)
+	assert.InDelta(t, 50, status.Zones[0].Ownership, 0.01)
+	assert.Equal(t, "zone-b", status.Zones[1].Zone)
+	assert.Equal(t, 1, status.Zones[1].Instances)
+	assert.Equal(t, 0, status.Zones[1].HealthyInstances)
+}
+
+func TestRing_ServeHTTP(t *testing.T) {
+	now := time.Now()
+	desc := &Desc{Ingesters: map[string]InstanceDesc{
+		"instance-1": {Addr: "127.0.0.1", Zone: "zone-a", State: ACTIVE, Timestamp: now.Unix(), Tokens: []uint32{1, 2}},
+		"instance-2": {Addr: "127.0.0.2", Zone: "zone-a", State: LEAVING, Timestamp: now.Add(-time.Hour).Unix(), Tokens: []uint32{3}},
+	}}
+
+	r := &Ring{
+		cfg:                 Config{HeartbeatTimeout: time.Minute},
+		ringDesc:            desc,
+		ringTokens:          desc.GetTokens(),
+		ringInstanceByToken: desc.getTokensInfo(),
+	}
+
+	tests := map[string]func(req *http.Request){
+		"format parameter": func(req *http.Request) {
+			req.URL.RawQuery = "format=json"
+		},
+		"accept header": func(req *http.Request) {
+			req.Header.Set("Accept", "application/json")
+		},
+	}
+
+	for testName, setup := range tests {
+		t.Run(testName, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ring", nil)
+			setup(req)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+			status := RingStatus{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+			require.Len(t, status.Instances, 2)
+			assert.Equal(t, "instance-1", status.Instances[0].ID)
+			assert.True(t, status.Instances[0].Healthy)
+			assert.Equal(t, []uint32{1, 2}, status.Instances[0].Tokens)
+			assert.Equal(t, "LEAVING", status.Instances[1].State)
+			assert.False(t, status.Instances[1].Healthy)
+			assert.Equal(t, []ZoneStatus{{Zone: "zone-a", Instances: 2, HealthyInstances: 1, Ownership: 100}}, status.Zones)
+		})
+	}
+
+	t.Run("html", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ring", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "instance-1")
+		assert.Contains(t, w.Body.String(), "Unhealthy")
+	})
+}
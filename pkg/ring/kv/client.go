@@ -11,6 +11,7 @@ import (
 	"github.com/cortexproject/cortex/pkg/ring/kv/codec"
 	"github.com/cortexproject/cortex/pkg/ring/kv/consul"
 	"github.com/cortexproject/cortex/pkg/ring/kv/etcd"
+	"github.com/cortexproject/cortex/pkg/ring/kv/file"
 	"github.com/cortexproject/cortex/pkg/ring/kv/memberlist"
 )
 
@@ -36,11 +37,12 @@ var inmemoryStoreInit sync.Once
 var inmemoryStore Client
 
 // StoreConfig is a configuration used for building single store client, either
-// Consul, Etcd, Memberlist or MultiClient. It was extracted from Config to keep
+// Consul, Etcd, File, Memberlist or MultiClient. It was extracted from Config to keep
 // single-client config separate from final client-config (with all the wrappers)
 type StoreConfig struct {
 	Consul consul.Config `yaml:"consul"`
 	Etcd   etcd.Config   `yaml:"etcd"`
+	File   file.Config   `yaml:"file"`
 	Multi  MultiConfig   `yaml:"multi"`
 
 	// Function that returns memberlist.KV store to use. By using a function, we can delay
@@ -49,7 +51,7 @@ type StoreConfig struct {
 }
 
 // Config is config for a KVStore currently used by ring and HA tracker,
-// where store can be consul or inmemory.
+// where store can be consul, etcd, file, inmemory, memberlist or multi.
 type Config struct {
 	Store       string `yaml:"store"`
 	Prefix      string `yaml:"prefix"`
@@ -70,13 +72,14 @@ func (cfg *Config) RegisterFlagsWithPrefix(flagsPrefix, defaultPrefix string, f
 	// be easier to have everything under ring, so ring.consul.<flag-name>
 	cfg.Consul.RegisterFlags(f, flagsPrefix)
 	cfg.Etcd.RegisterFlagsWithPrefix(f, flagsPrefix)
+	cfg.File.RegisterFlagsWithPrefix(f, flagsPrefix)
 	cfg.Multi.RegisterFlagsWithPrefix(f, flagsPrefix)
 
 	if flagsPrefix == "" {
 		flagsPrefix = "ring."
 	}
 	f.StringVar(&cfg.Prefix, flagsPrefix+"prefix", defaultPrefix, "The prefix for the keys in the store. Should end with a /.")
-	f.StringVar(&cfg.Store, flagsPrefix+"store", "consul", "Backend storage to use for the ring. Supported values are: consul, etcd, inmemory, memberlist, multi.")
+	f.StringVar(&cfg.Store, flagsPrefix+"store", "consul", "Backend storage to use for the ring. Supported values are: consul, etcd, file, inmemory, memberlist, multi.")
 }
 
 // Client is a high-level client for key-value stores (such as Etcd and
@@ -112,7 +115,7 @@ type Client interface {
 	WatchPrefix(ctx context.Context, prefix string, f func(string, interface{}) bool)
 }
 
-// NewClient creates a new Client (consul, etcd or inmemory) based on the config,
+// NewClient creates a new Client (consul, etcd, file or inmemory) based on the config,
 // encodes and decodes data for storage using the codec.
 func NewClient(cfg Config, codec codec.Codec, reg prometheus.Registerer) (Client, error) {
 	if cfg.Mock != nil {
@@ -133,6 +136,9 @@ func createClient(backend string, prefix string, cfg StoreConfig, codec codec.Co
 	case "etcd":
 		client, err = etcd.New(cfg.Etcd, codec)
 
+	case "file":
+		client, err = file.NewClient(cfg.File, codec)
+
 	case "inmemory":
 		// If we use the in-memory store, make sure everyone gets the same instance
 		// within the same process.
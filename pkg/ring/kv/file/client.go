@@ -0,0 +1,387 @@
+package file
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+
+	"github.com/cortexproject/cortex/pkg/ring/kv/codec"
+	util_log "github.com/cortexproject/cortex/pkg/util/log"
+)
+
+const (
+	// Temporary files are written in the same directory (so that rename is atomic)
+	// and are hidden, so that they're never returned as keys.
+	tmpFilePrefix = ".tmp-"
+
+	maxCasRetries = 10
+)
+
+// Config for a new file-backed Client.
+type Config struct {
+	Dir string `yaml:"dir"`
+}
+
+// RegisterFlagsWithPrefix adds the flags required to config this to the given FlagSet.
+// If prefix is not an empty string it should end with a period.
+func (cfg *Config) RegisterFlagsWithPrefix(f *flag.FlagSet, prefix string) {
+	f.StringVar(&cfg.Dir, prefix+"file.dir", "", "Directory where the file-backed KV store persists values, one file per key. The directory must not be shared between processes.")
+}
+
+// Client is a KV.Client storing each key in a file in a local directory. Changes are
+// notified to watchers running in the same process, so the directory must not be
+// written by any other process.
+type Client struct {
+	codec codec.Codec
+	store *store
+}
+
+// store is shared by all clients configured with the same directory, so that
+// CAS and watches are consistent within the process regardless of the codec.
+type store struct {
+	dir string
+
+	mtx      sync.Mutex
+	index    uint64            // the current 'index in the log', incremented on each change
+	versions map[string]uint64 // index of the last change of each key
+	changed  chan struct{}     // closed and replaced on each change
+}
+
+var (
+	storesMtx sync.Mutex
+	stores    = map[string]*store{}
+)
+
+// NewClient returns a new Client persisting values in the configured directory.
+func NewClient(cfg Config, codec codec.Codec) (*Client, error) {
+	if cfg.Dir == "" {
+		return nil, errors.New("no directory configured for the file KV store")
+	}
+
+	dir, err := filepath.Abs(cfg.Dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to resolve file KV store directory")
+	}
+
+	storesMtx.Lock()
+	defer storesMtx.Unlock()
+
+	s, ok := stores[dir]
+	if !ok {
+		if err := os.MkdirAll(dir, 0777); err != nil {
+			return nil, errors.Wrap(err, "failed to create file KV store directory")
+		}
+
+		s = &store{
+			dir:      dir,
+			versions: map[string]uint64{},
+			changed:  make(chan struct{}),
+		}
+		stores[dir] = s
+	}
+
+	return &Client{codec: codec, store: s}, nil
+}
+
+// List implements kv.List.
+func (c *Client) List(_ context.Context, prefix string) ([]string, error) {
+	c.store.mtx.Lock()
+	defer c.store.mtx.Unlock()
+
+	return c.store.list(prefix)
+}
+
+// Get implements kv.Get.
+func (c *Client) Get(_ context.Context, key string) (interface{}, error) {
+	c.store.mtx.Lock()
+	value, _, err := c.store.get(key)
+	c.store.mtx.Unlock()
+
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return c.codec.Decode(value)
+}
+
+// Delete implements kv.Delete.
+func (c *Client) Delete(_ context.Context, key string) error {
+	c.store.mtx.Lock()
+	defer c.store.mtx.Unlock()
+
+	err := os.Remove(c.store.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	c.store.index++
+	c.store.versions[key] = c.store.index
+	c.store.notify()
+	return nil
+}
+
+// CAS atomically modifies a value in a callback.
+// If value doesn't exist you'll get nil as an argument to your callback.
+func (c *Client) CAS(ctx context.Context, key string, f func(in interface{}) (out interface{}, retry bool, err error)) error {
+	for i := 0; i < maxCasRetries; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		c.store.mtx.Lock()
+		value, version, err := c.store.get(key)
+		c.store.mtx.Unlock()
+		if err != nil {
+			level.Error(util_log.Logger).Log("msg", "error getting key", "key", key, "err", err)
+			continue
+		}
+
+		var intermediate interface{}
+		if value != nil {
+			intermediate, err = c.codec.Decode(value)
+			if err != nil {
+				level.Error(util_log.Logger).Log("msg", "error decoding key", "key", key, "err", err)
+				continue
+			}
+		}
+
+		intermediate, retry, err := f(intermediate)
+		if err != nil {
+			if !retry {
+				return err
+			}
+			continue
+		}
+
+		// Treat the callback returning nil for intermediate as a decision to
+		// not actually write to the store, but this is not an error.
+		if intermediate == nil {
+			return nil
+		}
+
+		bytes, err := c.codec.Encode(intermediate)
+		if err != nil {
+			level.Error(util_log.Logger).Log("msg", "error serialising value", "key", key, "err", err)
+			continue
+		}
+
+		ok, err := c.store.cas(key, version, bytes)
+		if err != nil {
+			level.Error(util_log.Logger).Log("msg", "error CASing", "key", key, "err", err)
+			continue
+		}
+		if !ok {
+			level.Debug(util_log.Logger).Log("msg", "error CASing, trying again", "key", key, "version", version)
+			continue
+		}
+		return nil
+	}
+	return fmt.Errorf("failed to CAS %s", key)
+}
+
+// WatchKey calls f with the current value of the key and then whenever it changes.
+// This function blocks until the context is cancelled or f returns false.
+func (c *Client) WatchKey(ctx context.Context, key string, f func(interface{}) bool) {
+	first := true
+	lastVersion := uint64(0)
+
+	for {
+		c.store.mtx.Lock()
+		value, version, err := c.store.get(key)
+		changed := c.store.changed
+		c.store.mtx.Unlock()
+
+		if err != nil {
+			level.Error(util_log.Logger).Log("msg", "error getting key", "key", key, "err", err)
+		} else if value != nil && (first || version != lastVersion) {
+			out, err := c.codec.Decode(value)
+			if err != nil {
+				level.Error(util_log.Logger).Log("msg", "error decoding key", "key", key, "err", err)
+			} else if !f(out) {
+				return
+			}
+		}
+		first = false
+		lastVersion = version
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-changed:
+		}
+	}
+}
+
+// WatchPrefix calls f with the current values of all keys under the prefix and then
+// whenever any of them changes. This function blocks until the context is cancelled
+// or f returns false.
+func (c *Client) WatchPrefix(ctx context.Context, prefix string, f func(string, interface{}) bool) {
+	first := true
+	lastIndex := uint64(0)
+
+	for {
+		c.store.mtx.Lock()
+		keys, err := c.store.list(prefix)
+		values := make(map[string][]byte, len(keys))
+		for _, key := range keys {
+			if err != nil {
+				break
+			}
+
+			var (
+				value   []byte
+				version uint64
+			)
+			value, version, err = c.store.get(key)
+			if value != nil && (first || version > lastIndex) {
+				values[key] = value
+			}
+		}
+		index := c.store.index
+		changed := c.store.changed
+		c.store.mtx.Unlock()
+
+		if err != nil {
+			level.Error(util_log.Logger).Log("msg", "error getting path", "prefix", prefix, "err", err)
+		} else {
+			for _, key := range keys {
+				value, ok := values[key]
+				if !ok {
+					continue
+				}
+
+				out, err := c.codec.Decode(value)
+				if err != nil {
+					level.Error(util_log.Logger).Log("msg", "error decoding list of values for prefix:key", "prefix", prefix, "key", key, "err", err)
+					continue
+				}
+				if !f(key, out) {
+					return
+				}
+			}
+			first = false
+			lastIndex = index
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-changed:
+		}
+	}
+}
+
+// path returns the file storing the key. Keys are escaped, so that all of
+// them are stored in the top-level directory regardless of slashes.
+func (s *store) path(key string) string {
+	name := url.PathEscape(key)
+	if strings.HasPrefix(name, ".") {
+		name = "%2E" + name[1:]
+	}
+	return filepath.Join(s.dir, name)
+}
+
+// get returns the raw value and the version of the key, or nil if the key doesn't exist.
+// Keys which have not been written since the process started have version 0.
+// Must be called with the mutex held.
+func (s *store) get(key string) ([]byte, uint64, error) {
+	value, err := ioutil.ReadFile(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, s.versions[key], nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return value, s.versions[key], nil
+}
+
+// list returns the sorted keys starting with the prefix. Must be called with the mutex held.
+func (s *store) list(prefix string) ([]string, error) {
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+
+		key, err := url.PathUnescape(file.Name())
+		if err != nil {
+			level.Warn(util_log.Logger).Log("msg", "skipping file not created by the file KV store", "file", file.Name(), "err", err)
+			continue
+		}
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// cas writes the value iff the version of the key didn't change.
+func (s *store) cas(key string, version uint64, value []byte) (bool, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.versions[key] != version {
+		return false, nil
+	}
+
+	if err := s.write(key, value); err != nil {
+		return false, err
+	}
+
+	s.index++
+	s.versions[key] = s.index
+	s.notify()
+	return true, nil
+}
+
+// write atomically replaces the file of the key. Must be called with the mutex held.
+func (s *store) write(key string, value []byte) error {
+	f, err := ioutil.TempFile(s.dir, tmpFilePrefix)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		// Closing or removing the temporary file may fail if it has already been
+		// closed or renamed, so the errors are ignored.
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+
+	if _, err := f.Write(value); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), s.path(key))
+}
+
+// notify wakes up all watchers. Must be called with the mutex held.
+func (s *store) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
@@ -0,0 +1,123 @@
+package file
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cortexproject/cortex/pkg/ring/kv/codec"
+)
+
+func TestClient_ShouldPersistValuesAcrossRestarts(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "file-kv")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) //nolint:errcheck
+
+	keys := []string{"collectors/ring", "ha-tracker/user-1/cluster", ".hidden", "with space"}
+
+	client, err := NewClient(Config{Dir: dir}, codec.String{})
+	require.NoError(t, err)
+	for _, key := range keys {
+		require.NoError(t, client.CAS(ctx, key, func(in interface{}) (interface{}, bool, error) {
+			return "value-" + key, false, nil
+		}))
+	}
+
+	// All keys must be stored in the top-level directory, and no temporary file must be left.
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, len(keys))
+	for _, f := range files {
+		assert.False(t, f.IsDir())
+		assert.NotContains(t, f.Name(), tmpFilePrefix)
+	}
+
+	// Simulate a restart by forgetting the in-process store.
+	storesMtx.Lock()
+	delete(stores, client.store.dir)
+	storesMtx.Unlock()
+
+	client, err = NewClient(Config{Dir: dir}, codec.String{})
+	require.NoError(t, err)
+
+	listed, err := client.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{".hidden", "collectors/ring", "ha-tracker/user-1/cluster", "with space"}, listed)
+
+	for _, key := range keys {
+		value, err := client.Get(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, "value-"+key, value)
+	}
+
+	listed, err = client.List(ctx, "ha-tracker/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ha-tracker/user-1/cluster"}, listed)
+}
+
+func TestClient_ShouldIgnoreFilesNotCreatedByTheStore(t *testing.T) {
+	client, closer, err := Mock(codec.String{})
+	require.NoError(t, err)
+	defer closer.Close() //nolint:errcheck
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(client.store.dir, tmpFilePrefix+"123"), []byte("partial"), 0666))
+	require.NoError(t, os.Mkdir(filepath.Join(client.store.dir, "subdir"), 0777))
+
+	listed, err := client.List(context.Background(), "")
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+}
+
+func TestClient_ShouldShareStoreBetweenClientsOfTheSameDirectory(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	first, closer, err := Mock(codec.String{})
+	require.NoError(t, err)
+	defer closer.Close() //nolint:errcheck
+
+	second, err := NewClient(Config{Dir: first.store.dir}, codec.String{})
+	require.NoError(t, err)
+	require.Same(t, first.store, second.store)
+
+	observed := make(chan interface{}, 10)
+	go second.WatchKey(ctx, "key", func(value interface{}) bool {
+		observed <- value
+		return true
+	})
+
+	require.NoError(t, first.CAS(ctx, "key", func(in interface{}) (interface{}, bool, error) {
+		return "1", false, nil
+	}))
+
+	select {
+	case value := <-observed:
+		assert.Equal(t, "1", value)
+	case <-ctx.Done():
+		t.Fatal("change of the key was not notified to the watcher")
+	}
+
+	// Deleting the key must not be notified, and a CAS based on the deleted value must not succeed.
+	var seen []interface{}
+	require.NoError(t, second.CAS(ctx, "key", func(in interface{}) (interface{}, bool, error) {
+		if len(seen) == 0 {
+			require.NoError(t, first.Delete(ctx, "key"))
+		}
+		seen = append(seen, in)
+		return "2", true, nil
+	}))
+	assert.Equal(t, []interface{}{"1", nil}, seen)
+
+	value, err := first.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, "2", value)
+	assert.Equal(t, "2", <-observed)
+	assert.Empty(t, observed)
+}
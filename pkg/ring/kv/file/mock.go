@@ -0,0 +1,38 @@
+package file
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/cortexproject/cortex/pkg/ring/kv/codec"
+)
+
+// Mock returns a file-backed client persisting values in a new temporary
+// directory, which is removed by the returned closer.
+func Mock(codec codec.Codec) (*Client, io.Closer, error) {
+	dir, err := ioutil.TempDir("", "file-kv")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, err := NewClient(Config{Dir: dir}, codec)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, nil, err
+	}
+
+	return client, closerFunc(func() error {
+		storesMtx.Lock()
+		delete(stores, client.store.dir)
+		storesMtx.Unlock()
+
+		return os.RemoveAll(dir)
+	}), nil
+}
+
+type closerFunc func() error
+
+func (c closerFunc) Close() error {
+	return c()
+}
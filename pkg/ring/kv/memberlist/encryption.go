@@ -0,0 +1,56 @@
+package memberlist
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+
+	"github.com/hashicorp/memberlist"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Errors logged by memberlist when incoming packets or streams can't be decrypted,
+// or aren't encrypted while encryption is enforced.
+var decryptionFailureMessages = [][]byte{
+	[]byte("Decrypt packet failed"),
+	[]byte("No installed keys could decrypt the message"),
+	[]byte("Encryption is configured but remote state is not encrypted"),
+	[]byte("Remote state is encrypted and encryption is not configured"),
+}
+
+// newKeyring returns a memberlist keyring with the given base64-encoded keys. The
+// first key is the primary one, used to encrypt outgoing messages.
+func newKeyring(encodedKeys []string) (*memberlist.Keyring, error) {
+	keys := make([][]byte, 0, len(encodedKeys))
+	for i, encoded := range encodedKeys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode gossip encryption key #%d", i+1)
+		}
+		keys = append(keys, key)
+	}
+
+	keyring, err := memberlist.NewKeyring(keys, keys[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid gossip encryption keys")
+	}
+	return keyring, nil
+}
+
+// decryptionFailuresCounter is an io.Writer passed as memberlist log output, which
+// counts the logged decryption failures before writing them to the wrapped writer.
+type decryptionFailuresCounter struct {
+	io.Writer
+	failures prometheus.Counter
+}
+
+func (c *decryptionFailuresCounter) Write(p []byte) (int, error) {
+	for _, msg := range decryptionFailureMessages {
+		if bytes.Contains(p, msg) {
+			c.failures.Inc()
+			break
+		}
+	}
+	return c.Writer.Write(p)
+}
@@ -138,6 +138,12 @@ type KVConfig struct {
 	DeadNodeReclaimTime time.Duration `yaml:"dead_node_reclaim_time"`
 	EnableCompression   bool          `yaml:"compression_enabled"`
 
+	// Symmetric keys used to encrypt and authenticate gossip traffic. The first key
+	// is used for encryption, all keys are tried for decryption.
+	GossipEncryptionKeys flagext.StringSliceCSV `yaml:"gossip_encryption_keys"`
+	GossipVerifyIncoming bool                   `yaml:"gossip_verify_incoming"`
+	GossipVerifyOutgoing bool                   `yaml:"gossip_verify_outgoing"`
+
 	// List of members to join
 	JoinMembers      flagext.StringSlice `yaml:"join_members"`
 	MinJoinBackoff   time.Duration       `yaml:"min_join_backoff"`
@@ -189,6 +195,9 @@ func (cfg *KVConfig) RegisterFlagsWithPrefix(f *flag.FlagSet, prefix string) {
 	f.DurationVar(&cfg.DeadNodeReclaimTime, prefix+"memberlist.dead-node-reclaim-time", mlDefaults.DeadNodeReclaimTime, "How soon can dead node's name be reclaimed with new address. 0 to disable.")
 	f.IntVar(&cfg.MessageHistoryBufferBytes, prefix+"memberlist.message-history-buffer-bytes", 0, "How much space to use for keeping received and sent messages in memory for troubleshooting (two buffers). 0 to disable.")
 	f.BoolVar(&cfg.EnableCompression, prefix+"memberlist.compression-enabled", mlDefaults.EnableCompression, "Enable message compression. This can be used to reduce bandwidth usage at the cost of slightly more CPU utilization.")
+	f.Var(&cfg.GossipEncryptionKeys, prefix+"memberlist.gossip-encryption-keys", "Comma-separated list of base64-encoded AES keys (16, 24 or 32 bytes) used to encrypt and authenticate gossip traffic. The first key is used to encrypt outgoing messages, while all keys are used to decrypt incoming ones, so that keys can be rotated by first adding the new key at the end of the list on all nodes, then moving it to the front, and finally removing the old key. Empty to disable encryption.")
+	f.BoolVar(&cfg.GossipVerifyIncoming, prefix+"memberlist.gossip-verify-incoming", mlDefaults.GossipVerifyIncoming, "Reject incoming gossip messages which are not encrypted with any of the configured keys. Can be disabled while enabling encryption on a running cluster. Only used when gossip encryption keys are configured.")
+	f.BoolVar(&cfg.GossipVerifyOutgoing, prefix+"memberlist.gossip-verify-outgoing", mlDefaults.GossipVerifyOutgoing, "Encrypt outgoing gossip messages. Can be disabled while enabling encryption on a running cluster. Only used when gossip encryption keys are configured.")
 
 	cfg.TCPTransport.RegisterFlags(f, prefix)
 }
@@ -254,6 +263,7 @@ type KV struct {
 	numberOfReceivedMessages            prometheus.Counter
 	totalSizeOfReceivedMessages         prometheus.Counter
 	numberOfInvalidReceivedMessages     prometheus.Counter
+	numberOfRejectedMessages            prometheus.Counter
 	numberOfPulls                       prometheus.Counter
 	numberOfPushes                      prometheus.Counter
 	totalSizeOfPulls                    prometheus.Counter
@@ -395,6 +405,18 @@ func (m *KV) buildMemberlistConfig() (*memberlist.Config, error) {
 	mlCfg.LogOutput = newMemberlistLoggerAdapter(m.logger, false)
 	mlCfg.Transport = tr
 
+	if len(m.cfg.GossipEncryptionKeys) > 0 {
+		mlCfg.Keyring, err = newKeyring(m.cfg.GossipEncryptionKeys)
+		if err != nil {
+			return nil, err
+		}
+		mlCfg.GossipVerifyIncoming = m.cfg.GossipVerifyIncoming
+		mlCfg.GossipVerifyOutgoing = m.cfg.GossipVerifyOutgoing
+
+		// Memberlist doesn't expose decryption failures other than by logging them.
+		mlCfg.LogOutput = &decryptionFailuresCounter{Writer: mlCfg.LogOutput, failures: m.numberOfRejectedMessages}
+	}
+
 	// Memberlist uses UDPBufferSize to figure out how many messages it can put into single "packet".
 	// As we don't use UDP for sending packets, we can use higher value here.
 	mlCfg.UDPBufferSize = 10 * 1024 * 1024
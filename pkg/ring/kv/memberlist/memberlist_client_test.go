@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -1074,3 +1075,66 @@ func TestMessageBuffer(t *testing.T) {
 	assert.Len(t, buf, 2)
 	assert.Equal(t, size, 75)
 }
+
+func TestGossipEncryption(t *testing.T) {
+	const (
+		keyA = "ZjAxMjM0NTY3ODlhYmNkZWZmMDEyMzQ1Njc4OWFiY2Q=" // 32 bytes
+		keyB = "MDEyMzQ1Njc4OWFiY2RlZg=="                     // 16 bytes
+		keyC = "ZmVkY2JhOTg3NjU0MzIxMA=="                     // 16 bytes
+	)
+
+	ports, err := getFreePorts(3)
+	require.NoError(t, err)
+
+	newConfig := func(port int, keys ...string) KVConfig {
+		var cfg KVConfig
+		flagext.DefaultValues(&cfg)
+		cfg.TCPTransport = TCPTransportConfig{
+			BindAddrs: []string{"localhost"},
+			BindPort:  port,
+		}
+		cfg.RandomizeNodeName = true
+		cfg.Codecs = []codec.Codec{dataCodec{}}
+		cfg.AbortIfJoinFails = false
+		cfg.MaxJoinRetries = 1
+		cfg.GossipEncryptionKeys = keys
+		return cfg
+	}
+
+	// The first two nodes are in the middle of a key rotation: both keys are installed, but they
+	// use different primary keys.
+	mkv1 := NewKV(newConfig(ports[0], keyA, keyB), log.NewNopLogger())
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), mkv1))
+	defer services.StopAndAwaitTerminated(context.Background(), mkv1) //nolint:errcheck
+
+	cfg2 := newConfig(ports[1], keyB, keyA)
+	cfg2.JoinMembers = []string{fmt.Sprintf("localhost:%d", ports[0])}
+	mkv2 := NewKV(cfg2, log.NewNopLogger())
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), mkv2))
+	defer services.StopAndAwaitTerminated(context.Background(), mkv2) //nolint:errcheck
+
+	test.Poll(t, 5*time.Second, 2, func() interface{} {
+		return mkv1.memberlist.NumMembers()
+	})
+
+	// A node without any of the keys can't join.
+	cfg3 := newConfig(ports[2], keyC)
+	cfg3.JoinMembers = []string{fmt.Sprintf("localhost:%d", ports[0])}
+	mkv3 := NewKV(cfg3, log.NewNopLogger())
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), mkv3))
+	defer services.StopAndAwaitTerminated(context.Background(), mkv3) //nolint:errcheck
+
+	test.Poll(t, 5*time.Second, true, func() interface{} {
+		return testutil.ToFloat64(mkv1.numberOfRejectedMessages) > 0
+	})
+	assert.Equal(t, 2, mkv1.memberlist.NumMembers())
+	assert.Equal(t, 1, mkv3.memberlist.NumMembers())
+}
+
+func TestGossipEncryption_InvalidKeys(t *testing.T) {
+	_, err := newKeyring([]string{"not base64"})
+	require.Error(t, err)
+
+	_, err = newKeyring([]string{"c2hvcnQ="})
+	require.Error(t, err)
+}
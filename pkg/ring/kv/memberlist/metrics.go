@@ -36,6 +36,13 @@ func (m *KV) createAndRegisterMetrics() {
 		Help:      "Number of received broadcast user messages that were invalid. Hopefully 0.",
 	})
 
+	m.numberOfRejectedMessages = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: m.cfg.MetricsNamespace,
+		Subsystem: subsystem,
+		Name:      "received_messages_rejected_total",
+		Help:      "Number of received gossip messages and state exchanges rejected because they were not encrypted with any of the configured gossip encryption keys",
+	})
+
 	m.numberOfPushes = prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: m.cfg.MetricsNamespace,
 		Subsystem: subsystem,
@@ -172,6 +179,7 @@ func (m *KV) createAndRegisterMetrics() {
 		m.numberOfReceivedMessages,
 		m.totalSizeOfReceivedMessages,
 		m.numberOfInvalidReceivedMessages,
+		m.numberOfRejectedMessages,
 		m.numberOfBroadcastMessagesInQueue,
 		m.numberOfPushes,
 		m.numberOfPulls,
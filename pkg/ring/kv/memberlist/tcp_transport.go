@@ -58,6 +58,10 @@ type TCPTransportConfig struct {
 
 	TLSEnabled bool                 `yaml:"tls_enabled"`
 	TLS        tlsutil.ClientConfig `yaml:",inline"`
+
+	// When enabled, incoming connections are only accepted from peers presenting a
+	// client certificate signed by the configured CA.
+	TLSClientAuthEnabled bool `yaml:"tls_client_auth_enabled"`
 }
 
 // RegisterFlags registers flags.
@@ -71,6 +75,7 @@ func (cfg *TCPTransportConfig) RegisterFlags(f *flag.FlagSet, prefix string) {
 
 	f.BoolVar(&cfg.TLSEnabled, prefix+"memberlist.tls-enabled", false, "Enable TLS on the memberlist transport layer.")
 	cfg.TLS.RegisterFlagsWithPrefix(prefix+"memberlist", f)
+	f.BoolVar(&cfg.TLSClientAuthEnabled, prefix+"memberlist.tls-client-auth-enabled", false, "Require peers connecting to the memberlist transport layer to present a client certificate signed by the CA configured via -memberlist.tls-ca-path. The certificate configured via -memberlist.tls-cert-path is presented to both the peers this node connects to and the peers connecting to it.")
 }
 
 // TCPTransport is a memberlist.Transport implementation that uses TCP for both packet and stream
@@ -83,7 +88,8 @@ type TCPTransport struct {
 	connCh       chan net.Conn
 	wg           sync.WaitGroup
 	tcpListeners []net.Listener
-	tlsConfig    *tls.Config
+	tlsConfig    *tls.Config // used to connect to other nodes
+	tlsServer    *tls.Config // used to accept connections from other nodes
 
 	shutdown atomic.Int32
 
@@ -102,6 +108,7 @@ type TCPTransport struct {
 	sentPacketsBytes      prometheus.Counter
 	sentPacketsErrors     prometheus.Counter
 	unknownConnections    prometheus.Counter
+	rejectedConnections   prometheus.Counter
 }
 
 // NewTCPTransport returns a new tcp-based transport with the given configuration. On
@@ -126,6 +133,17 @@ func NewTCPTransport(config TCPTransportConfig, logger log.Logger) (*TCPTranspor
 		if err != nil {
 			return nil, errors.Wrap(err, "unable to create TLS config")
 		}
+
+		t.tlsServer = t.tlsConfig.Clone()
+		if config.TLSClientAuthEnabled {
+			if t.tlsConfig.RootCAs == nil {
+				return nil, errors.New("memberlist TLS client authentication requires the CA certificates to be configured")
+			}
+			t.tlsServer.ClientCAs = t.tlsConfig.RootCAs
+			t.tlsServer.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	} else if config.TLSClientAuthEnabled {
+		return nil, errors.New("memberlist TLS client authentication requires TLS to be enabled")
 	}
 
 	t.registerMetrics()
@@ -146,7 +164,7 @@ func NewTCPTransport(config TCPTransportConfig, logger log.Logger) (*TCPTranspor
 
 		var tcpLn net.Listener
 		if config.TLSEnabled {
-			tcpLn, err = tls.Listen("tcp", tcpAddr.String(), t.tlsConfig)
+			tcpLn, err = tls.Listen("tcp", tcpAddr.String(), t.tlsServer)
 			if err != nil {
 				return nil, errors.Wrapf(err, "failed to start TLS TCP listener on %q port %d", addr, port)
 			}
@@ -240,6 +258,16 @@ func (t *TCPTransport) handleConnection(conn net.Conn) {
 		}
 	}()
 
+	// Complete the TLS handshake explicitly, so that peers which failed to authenticate
+	// are distinguished from other errors.
+	if tlsConn, ok := conn.(*tls.Conn); ok {
+		if err := tlsConn.Handshake(); err != nil {
+			t.rejectedConnections.Inc()
+			level.Warn(t.logger).Log("msg", "TCPTransport: TLS handshake failed, rejecting connection", "addr", conn.RemoteAddr(), "err", err)
+			return
+		}
+	}
+
 	// let's read first byte, and determine what to do about this connection
 	msgType := []byte{0}
 	_, err := io.ReadFull(conn, msgType)
@@ -618,6 +646,13 @@ func (t *TCPTransport) registerMetrics() {
 		Help:      "Number of unknown TCP connections (not a packet or stream)",
 	})
 
+	t.rejectedConnections = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: t.cfg.MetricsNamespace,
+		Subsystem: subsystem,
+		Name:      "rejected_connections_total",
+		Help:      "Number of incoming TCP connections rejected because the TLS handshake failed, eg. because the peer didn't present a valid client certificate",
+	})
+
 	if t.cfg.MetricsRegisterer == nil {
 		return
 	}
@@ -633,6 +668,7 @@ func (t *TCPTransport) registerMetrics() {
 		t.sentPacketsBytes,
 		t.sentPacketsErrors,
 		t.unknownConnections,
+		t.rejectedConnections,
 	}
 
 	// if registration fails, that's too bad, but don't panic
@@ -0,0 +1,102 @@
+package memberlist
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cortexproject/cortex/integration/ca"
+	"github.com/cortexproject/cortex/pkg/util/test"
+	tlsutil "github.com/cortexproject/cortex/pkg/util/tls"
+)
+
+func TestTCPTransport_TLSClientAuth(t *testing.T) {
+	dir, err := ioutil.TempDir("", "memberlist-tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) //nolint:errcheck
+
+	authority := ca.New("memberlist")
+	caPath := filepath.Join(dir, "ca.crt")
+	require.NoError(t, authority.WriteCACertificate(caPath))
+
+	certPath, keyPath := filepath.Join(dir, "node.crt"), filepath.Join(dir, "node.key")
+	require.NoError(t, authority.WriteCertificate(&x509.Certificate{
+		Subject:     pkix.Name{CommonName: "node"},
+		DNSNames:    []string{"localhost"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}, certPath, keyPath))
+
+	newTransport := func(t *testing.T, tlsCfg tlsutil.ClientConfig, clientAuth bool) *TCPTransport {
+		tr, err := NewTCPTransport(TCPTransportConfig{
+			BindAddrs:            []string{"127.0.0.1"},
+			PacketDialTimeout:    time.Second,
+			PacketWriteTimeout:   time.Second,
+			TLSEnabled:           true,
+			TLS:                  tlsCfg,
+			TLSClientAuthEnabled: clientAuth,
+		}, log.NewNopLogger())
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = tr.Shutdown() })
+
+		_, _, err = tr.FinalAdvertiseAddr("127.0.0.1", tr.GetAutoBindPort())
+		require.NoError(t, err)
+		return tr
+	}
+
+	authenticated := tlsutil.ClientConfig{CAPath: caPath, CertPath: certPath, KeyPath: keyPath}
+	server := newTransport(t, authenticated, true)
+	serverAddr := fmt.Sprintf("127.0.0.1:%d", server.GetAutoBindPort())
+
+	t.Run("peer with a valid client certificate is accepted", func(t *testing.T) {
+		client := newTransport(t, authenticated, false)
+		require.NoError(t, client.writeTo([]byte("hello"), serverAddr))
+
+		select {
+		case p := <-server.PacketCh():
+			assert.Equal(t, []byte("hello"), p.Buf)
+		case <-time.After(5 * time.Second):
+			t.Fatal("packet not received")
+		}
+		assert.Equal(t, float64(0), testutil.ToFloat64(server.rejectedConnections))
+	})
+
+	t.Run("peer without client certificate is rejected", func(t *testing.T) {
+		cfg := tlsutil.ClientConfig{CAPath: caPath}
+		tlsCfg, err := cfg.GetTLSConfig()
+		require.NoError(t, err)
+
+		conn, err := tls.Dial("tcp", serverAddr, tlsCfg)
+		if err == nil {
+			// With TLS 1.3 the server may reject the client certificate after the client completed the handshake.
+			_, _ = conn.Write([]byte{byte(packet)})
+			_ = conn.Close()
+		}
+
+		test.Poll(t, 5*time.Second, float64(1), func() interface{} {
+			return testutil.ToFloat64(server.rejectedConnections)
+		})
+	})
+
+	t.Run("client authentication requires CA certificates", func(t *testing.T) {
+		_, err := NewTCPTransport(TCPTransportConfig{
+			BindAddrs:            []string{"127.0.0.1"},
+			TLSEnabled:           true,
+			TLS:                  tlsutil.ClientConfig{CertPath: certPath, KeyPath: keyPath},
+			TLSClientAuthEnabled: true,
+		}, log.NewNopLogger())
+		require.Error(t, err)
+	})
+}
@@ -6,7 +6,9 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"reflect"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -36,6 +38,10 @@ var (
 		Name: "cortex_member_ring_tokens_to_own",
 		Help: "The number of tokens to own in the ring.",
 	}, []string{"name"})
+	autoForgottenInstances = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cortex_member_ring_auto_forgotten_instances_total",
+		Help: "The total number of unhealthy instances automatically forgotten from the ring.",
+	}, []string{"name"})
 	shutdownDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Name:    "cortex_shutdown_duration_seconds",
 		Help:    "Duration (in seconds) of cortex shutdown procedure (ie transfer or flush).",
@@ -59,6 +65,13 @@ type LifecyclerConfig struct {
 	Zone                 string        `yaml:"availability_zone"`
 	UnregisterOnShutdown bool          `yaml:"unregister_on_shutdown"`
 
+	TokenGenerator TokenGeneratorConfig `yaml:",inline"`
+
+	// Config for the auto-forget of unhealthy instances
+	AutoForgetUnhealthy                  bool `yaml:"auto_forget_unhealthy"`
+	AutoForgetUnhealthyTimeoutMultiplier int  `yaml:"auto_forget_unhealthy_timeout_multiplier"`
+	AutoForgetMaxInstancesPerZone        int  `yaml:"auto_forget_max_instances_per_zone"`
+
 	// For testing, you can override the address and ID of this ingester
 	Addr string `yaml:"address" doc:"hidden"`
 	Port int    `doc:"hidden"`
@@ -90,6 +103,10 @@ func (cfg *LifecyclerConfig) RegisterFlagsWithPrefix(prefix string, f *flag.Flag
 	f.DurationVar(&cfg.MinReadyDuration, prefix+"min-ready-duration", 1*time.Minute, "Minimum duration to wait before becoming ready. This is to work around race conditions with ingesters exiting and updating the ring.")
 	f.DurationVar(&cfg.FinalSleep, prefix+"final-sleep", 30*time.Second, "Duration to sleep for before exiting, to ensure metrics are scraped.")
 	f.StringVar(&cfg.TokensFilePath, prefix+"tokens-file-path", "", "File path where tokens are stored. If empty, tokens are not stored at shutdown and restored at startup.")
+	cfg.TokenGenerator.RegisterFlagsWithPrefix(prefix, f)
+	f.BoolVar(&cfg.AutoForgetUnhealthy, prefix+"auto-forget-unhealthy", false, "Automatically forget the instances which have been unhealthy for longer than the heartbeat timeout multiplied by -"+prefix+"auto-forget-unhealthy-timeout-multiplier. The instances are forgotten by the other instances of the ring, at heartbeat.")
+	f.IntVar(&cfg.AutoForgetUnhealthyTimeoutMultiplier, prefix+"auto-forget-unhealthy-timeout-multiplier", 10, "How many times the heartbeat timeout an instance must be unhealthy for before being automatically forgotten.")
+	f.IntVar(&cfg.AutoForgetMaxInstancesPerZone, prefix+"auto-forget-max-instances-per-zone", 1, "Maximum number of instances of the same zone which can be automatically forgotten at once. If more instances of a zone should be forgotten, none of them is, because the failure is more likely a network partition or a zone outage than lost instances.")
 
 	hostname, err := os.Hostname()
 	if err != nil {
@@ -106,6 +123,27 @@ func (cfg *LifecyclerConfig) RegisterFlagsWithPrefix(prefix string, f *flag.Flag
 	f.BoolVar(&cfg.UnregisterOnShutdown, prefix+"unregister-on-shutdown", true, "Unregister from the ring upon clean shutdown. It can be useful to disable for rolling restarts with consistent naming in conjunction with -distributor.extend-writes=false.")
 }
 
+// Validate the config.
+func (cfg *LifecyclerConfig) Validate() error {
+	if err := cfg.TokenGenerator.Validate(); err != nil {
+		return err
+	}
+
+	if cfg.AutoForgetUnhealthy {
+		if cfg.RingConfig.HeartbeatTimeout <= 0 {
+			return errors.New("the auto-forget of unhealthy instances requires the heartbeat timeout to be enabled")
+		}
+		if cfg.AutoForgetUnhealthyTimeoutMultiplier < 1 {
+			return errors.New("the auto-forget unhealthy timeout multiplier must be at least 1")
+		}
+		if cfg.AutoForgetMaxInstancesPerZone < 1 {
+			return errors.New("the auto-forget max instances per zone must be at least 1")
+		}
+	}
+
+	return nil
+}
+
 // Lifecycler is responsible for managing the lifecycle of entries in the ring.
 type Lifecycler struct {
 	*services.BasicService
@@ -123,6 +161,8 @@ type Lifecycler struct {
 	RingKey  string
 	Zone     string
 
+	tokenGenerator TokenGenerator
+
 	// Whether to flush if transfer fails on shutdown.
 	flushOnShutdown      *atomic.Bool
 	unregisterOnShutdown *atomic.Bool
@@ -143,6 +183,20 @@ type Lifecycler struct {
 	countersLock          sync.RWMutex
 	healthyInstancesCount int
 	zonesCount            int
+	writeExtending        []string  // Sorted IDs of the instances whose state extends the Write replication set.
+	writeExtendingSince   time.Time // When writeExtending last changed.
+
+	// Read-only copy of the ring, updated at every heartbeat period, used to compute the
+	// instance ownership. The ownership is cached by tenant and shard size, and the cache
+	// is invalidated whenever the ring topology or the writeExtending instances change.
+	ring             *Ring
+	ownershipMtx     sync.Mutex
+	ownership        map[subringCacheKey]float64
+	ownershipVersion ownershipVersion
+
+	// Unhealthy instances, by zone, not auto-forgotten at the last heartbeat because too many
+	// instances of their zone are unhealthy. Only accessed by the lifecycler loop.
+	autoForgetSkipped map[string][]string
 }
 
 // NewLifecycler creates new Lifecycler. It must be started via StartAsync.
@@ -168,6 +222,11 @@ func NewLifecycler(cfg LifecyclerConfig, flushTransferer FlushTransferer, ringNa
 		log.WarnExperimentalUse("Zone aware replication")
 	}
 
+	tokenGenerator, err := NewTokenGenerator(cfg.TokenGenerator, cfg.ID, zone)
+	if err != nil {
+		return nil, err
+	}
+
 	// We do allow a nil FlushTransferer, but to keep the ring logic easier we assume
 	// it's always set, so we use a noop FlushTransferer
 	if flushTransferer == nil {
@@ -186,11 +245,19 @@ func NewLifecycler(cfg LifecyclerConfig, flushTransferer FlushTransferer, ringNa
 		flushOnShutdown:      atomic.NewBool(flushOnShutdown),
 		unregisterOnShutdown: atomic.NewBool(cfg.UnregisterOnShutdown),
 		Zone:                 zone,
+		tokenGenerator:       tokenGenerator,
 
 		actorChan: make(chan func()),
 
 		state:     PENDING,
 		startTime: time.Now(),
+
+		ring: &Ring{
+			cfg:                  cfg.RingConfig,
+			ringDesc:             &Desc{},
+			shuffledSubringCache: map[subringCacheKey]*Ring{},
+		},
+		ownership: map[subringCacheKey]float64{},
 	}
 
 	tokensToOwn.WithLabelValues(l.RingName).Set(float64(cfg.NumTokens))
@@ -382,6 +449,62 @@ func (i *Lifecycler) ZonesCount() int {
 	return i.zonesCount
 }
 
+// ownershipVersion identifies the ring used to compute the cached instance ownership.
+type ownershipVersion struct {
+	topology       time.Time
+	writeExtending time.Time
+}
+
+// InstanceOwnership returns the fraction of the keys (eg. series) of the given identifier (eg. a
+// tenant ID) for which this instance is part of the Write replication set, once the identifier is
+// shuffle sharded across the given number of instances (0 to use all instances). Keys are assumed to
+// be evenly distributed across the ring. The ownership is based on the ring as of the last heartbeat
+// period, and it's 0 if this instance doesn't own any token in the identifier's shard.
+func (i *Lifecycler) InstanceOwnership(identifier string, shardSize int) float64 {
+	i.ring.mtx.RLock()
+	version := ownershipVersion{topology: i.ring.lastTopologyChange}
+	i.ring.mtx.RUnlock()
+
+	i.countersLock.RLock()
+	extending := i.writeExtending
+	version.writeExtending = i.writeExtendingSince
+	i.countersLock.RUnlock()
+
+	// Without shuffle sharding the whole ring is used, so the ownership is the same for all identifiers.
+	key := subringCacheKey{}
+	if shardSize > 0 {
+		key = subringCacheKey{identifier: identifier, shardSize: shardSize}
+	}
+
+	i.ownershipMtx.Lock()
+	if i.ownershipVersion != version {
+		i.ownership = map[subringCacheKey]float64{}
+		i.ownershipVersion = version
+	}
+	owned, ok := i.ownership[key]
+	i.ownershipMtx.Unlock()
+
+	if ok {
+		return owned
+	}
+
+	subring := i.ring.ShuffleShard(identifier, shardSize).(*Ring)
+	owned = subring.replicatedOwnership(i.ID, extending)
+
+	subring.mtx.RLock()
+	version.topology = subring.lastTopologyChange
+	subring.mtx.RUnlock()
+
+	i.ownershipMtx.Lock()
+	// Only cache if the ring hasn't changed in the meanwhile.
+	if i.ownershipVersion == version {
+		i.ownership[key] = owned
+	}
+	i.ownershipMtx.Unlock()
+
+	return owned
+}
+
 func (i *Lifecycler) loop(ctx context.Context) error {
 	// First, see if we exist in the cluster, update our state to match if we do,
 	// and add ourselves (without tokens) if we don't.
@@ -544,7 +667,12 @@ func (i *Lifecycler) initRing(ctx context.Context) error {
 			registeredAt := time.Now()
 			i.setRegisteredAt(registeredAt)
 
-			// We use the tokens from the file only if it does not exist in the ring yet.
+			// We use the tokens from the file only if it does not exist in the ring yet, and
+			// only if they're allowed by the token generation strategy.
+			if len(tokensFromFile) > 0 && !i.tokenGenerator.CanKeepTokens(tokensFromFile, ringDesc.GetTokens()) {
+				level.Info(log.Logger).Log("msg", "not using tokens from file, they don't match the token generation strategy", "strategy", i.cfg.TokenGenerator.Strategy, "ring", i.RingName)
+				tokensFromFile = nil
+			}
 			if len(tokensFromFile) > 0 {
 				level.Info(log.Logger).Log("msg", "adding tokens from file", "num_tokens", len(tokensFromFile))
 				if len(tokensFromFile) >= i.cfg.NumTokens {
@@ -584,7 +712,19 @@ func (i *Lifecycler) initRing(ctx context.Context) error {
 
 		// We exist in the ring, so assume the ring is right and copy out tokens & state out of there.
 		i.setState(instanceDesc.State)
-		tokens, _ := ringDesc.TokensFor(i.ID)
+		tokens, takenTokens := ringDesc.TokensFor(i.ID)
+
+		// The tokens in the ring don't match the token generation strategy (eg. the strategy
+		// has been changed), so we replace them.
+		if len(tokens) > 0 && !i.tokenGenerator.CanKeepTokens(tokens, takenTokens) {
+			level.Info(log.Logger).Log("msg", "replacing the tokens found in ring, they don't match the token generation strategy", "strategy", i.cfg.TokenGenerator.Strategy, "ring", i.RingName)
+			tokens = InstanceTokens(i.tokenGenerator, len(tokens), nil, takenTokens)
+			i.setTokens(tokens)
+
+			instanceDesc.Tokens = tokens
+			ringDesc.Ingesters[i.ID] = instanceDesc
+			return ringDesc, true, nil
+		}
 		i.setTokens(tokens)
 
 		level.Info(log.Logger).Log("msg", "existing entry found in ring", "state", i.GetState(), "tokens", len(tokens), "ring", i.RingName)
@@ -622,10 +762,7 @@ func (i *Lifecycler) verifyTokens(ctx context.Context) bool {
 			needTokens := i.cfg.NumTokens - len(ringTokens)
 
 			level.Info(log.Logger).Log("msg", "generating new tokens", "count", needTokens, "ring", i.RingName)
-			newTokens := GenerateTokens(needTokens, takenTokens)
-
-			ringTokens = append(ringTokens, newTokens...)
-			sort.Sort(ringTokens)
+			ringTokens = InstanceTokens(i.tokenGenerator, i.cfg.NumTokens, ringTokens, takenTokens)
 
 			ringDesc.AddIngester(i.ID, i.Addr, i.Zone, ringTokens, i.GetState(), i.getRegisteredAt())
 
@@ -682,11 +819,8 @@ func (i *Lifecycler) autoJoin(ctx context.Context, targetState InstanceState) er
 			level.Error(log.Logger).Log("msg", "tokens already exist for this instance - wasn't expecting any!", "num_tokens", len(myTokens), "ring", i.RingName)
 		}
 
-		newTokens := GenerateTokens(i.cfg.NumTokens-len(myTokens), takenTokens)
+		myTokens = InstanceTokens(i.tokenGenerator, i.cfg.NumTokens, myTokens, takenTokens)
 		i.setState(targetState)
-
-		myTokens = append(myTokens, newTokens...)
-		sort.Sort(myTokens)
 		i.setTokens(myTokens)
 
 		ringDesc.AddIngester(i.ID, i.Addr, i.Zone, i.getTokens(), i.GetState(), i.getRegisteredAt())
@@ -705,7 +839,11 @@ func (i *Lifecycler) autoJoin(ctx context.Context, targetState InstanceState) er
 // updateConsul updates our entries in consul, heartbeating and dealing with
 // consul restarts.
 func (i *Lifecycler) updateConsul(ctx context.Context) error {
-	var ringDesc *Desc
+	var (
+		ringDesc  *Desc
+		forgotten []string
+		skipped   map[string][]string
+	)
 
 	err := i.KVStore.CAS(ctx, i.RingKey, func(in interface{}) (out interface{}, retry bool, err error) {
 		if in == nil {
@@ -714,6 +852,12 @@ func (i *Lifecycler) updateConsul(ctx context.Context) error {
 			ringDesc = in.(*Desc)
 		}
 
+		forgotten, skipped = nil, nil
+		if i.cfg.AutoForgetUnhealthy {
+			forgetPeriod := i.cfg.RingConfig.HeartbeatTimeout * time.Duration(i.cfg.AutoForgetUnhealthyTimeoutMultiplier)
+			forgotten, skipped = autoForgetUnhealthyInstances(ringDesc, i.ID, forgetPeriod, i.cfg.AutoForgetMaxInstancesPerZone, time.Now())
+		}
+
 		instanceDesc, ok := ringDesc.Ingesters[i.ID]
 		if !ok {
 			// consul must have restarted
@@ -733,12 +877,61 @@ func (i *Lifecycler) updateConsul(ctx context.Context) error {
 
 	// Update counters
 	if err == nil {
+		for _, id := range forgotten {
+			level.Warn(log.Logger).Log("msg", "auto-forgot instance from the ring because it has been unhealthy for a long time", "instance", id, "ring", i.RingName)
+		}
+		autoForgottenInstances.WithLabelValues(i.RingName).Add(float64(len(forgotten)))
+
+		// The skipped instances are logged only when they change, because all the instances
+		// of the ring would otherwise log the same warning at every heartbeat.
+		if !reflect.DeepEqual(skipped, i.autoForgetSkipped) {
+			for zone, ids := range skipped {
+				level.Warn(log.Logger).Log("msg", "not auto-forgetting unhealthy instances because too many instances of the zone are unhealthy", "zone", zone, "unhealthy", strings.Join(ids, ","), "max", i.cfg.AutoForgetMaxInstancesPerZone, "ring", i.RingName)
+			}
+			i.autoForgetSkipped = skipped
+		}
+
 		i.updateCounters(ringDesc)
 	}
 
 	return err
 }
 
+// autoForgetUnhealthyInstances removes from the ring the instances, except selfID, whose last heartbeat is older
+// than forgetPeriod, and returns their IDs. If more than maxPerZone instances of a zone should be forgotten, none
+// of them is, because such a failure is more likely a network partition or a zone outage than lost instances.
+// The instances not forgotten for this reason are returned by zone.
+func autoForgetUnhealthyInstances(ringDesc *Desc, selfID string, forgetPeriod time.Duration, maxPerZone int, now time.Time) (forgotten []string, skipped map[string][]string) {
+	candidates := map[string][]string{}
+	for id, instance := range ringDesc.Ingesters {
+		if id == selfID || instance.State == LEFT {
+			continue
+		}
+		if now.Sub(time.Unix(instance.GetTimestamp(), 0)) > forgetPeriod {
+			candidates[instance.Zone] = append(candidates[instance.Zone], id)
+		}
+	}
+
+	for zone, ids := range candidates {
+		if len(ids) > maxPerZone {
+			if skipped == nil {
+				skipped = map[string][]string{}
+			}
+			sort.Strings(ids)
+			skipped[zone] = ids
+			continue
+		}
+
+		for _, id := range ids {
+			ringDesc.RemoveIngester(id)
+			forgotten = append(forgotten, id)
+		}
+	}
+
+	sort.Strings(forgotten)
+	return forgotten, skipped
+}
+
 // changeState updates consul with state transitions for us.  NB this must be
 // called from loop()!  Use ChangeState for calls from outside of loop().
 func (i *Lifecycler) changeState(ctx context.Context, state InstanceState) error {
@@ -748,7 +941,10 @@ func (i *Lifecycler) changeState(ctx context.Context, state InstanceState) error
 		(currState == JOINING && state == PENDING) || // triggered by TransferChunks on failure
 		(currState == JOINING && state == ACTIVE) || // triggered by TransferChunks on success
 		(currState == PENDING && state == ACTIVE) || // triggered by autoJoin
-		(currState == ACTIVE && state == LEAVING)) { // triggered by shutdown
+		(currState == ACTIVE && state == LEAVING) || // triggered by shutdown
+		(currState == ACTIVE && state == READONLY) || // triggered by the read-only API
+		(currState == READONLY && state == ACTIVE) || // triggered by the read-only API
+		(currState == READONLY && state == LEAVING)) { // triggered by shutdown
 		return fmt.Errorf("Changing instance state from %v -> %v is disallowed", currState, state)
 	}
 
@@ -760,25 +956,39 @@ func (i *Lifecycler) changeState(ctx context.Context, state InstanceState) error
 func (i *Lifecycler) updateCounters(ringDesc *Desc) {
 	healthyInstancesCount := 0
 	zones := map[string]struct{}{}
+	var writeExtending []string
 
 	if ringDesc != nil {
 		now := time.Now()
 
-		for _, ingester := range ringDesc.Ingesters {
+		for id, ingester := range ringDesc.Ingesters {
 			zones[ingester.Zone] = struct{}{}
 
 			// Count the number of healthy instances for Write operation.
 			if ingester.IsHealthy(Write, i.cfg.RingConfig.HeartbeatTimeout, now) {
 				healthyInstancesCount++
 			}
+
+			if Write.ShouldExtendReplicaSetOnState(ingester.State) {
+				writeExtending = append(writeExtending, id)
+			}
 		}
 	}
+	sort.Strings(writeExtending)
 
 	// Update counters
 	i.countersLock.Lock()
 	i.healthyInstancesCount = healthyInstancesCount
 	i.zonesCount = len(zones)
+	if !reflect.DeepEqual(i.writeExtending, writeExtending) {
+		i.writeExtending = writeExtending
+		i.writeExtendingSince = time.Now()
+	}
 	i.countersLock.Unlock()
+
+	if ringDesc != nil {
+		i.ring.updateRingState(ringDesc)
+	}
 }
 
 // FlushOnShutdown returns if flushing is enabled if transfer fails on a shutdown.
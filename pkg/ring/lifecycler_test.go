@@ -129,6 +129,64 @@ func TestLifecycler_ZonesCount(t *testing.T) {
 	}
 }
 
+func TestLifecycler_InstanceOwnership(t *testing.T) {
+	var ringConfig Config
+	flagext.DefaultValues(&ringConfig)
+	ringConfig.KVStore.Mock = consul.NewInMemoryClient(GetCodec())
+	ringConfig.ReplicationFactor = 1
+
+	ctx := context.Background()
+
+	var lifecyclers []*Lifecycler
+	for _, id := range []string{"ing1", "ing2"} {
+		cfg := testLifecyclerConfig(ringConfig, id)
+		cfg.NumTokens = 64
+		cfg.JoinAfter = 100 * time.Millisecond
+
+		l, err := NewLifecycler(cfg, &nopFlushTransferer{}, "ingester", IngesterRingKey, true, nil)
+		require.NoError(t, err)
+		assert.Equal(t, float64(0), l.InstanceOwnership("user-1", 0))
+
+		require.NoError(t, services.StartAndAwaitRunning(ctx, l))
+		defer services.StopAndAwaitTerminated(ctx, l) // nolint:errcheck
+
+		lifecyclers = append(lifecyclers, l)
+	}
+
+	// Wait until both ingesters have seen each other in the ring.
+	for _, l := range lifecyclers {
+		l := l
+		test.Poll(t, time.Second, 2, func() interface{} {
+			return l.HealthyInstancesCount()
+		})
+	}
+
+	// Each key is owned by exactly one ingester.
+	owned1 := lifecyclers[0].InstanceOwnership("user-1", 0)
+	owned2 := lifecyclers[1].InstanceOwnership("user-1", 0)
+	assert.Greater(t, owned1, float64(0))
+	assert.Greater(t, owned2, float64(0))
+	assert.InDelta(t, 1, owned1+owned2, 0.000001)
+
+	// A tenant sharded on a single ingester is fully owned by it.
+	assert.InDelta(t, 1, lifecyclers[0].InstanceOwnership("user-1", 1)+lifecyclers[1].InstanceOwnership("user-1", 1), 0.000001)
+	assert.Contains(t, []float64{0, 1}, lifecyclers[0].InstanceOwnership("user-1", 1))
+
+	// Without shuffle sharding, the ownership is cached once for all tenants.
+	assert.Equal(t, owned1, lifecyclers[0].InstanceOwnership("user-2", 0))
+	lifecyclers[0].ownershipMtx.Lock()
+	assert.Contains(t, lifecyclers[0].ownership, subringCacheKey{})
+	assert.NotContains(t, lifecyclers[0].ownership, subringCacheKey{identifier: "user-2"})
+	lifecyclers[0].ownershipMtx.Unlock()
+
+	// The Write replication set is extended past a LEAVING ingester, so the other
+	// ingester receives all the keys.
+	require.NoError(t, lifecyclers[1].ChangeState(ctx, LEAVING))
+	test.Poll(t, time.Second, float64(1), func() interface{} {
+		return lifecyclers[0].InstanceOwnership("user-1", 0)
+	})
+}
+
 func TestLifecycler_NilFlushTransferer(t *testing.T) {
 	var ringConfig Config
 	flagext.DefaultValues(&ringConfig)
@@ -504,6 +562,165 @@ func TestJoinInJoiningState(t *testing.T) {
 	})
 }
 
+func TestLifecycler_ShouldReplaceTokensNotMatchingTheTokenGenerationStrategy(t *testing.T) {
+	var ringConfig Config
+	flagext.DefaultValues(&ringConfig)
+	ringConfig.KVStore.Mock = consul.NewInMemoryClient(GetCodec())
+
+	r, err := New(ringConfig, "ingester", IngesterRingKey, nil)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), r))
+	defer services.StopAndAwaitTerminated(context.Background(), r) //nolint:errcheck
+
+	cfg := testLifecyclerConfig(ringConfig, "ing-1")
+	cfg.NumTokens = 4
+	cfg.MinReadyDuration = 1 * time.Nanosecond
+	cfg.TokenGenerator.Strategy = SpreadMinimizingTokenGeneration
+
+	// Register the instance with random tokens, as if it was running with the random strategy.
+	err = r.KVClient.CAS(context.Background(), IngesterRingKey, func(in interface{}) (interface{}, bool, error) {
+		return &Desc{
+			Ingesters: map[string]InstanceDesc{
+				"ing-1": {State: ACTIVE, Tokens: []uint32{1, 2, 3, 4}},
+				"ing-0": {State: ACTIVE, Tokens: []uint32{5, 6, 7, 8}},
+			},
+		}, true, nil
+	})
+	require.NoError(t, err)
+
+	l1, err := NewLifecycler(cfg, &nopFlushTransferer{}, "ingester", IngesterRingKey, true, nil)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), l1))
+	defer services.StopAndAwaitTerminated(context.Background(), l1) //nolint:errcheck
+
+	generator, err := NewSpreadMinimizingTokenGenerator("ing-1", "", nil)
+	require.NoError(t, err)
+	expected := generator.GenerateTokens(cfg.NumTokens, []uint32{5, 6, 7, 8})
+
+	test.Poll(t, time.Second, true, func() interface{} {
+		d, err := r.KVClient.Get(context.Background(), IngesterRingKey)
+		require.NoError(t, err)
+
+		desc, ok := d.(*Desc)
+		return ok &&
+			desc.Ingesters["ing-1"].State == ACTIVE &&
+			Tokens(desc.Ingesters["ing-1"].Tokens).Equals(expected) &&
+			Tokens(desc.Ingesters["ing-0"].Tokens).Equals(Tokens{5, 6, 7, 8})
+	})
+	assert.Equal(t, expected, l1.getTokens())
+}
+
+func TestLifecyclerConfig_Validate(t *testing.T) {
+	var cfg LifecyclerConfig
+	flagext.DefaultValues(&cfg)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.AutoForgetUnhealthy = true
+	assert.NoError(t, cfg.Validate())
+
+	cfg.AutoForgetUnhealthyTimeoutMultiplier = 0
+	assert.Error(t, cfg.Validate())
+
+	cfg.AutoForgetUnhealthyTimeoutMultiplier = 10
+	cfg.AutoForgetMaxInstancesPerZone = 0
+	assert.Error(t, cfg.Validate())
+
+	cfg.AutoForgetMaxInstancesPerZone = 1
+	cfg.RingConfig.HeartbeatTimeout = 0
+	assert.Error(t, cfg.Validate())
+}
+
+func TestAutoForgetUnhealthyInstances(t *testing.T) {
+	now := time.Now()
+	healthy := now.Add(-time.Second).Unix()
+	unhealthy := now.Add(-time.Hour).Unix()
+
+	tests := map[string]struct {
+		instances       map[string]InstanceDesc
+		expected        []string
+		expectedSkipped map[string][]string
+	}{
+		"should forget unhealthy instances": {
+			instances: map[string]InstanceDesc{
+				"ing-1": {Zone: "zone-a", Timestamp: healthy},
+				"ing-2": {Zone: "zone-a", Timestamp: unhealthy},
+				"ing-3": {Zone: "zone-b", Timestamp: unhealthy},
+			},
+			expected: []string{"ing-2", "ing-3"},
+		},
+		"should never forget itself": {
+			instances: map[string]InstanceDesc{
+				"ing-1": {Zone: "zone-a", Timestamp: unhealthy},
+			},
+			expected: nil,
+		},
+		"should not forget instances of a zone with too many unhealthy instances": {
+			instances: map[string]InstanceDesc{
+				"ing-1": {Zone: "zone-a", Timestamp: healthy},
+				"ing-2": {Zone: "zone-a", Timestamp: unhealthy},
+				"ing-3": {Zone: "zone-b", Timestamp: unhealthy},
+				"ing-4": {Zone: "zone-b", Timestamp: unhealthy},
+			},
+			expected:        []string{"ing-2"},
+			expectedSkipped: map[string][]string{"zone-b": {"ing-3", "ing-4"}},
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			desc := &Desc{Ingesters: testData.instances}
+			before := len(desc.Ingesters)
+
+			forgotten, skipped := autoForgetUnhealthyInstances(desc, "ing-1", time.Minute, 1, now)
+			assert.Equal(t, testData.expected, forgotten)
+			assert.Equal(t, testData.expectedSkipped, skipped)
+			assert.Len(t, desc.Ingesters, before-len(testData.expected))
+			for _, id := range testData.expected {
+				assert.NotContains(t, desc.Ingesters, id)
+			}
+		})
+	}
+}
+
+func TestLifecycler_ShouldAutoForgetUnhealthyInstances(t *testing.T) {
+	var ringConfig Config
+	flagext.DefaultValues(&ringConfig)
+	ringConfig.HeartbeatTimeout = time.Second
+	ringConfig.KVStore.Mock = consul.NewInMemoryClient(GetCodec())
+
+	// Register an instance which hasn't heartbeated for a long time.
+	err := ringConfig.KVStore.Mock.CAS(context.Background(), IngesterRingKey, func(in interface{}) (interface{}, bool, error) {
+		desc := NewDesc()
+		desc.AddIngester("ing-2", "127.0.0.2", "zone1", []uint32{1}, ACTIVE, time.Now())
+		instance := desc.Ingesters["ing-2"]
+		instance.Timestamp = time.Now().Add(-time.Hour).Unix()
+		desc.Ingesters["ing-2"] = instance
+		return desc, true, nil
+	})
+	require.NoError(t, err)
+
+	cfg := testLifecyclerConfig(ringConfig, "ing-1")
+	cfg.AutoForgetUnhealthy = true
+	require.NoError(t, cfg.Validate())
+
+	l1, err := NewLifecycler(cfg, &nopFlushTransferer{}, "ingester", IngesterRingKey, true, nil)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), l1))
+	defer services.StopAndAwaitTerminated(context.Background(), l1) //nolint:errcheck
+
+	test.Poll(t, time.Second, true, func() interface{} {
+		d, err := ringConfig.KVStore.Mock.Get(context.Background(), IngesterRingKey)
+		require.NoError(t, err)
+
+		desc, ok := d.(*Desc)
+		if !ok {
+			return false
+		}
+		_, found := desc.Ingesters["ing-2"]
+		return len(desc.Ingesters) == 1 && !found
+	})
+}
+
 func TestRestoreOfZoneWhenOverwritten(t *testing.T) {
 	// This test is simulating a case during upgrade of pre 1.0 cortex where
 	// older ingesters do not have the zone field in their ring structs
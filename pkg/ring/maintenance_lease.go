@@ -0,0 +1,250 @@
+package ring
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/cortexproject/cortex/pkg/ring/kv"
+	"github.com/cortexproject/cortex/pkg/ring/kv/codec"
+	"github.com/cortexproject/cortex/pkg/ring/kv/memberlist"
+)
+
+var (
+	// ErrMaintenanceLeaseHeld is returned when the maintenance lease is held by another zone.
+	ErrMaintenanceLeaseHeld = errors.New("the maintenance lease is held by another zone")
+
+	// ErrMaintenanceNotAllowed is returned when an instance of another zone is not ACTIVE and healthy.
+	ErrMaintenanceNotAllowed = errors.New("an instance of another zone is not ACTIVE and healthy")
+)
+
+// ProtoMaintenanceLeaseFactory makes new MaintenanceLease.
+func ProtoMaintenanceLeaseFactory() proto.Message {
+	return &MaintenanceLease{}
+}
+
+// GetMaintenanceLeaseCodec returns the codec used to encode and decode the maintenance lease in the KV store.
+func GetMaintenanceLeaseCodec() codec.Codec {
+	return codec.NewProtoCodec("maintenanceLease", ProtoMaintenanceLeaseFactory)
+}
+
+// MaintenanceLeaseKey returns the key of the maintenance lease of the ring stored under ringKey.
+func MaintenanceLeaseKey(ringKey string) string {
+	return ringKey + "-maintenance-lease"
+}
+
+// IsHeld returns whether the lease is held at the given time.
+func (l *MaintenanceLease) IsHeld(now time.Time) bool {
+	return l != nil && l.Holder != "" && now.Unix() < l.ExpiresAt
+}
+
+// Merge implements memberlist.Mergeable. The most recently changed lease wins.
+func (l *MaintenanceLease) Merge(mergeable memberlist.Mergeable, _ bool) (memberlist.Mergeable, error) {
+	if mergeable == nil {
+		return nil, nil
+	}
+
+	other, ok := mergeable.(*MaintenanceLease)
+	if !ok {
+		return nil, fmt.Errorf("expected *ring.MaintenanceLease, got %T", mergeable)
+	}
+
+	if other == nil || !other.newerThan(l) {
+		return nil, nil
+	}
+
+	*l = *other
+	return l.Clone(), nil
+}
+
+// newerThan returns whether l has been changed after other. Leases changed at the same time
+// are ordered by their content, so that merging is commutative.
+func (l *MaintenanceLease) newerThan(other *MaintenanceLease) bool {
+	switch {
+	case l.UpdatedAt != other.UpdatedAt:
+		return l.UpdatedAt > other.UpdatedAt
+	case l.Zone != other.Zone:
+		return l.Zone > other.Zone
+	case l.Holder != other.Holder:
+		return l.Holder > other.Holder
+	case l.ExpiresAt != other.ExpiresAt:
+		return l.ExpiresAt > other.ExpiresAt
+	case l.AcquiredAt != other.AcquiredAt:
+		return l.AcquiredAt > other.AcquiredAt
+	default:
+		return strings.Join(l.Holders, ",") > strings.Join(other.Holders, ",")
+	}
+}
+
+// holders returns the IDs of the instances holding the lease. Leases written before the holders
+// were tracked only have the last holder.
+func (l *MaintenanceLease) holders() []string {
+	if len(l.Holders) == 0 && l.Holder != "" {
+		return []string{l.Holder}
+	}
+	return l.Holders
+}
+
+// MergeContent implements memberlist.Mergeable.
+func (l *MaintenanceLease) MergeContent() []string {
+	return []string{"lease"}
+}
+
+// RemoveTombstones implements memberlist.Mergeable. The lease has no tombstones.
+func (l *MaintenanceLease) RemoveTombstones(_ time.Time) (total, removed int) {
+	return 0, 0
+}
+
+// Clone implements memberlist.Mergeable.
+func (l *MaintenanceLease) Clone() memberlist.Mergeable {
+	return proto.Clone(l).(*MaintenanceLease)
+}
+
+// MaintenanceLeaseClient acquires and releases the maintenance lease of a ring. The lease is held by
+// one zone at a time, and it can only be acquired while the instances of all the other zones are ACTIVE
+// and healthy, so that instances of different zones are never restarted concurrently. Multiple instances
+// of the zone can hold the lease at the same time, and it's released once all of them released it.
+//
+// When the KV store is memberlist, the lease is eventually consistent, so two zones acquiring it at the
+// same time may both succeed.
+type MaintenanceLeaseClient struct {
+	leaseKV          kv.Client
+	ringKV           kv.Client
+	ringKey          string
+	heartbeatTimeout time.Duration
+}
+
+// NewMaintenanceLeaseClient makes a new MaintenanceLeaseClient for the ring stored under ringKey. The leaseKV
+// client must use the GetMaintenanceLeaseCodec() codec, while the ringKV client is used to read the ring.
+func NewMaintenanceLeaseClient(leaseKV, ringKV kv.Client, ringKey string, heartbeatTimeout time.Duration) *MaintenanceLeaseClient {
+	return &MaintenanceLeaseClient{
+		leaseKV:          leaseKV,
+		ringKV:           ringKV,
+		ringKey:          ringKey,
+		heartbeatTimeout: heartbeatTimeout,
+	}
+}
+
+// Get returns the current maintenance lease, or nil if the lease is not held.
+func (c *MaintenanceLeaseClient) Get(ctx context.Context) (*MaintenanceLease, error) {
+	val, err := c.leaseKV.Get(ctx, MaintenanceLeaseKey(c.ringKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the maintenance lease")
+	}
+
+	lease, _ := val.(*MaintenanceLease)
+	if !lease.IsHeld(time.Now()) {
+		return nil, nil
+	}
+	return lease, nil
+}
+
+// Acquire acquires the maintenance lease on behalf of the holder instance in zone, or renews it if
+// it's already held by the zone. The lease expires after ttl, unless renewed or released.
+func (c *MaintenanceLeaseClient) Acquire(ctx context.Context, zone, holder string, ttl time.Duration) (*MaintenanceLease, error) {
+	val, err := c.ringKV.Get(ctx, c.ringKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the ring %s", c.ringKey)
+	}
+
+	if desc, ok := val.(*Desc); ok && desc != nil {
+		now := time.Now()
+		for id, instance := range desc.Ingesters {
+			if instance.Zone == zone {
+				continue
+			}
+			if instance.State != ACTIVE || !instance.IsHeartbeatHealthy(c.heartbeatTimeout, now) {
+				return nil, errors.Wrapf(ErrMaintenanceNotAllowed, "instance %s in zone %q is %s", id, instance.Zone, instanceStateOrUnhealthy(instance, c.heartbeatTimeout, now))
+			}
+		}
+	}
+
+	var (
+		lease   *MaintenanceLease
+		heldErr error
+	)
+	err = c.leaseKV.CAS(ctx, MaintenanceLeaseKey(c.ringKey), func(in interface{}) (out interface{}, retry bool, err error) {
+		now := time.Now()
+
+		// The error is not returned from the CAS function, because some KV stores don't preserve it.
+		current, _ := in.(*MaintenanceLease)
+		if current.IsHeld(now) && current.Zone != zone {
+			lease, heldErr = nil, errors.Wrapf(ErrMaintenanceLeaseHeld, "zone %q, holder %s", current.Zone, current.Holder)
+			return nil, false, nil
+		}
+		heldErr = nil
+
+		lease = &MaintenanceLease{
+			Zone:       zone,
+			Holder:     holder,
+			AcquiredAt: now.Unix(),
+			ExpiresAt:  now.Add(ttl).Unix(),
+			UpdatedAt:  now.UnixNano(),
+			Holders:    []string{holder},
+		}
+		if current.IsHeld(now) {
+			lease.AcquiredAt = current.AcquiredAt
+			for _, h := range current.holders() {
+				if h != holder {
+					lease.Holders = append(lease.Holders, h)
+				}
+			}
+			sort.Strings(lease.Holders)
+		}
+		return lease, true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if heldErr != nil {
+		return nil, heldErr
+	}
+
+	return lease, nil
+}
+
+// Release releases the maintenance lease on behalf of the holder instance in zone. The lease is released
+// once all its holders released it. Releasing a lease which is not held by the holder is a no-op.
+func (c *MaintenanceLeaseClient) Release(ctx context.Context, zone, holder string) error {
+	return c.leaseKV.CAS(ctx, MaintenanceLeaseKey(c.ringKey), func(in interface{}) (out interface{}, retry bool, err error) {
+		now := time.Now()
+
+		current, _ := in.(*MaintenanceLease)
+		if !current.IsHeld(now) || current.Zone != zone {
+			return nil, false, nil
+		}
+
+		remaining := make([]string, 0, len(current.holders()))
+		for _, h := range current.holders() {
+			if h != holder {
+				remaining = append(remaining, h)
+			}
+		}
+		if len(remaining) == len(current.holders()) {
+			return nil, false, nil
+		}
+		if len(remaining) == 0 {
+			return &MaintenanceLease{UpdatedAt: now.UnixNano()}, true, nil
+		}
+
+		lease := current.Clone().(*MaintenanceLease)
+		lease.Holders = remaining
+		lease.UpdatedAt = now.UnixNano()
+		if lease.Holder == holder {
+			lease.Holder = remaining[len(remaining)-1]
+		}
+		return lease, true, nil
+	})
+}
+
+func instanceStateOrUnhealthy(instance InstanceDesc, heartbeatTimeout time.Duration, now time.Time) string {
+	if !instance.IsHeartbeatHealthy(heartbeatTimeout, now) {
+		return "unhealthy"
+	}
+	return instance.State.String()
+}
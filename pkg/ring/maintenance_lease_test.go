@@ -0,0 +1,184 @@
+package ring
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cortexproject/cortex/pkg/ring/kv/consul"
+)
+
+func prepareMaintenanceLeaseClient(t *testing.T, desc *Desc) (*MaintenanceLeaseClient, *consul.Client) {
+	ringKV := consul.NewInMemoryClient(GetCodec())
+	require.NoError(t, ringKV.CAS(context.Background(), IngesterRingKey, func(interface{}) (interface{}, bool, error) {
+		return desc, true, nil
+	}))
+
+	return NewMaintenanceLeaseClient(consul.NewInMemoryClient(GetMaintenanceLeaseCodec()), ringKV, IngesterRingKey, time.Minute), ringKV
+}
+
+func TestMaintenanceLeaseClient(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	desc := NewDesc()
+	desc.AddIngester("ingester-zone-a-0", "127.0.0.1", "zone-a", []uint32{1}, ACTIVE, now)
+	desc.AddIngester("ingester-zone-b-0", "127.0.0.2", "zone-b", []uint32{2}, ACTIVE, now)
+	desc.AddIngester("ingester-zone-c-0", "127.0.0.3", "zone-c", []uint32{3}, ACTIVE, now)
+	c, ringKV := prepareMaintenanceLeaseClient(t, desc)
+
+	lease, err := c.Get(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, lease)
+
+	lease, err = c.Acquire(ctx, "zone-a", "ingester-zone-a-0", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "zone-a", lease.Zone)
+	assert.Equal(t, "ingester-zone-a-0", lease.Holder)
+
+	// The instance restarting in zone-a is not ACTIVE anymore.
+	require.NoError(t, ringKV.CAS(ctx, IngesterRingKey, func(in interface{}) (interface{}, bool, error) {
+		desc := in.(*Desc)
+		desc.AddIngester("ingester-zone-a-0", "127.0.0.1", "zone-a", []uint32{1}, LEAVING, now)
+		return desc, true, nil
+	}))
+
+	// Another instance of the same zone can renew the lease.
+	renewed, err := c.Acquire(ctx, "zone-a", "ingester-zone-a-1", 2*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "ingester-zone-a-1", renewed.Holder)
+	assert.Equal(t, []string{"ingester-zone-a-0", "ingester-zone-a-1"}, renewed.Holders)
+	assert.Equal(t, lease.AcquiredAt, renewed.AcquiredAt)
+	assert.Greater(t, renewed.ExpiresAt, lease.ExpiresAt)
+
+	// The instances of another zone can't acquire it, because an instance of zone-a is not ACTIVE.
+	_, err = c.Acquire(ctx, "zone-b", "ingester-zone-b-0", time.Minute)
+	assert.True(t, errors.Is(err, ErrMaintenanceNotAllowed), err)
+
+	// Once all instances are ACTIVE, the lease is still held by zone-a.
+	require.NoError(t, ringKV.CAS(ctx, IngesterRingKey, func(in interface{}) (interface{}, bool, error) {
+		desc := in.(*Desc)
+		desc.AddIngester("ingester-zone-a-0", "127.0.0.1", "zone-a", []uint32{1}, ACTIVE, now)
+		return desc, true, nil
+	}))
+	_, err = c.Acquire(ctx, "zone-b", "ingester-zone-b-0", time.Minute)
+	assert.True(t, errors.Is(err, ErrMaintenanceLeaseHeld), err)
+
+	lease, err = c.Get(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, lease)
+	assert.Equal(t, "zone-a", lease.Zone)
+
+	// Releasing the lease of another zone is a no-op.
+	require.NoError(t, c.Release(ctx, "zone-b", "ingester-zone-b-0"))
+	lease, err = c.Get(ctx)
+	require.NoError(t, err)
+	assert.NotNil(t, lease)
+
+	// The lease is still held by zone-a until all its holders released it.
+	require.NoError(t, c.Release(ctx, "zone-a", "ingester-zone-a-1"))
+	lease, err = c.Get(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, lease)
+	assert.Equal(t, "zone-a", lease.Zone)
+	assert.Equal(t, "ingester-zone-a-0", lease.Holder)
+	assert.Equal(t, []string{"ingester-zone-a-0"}, lease.Holders)
+
+	_, err = c.Acquire(ctx, "zone-b", "ingester-zone-b-0", time.Minute)
+	assert.True(t, errors.Is(err, ErrMaintenanceLeaseHeld), err)
+
+	// Releasing the lease twice is a no-op.
+	require.NoError(t, c.Release(ctx, "zone-a", "ingester-zone-a-1"))
+	lease, err = c.Get(ctx)
+	require.NoError(t, err)
+	assert.NotNil(t, lease)
+
+	require.NoError(t, c.Release(ctx, "zone-a", "ingester-zone-a-0"))
+	lease, err = c.Get(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, lease)
+
+	lease, err = c.Acquire(ctx, "zone-b", "ingester-zone-b-0", time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "zone-b", lease.Zone)
+}
+
+func TestMaintenanceLeaseClient_ShouldIgnoreExpiredLease(t *testing.T) {
+	ctx := context.Background()
+
+	desc := NewDesc()
+	desc.AddIngester("ingester-zone-a-0", "127.0.0.1", "zone-a", []uint32{1}, ACTIVE, time.Now())
+	desc.AddIngester("ingester-zone-b-0", "127.0.0.2", "zone-b", []uint32{2}, ACTIVE, time.Now())
+	c, _ := prepareMaintenanceLeaseClient(t, desc)
+
+	_, err := c.Acquire(ctx, "zone-a", "ingester-zone-a-0", -time.Second)
+	require.NoError(t, err)
+
+	lease, err := c.Get(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, lease)
+
+	_, err = c.Acquire(ctx, "zone-b", "ingester-zone-b-0", time.Minute)
+	require.NoError(t, err)
+}
+
+func TestMaintenanceLeaseClient_ShouldRefuseWhenAnotherZoneIsUnhealthy(t *testing.T) {
+	desc := NewDesc()
+	desc.AddIngester("ingester-zone-a-0", "127.0.0.1", "zone-a", []uint32{1}, ACTIVE, time.Now())
+	desc.AddIngester("ingester-zone-b-0", "127.0.0.2", "zone-b", []uint32{2}, ACTIVE, time.Now())
+
+	instance := desc.Ingesters["ingester-zone-b-0"]
+	instance.Timestamp = time.Now().Add(-time.Hour).Unix()
+	desc.Ingesters["ingester-zone-b-0"] = instance
+
+	c, _ := prepareMaintenanceLeaseClient(t, desc)
+
+	_, err := c.Acquire(context.Background(), "zone-a", "ingester-zone-a-0", time.Minute)
+	assert.True(t, errors.Is(err, ErrMaintenanceNotAllowed), err)
+	assert.Contains(t, err.Error(), "unhealthy")
+}
+
+func TestMaintenanceLease_Merge(t *testing.T) {
+	older := &MaintenanceLease{Zone: "zone-a", Holder: "ingester-zone-a-0", ExpiresAt: 100, UpdatedAt: 1}
+	newer := &MaintenanceLease{Zone: "zone-b", Holder: "ingester-zone-b-0", ExpiresAt: 200, UpdatedAt: 2}
+
+	// Merging a newer lease replaces the current one.
+	local := older.Clone().(*MaintenanceLease)
+	change, err := local.Merge(newer, false)
+	require.NoError(t, err)
+	assert.Equal(t, newer, change)
+	assert.Equal(t, newer, local)
+
+	// Merging an older lease is a no-op.
+	change, err = local.Merge(older, false)
+	require.NoError(t, err)
+	assert.Nil(t, change)
+	assert.Equal(t, newer, local)
+
+	// Merging is commutative, even when leases have been changed at the same time.
+	a := &MaintenanceLease{Zone: "zone-a", Holder: "ingester-zone-a-0", UpdatedAt: 1}
+	b := &MaintenanceLease{Zone: "zone-b", Holder: "ingester-zone-b-0", UpdatedAt: 1}
+	ab, ba := a.Clone().(*MaintenanceLease), b.Clone().(*MaintenanceLease)
+	_, err = ab.Merge(b, false)
+	require.NoError(t, err)
+	_, err = ba.Merge(a, false)
+	require.NoError(t, err)
+	assert.Equal(t, ab, ba)
+
+	_, err = local.Merge(&Desc{}, false)
+	assert.Error(t, err)
+}
+
+func TestMaintenanceLease_ShouldEncodeHolders(t *testing.T) {
+	lease := &MaintenanceLease{Zone: "zone-a", Holder: "ingester-zone-a-1", ExpiresAt: 100, UpdatedAt: 1, Holders: []string{"ingester-zone-a-0", "ingester-zone-a-1"}}
+
+	data, err := GetMaintenanceLeaseCodec().Encode(lease)
+	require.NoError(t, err)
+	decoded, err := GetMaintenanceLeaseCodec().Decode(data)
+	require.NoError(t, err)
+	assert.Equal(t, lease, decoded)
+}
@@ -97,13 +97,13 @@ func (d *Desc) FindIngestersByState(state InstanceState) []InstanceDesc {
 	return result
 }
 
-// Ready returns no error when all ingesters are active and healthy.
+// Ready returns no error when all ingesters are active (or read-only) and healthy.
 func (d *Desc) Ready(now time.Time, heartbeatTimeout time.Duration) error {
 	numTokens := 0
 	for id, ingester := range d.Ingesters {
 		if !ingester.IsHeartbeatHealthy(heartbeatTimeout, now) {
 			return fmt.Errorf("instance %s past heartbeat timeout", id)
-		} else if ingester.State != ACTIVE {
+		} else if ingester.State != ACTIVE && ingester.State != READONLY {
 			return fmt.Errorf("instance %s in state %v", id, ingester.State)
 		}
 		numTokens += len(ingester.Tokens)
@@ -45,6 +45,13 @@ func TestInstanceDesc_IsHealthy_ForIngesterOperations(t *testing.T) {
 			readExpected:   true,
 			reportExpected: true,
 		},
+		"READONLY ingester with last keepalive newer than timeout": {
+			ingester:       &InstanceDesc{State: READONLY, Timestamp: time.Now().Add(-30 * time.Second).Unix()},
+			timeout:        time.Minute,
+			writeExpected:  false,
+			readExpected:   true,
+			reportExpected: true,
+		},
 	}
 
 	for testName, testData := range tests {
@@ -148,6 +155,18 @@ func TestDesc_Ready(t *testing.T) {
 		t.Fatal("expected ready (no heartbeat but timeout disabled), got", err)
 	}
 
+	r.Ingesters["ing2"] = InstanceDesc{
+		Tokens:    []uint32{400},
+		State:     READONLY,
+		Timestamp: now.Unix(),
+	}
+
+	if err := r.Ready(now, 10*time.Second); err != nil {
+		t.Fatal("expected ready (read-only ingester), got", err)
+	}
+
+	delete(r.Ingesters, "ing2")
+
 	r = &Desc{
 		Ingesters: map[string]InstanceDesc{
 			"ing1": {
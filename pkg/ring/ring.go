@@ -99,9 +99,11 @@ var (
 	// WriteNoExtend is like Write, but with no replicaset extension.
 	WriteNoExtend = NewOp([]InstanceState{ACTIVE}, nil)
 
-	Read = NewOp([]InstanceState{ACTIVE, PENDING, LEAVING}, func(s InstanceState) bool {
+	Read = NewOp([]InstanceState{ACTIVE, PENDING, LEAVING, READONLY}, func(s InstanceState) bool {
 		// To match Write with extended replica set we have to also increase the
 		// size of the replica set for Read, but we can read from LEAVING ingesters.
+		// READONLY ingesters are read too, and the replica set is extended because
+		// their newest samples have been written to the next instance.
 		return s != ACTIVE && s != LEAVING
 	})
 
@@ -516,25 +518,31 @@ func (r *Ring) Describe(ch chan<- *prometheus.Desc) {
 // countTokens returns the number of tokens and tokens within the range for each instance.
 // The ring read lock must be already taken when calling this function.
 func (r *Ring) countTokens() (map[string]uint32, map[string]uint32) {
+	return countTokens(r.ringDesc, r.ringTokens, r.ringInstanceByToken)
+}
+
+// countTokens returns the number of tokens and the size of the tokens range owned by each instance.
+// ringTokens must be sorted.
+func countTokens(ringDesc *Desc, ringTokens []uint32, instanceByToken map[uint32]instanceInfo) (map[string]uint32, map[string]uint32) {
 	owned := map[string]uint32{}
 	numTokens := map[string]uint32{}
-	for i, token := range r.ringTokens {
+	for i, token := range ringTokens {
 		var diff uint32
 
 		// Compute how many tokens are within the range.
-		if i+1 == len(r.ringTokens) {
-			diff = (math.MaxUint32 - token) + r.ringTokens[0]
+		if i+1 == len(ringTokens) {
+			diff = (math.MaxUint32 - token) + ringTokens[0]
 		} else {
-			diff = r.ringTokens[i+1] - token
+			diff = ringTokens[i+1] - token
 		}
 
-		info := r.ringInstanceByToken[token]
+		info := instanceByToken[token]
 		numTokens[info.InstanceID] = numTokens[info.InstanceID] + 1
 		owned[info.InstanceID] = owned[info.InstanceID] + diff
 	}
 
 	// Set to 0 the number of owned tokens by instances which don't have tokens yet.
-	for id := range r.ringDesc.Ingesters {
+	for id := range ringDesc.Ingesters {
 		if _, ok := owned[id]; !ok {
 			owned[id] = 0
 			numTokens[id] = 0
@@ -544,6 +552,69 @@ func (r *Ring) countTokens() (map[string]uint32, map[string]uint32) {
 	return numTokens, owned
 }
 
+// replicatedOwnership returns the fraction of the keys for which the instance is part of the
+// replication set for the Write operation, assuming keys are evenly distributed across the ring.
+// The replication set is extended past the given instances, which are the ones whose state
+// extends the Write replication set (eg. LEAVING), the same way the Write operation does, so
+// that the instances receiving their keys get a larger ownership. With evenly balanced tokens
+// and all instances ACTIVE, the ownership is replication factor / number of instances.
+func (r *Ring) replicatedOwnership(instanceID string, extending []string) float64 {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	if len(r.ringTokens) == 0 {
+		return 0
+	}
+	if len(r.ringTokens) == 1 {
+		if r.ringInstanceByToken[r.ringTokens[0]].InstanceID == instanceID {
+			return 1
+		}
+		return 0
+	}
+
+	var (
+		owned     uint64
+		instances = make([]string, 0, r.cfg.ReplicationFactor)
+		zones     = make([]string, 0, r.cfg.ReplicationFactor)
+	)
+
+	for i, token := range r.ringTokens {
+		// Keys in the range (previous token, token] are replicated starting from the token.
+		// The subtraction wraps around the ring for the first token.
+		prev := r.ringTokens[(i+len(r.ringTokens)-1)%len(r.ringTokens)]
+		diff := token - prev
+
+		n := r.cfg.ReplicationFactor
+		instances, zones = instances[:0], zones[:0]
+		for iterations := 0; iterations < len(r.ringTokens) && len(instances) < n; iterations++ {
+			info := r.ringInstanceByToken[r.ringTokens[(i+iterations)%len(r.ringTokens)]]
+
+			// Ensure we select an unique instance, in a different zone if zone-awareness is enabled.
+			if util.StringsContain(instances, info.InstanceID) {
+				continue
+			}
+			if r.cfg.ZoneAwarenessEnabled && info.Zone != "" && util.StringsContain(zones, info.Zone) {
+				continue
+			}
+
+			instances = append(instances, info.InstanceID)
+
+			// Extend the replication set like the Write operation does.
+			if util.StringsContain(extending, info.InstanceID) {
+				n++
+			} else if r.cfg.ZoneAwarenessEnabled && info.Zone != "" {
+				zones = append(zones, info.Zone)
+			}
+		}
+
+		if util.StringsContain(instances, instanceID) {
+			owned += uint64(diff)
+		}
+	}
+
+	return float64(owned) / float64(math.MaxUint32+1)
+}
+
 // Collect implements prometheus.Collector.
 func (r *Ring) Collect(ch chan<- prometheus.Metric) {
 	r.mtx.RLock()
@@ -569,7 +640,7 @@ func (r *Ring) Collect(ch chan<- prometheus.Metric) {
 	oldestTimestampByState := map[string]int64{}
 
 	// Initialised to zero so we emit zero-metrics (instead of not emitting anything)
-	for _, s := range []string{unhealthy, ACTIVE.String(), LEAVING.String(), PENDING.String(), JOINING.String()} {
+	for _, s := range []string{unhealthy, ACTIVE.String(), LEAVING.String(), PENDING.String(), JOINING.String(), READONLY.String()} {
 		numByState[s] = 0
 		oldestTimestampByState[s] = 0
 	}
@@ -865,7 +936,7 @@ func NewOp(healthyStates []InstanceState, shouldExtendReplicaSet func(s Instance
 	}
 
 	if shouldExtendReplicaSet != nil {
-		for _, s := range []InstanceState{ACTIVE, LEAVING, PENDING, JOINING, LEAVING, LEFT} {
+		for _, s := range []InstanceState{ACTIVE, LEAVING, PENDING, JOINING, LEAVING, LEFT, READONLY} {
 			if shouldExtendReplicaSet(s) {
 				op |= (0x10000 << s)
 			}
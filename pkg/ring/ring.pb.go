@@ -37,6 +37,10 @@ const (
 	// This state is only used by gossiping code to distribute information about
 	// instances that have been removed from the ring. Ring users should not use it directly.
 	LEFT InstanceState = 4
+	// The instance doesn't receive writes anymore, but it's still queried. It's used to
+	// gracefully scale down ingesters, waiting until their data has been shipped to the storage
+	// and is not queried from the ingesters anymore, before removing them.
+	READONLY InstanceState = 5
 )
 
 var InstanceState_name = map[int32]string{
@@ -45,14 +49,16 @@ var InstanceState_name = map[int32]string{
 	2: "PENDING",
 	3: "JOINING",
 	4: "LEFT",
+	5: "READONLY",
 }
 
 var InstanceState_value = map[string]int32{
-	"ACTIVE":  0,
-	"LEAVING": 1,
-	"PENDING": 2,
-	"JOINING": 3,
-	"LEFT":    4,
+	"ACTIVE":   0,
+	"LEAVING":  1,
+	"PENDING":  2,
+	"JOINING":  3,
+	"LEFT":     4,
+	"READONLY": 5,
 }
 
 func (InstanceState) EnumDescriptor() ([]byte, []int) {
@@ -200,44 +206,144 @@ func (m *InstanceDesc) GetRegisteredTimestamp() int64 {
 	return 0
 }
 
+// MaintenanceLease is acquired by a zone before restarting its instances, to prevent
+// instances of different zones from being restarted concurrently.
+type MaintenanceLease struct {
+	// Zone holding the lease.
+	Zone string `protobuf:"bytes,1,opt,name=zone,proto3" json:"zone,omitempty"`
+	// ID of the instance which acquired (or last renewed) the lease. Empty if the lease is not held.
+	Holder string `protobuf:"bytes,2,opt,name=holder,proto3" json:"holder,omitempty"`
+	// Unix timestamp (with seconds precision) when the lease has been acquired by the zone.
+	AcquiredAt int64 `protobuf:"varint,3,opt,name=acquired_at,json=acquiredAt,proto3" json:"acquired_at,omitempty"`
+	// Unix timestamp (with seconds precision) when the lease expires.
+	ExpiresAt int64 `protobuf:"varint,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Unix timestamp (with nanoseconds precision) of the last lease change. Used to merge the
+	// lease across the members when the KV store is memberlist.
+	UpdatedAt int64 `protobuf:"varint,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// IDs of all the instances of the zone currently holding the lease. The lease is released
+	// once all of them released it.
+	Holders []string `protobuf:"bytes,6,rep,name=holders,proto3" json:"holders,omitempty"`
+}
+
+func (m *MaintenanceLease) Reset()      { *m = MaintenanceLease{} }
+func (*MaintenanceLease) ProtoMessage() {}
+func (*MaintenanceLease) Descriptor() ([]byte, []int) {
+	return fileDescriptor_26381ed67e202a6e, []int{2}
+}
+func (m *MaintenanceLease) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *MaintenanceLease) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_MaintenanceLease.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *MaintenanceLease) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MaintenanceLease.Merge(m, src)
+}
+func (m *MaintenanceLease) XXX_Size() int {
+	return m.Size()
+}
+func (m *MaintenanceLease) XXX_DiscardUnknown() {
+	xxx_messageInfo_MaintenanceLease.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MaintenanceLease proto.InternalMessageInfo
+
+func (m *MaintenanceLease) GetZone() string {
+	if m != nil {
+		return m.Zone
+	}
+	return ""
+}
+
+func (m *MaintenanceLease) GetHolder() string {
+	if m != nil {
+		return m.Holder
+	}
+	return ""
+}
+
+func (m *MaintenanceLease) GetAcquiredAt() int64 {
+	if m != nil {
+		return m.AcquiredAt
+	}
+	return 0
+}
+
+func (m *MaintenanceLease) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *MaintenanceLease) GetUpdatedAt() int64 {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return 0
+}
+
+func (m *MaintenanceLease) GetHolders() []string {
+	if m != nil {
+		return m.Holders
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("ring.InstanceState", InstanceState_name, InstanceState_value)
 	proto.RegisterType((*Desc)(nil), "ring.Desc")
 	proto.RegisterMapType((map[string]InstanceDesc)(nil), "ring.Desc.IngestersEntry")
 	proto.RegisterType((*InstanceDesc)(nil), "ring.InstanceDesc")
+	proto.RegisterType((*MaintenanceLease)(nil), "ring.MaintenanceLease")
 }
 
 func init() { proto.RegisterFile("ring.proto", fileDescriptor_26381ed67e202a6e) }
 
 var fileDescriptor_26381ed67e202a6e = []byte{
-	// 425 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x92, 0xc1, 0x6e, 0xd3, 0x40,
-	0x18, 0x84, 0xf7, 0x8f, 0x37, 0xae, 0xf3, 0x87, 0x56, 0xd6, 0x16, 0x21, 0x53, 0xa1, 0xc5, 0xea,
-	0xc9, 0x20, 0xe1, 0x8a, 0xc0, 0x01, 0x21, 0x71, 0x68, 0xa9, 0x41, 0xb6, 0xa2, 0x50, 0x99, 0xa8,
-	0x57, 0xe4, 0x24, 0x8b, 0xb1, 0x4a, 0xec, 0xca, 0xde, 0x20, 0x95, 0x13, 0x8f, 0xc0, 0x0b, 0x70,
-	0xe7, 0x51, 0x7a, 0xcc, 0x09, 0xf5, 0x84, 0x88, 0x73, 0xe1, 0xd8, 0x47, 0x40, 0xbb, 0x6e, 0x65,
-	0x72, 0x9b, 0xcf, 0x33, 0xff, 0x8c, 0x2d, 0x19, 0xb1, 0xcc, 0xf2, 0xd4, 0x3f, 0x2f, 0x0b, 0x59,
-	0x30, 0xaa, 0xf4, 0xde, 0x93, 0x34, 0x93, 0x9f, 0x16, 0x13, 0x7f, 0x5a, 0xcc, 0x0f, 0xd2, 0x22,
-	0x2d, 0x0e, 0xb4, 0x39, 0x59, 0x7c, 0xd4, 0xa4, 0x41, 0xab, 0xe6, 0x68, 0xff, 0x07, 0x20, 0x3d,
-	0x16, 0xd5, 0x94, 0xbd, 0xc2, 0x5e, 0x96, 0xa7, 0xa2, 0x92, 0xa2, 0xac, 0x1c, 0x70, 0x0d, 0xaf,
-	0x3f, 0xb8, 0xef, 0xeb, 0x76, 0x65, 0xfb, 0xe1, 0xad, 0x17, 0xe4, 0xb2, 0xbc, 0x38, 0xa2, 0x97,
-	0xbf, 0x1f, 0x92, 0xb8, 0xbd, 0xd8, 0x3b, 0xc1, 0x9d, 0xcd, 0x08, 0xb3, 0xd1, 0x38, 0x13, 0x17,
-	0x0e, 0xb8, 0xe0, 0xf5, 0x62, 0x25, 0x99, 0x87, 0xdd, 0x2f, 0xc9, 0xe7, 0x85, 0x70, 0x3a, 0x2e,
-	0x78, 0xfd, 0x01, 0x6b, 0xea, 0xc3, 0xbc, 0x92, 0x49, 0x3e, 0x15, 0x6a, 0x26, 0x6e, 0x02, 0x2f,
-	0x3b, 0x2f, 0x20, 0xa2, 0x56, 0xc7, 0x36, 0xf6, 0x7f, 0x01, 0xde, 0xf9, 0x3f, 0xc1, 0x18, 0xd2,
-	0x64, 0x36, 0x2b, 0x6f, 0x7a, 0xb5, 0x66, 0x0f, 0xb0, 0x27, 0xb3, 0xb9, 0xa8, 0x64, 0x32, 0x3f,
-	0xd7, 0xe5, 0x46, 0xdc, 0x3e, 0x60, 0x8f, 0xb0, 0x5b, 0xc9, 0x44, 0x0a, 0xc7, 0x70, 0xc1, 0xdb,
-	0x19, 0xec, 0x6e, 0xce, 0xbe, 0x57, 0x56, 0xdc, 0x24, 0xd8, 0x3d, 0x34, 0x65, 0x71, 0x26, 0xf2,
-	0xca, 0x31, 0x5d, 0xc3, 0xdb, 0x8e, 0x6f, 0x48, 0x8d, 0x7e, 0x2d, 0x72, 0xe1, 0x6c, 0x35, 0xa3,
-	0x4a, 0xb3, 0xa7, 0x78, 0xb7, 0x14, 0x69, 0xa6, 0xbe, 0x58, 0xcc, 0x3e, 0xb4, 0xfb, 0x96, 0xde,
-	0xdf, 0x6d, 0xbd, 0xf1, 0xad, 0x15, 0x51, 0x8b, 0xda, 0xdd, 0x88, 0x5a, 0x5d, 0xdb, 0x7c, 0x3c,
-	0xc4, 0xed, 0x8d, 0x57, 0x60, 0x88, 0xe6, 0xe1, 0xeb, 0x71, 0x78, 0x1a, 0xd8, 0x84, 0xf5, 0x71,
-	0x6b, 0x18, 0x1c, 0x9e, 0x86, 0xa3, 0xb7, 0x36, 0x28, 0x38, 0x09, 0x46, 0xc7, 0x0a, 0x3a, 0x0a,
-	0xa2, 0x77, 0xe1, 0x48, 0x81, 0xc1, 0x2c, 0xa4, 0xc3, 0xe0, 0xcd, 0xd8, 0xa6, 0x47, 0xcf, 0x97,
-	0x2b, 0x4e, 0xae, 0x56, 0x9c, 0x5c, 0xaf, 0x38, 0x7c, 0xab, 0x39, 0xfc, 0xac, 0x39, 0x5c, 0xd6,
-	0x1c, 0x96, 0x35, 0x87, 0x3f, 0x35, 0x87, 0xbf, 0x35, 0x27, 0xd7, 0x35, 0x87, 0xef, 0x6b, 0x4e,
-	0x96, 0x6b, 0x4e, 0xae, 0xd6, 0x9c, 0x4c, 0x4c, 0xfd, 0x0f, 0x3c, 0xfb, 0x17, 0x00, 0x00, 0xff,
-	0xff, 0x97, 0x76, 0x41, 0xaf, 0x46, 0x02, 0x00, 0x00,
+	// 526 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x92, 0xc1, 0x6e, 0xd3, 0x4e,
+	0x10, 0xc6, 0xbd, 0xf1, 0xda, 0x71, 0x26, 0x6d, 0x65, 0x6d, 0xff, 0xaa, 0xfc, 0xaf, 0x60, 0x1b,
+	0xe5, 0x64, 0x90, 0x48, 0x45, 0xe0, 0x80, 0x90, 0x38, 0xa4, 0xc4, 0xa0, 0x44, 0x21, 0xad, 0x4c,
+	0x54, 0x09, 0x2e, 0xc8, 0x49, 0x16, 0xd7, 0x6a, 0x63, 0x07, 0x7b, 0x83, 0x28, 0x27, 0x1e, 0x81,
+	0x17, 0xe0, 0xce, 0x1b, 0xf0, 0x0a, 0x3d, 0xe6, 0x84, 0x7a, 0x42, 0xc4, 0xb9, 0x70, 0xec, 0x23,
+	0xa0, 0xdd, 0x75, 0x48, 0x73, 0xfb, 0xbe, 0xf9, 0x66, 0xe7, 0xe7, 0x19, 0x19, 0x20, 0x8d, 0xe2,
+	0xb0, 0x31, 0x4d, 0x13, 0x9e, 0x10, 0x2c, 0xf4, 0xfe, 0x83, 0x30, 0xe2, 0x67, 0xb3, 0x61, 0x63,
+	0x94, 0x4c, 0x0e, 0xc3, 0x24, 0x4c, 0x0e, 0x65, 0x38, 0x9c, 0xbd, 0x97, 0x4e, 0x1a, 0xa9, 0xd4,
+	0xa3, 0xfa, 0x37, 0x04, 0xb8, 0xcd, 0xb2, 0x11, 0x79, 0x06, 0x95, 0x28, 0x0e, 0x59, 0xc6, 0x59,
+	0x9a, 0x39, 0xa8, 0xa6, 0xbb, 0xd5, 0xe6, 0xff, 0x0d, 0x39, 0x5d, 0xc4, 0x8d, 0xce, 0x2a, 0xf3,
+	0x62, 0x9e, 0x5e, 0x1e, 0xe1, 0xab, 0x5f, 0x07, 0x9a, 0xbf, 0x7e, 0xb1, 0x7f, 0x02, 0x3b, 0x9b,
+	0x2d, 0xc4, 0x06, 0xfd, 0x9c, 0x5d, 0x3a, 0xa8, 0x86, 0xdc, 0x8a, 0x2f, 0x24, 0x71, 0xc1, 0xf8,
+	0x18, 0x5c, 0xcc, 0x98, 0x53, 0xaa, 0x21, 0xb7, 0xda, 0x24, 0x6a, 0x7c, 0x27, 0xce, 0x78, 0x10,
+	0x8f, 0x98, 0xc0, 0xf8, 0xaa, 0xe1, 0x69, 0xe9, 0x09, 0xea, 0x62, 0xab, 0x64, 0xeb, 0xf5, 0x9f,
+	0x08, 0xb6, 0x6e, 0x77, 0x10, 0x02, 0x38, 0x18, 0x8f, 0xd3, 0x62, 0xae, 0xd4, 0xe4, 0x0e, 0x54,
+	0x78, 0x34, 0x61, 0x19, 0x0f, 0x26, 0x53, 0x39, 0x5c, 0xf7, 0xd7, 0x05, 0x72, 0x0f, 0x8c, 0x8c,
+	0x07, 0x9c, 0x39, 0x7a, 0x0d, 0xb9, 0x3b, 0xcd, 0xdd, 0x4d, 0xec, 0x6b, 0x11, 0xf9, 0xaa, 0x83,
+	0xec, 0x81, 0xc9, 0x93, 0x73, 0x16, 0x67, 0x8e, 0x59, 0xd3, 0xdd, 0x6d, 0xbf, 0x70, 0x02, 0xfa,
+	0x39, 0x89, 0x99, 0x53, 0x56, 0x50, 0xa1, 0xc9, 0x43, 0xf8, 0x2f, 0x65, 0x61, 0x24, 0x36, 0x66,
+	0xe3, 0x77, 0x6b, 0xbe, 0x25, 0xf9, 0xbb, 0xeb, 0x6c, 0xb0, 0x8a, 0xba, 0xd8, 0xc2, 0xb6, 0xd1,
+	0xc5, 0x96, 0x61, 0x9b, 0xf5, 0x1f, 0x08, 0xec, 0x57, 0x41, 0x14, 0x73, 0x16, 0x8b, 0xcf, 0xe8,
+	0xb1, 0x20, 0x63, 0xff, 0x38, 0xe8, 0x16, 0x67, 0x0f, 0xcc, 0xb3, 0xe4, 0x62, 0xcc, 0x52, 0xb9,
+	0x59, 0xc5, 0x2f, 0x1c, 0x39, 0x80, 0x6a, 0x30, 0xfa, 0x30, 0x8b, 0x04, 0x3d, 0xe0, 0x72, 0x39,
+	0xdd, 0x87, 0x55, 0xa9, 0xc5, 0xc9, 0x5d, 0x00, 0xf6, 0x69, 0x1a, 0xa5, 0x2c, 0x13, 0x39, 0x56,
+	0x67, 0x29, 0x2a, 0x2a, 0x9e, 0x4d, 0xc7, 0x01, 0x57, 0xcf, 0x0d, 0x15, 0x17, 0x95, 0x16, 0x27,
+	0x0e, 0x94, 0x15, 0x48, 0xdd, 0xa2, 0xe2, 0xaf, 0xec, 0xfd, 0xb7, 0xb0, 0xbd, 0x71, 0x3c, 0x02,
+	0x60, 0xb6, 0x9e, 0x0f, 0x3a, 0xa7, 0x9e, 0xad, 0x91, 0x2a, 0x94, 0x7b, 0x5e, 0xeb, 0xb4, 0xd3,
+	0x7f, 0x69, 0x23, 0x61, 0x4e, 0xbc, 0x7e, 0x5b, 0x98, 0x92, 0x30, 0xdd, 0xe3, 0x4e, 0x5f, 0x18,
+	0x9d, 0x58, 0x80, 0x7b, 0xde, 0x8b, 0x81, 0x8d, 0xc9, 0x16, 0x58, 0xbe, 0xd7, 0x6a, 0x1f, 0xf7,
+	0x7b, 0x6f, 0x6c, 0xe3, 0xe8, 0xf1, 0x7c, 0x41, 0xb5, 0xeb, 0x05, 0xd5, 0x6e, 0x16, 0x14, 0x7d,
+	0xc9, 0x29, 0xfa, 0x9e, 0x53, 0x74, 0x95, 0x53, 0x34, 0xcf, 0x29, 0xfa, 0x9d, 0x53, 0xf4, 0x27,
+	0xa7, 0xda, 0x4d, 0x4e, 0xd1, 0xd7, 0x25, 0xd5, 0xe6, 0x4b, 0xaa, 0x5d, 0x2f, 0xa9, 0x36, 0x34,
+	0xe5, 0xbf, 0xfc, 0xe8, 0xef, 0x00, 0x8b, 0x7c, 0xfa, 0x6e, 0x0e, 0x03, 0x00, 0x00,
 }
 
 func (x InstanceState) String() string {
@@ -322,6 +428,50 @@ func (this *InstanceDesc) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *MaintenanceLease) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*MaintenanceLease)
+	if !ok {
+		that2, ok := that.(MaintenanceLease)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Zone != that1.Zone {
+		return false
+	}
+	if this.Holder != that1.Holder {
+		return false
+	}
+	if this.AcquiredAt != that1.AcquiredAt {
+		return false
+	}
+	if this.ExpiresAt != that1.ExpiresAt {
+		return false
+	}
+	if this.UpdatedAt != that1.UpdatedAt {
+		return false
+	}
+	if len(this.Holders) != len(that1.Holders) {
+		return false
+	}
+	for i := range this.Holders {
+		if this.Holders[i] != that1.Holders[i] {
+			return false
+		}
+	}
+	return true
+}
 func (this *Desc) GoString() string {
 	if this == nil {
 		return "nil"
@@ -359,6 +509,21 @@ func (this *InstanceDesc) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *MaintenanceLease) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 10)
+	s = append(s, "&ring.MaintenanceLease{")
+	s = append(s, "Zone: "+fmt.Sprintf("%#v", this.Zone)+",\n")
+	s = append(s, "Holder: "+fmt.Sprintf("%#v", this.Holder)+",\n")
+	s = append(s, "AcquiredAt: "+fmt.Sprintf("%#v", this.AcquiredAt)+",\n")
+	s = append(s, "ExpiresAt: "+fmt.Sprintf("%#v", this.ExpiresAt)+",\n")
+	s = append(s, "UpdatedAt: "+fmt.Sprintf("%#v", this.UpdatedAt)+",\n")
+	s = append(s, "Holders: "+fmt.Sprintf("%#v", this.Holders)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func valueToGoStringRing(v interface{}, typ string) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -484,6 +649,67 @@ func (m *InstanceDesc) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *MaintenanceLease) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MaintenanceLease) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *MaintenanceLease) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Holders) > 0 {
+		for iNdEx := len(m.Holders) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Holders[iNdEx])
+			copy(dAtA[i:], m.Holders[iNdEx])
+			i = encodeVarintRing(dAtA, i, uint64(len(m.Holders[iNdEx])))
+			i--
+			dAtA[i] = 0x32
+		}
+	}
+	if m.UpdatedAt != 0 {
+		i = encodeVarintRing(dAtA, i, uint64(m.UpdatedAt))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.ExpiresAt != 0 {
+		i = encodeVarintRing(dAtA, i, uint64(m.ExpiresAt))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.AcquiredAt != 0 {
+		i = encodeVarintRing(dAtA, i, uint64(m.AcquiredAt))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Holder) > 0 {
+		i -= len(m.Holder)
+		copy(dAtA[i:], m.Holder)
+		i = encodeVarintRing(dAtA, i, uint64(len(m.Holder)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Zone) > 0 {
+		i -= len(m.Zone)
+		copy(dAtA[i:], m.Zone)
+		i = encodeVarintRing(dAtA, i, uint64(len(m.Zone)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintRing(dAtA []byte, offset int, v uint64) int {
 	offset -= sovRing(v)
 	base := offset
@@ -546,6 +772,38 @@ func (m *InstanceDesc) Size() (n int) {
 	return n
 }
 
+func (m *MaintenanceLease) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Zone)
+	if l > 0 {
+		n += 1 + l + sovRing(uint64(l))
+	}
+	l = len(m.Holder)
+	if l > 0 {
+		n += 1 + l + sovRing(uint64(l))
+	}
+	if m.AcquiredAt != 0 {
+		n += 1 + sovRing(uint64(m.AcquiredAt))
+	}
+	if m.ExpiresAt != 0 {
+		n += 1 + sovRing(uint64(m.ExpiresAt))
+	}
+	if m.UpdatedAt != 0 {
+		n += 1 + sovRing(uint64(m.UpdatedAt))
+	}
+	if len(m.Holders) > 0 {
+		for _, s := range m.Holders {
+			l = len(s)
+			n += 1 + l + sovRing(uint64(l))
+		}
+	}
+	return n
+}
+
 func sovRing(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -587,6 +845,21 @@ func (this *InstanceDesc) String() string {
 	}, "")
 	return s
 }
+func (this *MaintenanceLease) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&MaintenanceLease{`,
+		`Zone:` + fmt.Sprintf("%v", this.Zone) + `,`,
+		`Holder:` + fmt.Sprintf("%v", this.Holder) + `,`,
+		`AcquiredAt:` + fmt.Sprintf("%v", this.AcquiredAt) + `,`,
+		`ExpiresAt:` + fmt.Sprintf("%v", this.ExpiresAt) + `,`,
+		`UpdatedAt:` + fmt.Sprintf("%v", this.UpdatedAt) + `,`,
+		`Holders:` + fmt.Sprintf("%v", this.Holders) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func valueToStringRing(v interface{}) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -1027,6 +1300,212 @@ func (m *InstanceDesc) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *MaintenanceLease) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRing
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MaintenanceLease: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MaintenanceLease: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Zone", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRing
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRing
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRing
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Zone = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Holder", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRing
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRing
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRing
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Holder = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AcquiredAt", wireType)
+			}
+			m.AcquiredAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRing
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.AcquiredAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpiresAt", wireType)
+			}
+			m.ExpiresAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRing
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ExpiresAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UpdatedAt", wireType)
+			}
+			m.UpdatedAt = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRing
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.UpdatedAt |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Holders", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRing
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRing
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRing
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Holders = append(m.Holders, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRing(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRing
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRing
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipRing(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
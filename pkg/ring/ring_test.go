@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -209,6 +210,60 @@ func TestRing_Get_ZoneAwarenessWithIngesterLeaving(t *testing.T) {
 	}
 }
 
+func TestRing_Get_ShouldNotWriteToReadOnlyInstances(t *testing.T) {
+	now := time.Now()
+	r := NewDesc()
+	r.AddIngester("instance-1", "127.0.0.1", "", []uint32{10}, ACTIVE, now)
+	r.AddIngester("instance-2", "127.0.0.2", "", []uint32{20}, READONLY, now)
+	r.AddIngester("instance-3", "127.0.0.3", "", []uint32{30}, ACTIVE, now)
+
+	ring := Ring{
+		cfg:                 Config{HeartbeatTimeout: time.Hour, ReplicationFactor: 2},
+		ringDesc:            r,
+		ringTokens:          r.GetTokens(),
+		ringTokensByZone:    r.getTokensByZone(),
+		ringInstanceByToken: r.getTokensInfo(),
+		ringZones:           getZones(r.getTokensByZone()),
+		strategy:            NewDefaultReplicationStrategy(),
+	}
+
+	bufDescs, bufHosts, bufZones := MakeBuffersForGet()
+
+	// The read-only instance is skipped and the replica set extended to the next instance.
+	set, err := ring.Get(15, Write, bufDescs, bufHosts, bufZones)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"127.0.0.1", "127.0.0.3"}, set.GetAddresses())
+	assert.Equal(t, 0, set.MaxErrors)
+
+	// The read-only instance is still read, together with the instances written in its place.
+	set, err = ring.Get(15, Read, bufDescs, bufHosts, bufZones)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"127.0.0.1", "127.0.0.2", "127.0.0.3"}, set.GetAddresses())
+}
+
+func TestRing_Collect_ShouldReportMembersByState(t *testing.T) {
+	now := time.Now()
+	desc := NewDesc()
+	desc.AddIngester("instance-1", "127.0.0.1", "", []uint32{10}, ACTIVE, now)
+	desc.AddIngester("instance-2", "127.0.0.2", "", []uint32{20}, ACTIVE, now)
+
+	r, err := NewWithStoreClientAndStrategy(Config{HeartbeatTimeout: time.Hour, ReplicationFactor: 1}, "test", "test", nil, NewDefaultReplicationStrategy())
+	require.NoError(t, err)
+	r.updateRingState(desc)
+
+	// All the states are reported, even if no instance is in that state.
+	require.NoError(t, testutil.CollectAndCompare(r, strings.NewReader(`
+		# HELP cortex_ring_members Number of members in the ring
+		# TYPE cortex_ring_members gauge
+		cortex_ring_members{name="test",state="ACTIVE"} 2
+		cortex_ring_members{name="test",state="JOINING"} 0
+		cortex_ring_members{name="test",state="LEAVING"} 0
+		cortex_ring_members{name="test",state="PENDING"} 0
+		cortex_ring_members{name="test",state="READONLY"} 0
+		cortex_ring_members{name="test",state="Unhealthy"} 0
+	`), "cortex_ring_members"))
+}
+
 func TestRing_Get_ZoneAwareness(t *testing.T) {
 	// Number of tests to run.
 	const testCount = 10000
@@ -845,6 +900,112 @@ func TestRing_GetReplicationSetForOperation_WithZoneAwarenessEnabled(t *testing.
 	}
 }
 
+func TestRing_replicatedOwnership(t *testing.T) {
+	const quarter = uint32(1 << 30)
+
+	tests := map[string]struct {
+		instances            map[string]InstanceDesc
+		replicationFactor    int
+		zoneAwarenessEnabled bool
+		extending            []string
+		expected             map[string]float64
+	}{
+		"empty ring": {
+			instances:         nil,
+			replicationFactor: 1,
+			expected:          map[string]float64{"instance-1": 0},
+		},
+		"single token": {
+			instances: map[string]InstanceDesc{
+				"instance-1": {Tokens: []uint32{quarter}},
+			},
+			replicationFactor: 3,
+			expected:          map[string]float64{"instance-1": 1},
+		},
+		"unbalanced tokens without replication": {
+			instances: map[string]InstanceDesc{
+				"instance-1": {Tokens: []uint32{quarter}},
+				"instance-2": {Tokens: []uint32{2 * quarter}},
+				"instance-3": {Tokens: []uint32{3 * quarter}},
+			},
+			replicationFactor: 1,
+			expected:          map[string]float64{"instance-1": 0.5, "instance-2": 0.25, "instance-3": 0.25},
+		},
+		"unbalanced tokens with replication": {
+			instances: map[string]InstanceDesc{
+				"instance-1": {Tokens: []uint32{quarter}},
+				"instance-2": {Tokens: []uint32{2 * quarter}},
+				"instance-3": {Tokens: []uint32{3 * quarter}},
+			},
+			replicationFactor: 2,
+			expected:          map[string]float64{"instance-1": 0.75, "instance-2": 0.75, "instance-3": 0.5},
+		},
+		"more replicas than instances": {
+			instances: map[string]InstanceDesc{
+				"instance-1": {Tokens: []uint32{quarter}},
+				"instance-2": {Tokens: []uint32{2 * quarter}},
+			},
+			replicationFactor: 3,
+			expected:          map[string]float64{"instance-1": 1, "instance-2": 1},
+		},
+		"unbalanced zones": {
+			instances: map[string]InstanceDesc{
+				"instance-1": {Zone: "zone-a", Tokens: []uint32{quarter}},
+				"instance-2": {Zone: "zone-a", Tokens: []uint32{2 * quarter}},
+				"instance-3": {Zone: "zone-b", Tokens: []uint32{3 * quarter}},
+			},
+			replicationFactor:    2,
+			zoneAwarenessEnabled: true,
+			expected:             map[string]float64{"instance-1": 0.75, "instance-2": 0.25, "instance-3": 1},
+		},
+		"unbalanced tokens with replication and a LEAVING instance": {
+			instances: map[string]InstanceDesc{
+				"instance-1": {Tokens: []uint32{quarter}},
+				"instance-2": {Tokens: []uint32{2 * quarter}, State: LEAVING},
+				"instance-3": {Tokens: []uint32{3 * quarter}},
+			},
+			replicationFactor: 2,
+			extending:         []string{"instance-2"},
+			expected:          map[string]float64{"instance-1": 1, "instance-2": 0.75, "instance-3": 1},
+		},
+		"unbalanced zones and a LEAVING instance": {
+			instances: map[string]InstanceDesc{
+				"instance-1": {Zone: "zone-a", Tokens: []uint32{quarter}, State: LEAVING},
+				"instance-2": {Zone: "zone-a", Tokens: []uint32{2 * quarter}},
+				"instance-3": {Zone: "zone-b", Tokens: []uint32{3 * quarter}},
+			},
+			replicationFactor:    2,
+			zoneAwarenessEnabled: true,
+			extending:            []string{"instance-1"},
+			expected:             map[string]float64{"instance-1": 0.75, "instance-2": 1, "instance-3": 1},
+		},
+	}
+
+	for testName, testData := range tests {
+		testData := testData
+
+		t.Run(testName, func(t *testing.T) {
+			desc := &Desc{Ingesters: testData.instances}
+			ring := Ring{
+				cfg: Config{
+					ReplicationFactor:    testData.replicationFactor,
+					ZoneAwarenessEnabled: testData.zoneAwarenessEnabled,
+				},
+				ringDesc:            desc,
+				ringTokens:          desc.GetTokens(),
+				ringTokensByZone:    desc.getTokensByZone(),
+				ringInstanceByToken: desc.getTokensInfo(),
+				ringZones:           getZones(desc.getTokensByZone()),
+				strategy:            NewDefaultReplicationStrategy(),
+			}
+
+			for id, expected := range testData.expected {
+				assert.InDelta(t, expected, ring.replicatedOwnership(id, testData.extending), 0.000001, id)
+			}
+		})
+	}
+}
+
 func TestRing_ShuffleShard(t *testing.T) {
 	tests := map[string]struct {
 		ringInstances        map[string]InstanceDesc
@@ -0,0 +1,327 @@
+package ring
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cortexproject/cortex/pkg/util"
+	"github.com/cortexproject/cortex/pkg/util/flagext"
+)
+
+const (
+	// RandomTokenGeneration generates random tokens.
+	RandomTokenGeneration = "random"
+
+	// SpreadMinimizingTokenGeneration generates deterministic tokens, based on the instance zone
+	// and ordinal, minimizing the spread of the ownership across the instances of each zone.
+	SpreadMinimizingTokenGeneration = "spread-minimizing"
+
+	// maxSpreadMinimizingOrdinal is the highest instance ordinal supported by the spread-minimizing
+	// strategy: the tokens of an instance are computed from the ones of all the instances with a lower
+	// ordinal, so higher ordinals (eg. a date at the end of the instance ID) would take too long.
+	maxSpreadMinimizingOrdinal = 1023
+)
+
+var (
+	supportedTokenGenerationStrategies = []string{RandomTokenGeneration, SpreadMinimizingTokenGeneration}
+
+	// instanceOrdinalRegexp matches the ordinal at the end of an instance ID (eg. ingester-zone-a-7).
+	instanceOrdinalRegexp = regexp.MustCompile(`-(\d+)$`)
+)
+
+// TokenGeneratorConfig configures how the tokens of the ring instances are generated.
+type TokenGeneratorConfig struct {
+	Strategy              string                 `yaml:"token_generation_strategy"`
+	SpreadMinimizingZones flagext.StringSliceCSV `yaml:"spread_minimizing_zones"`
+}
+
+// RegisterFlagsWithPrefix adds the flags required to config this to the given FlagSet.
+func (cfg *TokenGeneratorConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.Strategy, prefix+"token-generation-strategy", RandomTokenGeneration, fmt.Sprintf("Strategy to generate the tokens of the instances joining the ring. Supported values are: %s. The %s strategy assigns each instance deterministic tokens, based on its zone and the ordinal at the end of its ID (eg. instance-7, at most %d), which balance the ownership across the instances of the zone. When switching to it, the existing tokens of each instance are replaced at its next restart.", strings.Join(supportedTokenGenerationStrategies, ", "), SpreadMinimizingTokenGeneration, maxSpreadMinimizingOrdinal))
+	f.Var(&cfg.SpreadMinimizingZones, prefix+"spread-minimizing-zones", fmt.Sprintf("Comma-separated list of all the availability zones of the ring instances, required by the %s token generation strategy when zone-awareness is used. It must be the same for all the instances.", SpreadMinimizingTokenGeneration))
+}
+
+// Validate the config.
+func (cfg *TokenGeneratorConfig) Validate() error {
+	if !util.StringsContain(supportedTokenGenerationStrategies, cfg.Strategy) {
+		return fmt.Errorf("unsupported token generation strategy %q, supported values are: %s", cfg.Strategy, strings.Join(supportedTokenGenerationStrategies, ", "))
+	}
+	return nil
+}
+
+// TokenGenerator generates the tokens of the instances joining the ring.
+type TokenGenerator interface {
+	// GenerateTokens make numTokens unique tokens, none of which clash with takenTokens.
+	// Generated tokens are sorted.
+	GenerateTokens(numTokens int, takenTokens []uint32) Tokens
+
+	// CanKeepTokens returns whether the tokens already owned by the instance (eg. loaded from the
+	// ring or the tokens file) can be kept, or they have to be replaced by generated ones.
+	CanKeepTokens(tokens Tokens, takenTokens []uint32) bool
+}
+
+// NewTokenGenerator returns the TokenGenerator configured for the instance with the given ID and zone.
+func NewTokenGenerator(cfg TokenGeneratorConfig, instanceID, zone string) (TokenGenerator, error) {
+	switch cfg.Strategy {
+	case "", RandomTokenGeneration:
+		return RandomTokenGenerator{}, nil
+	case SpreadMinimizingTokenGeneration:
+		return NewSpreadMinimizingTokenGenerator(instanceID, zone, cfg.SpreadMinimizingZones)
+	default:
+		return nil, fmt.Errorf("unsupported token generation strategy %q", cfg.Strategy)
+	}
+}
+
+// InstanceTokens returns the tokens of an instance joining the ring: the existing tokens are kept, if allowed
+// by the generator, and completed up to numTokens with generated ones. Returned tokens are sorted.
+func InstanceTokens(generator TokenGenerator, numTokens int, existing Tokens, takenTokens []uint32) Tokens {
+	if generator == nil {
+		generator = RandomTokenGenerator{}
+	}
+
+	if len(existing) > 0 && !generator.CanKeepTokens(existing, takenTokens) {
+		existing = nil
+	}
+
+	// The generated tokens must not clash with the existing ones either.
+	taken := make([]uint32, 0, len(takenTokens)+len(existing))
+	taken = append(taken, takenTokens...)
+	taken = append(taken, existing...)
+
+	tokens := make(Tokens, 0, numTokens)
+	tokens = append(tokens, existing...)
+	tokens = append(tokens, generator.GenerateTokens(numTokens-len(existing), taken)...)
+	sort.Sort(tokens)
+
+	return tokens
+}
+
+// RandomTokenGenerator generates random tokens.
+type RandomTokenGenerator struct{}
+
+// GenerateTokens implements TokenGenerator.
+func (RandomTokenGenerator) GenerateTokens(numTokens int, takenTokens []uint32) Tokens {
+	return GenerateTokens(numTokens, takenTokens)
+}
+
+// CanKeepTokens implements TokenGenerator.
+func (RandomTokenGenerator) CanKeepTokens(Tokens, []uint32) bool {
+	return true
+}
+
+// SpreadMinimizingTokenGenerator generates deterministic tokens, based on the instance ordinal and zone.
+//
+// Each zone owns the tokens congruent to the zone index modulo the number of zones, so that the tokens of
+// different zones never clash. Within a zone, the instance with ordinal 0 gets evenly spaced tokens, and the
+// tokens of each following instance are computed from the ones of the previous instances: each token of the
+// new instance takes its optimal share of ownership from the largest token range of the instance owning
+// the most. This keeps the ownership near-perfectly balanced, and existing instances never change tokens
+// when new ones are added.
+type SpreadMinimizingTokenGenerator struct {
+	ordinal   int
+	zoneIndex int
+	zones     int
+
+	// Cache of the tokens of the zone computed for the instance, by number of tokens.
+	localTokensMtx sync.Mutex
+	localTokens    map[int][]uint64
+}
+
+// NewSpreadMinimizingTokenGenerator makes a new SpreadMinimizingTokenGenerator for the instance with the given ID and zone.
+// The instance ID must end with the instance ordinal, and the zone must be one of the zones, if any.
+func NewSpreadMinimizingTokenGenerator(instanceID, zone string, zones []string) (*SpreadMinimizingTokenGenerator, error) {
+	matches := instanceOrdinalRegexp.FindStringSubmatch(instanceID)
+	if matches == nil {
+		return nil, fmt.Errorf("the %s token generation strategy requires the instance ID to end with the instance ordinal, but it's %q", SpreadMinimizingTokenGeneration, instanceID)
+	}
+	ordinal, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid ordinal of the instance %q: %v", instanceID, err)
+	}
+	if ordinal > maxSpreadMinimizingOrdinal {
+		return nil, fmt.Errorf("the ordinal %d of the instance %q is greater than the max %d supported by the %s token generation strategy", ordinal, instanceID, maxSpreadMinimizingOrdinal, SpreadMinimizingTokenGeneration)
+	}
+
+	g := &SpreadMinimizingTokenGenerator{ordinal: ordinal, zones: 1, localTokens: map[int][]uint64{}}
+	if len(zones) == 0 {
+		return g, nil
+	}
+
+	sorted := append([]string(nil), zones...)
+	sort.Strings(sorted)
+	g.zones = len(sorted)
+	g.zoneIndex = sort.SearchStrings(sorted, zone)
+	if g.zoneIndex == len(sorted) || sorted[g.zoneIndex] != zone {
+		return nil, fmt.Errorf("the zone %q of the instance %q is not one of the %s token generation zones %v", zone, instanceID, SpreadMinimizingTokenGeneration, zones)
+	}
+
+	return g, nil
+}
+
+// GenerateTokens implements TokenGenerator. Tokens clashing with takenTokens (eg. random tokens of instances
+// not migrated yet) are moved to the next free token of the zone.
+func (g *SpreadMinimizingTokenGenerator) GenerateTokens(numTokens int, takenTokens []uint32) Tokens {
+	if numTokens <= 0 {
+		return Tokens{}
+	}
+
+	used := make(map[uint32]bool, len(takenTokens))
+	for _, t := range takenTokens {
+		used[t] = true
+	}
+
+	tokens := make(Tokens, 0, numTokens)
+	for _, local := range g.instanceTokens(numTokens) {
+		token := g.toRingToken(local)
+		for used[token] {
+			local = (local + 1) % g.zoneSize()
+			token = g.toRingToken(local)
+		}
+		used[token] = true
+		tokens = append(tokens, token)
+	}
+
+	sort.Sort(tokens)
+	return tokens
+}
+
+// CanKeepTokens implements TokenGenerator. The tokens can be kept if they are the deterministic tokens of the
+// instance, each one possibly moved forward because it clashed with a taken token when it was generated. This
+// doesn't depend on the tokens currently taken, so that the instance keeps its moved tokens across restarts,
+// even once the tokens they clashed with are gone.
+func (g *SpreadMinimizingTokenGenerator) CanKeepTokens(tokens Tokens, takenTokens []uint32) bool {
+	expected := append([]uint64(nil), g.instanceTokens(len(tokens))...)
+	if len(expected) == 0 {
+		return false
+	}
+	sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+
+	// A token can't have been moved forward by more than the number of tokens it may have clashed with.
+	maxShift := uint64(len(takenTokens) + len(tokens))
+	matched := make([]bool, len(expected))
+
+	for _, token := range tokens {
+		if uint64(token)%uint64(g.zones) != uint64(g.zoneIndex) {
+			return false
+		}
+		local := uint64(token) / uint64(g.zones)
+
+		// Find the closest deterministic token at or before the token, wrapping around the zone.
+		i := sort.Search(len(expected), func(i int) bool { return expected[i] > local }) - 1
+		if i < 0 {
+			i = len(expected) - 1
+		}
+
+		shift := (local + g.zoneSize() - expected[i]) % g.zoneSize()
+		if shift > maxShift || matched[i] {
+			return false
+		}
+		matched[i] = true
+	}
+
+	return true
+}
+
+// instanceTokens returns the numTokens tokens of the zone of the instance, unsorted.
+func (g *SpreadMinimizingTokenGenerator) instanceTokens(numTokens int) []uint64 {
+	if numTokens <= 0 {
+		return nil
+	}
+
+	g.localTokensMtx.Lock()
+	defer g.localTokensMtx.Unlock()
+
+	if tokens, ok := g.localTokens[numTokens]; ok {
+		return tokens
+	}
+
+	tokens := spreadMinimizingInstanceTokens(g.ordinal, numTokens, g.zoneSize())
+	g.localTokens[numTokens] = tokens
+	return tokens
+}
+
+// zoneSize returns the number of tokens of each zone.
+func (g *SpreadMinimizingTokenGenerator) zoneSize() uint64 {
+	return (math.MaxUint32 + 1) / uint64(g.zones)
+}
+
+// toRingToken maps a token of the zone to the ring token.
+func (g *SpreadMinimizingTokenGenerator) toRingToken(local uint64) uint32 {
+	return uint32(local*uint64(g.zones) + uint64(g.zoneIndex))
+}
+
+// tokenRange is the range of tokens (start, start+size] owned by a token.
+type tokenRange struct {
+	start uint64
+	size  uint64
+}
+
+// spreadMinimizingInstanceTokens returns the numTokens tokens in [0, size) of the instance with the given
+// ordinal, computing the tokens of all the instances with a lower ordinal first.
+func spreadMinimizingInstanceTokens(ordinal, numTokens int, size uint64) []uint64 {
+	ranges := make([][]tokenRange, 0, ordinal+1)
+	ownership := make([]uint64, 0, ordinal+1)
+
+	// The first instance owns evenly spaced tokens.
+	first := make([]tokenRange, 0, numTokens)
+	for i := 0; i < numTokens; i++ {
+		start := uint64(i) * size / uint64(numTokens)
+		end := uint64(i+1) * size / uint64(numTokens)
+		first = append(first, tokenRange{start: start, size: end - start})
+	}
+	ranges = append(ranges, first)
+	ownership = append(ownership, size)
+
+	for instance := 1; instance <= ordinal; instance++ {
+		optimal := size / uint64((instance+1)*numTokens)
+		if optimal == 0 {
+			optimal = 1
+		}
+
+		added := make([]tokenRange, 0, numTokens)
+		ownership = append(ownership, 0)
+
+		for i := 0; i < numTokens; i++ {
+			// Take from the largest range of the instance owning the most.
+			owner := 0
+			for o := range ownership[:instance] {
+				if ownership[o] > ownership[owner] {
+					owner = o
+				}
+			}
+
+			largest := 0
+			for r, tr := range ranges[owner] {
+				if tr.size > ranges[owner][largest].size || (tr.size == ranges[owner][largest].size && tr.start < ranges[owner][largest].start) {
+					largest = r
+				}
+			}
+
+			tr := ranges[owner][largest]
+			take := optimal
+			if take >= tr.size {
+				take = tr.size - 1
+			}
+
+			added = append(added, tokenRange{start: tr.start, size: take})
+			ranges[owner][largest] = tokenRange{start: (tr.start + take) % size, size: tr.size - take}
+			ownership[owner] -= take
+			ownership[instance] += take
+		}
+
+		ranges = append(ranges, added)
+	}
+
+	// Each token is the end of the range it owns.
+	tokens := make([]uint64, 0, numTokens)
+	for _, tr := range ranges[ordinal] {
+		tokens = append(tokens, (tr.start+tr.size)%size)
+	}
+	return tokens
+}
@@ -0,0 +1,222 @@
+package ring
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenGeneratorConfig_Validate(t *testing.T) {
+	assert.NoError(t, (&TokenGeneratorConfig{Strategy: RandomTokenGeneration}).Validate())
+	assert.NoError(t, (&TokenGeneratorConfig{Strategy: SpreadMinimizingTokenGeneration}).Validate())
+	assert.Error(t, (&TokenGeneratorConfig{Strategy: "unknown"}).Validate())
+}
+
+func TestNewTokenGenerator(t *testing.T) {
+	g, err := NewTokenGenerator(TokenGeneratorConfig{Strategy: RandomTokenGeneration}, "ingester-1", "")
+	require.NoError(t, err)
+	assert.IsType(t, RandomTokenGenerator{}, g)
+
+	g, err = NewTokenGenerator(TokenGeneratorConfig{Strategy: SpreadMinimizingTokenGeneration}, "ingester-1", "")
+	require.NoError(t, err)
+	assert.IsType(t, &SpreadMinimizingTokenGenerator{}, g)
+
+	_, err = NewTokenGenerator(TokenGeneratorConfig{Strategy: SpreadMinimizingTokenGeneration}, "ingester", "")
+	assert.Error(t, err)
+
+	_, err = NewTokenGenerator(TokenGeneratorConfig{Strategy: SpreadMinimizingTokenGeneration, SpreadMinimizingZones: []string{"zone-a", "zone-b"}}, "ingester-zone-c-1", "zone-c")
+	assert.Error(t, err)
+
+	_, err = NewTokenGenerator(TokenGeneratorConfig{Strategy: SpreadMinimizingTokenGeneration}, "ingester-20240101", "")
+	assert.Error(t, err)
+
+	_, err = NewTokenGenerator(TokenGeneratorConfig{Strategy: "unknown"}, "ingester-1", "")
+	assert.Error(t, err)
+}
+
+func TestSpreadMinimizingTokenGenerator_Ordinal(t *testing.T) {
+	tests := map[string]int{
+		"ingester-0":             0,
+		"ingester-zone-a-12":     12,
+		"cortex-store-gateway-3": 3,
+	}
+
+	for instanceID, expected := range tests {
+		g, err := NewSpreadMinimizingTokenGenerator(instanceID, "", nil)
+		require.NoError(t, err, instanceID)
+		assert.Equal(t, expected, g.ordinal, instanceID)
+	}
+}
+
+func TestSpreadMinimizingTokenGenerator_ShouldBalanceOwnership(t *testing.T) {
+	const (
+		numTokens    = 128
+		maxInstances = 30
+	)
+
+	desc := NewDesc()
+	for ordinal := 0; ordinal < maxInstances; ordinal++ {
+		id := fmt.Sprintf("ingester-%d", ordinal)
+		g, err := NewSpreadMinimizingTokenGenerator(id, "", nil)
+		require.NoError(t, err)
+
+		tokens := g.GenerateTokens(numTokens, desc.GetTokens())
+		require.Len(t, tokens, numTokens)
+		require.True(t, sort.IsSorted(tokens))
+		desc.AddIngester(id, id, "", tokens, ACTIVE, time.Now())
+
+		// The ownership stays balanced while instances are added.
+		ownership := tokensOwnership(desc)
+		optimal := float64(math.MaxUint32+1) / float64(ordinal+1)
+		for instance, owned := range ownership {
+			assert.InEpsilon(t, optimal, owned, 0.01, "instance: %s, instances: %d", instance, ordinal+1)
+		}
+	}
+}
+
+func TestSpreadMinimizingTokenGenerator_ShouldBeDeterministic(t *testing.T) {
+	g1, err := NewSpreadMinimizingTokenGenerator("ingester-zone-b-5", "zone-b", []string{"zone-a", "zone-b", "zone-c"})
+	require.NoError(t, err)
+	g2, err := NewSpreadMinimizingTokenGenerator("another-zone-b-5", "zone-b", []string{"zone-c", "zone-b", "zone-a"})
+	require.NoError(t, err)
+
+	assert.Equal(t, g1.GenerateTokens(64, nil), g2.GenerateTokens(64, nil))
+
+	// Adding instances doesn't change the tokens of the existing ones.
+	g3, err := NewSpreadMinimizingTokenGenerator("ingester-zone-b-6", "zone-b", []string{"zone-a", "zone-b", "zone-c"})
+	require.NoError(t, err)
+	tokens := g1.GenerateTokens(64, nil)
+	assert.True(t, g1.CanKeepTokens(tokens, g3.GenerateTokens(64, nil)))
+}
+
+func TestSpreadMinimizingTokenGenerator_ShouldNotClashAcrossZones(t *testing.T) {
+	zones := []string{"zone-a", "zone-b", "zone-c"}
+	seen := map[uint32]string{}
+
+	for _, zone := range zones {
+		for ordinal := 0; ordinal < 10; ordinal++ {
+			id := fmt.Sprintf("ingester-%s-%d", zone, ordinal)
+			g, err := NewSpreadMinimizingTokenGenerator(id, zone, zones)
+			require.NoError(t, err)
+
+			for _, token := range g.GenerateTokens(128, nil) {
+				owner, ok := seen[token]
+				require.False(t, ok, "token %d of %s already owned by %s", token, id, owner)
+				seen[token] = id
+			}
+		}
+	}
+}
+
+func TestSpreadMinimizingTokenGenerator_ShouldSkipTakenTokens(t *testing.T) {
+	g, err := NewSpreadMinimizingTokenGenerator("ingester-0", "", nil)
+	require.NoError(t, err)
+
+	expected := g.GenerateTokens(16, nil)
+	taken := []uint32{expected[0], expected[5]}
+
+	tokens := g.GenerateTokens(16, taken)
+	require.Len(t, tokens, 16)
+	assert.NotContains(t, tokens, expected[0])
+	assert.NotContains(t, tokens, expected[5])
+	assert.Contains(t, tokens, expected[0]+1)
+	assert.Contains(t, tokens, expected[5]+1)
+}
+
+func TestSpreadMinimizingTokenGenerator_ShouldKeepMovedTokens(t *testing.T) {
+	g, err := NewSpreadMinimizingTokenGenerator("ingester-1", "", nil)
+	require.NoError(t, err)
+
+	expected := g.GenerateTokens(16, nil)
+	taken := []uint32{expected[0], expected[5], expected[5] + 1}
+	moved := g.GenerateTokens(16, taken)
+	require.NotEqual(t, expected, moved)
+
+	// The moved tokens are kept even once the tokens they clashed with are gone.
+	assert.True(t, g.CanKeepTokens(moved, taken))
+	assert.True(t, g.CanKeepTokens(moved, nil))
+	assert.True(t, g.CanKeepTokens(expected, taken))
+
+	// The tokens of another instance can't be kept.
+	other, err := NewSpreadMinimizingTokenGenerator("ingester-2", "", nil)
+	require.NoError(t, err)
+	assert.False(t, g.CanKeepTokens(other.GenerateTokens(16, nil), nil))
+
+	// Tokens moved too far can't be kept.
+	far := append(Tokens(nil), expected...)
+	far[3] += 1000
+	assert.False(t, g.CanKeepTokens(far, nil))
+}
+
+func TestInstanceTokens(t *testing.T) {
+	spreadMinimizing, err := NewSpreadMinimizingTokenGenerator("ingester-1", "", nil)
+	require.NoError(t, err)
+	expected := spreadMinimizing.GenerateTokens(8, nil)
+
+	tests := map[string]struct {
+		generator TokenGenerator
+		existing  Tokens
+		check     func(t *testing.T, tokens Tokens)
+	}{
+		"random generator should keep the existing tokens": {
+			generator: RandomTokenGenerator{},
+			existing:  Tokens{1, 2, 3},
+			check: func(t *testing.T, tokens Tokens) {
+				assert.Subset(t, tokens, Tokens{1, 2, 3})
+			},
+		},
+		"nil generator should generate random tokens": {
+			generator: nil,
+			existing:  Tokens{1, 2, 3},
+			check: func(t *testing.T, tokens Tokens) {
+				assert.Subset(t, tokens, Tokens{1, 2, 3})
+			},
+		},
+		"spread-minimizing generator should keep matching tokens": {
+			generator: spreadMinimizing,
+			existing:  expected,
+			check: func(t *testing.T, tokens Tokens) {
+				assert.Equal(t, expected, tokens)
+			},
+		},
+		"spread-minimizing generator should replace non matching tokens": {
+			generator: spreadMinimizing,
+			existing:  Tokens{1, 2, 3},
+			check: func(t *testing.T, tokens Tokens) {
+				assert.Equal(t, expected, tokens)
+			},
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			tokens := InstanceTokens(testData.generator, 8, testData.existing, nil)
+			require.Len(t, tokens, 8)
+			assert.True(t, sort.IsSorted(tokens))
+			testData.check(t, tokens)
+		})
+	}
+}
+
+// tokensOwnership returns the number of tokens of the ring owned by each instance.
+func tokensOwnership(desc *Desc) map[string]float64 {
+	tokens := desc.getTokensInfo()
+	sorted := desc.GetTokens()
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	ownership := map[string]float64{}
+	for i, token := range sorted {
+		prev := sorted[len(sorted)-1]
+		if i > 0 {
+			prev = sorted[i-1]
+		}
+		// The token owns the range (prev, token], wrapping around the ring.
+		ownership[tokens[token].InstanceID] += float64(token - prev)
+	}
+	return ownership
+}
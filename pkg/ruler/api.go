@@ -124,15 +124,19 @@ type API struct {
 	ruler *Ruler
 	store rulestore.RuleStore
 
+	// Used to evaluate rules on rule group dry-runs. Dry-runs are not supported if nil.
+	queryFuncFactory QueryFuncFactory
+
 	logger log.Logger
 }
 
-// NewAPI returns a new API struct with the provided ruler and rule store
-func NewAPI(r *Ruler, s rulestore.RuleStore, logger log.Logger) *API {
+// NewAPI returns a new API struct with the provided ruler, rule store and query function factory
+func NewAPI(r *Ruler, s rulestore.RuleStore, queryFuncFactory QueryFuncFactory, logger log.Logger) *API {
 	return &API{
-		ruler:  r,
-		store:  s,
-		logger: logger,
+		ruler:            r,
+		store:            s,
+		queryFuncFactory: queryFuncFactory,
+		logger:           logger,
 	}
 }
 
@@ -68,6 +68,13 @@ func (a *PusherAppender) AppendExemplar(_ uint64, _ labels.Labels, _ exemplar.Ex
 }
 
 func (a *PusherAppender) Commit() error {
+	// When rule groups are replicated, only the elected replica writes the results.
+	if elector := groupReplicaElectorFromContext(a.ctx); elector != nil {
+		if file, group, ok := ruleGroupFromContext(a.ctx); ok && !elector.allowWrite(a.userID, file, group) {
+			return a.Rollback()
+		}
+	}
+
 	a.totalWrites.Inc()
 
 	// Since a.pusher is distributor, client.ReuseSlice will be called in a.pusher.Push.
@@ -81,6 +88,12 @@ func (a *PusherAppender) Commit() error {
 		}
 	}
 
+	if h := evaluationHistoryFromContext(a.ctx); h != nil {
+		if file, group, ok := ruleGroupFromContext(a.ctx); ok {
+			h.recordWrite(newUserGroupKeyFromFile(a.userID, file, group), len(a.samples), err)
+		}
+	}
+
 	a.labels = nil
 	a.samples = nil
 	return err
@@ -131,6 +144,8 @@ type RulesLimits interface {
 	RulerTenantShardSize(userID string) int
 	RulerMaxRuleGroupsPerTenant(userID string) int
 	RulerMaxRulesPerRuleGroup(userID string) int
+
+	NotifierLimits
 }
 
 // EngineQueryFunc returns a new query function using the rules.EngineQueryFunc function
@@ -208,7 +223,24 @@ type RulesManager interface {
 // ManagerFactory is a function that creates new RulesManager for given user and notifier.Manager.
 type ManagerFactory func(ctx context.Context, userID string, notifier *notifier.Manager, logger log.Logger, reg prometheus.Registerer) RulesManager
 
-func DefaultTenantManagerFactory(cfg Config, p Pusher, q storage.Queryable, engine *promql.Engine, overrides RulesLimits, reg prometheus.Registerer) ManagerFactory {
+// QueryFuncFactory returns the query function used to evaluate the rules of a tenant.
+type QueryFuncFactory func(userID string) rules.QueryFunc
+
+// DefaultQueryFuncFactory returns a QueryFuncFactory evaluating queries with the given engine or,
+// if frontendClient is not nil, sending them to the query-frontend.
+func DefaultQueryFuncFactory(engine *promql.Engine, q storage.Queryable, frontendClient *FrontendClient, overrides RulesLimits) QueryFuncFactory {
+	return func(userID string) rules.QueryFunc {
+		if frontendClient != nil {
+			return RemoteQueryFunc(frontendClient, overrides, userID)
+		}
+		return EngineQueryFunc(engine, q, overrides, userID)
+	}
+}
+
+// DefaultTenantManagerFactory returns a ManagerFactory evaluating rules with the given engine.
+// If frontendClient is not nil, rules queries are sent to the query-frontend instead, while
+// the queryable is still used to restore the alerts "for" state.
+func DefaultTenantManagerFactory(cfg Config, p Pusher, q storage.Queryable, engine *promql.Engine, frontendClient *FrontendClient, overrides RulesLimits, reg prometheus.Registerer) ManagerFactory {
 	totalWrites := promauto.With(reg).NewCounter(prometheus.CounterOpts{
 		Name: "cortex_ruler_write_requests_total",
 		Help: "Number of write requests to ingesters.",
@@ -226,6 +258,8 @@ func DefaultTenantManagerFactory(cfg Config, p Pusher, q storage.Queryable, engi
 		Name: "cortex_ruler_queries_failed_total",
 		Help: "Number of failed queries by ruler.",
 	})
+	queryFuncFactory := DefaultQueryFuncFactory(engine, q, frontendClient, overrides)
+
 	var rulerQuerySeconds *prometheus.CounterVec
 	if cfg.EnableQueryStats {
 		rulerQuerySeconds = promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
@@ -243,7 +277,7 @@ func DefaultTenantManagerFactory(cfg Config, p Pusher, q storage.Queryable, engi
 		return rules.NewManager(&rules.ManagerOptions{
 			Appendable:      NewPusherAppendable(p, userID, overrides, totalWrites, failedWrites),
 			Queryable:       q,
-			QueryFunc:       RecordAndReportRuleQueryMetrics(MetricsQueryFunc(EngineQueryFunc(engine, q, overrides, userID), totalQueries, failedQueries), queryTime, logger),
+			QueryFunc:       EvaluationHistoryQueryFunc(RecordAndReportRuleQueryMetrics(MetricsQueryFunc(queryFuncFactory(userID), totalQueries, failedQueries), queryTime, logger), userID),
 			Context:         user.InjectOrgID(ctx, userID),
 			ExternalURL:     cfg.ExternalURL.URL,
 			NotifyFunc:      SendAlerts(notifier, cfg.ExternalURL.URL.String()),
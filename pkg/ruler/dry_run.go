@@ -0,0 +1,353 @@
+package ruler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/rulefmt"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/rules"
+	"gopkg.in/yaml.v3"
+
+	"github.com/cortexproject/cortex/pkg/util"
+	util_log "github.com/cortexproject/cortex/pkg/util/log"
+)
+
+const (
+	// Max number of evaluations of each rule for a single dry-run request.
+	maxDryRunEvaluations = 1000
+
+	// Default max number of samples and alerts returned for each rule.
+	defaultDryRunLimit = 10
+)
+
+var (
+	errDryRunNotSupported = errors.New("rule group dry-run is not supported by this ruler")
+	errDryRunBadTimeRange = errors.New("end timestamp must not be before start timestamp")
+	errDryRunBadStep      = errors.New("step must be a positive duration")
+)
+
+// DryRunGroupResult holds the results of a rule group dry-run.
+type DryRunGroupResult struct {
+	Name  string              `json:"name"`
+	Start time.Time           `json:"start"`
+	End   time.Time           `json:"end"`
+	Step  float64             `json:"step"`
+	Rules []*DryRunRuleResult `json:"rules"`
+}
+
+// DryRunRuleResult holds the results of the dry-run of a single rule.
+type DryRunRuleResult struct {
+	Record string `json:"record,omitempty"`
+	Alert  string `json:"alert,omitempty"`
+	Expr   string `json:"expr"`
+
+	// Number of times the rule has been evaluated.
+	Evaluations int `json:"evaluations"`
+	// Max number of series returned by a single evaluation.
+	SeriesCount int `json:"seriesCount"`
+	// Samples returned by the last evaluation (recording rules only).
+	Samples []*DryRunSample `json:"samples,omitempty"`
+	// Alerts active after the last evaluation (alerting rules only).
+	Alerts []*Alert `json:"alerts,omitempty"`
+	// Max number of alerts firing at the same time (alerting rules only).
+	FiringAlerts int `json:"firingAlerts"`
+	// Max number of alerts pending at the same time (alerting rules only).
+	PendingAlerts int `json:"pendingAlerts"`
+	// Whether samples or alerts have been truncated to the requested limit.
+	Truncated bool `json:"truncated"`
+
+	// Total time spent evaluating the rule, in seconds.
+	EvaluationTime float64 `json:"evaluationTime"`
+	Error          string  `json:"error,omitempty"`
+}
+
+// DryRunSample is a sample returned by a recording rule.
+type DryRunSample struct {
+	Labels    labels.Labels `json:"labels"`
+	Value     string        `json:"value"`
+	Timestamp time.Time     `json:"timestamp"`
+}
+
+// DryRunRuleGroup validates the rule group in the request body and evaluates each of its rules
+// against the tenant's data, without persisting anything. Rules are evaluated once at the "time"
+// query parameter (defaults to now) or, if "start" and "end" are given, at each "step" (defaults
+// to the group interval) of the time range.
+func (a *API) DryRunRuleGroup(w http.ResponseWriter, req *http.Request) {
+	logger := util_log.WithContext(req.Context(), a.logger)
+	userID, _, _, err := parseRequest(req, true, false)
+	if err != nil {
+		respondError(logger, w, err.Error())
+		return
+	}
+
+	if a.queryFuncFactory == nil {
+		http.Error(w, errDryRunNotSupported.Error(), http.StatusNotImplemented)
+		return
+	}
+
+	payload, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		level.Error(logger).Log("msg", "unable to read rule group payload", "err", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rg := rulefmt.RuleGroup{}
+	err = yaml.Unmarshal(payload, &rg)
+	if err != nil {
+		level.Error(logger).Log("msg", "unable to unmarshal rule group payload", "err", err.Error())
+		http.Error(w, ErrBadRuleGroup.Error(), http.StatusBadRequest)
+		return
+	}
+
+	errs := a.ruler.manager.ValidateRuleGroup(rg)
+	if len(errs) > 0 {
+		e := []string{}
+		for _, err := range errs {
+			e = append(e, err.Error())
+		}
+
+		http.Error(w, strings.Join(e, ", "), http.StatusBadRequest)
+		return
+	}
+
+	if err := a.ruler.AssertMaxRulesPerRuleGroup(userID, len(rg.Rules)); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	interval := a.ruler.cfg.EvaluationInterval
+	if rg.Interval != 0 {
+		interval = time.Duration(rg.Interval)
+	}
+
+	start, end, step, err := parseDryRunTimeRange(req.URL.Query(), interval)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	limit := defaultDryRunLimit
+	if v := req.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			http.Error(w, "limit must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+	}
+
+	result := &DryRunGroupResult{
+		Name:  rg.Name,
+		Start: start,
+		End:   end,
+		Step:  step.Seconds(),
+		Rules: dryRunRules(req.Context(), rg, a.queryFuncFactory(userID), a.ruler.cfg.ExternalURL.URL, start, end, step, limit, logger),
+	}
+
+	b, err := json.Marshal(&response{
+		Status: "success",
+		Data:   result,
+	})
+	if err != nil {
+		level.Error(logger).Log("msg", "error marshaling json response", "err", err)
+		respondError(logger, w, "unable to marshal the requested data")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if n, err := w.Write(b); err != nil {
+		level.Error(logger).Log("msg", "error writing response", "bytesWritten", n, "err", err)
+	}
+}
+
+func parseDryRunTimeRange(params url.Values, interval time.Duration) (start, end time.Time, step time.Duration, err error) {
+	parse := func(name string, def time.Time) (time.Time, error) {
+		v := params.Get(name)
+		if v == "" {
+			return def, nil
+		}
+
+		ms, err := util.ParseTime(v)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return util.TimeFromMillis(ms), nil
+	}
+
+	// An instant evaluation is a range made of a single step.
+	if params.Get("start") == "" && params.Get("end") == "" {
+		ts, err := parse("time", time.Now())
+		return ts, ts, interval, err
+	}
+
+	if start, err = parse("start", time.Now()); err != nil {
+		return
+	}
+	if end, err = parse("end", time.Now()); err != nil {
+		return
+	}
+	if end.Before(start) {
+		err = errDryRunBadTimeRange
+		return
+	}
+
+	step = interval
+	if v := params.Get("step"); v != "" {
+		if step, err = parseStep(v); err != nil {
+			return
+		}
+	}
+	if step <= 0 {
+		err = errDryRunBadStep
+		return
+	}
+
+	if evaluations := int64(end.Sub(start)/step) + 1; evaluations > maxDryRunEvaluations {
+		err = fmt.Errorf("the time range requires %d evaluations of each rule, which exceeds the limit of %d: increase the step or reduce the time range", evaluations, maxDryRunEvaluations)
+	}
+	return
+}
+
+func parseStep(s string) (time.Duration, error) {
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(secs * float64(time.Second)), nil
+	}
+
+	d, err := model.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse %q to a valid duration", s)
+	}
+	return time.Duration(d), nil
+}
+
+// dryRunRules evaluates each rule of the group at each step between start and end. The rules
+// are evaluated like the Prometheus rules manager does, but the results are discarded.
+func dryRunRules(ctx context.Context, rg rulefmt.RuleGroup, queryFunc rules.QueryFunc, externalURL *url.URL, start, end time.Time, step time.Duration, limit int, logger log.Logger) []*DryRunRuleResult {
+	results := make([]*DryRunRuleResult, 0, len(rg.Rules))
+
+	for _, r := range rg.Rules {
+		result := &DryRunRuleResult{
+			Record: r.Record.Value,
+			Alert:  r.Alert.Value,
+			Expr:   r.Expr.Value,
+		}
+		results = append(results, result)
+
+		// The group has already been validated, so the expression is expected to be valid.
+		expr, err := parser.ParseExpr(r.Expr.Value)
+		if err != nil {
+			result.Error = err.Error()
+			continue
+		}
+
+		var rule rules.Rule
+		if r.Alert.Value != "" {
+			rule = rules.NewAlertingRule(r.Alert.Value, expr, time.Duration(r.For), labels.FromMap(r.Labels), labels.FromMap(r.Annotations), nil, true, log.With(logger, "alert", r.Alert.Value))
+		} else {
+			rule = rules.NewRecordingRule(r.Record.Value, expr, labels.FromMap(r.Labels))
+		}
+
+		var evaluationTime time.Duration
+		for ts := start; !ts.After(end); ts = ts.Add(step) {
+			if ctx.Err() != nil {
+				result.Error = ctx.Err().Error()
+				break
+			}
+
+			evalStart := time.Now()
+			vector, err := rule.Eval(ctx, ts, queryFunc, externalURL)
+			evaluationTime += time.Since(evalStart)
+			result.Evaluations++
+
+			if err != nil {
+				result.Error = err.Error()
+				break
+			}
+
+			if alerting, ok := rule.(*rules.AlertingRule); ok {
+				updateAlertsResult(result, alerting)
+			} else {
+				updateSamplesResult(result, vector, limit)
+			}
+		}
+
+		if alerting, ok := rule.(*rules.AlertingRule); ok {
+			for _, alert := range alerting.ActiveAlerts() {
+				if len(result.Alerts) >= limit {
+					result.Truncated = true
+					break
+				}
+
+				activeAt := alert.ActiveAt
+				result.Alerts = append(result.Alerts, &Alert{
+					Labels:      alert.Labels,
+					Annotations: alert.Annotations,
+					State:       alert.State.String(),
+					ActiveAt:    &activeAt,
+					Value:       strconv.FormatFloat(alert.Value, 'e', -1, 64),
+				})
+			}
+		}
+
+		result.EvaluationTime = evaluationTime.Seconds()
+	}
+
+	return results
+}
+
+func updateSamplesResult(result *DryRunRuleResult, vector promql.Vector, limit int) {
+	if len(vector) > result.SeriesCount {
+		result.SeriesCount = len(vector)
+	}
+
+	// Only keep the samples of the last evaluation.
+	result.Samples = result.Samples[:0]
+	result.Truncated = len(vector) > limit
+	for i, s := range vector {
+		if i >= limit {
+			break
+		}
+
+		result.Samples = append(result.Samples, &DryRunSample{
+			Labels:    s.Metric,
+			Value:     strconv.FormatFloat(s.V, 'e', -1, 64),
+			Timestamp: util.TimeFromMillis(s.T),
+		})
+	}
+}
+
+func updateAlertsResult(result *DryRunRuleResult, rule *rules.AlertingRule) {
+	firing, pending := 0, 0
+	rule.ForEachActiveAlert(func(alert *rules.Alert) {
+		switch alert.State {
+		case rules.StateFiring:
+			firing++
+		case rules.StatePending:
+			pending++
+		}
+	})
+
+	if firing+pending > result.SeriesCount {
+		result.SeriesCount = firing + pending
+	}
+	if firing > result.FiringAlerts {
+		result.FiringAlerts = firing
+	}
+	if pending > result.PendingAlerts {
+		result.PendingAlerts = pending
+	}
+}
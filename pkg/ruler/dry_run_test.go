@@ -0,0 +1,158 @@
+package ruler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/rules"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cortexproject/cortex/pkg/ruler/rulespb"
+	"github.com/cortexproject/cortex/pkg/util/services"
+)
+
+func TestRuler_DryRunRuleGroup(t *testing.T) {
+	store := newMockRuleStore(make(map[string]rulespb.RuleGroupList))
+	cfg, cleanup := defaultRulerConfig(store)
+	defer cleanup()
+
+	r, rcleanup := newTestRuler(t, cfg)
+	defer rcleanup()
+	defer services.StopAndAwaitTerminated(context.Background(), r) //nolint:errcheck
+
+	queryFuncFactory := func(userID string) rules.QueryFunc {
+		return func(_ context.Context, qs string, ts time.Time) (promql.Vector, error) {
+			switch qs {
+			case "up":
+				return promql.Vector{
+					{Point: promql.Point{T: ts.UnixNano() / int64(time.Millisecond), V: 1}, Metric: labels.FromStrings("__name__", "up", "job", "a")},
+					{Point: promql.Point{T: ts.UnixNano() / int64(time.Millisecond), V: 0}, Metric: labels.FromStrings("__name__", "up", "job", "b")},
+				}, nil
+			case "up == 0":
+				return promql.Vector{
+					{Point: promql.Point{T: ts.UnixNano() / int64(time.Millisecond), V: 0}, Metric: labels.FromStrings("__name__", "up", "job", "b")},
+				}, nil
+			case "label_replace(up, \"job\", \"\", \"\", \"\")":
+				return promql.Vector{
+					{Point: promql.Point{T: ts.UnixNano() / int64(time.Millisecond), V: 1}, Metric: labels.FromStrings("__name__", "up")},
+					{Point: promql.Point{T: ts.UnixNano() / int64(time.Millisecond), V: 0}, Metric: labels.FromStrings("__name__", "up")},
+				}, nil
+			}
+			return promql.Vector{}, nil
+		}
+	}
+
+	a := NewAPI(r, r.store, queryFuncFactory, log.NewNopLogger())
+
+	router := mux.NewRouter()
+	router.Path("/api/v1/rules/{namespace}/dry_run").Methods("POST").HandlerFunc(a.DryRunRuleGroup)
+
+	group := `
+name: test
+interval: 1m
+rules:
+- record: job:up
+  expr: up
+- record: duplicated
+  expr: label_replace(up, "job", "", "", "")
+- alert: down
+  expr: up == 0
+  for: 2m
+  labels:
+    severity: critical
+`
+
+	t.Run("instant evaluation", func(t *testing.T) {
+		req := requestFor(t, http.MethodPost, "https://localhost:8080/api/v1/rules/namespace/dry_run?time=1600000000&limit=1", strings.NewReader(group), "user1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		result := decodeDryRunResponse(t, w.Body.Bytes())
+		assert.Equal(t, "test", result.Name)
+		require.Len(t, result.Rules, 3)
+
+		recording := result.Rules[0]
+		assert.Equal(t, "job:up", recording.Record)
+		assert.Equal(t, 1, recording.Evaluations)
+		assert.Equal(t, 2, recording.SeriesCount)
+		assert.True(t, recording.Truncated)
+		require.Len(t, recording.Samples, 1)
+		assert.Equal(t, labels.FromStrings("__name__", "job:up", "job", "a"), recording.Samples[0].Labels)
+		assert.Empty(t, recording.Error)
+
+		duplicated := result.Rules[1]
+		assert.Equal(t, 1, duplicated.Evaluations)
+		assert.Contains(t, duplicated.Error, "same labelset")
+
+		alert := result.Rules[2]
+		assert.Equal(t, "down", alert.Alert)
+		assert.Equal(t, 1, alert.Evaluations)
+		assert.Equal(t, 0, alert.FiringAlerts)
+		assert.Equal(t, 1, alert.PendingAlerts)
+		require.Len(t, alert.Alerts, 1)
+		assert.Equal(t, "pending", alert.Alerts[0].State)
+		assert.Equal(t, labels.FromStrings("alertname", "down", "job", "b", "severity", "critical"), alert.Alerts[0].Labels)
+	})
+
+	t.Run("range evaluation", func(t *testing.T) {
+		req := requestFor(t, http.MethodPost, "https://localhost:8080/api/v1/rules/namespace/dry_run?start=1600000000&end=1600000300", strings.NewReader(group), "user1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		result := decodeDryRunResponse(t, w.Body.Bytes())
+		assert.Equal(t, float64(60), result.Step)
+		require.Len(t, result.Rules, 3)
+
+		// Evaluations stop at the first error.
+		assert.Equal(t, 6, result.Rules[0].Evaluations)
+		assert.Equal(t, 1, result.Rules[1].Evaluations)
+
+		alert := result.Rules[2]
+		assert.Equal(t, 6, alert.Evaluations)
+		assert.Equal(t, 1, alert.FiringAlerts)
+		assert.Equal(t, 1, alert.PendingAlerts)
+		require.Len(t, alert.Alerts, 1)
+		assert.Equal(t, "firing", alert.Alerts[0].State)
+	})
+
+	t.Run("too many evaluations", func(t *testing.T) {
+		req := requestFor(t, http.MethodPost, "https://localhost:8080/api/v1/rules/namespace/dry_run?start=0&end=1600000000&step=1s", strings.NewReader(group), "user1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid rule group", func(t *testing.T) {
+		req := requestFor(t, http.MethodPost, "https://localhost:8080/api/v1/rules/namespace/dry_run", strings.NewReader("name: test\n"), "user1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	// The dry-run must not store anything.
+	rgs, err := store.ListAllRuleGroups(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, rgs)
+}
+
+func decodeDryRunResponse(t *testing.T, body []byte) DryRunGroupResult {
+	var resp struct {
+		Status string            `json:"status"`
+		Data   DryRunGroupResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &resp))
+	require.Equal(t, "success", resp.Status)
+	return resp.Data
+}
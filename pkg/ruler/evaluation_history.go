@@ -0,0 +1,297 @@
+package ruler
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/rules"
+
+	"github.com/cortexproject/cortex/pkg/ruler/rulespb"
+	"github.com/cortexproject/cortex/pkg/tenant"
+	"github.com/cortexproject/cortex/pkg/util"
+	util_log "github.com/cortexproject/cortex/pkg/util/log"
+)
+
+// evaluationHistory keeps track of the most recent evaluations of the rule groups loaded
+// by the ruler, and detects missed evaluations.
+//
+// The Prometheus rules manager doesn't expose any hook on rule group evaluations, so they're
+// tracked through the queries and writes of the rules, which carry the rule group in their
+// context: the first query with a new evaluation timestamp starts a new evaluation.
+type evaluationHistory struct {
+	size int
+
+	mtx    sync.Mutex
+	groups map[userGroupKey]*groupEvaluations
+
+	missedEvaluations *prometheus.CounterVec
+}
+
+type groupEvaluations struct {
+	interval    time.Duration
+	missed      int64
+	evaluations []*evaluation
+}
+
+type evaluation struct {
+	timestamp time.Time
+	start     time.Time
+	end       time.Time
+	samples   int64
+	err       error
+}
+
+func newEvaluationHistory(size int, reg prometheus.Registerer) *evaluationHistory {
+	return &evaluationHistory{
+		size:   size,
+		groups: map[userGroupKey]*groupEvaluations{},
+		missedEvaluations: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_ruler_missed_evaluations_total",
+			Help: "Number of rule group evaluations missed by the ruler, detected from the gaps between consecutive evaluations.",
+		}, []string{"user"}),
+	}
+}
+
+// sync updates the tracked rule groups to the ones loaded by the ruler.
+func (h *evaluationHistory) sync(configs map[string]rulespb.RuleGroupList, defaultInterval time.Duration) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	previousUsers := map[string]struct{}{}
+	for key := range h.groups {
+		previousUsers[key.user] = struct{}{}
+	}
+
+	loaded := map[userGroupKey]struct{}{}
+	for userID, groups := range configs {
+		for _, g := range groups {
+			key := userGroupKey{user: userID, namespace: g.Namespace, group: g.Name}
+			loaded[key] = struct{}{}
+
+			interval := g.Interval
+			if interval == 0 {
+				interval = defaultInterval
+			}
+
+			if existing, ok := h.groups[key]; ok {
+				existing.interval = interval
+			} else {
+				h.groups[key] = &groupEvaluations{interval: interval}
+			}
+		}
+	}
+
+	for key := range h.groups {
+		if _, ok := loaded[key]; !ok {
+			delete(h.groups, key)
+		}
+	}
+
+	for userID := range previousUsers {
+		if _, ok := configs[userID]; !ok {
+			h.missedEvaluations.DeleteLabelValues(userID)
+		}
+	}
+}
+
+// recordQuery records a query run at the evaluation timestamp ts by a rule of the group.
+func (h *evaluationHistory) recordQuery(key userGroupKey, ts, start, end time.Time, err error) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	g, ok := h.groups[key]
+	if !ok {
+		return
+	}
+
+	last := g.last()
+	switch {
+	case last == nil || ts.After(last.timestamp):
+		if last != nil && g.interval > 0 {
+			// Evaluation timestamps are slotted at the group interval.
+			if missed := int64((ts.Sub(last.timestamp)+g.interval/2)/g.interval) - 1; missed > 0 {
+				g.missed += missed
+				h.missedEvaluations.WithLabelValues(key.user).Add(float64(missed))
+			}
+		}
+
+		last = &evaluation{timestamp: ts, start: start}
+		g.evaluations = append(g.evaluations, last)
+		if len(g.evaluations) > h.size {
+			g.evaluations = g.evaluations[len(g.evaluations)-h.size:]
+		}
+
+	case ts.Before(last.timestamp):
+		// Not part of the current evaluation.
+		return
+	}
+
+	last.end = end
+	if last.err == nil {
+		last.err = err
+	}
+}
+
+// recordWrite records the samples written by a rule of the group, for the current evaluation.
+func (h *evaluationHistory) recordWrite(key userGroupKey, samples int, err error) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	g, ok := h.groups[key]
+	if !ok {
+		return
+	}
+
+	last := g.last()
+	if last == nil {
+		return
+	}
+
+	if err != nil {
+		if last.err == nil {
+			last.err = err
+		}
+	} else {
+		last.samples += int64(samples)
+	}
+
+	if now := time.Now(); now.After(last.end) {
+		last.end = now
+	}
+}
+
+// get returns the most recent evaluations of the group, oldest first, and the number
+// of missed evaluations.
+func (h *evaluationHistory) get(key userGroupKey) ([]*EvaluationDesc, int64) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	g, ok := h.groups[key]
+	if !ok {
+		return nil, 0
+	}
+
+	result := make([]*EvaluationDesc, 0, len(g.evaluations))
+	for _, e := range g.evaluations {
+		desc := &EvaluationDesc{
+			Timestamp: e.timestamp,
+			Duration:  e.end.Sub(e.start),
+			Samples:   e.samples,
+		}
+		if e.err != nil {
+			desc.Error = e.err.Error()
+		}
+		result = append(result, desc)
+	}
+	return result, g.missed
+}
+
+func (g *groupEvaluations) last() *evaluation {
+	if len(g.evaluations) == 0 {
+		return nil
+	}
+	return g.evaluations[len(g.evaluations)-1]
+}
+
+type evaluationHistoryContextKey struct{}
+
+func withEvaluationHistory(ctx context.Context, h *evaluationHistory) context.Context {
+	return context.WithValue(ctx, evaluationHistoryContextKey{}, h)
+}
+
+func evaluationHistoryFromContext(ctx context.Context) *evaluationHistory {
+	h, _ := ctx.Value(evaluationHistoryContextKey{}).(*evaluationHistory)
+	return h
+}
+
+// EvaluationHistoryQueryFunc returns a new query function recording the queries in the evaluation
+// history of the rule group. It must wrap any other query function altering the query timestamp.
+func EvaluationHistoryQueryFunc(qf rules.QueryFunc, userID string) rules.QueryFunc {
+	return func(ctx context.Context, qs string, t time.Time) (promql.Vector, error) {
+		h := evaluationHistoryFromContext(ctx)
+		file, group, ok := ruleGroupFromContext(ctx)
+		if h == nil || !ok {
+			return qf(ctx, qs, t)
+		}
+
+		start := time.Now()
+		result, err := qf(ctx, qs, t)
+		h.recordQuery(newUserGroupKeyFromFile(userID, file, group), t, start, time.Now(), err)
+		return result, err
+	}
+}
+
+// RuleGroupEvaluationHistory is the evaluation history of a rule group.
+type RuleGroupEvaluationHistory struct {
+	Name              string                 `json:"name"`
+	File              string                 `json:"file"`
+	Interval          float64                `json:"interval"`
+	MissedEvaluations int64                  `json:"missedEvaluations"`
+	Evaluations       []*RuleGroupEvaluation `json:"evaluations"`
+}
+
+// RuleGroupEvaluation is a single evaluation of a rule group.
+type RuleGroupEvaluation struct {
+	Timestamp      time.Time `json:"timestamp"`
+	EvaluationTime float64   `json:"evaluationTime"`
+	Samples        int64     `json:"samples"`
+	Error          string    `json:"error,omitempty"`
+}
+
+// EvaluationHistory returns the most recent evaluations of the tenant's rule groups, gathered
+// from all the rulers if sharding is enabled.
+func (r *Ruler) EvaluationHistory(w http.ResponseWriter, req *http.Request) {
+	logger := util_log.WithContext(req.Context(), r.logger)
+
+	if _, err := tenant.TenantID(req.Context()); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	rgs, err := r.GetRules(req.Context())
+	if err != nil {
+		level.Error(logger).Log("msg", "failed to get the rule groups evaluation history", "err", err)
+		respondError(logger, w, err.Error())
+		return
+	}
+
+	groups := make([]*RuleGroupEvaluationHistory, 0, len(rgs))
+	for _, g := range rgs {
+		history := &RuleGroupEvaluationHistory{
+			Name:              g.Group.Name,
+			File:              g.Group.Namespace,
+			Interval:          g.Group.Interval.Seconds(),
+			MissedEvaluations: g.MissedEvaluations,
+			Evaluations:       make([]*RuleGroupEvaluation, 0, len(g.EvaluationHistory)),
+		}
+
+		for _, e := range g.EvaluationHistory {
+			history.Evaluations = append(history.Evaluations, &RuleGroupEvaluation{
+				Timestamp:      e.Timestamp,
+				EvaluationTime: e.Duration.Seconds(),
+				Samples:        e.Samples,
+				Error:          e.Error,
+			})
+		}
+		groups = append(groups, history)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].File != groups[j].File {
+			return groups[i].File < groups[j].File
+		}
+		return groups[i].Name < groups[j].Name
+	})
+
+	util.WriteJSONResponse(w, &response{
+		Status: "success",
+		Data:   map[string]interface{}{"groups": groups},
+	})
+}
@@ -0,0 +1,111 @@
+package ruler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cortexproject/cortex/pkg/ruler/rulespb"
+)
+
+func TestEvaluationHistory(t *testing.T) {
+	reg := prometheus.NewPedanticRegistry()
+	h := newEvaluationHistory(3, reg)
+	key := userGroupKey{user: "user-1", namespace: "namespace", group: "group"}
+
+	h.sync(map[string]rulespb.RuleGroupList{
+		"user-1": {{Name: "group", Namespace: "namespace", User: "user-1"}},
+	}, time.Minute)
+
+	base := time.Unix(1000, 0)
+	queryErr := errors.New("query failed")
+
+	// First evaluation, with two rules.
+	h.recordQuery(key, base, base, base.Add(time.Second), nil)
+	h.recordWrite(key, 2, nil)
+	h.recordQuery(key, base, base.Add(time.Second), base.Add(2*time.Second), nil)
+	h.recordWrite(key, 3, nil)
+
+	// Second evaluation, with a failed query.
+	h.recordQuery(key, base.Add(time.Minute), base.Add(time.Minute), base.Add(time.Minute+time.Second), queryErr)
+
+	// Two evaluations missed before the third one.
+	h.recordQuery(key, base.Add(4*time.Minute), base.Add(4*time.Minute), base.Add(4*time.Minute+time.Second), nil)
+
+	// Late query of an older evaluation is ignored.
+	h.recordQuery(key, base.Add(time.Minute), base.Add(5*time.Minute), base.Add(5*time.Minute), queryErr)
+
+	evaluations, missed := h.get(key)
+	assert.Equal(t, int64(2), missed)
+	require.Len(t, evaluations, 3)
+
+	assert.Equal(t, base, evaluations[0].Timestamp)
+	assert.Equal(t, int64(5), evaluations[0].Samples)
+	assert.Empty(t, evaluations[0].Error)
+
+	assert.Equal(t, base.Add(time.Minute), evaluations[1].Timestamp)
+	assert.Equal(t, time.Second, evaluations[1].Duration)
+	assert.Equal(t, queryErr.Error(), evaluations[1].Error)
+
+	assert.Equal(t, base.Add(4*time.Minute), evaluations[2].Timestamp)
+	assert.Equal(t, time.Second, evaluations[2].Duration)
+	assert.Empty(t, evaluations[2].Error)
+
+	// Only the most recent evaluations are kept.
+	h.recordQuery(key, base.Add(5*time.Minute), base.Add(5*time.Minute), base.Add(5*time.Minute), nil)
+	evaluations, _ = h.get(key)
+	require.Len(t, evaluations, 3)
+	assert.Equal(t, base.Add(time.Minute), evaluations[0].Timestamp)
+	assert.Equal(t, base.Add(5*time.Minute), evaluations[2].Timestamp)
+
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP cortex_ruler_missed_evaluations_total Number of rule group evaluations missed by the ruler, detected from the gaps between consecutive evaluations.
+		# TYPE cortex_ruler_missed_evaluations_total counter
+		cortex_ruler_missed_evaluations_total{user="user-1"} 2
+	`), "cortex_ruler_missed_evaluations_total"))
+
+	// Rule groups no longer loaded are removed.
+	h.sync(map[string]rulespb.RuleGroupList{}, time.Minute)
+	evaluations, missed = h.get(key)
+	assert.Empty(t, evaluations)
+	assert.Equal(t, int64(0), missed)
+
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(""), "cortex_ruler_missed_evaluations_total"))
+}
+
+func TestEvaluationHistory_ShouldRecordQueriesAndWritesFromTheRuleGroupContext(t *testing.T) {
+	h := newEvaluationHistory(10, nil)
+	h.sync(map[string]rulespb.RuleGroupList{
+		"user-1": {{Name: "group", Namespace: "namespace", User: "user-1"}},
+	}, time.Minute)
+
+	ts := time.Unix(1000, 0)
+	ctx := ruleGroupContext(withEvaluationHistory(context.Background(), h), "group")
+
+	qf := EvaluationHistoryQueryFunc(func(context.Context, string, time.Time) (promql.Vector, error) {
+		return promql.Vector{}, nil
+	}, "user-1")
+	_, err := qf(ctx, "up", ts)
+	require.NoError(t, err)
+
+	pa := NewPusherAppendable(&fakePusher{}, "user-1", ruleLimits{}, prometheus.NewCounter(prometheus.CounterOpts{}), prometheus.NewCounter(prometheus.CounterOpts{}))
+	app := pa.Appender(ctx)
+	_, err = app.Append(0, labels.FromStrings(labels.MetricName, "foo"), ts.UnixNano()/int64(time.Millisecond), 1)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+
+	evaluations, missed := h.get(userGroupKey{user: "user-1", namespace: "namespace", group: "group"})
+	assert.Equal(t, int64(0), missed)
+	require.Len(t, evaluations, 1)
+	assert.Equal(t, ts, evaluations[0].Timestamp)
+	assert.Equal(t, int64(1), evaluations[0].Samples)
+}
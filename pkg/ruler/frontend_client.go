@@ -0,0 +1,220 @@
+package ruler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/rules"
+	"github.com/weaveworks/common/httpgrpc"
+	"github.com/weaveworks/common/user"
+	"google.golang.org/grpc"
+
+	"github.com/cortexproject/cortex/pkg/util"
+	"github.com/cortexproject/cortex/pkg/util/grpcclient"
+)
+
+const (
+	statusSuccess = "success"
+	statusError   = "error"
+)
+
+// FrontendClient runs instant queries against the query-frontend, sending
+// HTTP requests over gRPC (httpgrpc).
+type FrontendClient struct {
+	client     httpgrpc.HTTPClient
+	timeout    time.Duration
+	maxRetries int
+	queryPath  string
+}
+
+// NewFrontendClient makes a new FrontendClient. prometheusHTTPPrefix is the path prefix
+// under which the query-frontend serves the Prometheus API.
+func NewFrontendClient(client httpgrpc.HTTPClient, timeout time.Duration, maxRetries int, prometheusHTTPPrefix string) *FrontendClient {
+	return &FrontendClient{
+		client:     client,
+		timeout:    timeout,
+		maxRetries: maxRetries,
+		queryPath:  path.Join(prometheusHTTPPrefix, "/api/v1/query"),
+	}
+}
+
+// DialQueryFrontend creates a FrontendClient connected to the query-frontend configured in cfg.
+func DialQueryFrontend(cfg Config, prometheusHTTPPrefix string, reg prometheus.Registerer) (*FrontendClient, error) {
+	requestDuration := promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "cortex_ruler_query_frontend_request_duration_seconds",
+		Help:    "Time spent executing requests to the query-frontend.",
+		Buckets: prometheus.ExponentialBuckets(0.008, 4, 7),
+	}, []string{"operation", "status_code"})
+
+	opts, err := cfg.FrontendClient.DialOption(grpcclient.Instrument(requestDuration))
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.Dial(cfg.FrontendAddress, opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to dial query-frontend %s", cfg.FrontendAddress)
+	}
+
+	return NewFrontendClient(httpgrpc.NewHTTPClient(conn), cfg.FrontendTimeout, cfg.FrontendMaxRetries, prometheusHTTPPrefix), nil
+}
+
+// InstantQuery runs the instant query qs at time t for the tenant in the context.
+// Failed requests are retried, unless the query-frontend returned a 4xx status code.
+func (c *FrontendClient) InstantQuery(ctx context.Context, qs string, t time.Time) (promql.Vector, error) {
+	userID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	args := url.Values{}
+	args.Set("query", qs)
+	args.Set("time", strconv.FormatFloat(float64(t.UnixNano())/float64(time.Second), 'f', -1, 64))
+	body := []byte(args.Encode())
+
+	req := &httpgrpc.HTTPRequest{
+		Method: http.MethodPost,
+		Url:    c.queryPath,
+		Body:   body,
+		Headers: []*httpgrpc.Header{
+			{Key: "Content-Type", Values: []string{"application/x-www-form-urlencoded"}},
+			{Key: "Content-Length", Values: []string{strconv.Itoa(len(body))}},
+			{Key: user.OrgIDHeaderName, Values: []string{userID}},
+		},
+	}
+
+	backoff := util.NewBackoff(ctx, util.BackoffConfig{
+		MinBackoff: 100 * time.Millisecond,
+		MaxBackoff: 2 * time.Second,
+		MaxRetries: c.maxRetries + 1,
+	})
+
+	for backoff.Ongoing() {
+		var resp *httpgrpc.HTTPResponse
+		resp, err = c.handle(ctx, req)
+		if err == nil {
+			return decodeQueryResponse(resp.Body)
+		}
+
+		if r, ok := httpgrpc.HTTPResponseFromError(err); ok && r.Code/100 == 4 {
+			return nil, err
+		}
+
+		backoff.Wait()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return nil, backoff.Err()
+}
+
+func (c *FrontendClient) handle(ctx context.Context, req *httpgrpc.HTTPRequest) (*httpgrpc.HTTPResponse, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	resp, err := c.client.Handle(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// The httpgrpc server only returns an error for 5xx responses,
+	// so we convert the other non-2xx responses into errors too.
+	if resp.Code/100 != 2 {
+		return nil, httpgrpc.ErrorFromHTTPResponse(resp)
+	}
+	return resp, nil
+}
+
+type queryResponse struct {
+	Status    string    `json:"status"`
+	Data      queryData `json:"data"`
+	ErrorType string    `json:"errorType"`
+	Error     string    `json:"error"`
+}
+
+type queryData struct {
+	ResultType model.ValueType `json:"resultType"`
+	Result     json.RawMessage `json:"result"`
+}
+
+// decodeQueryResponse decodes the Prometheus API response of an instant query into a promql.Vector.
+func decodeQueryResponse(body []byte) (promql.Vector, error) {
+	var resp queryResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, errors.Wrap(err, "failed to decode query response")
+	}
+
+	if resp.Status == statusError {
+		return nil, fmt.Errorf("query failed with %s: %s", resp.ErrorType, resp.Error)
+	}
+	if resp.Status != statusSuccess {
+		return nil, fmt.Errorf("unexpected query response status %q", resp.Status)
+	}
+
+	switch resp.Data.ResultType {
+	case model.ValVector:
+		var vector model.Vector
+		if err := json.Unmarshal(resp.Data.Result, &vector); err != nil {
+			return nil, errors.Wrap(err, "failed to decode vector result")
+		}
+
+		out := make(promql.Vector, 0, len(vector))
+		for _, s := range vector {
+			out = append(out, promql.Sample{
+				Point:  promql.Point{T: int64(s.Timestamp), V: float64(s.Value)},
+				Metric: metricToLabels(s.Metric),
+			})
+		}
+		return out, nil
+
+	case model.ValScalar:
+		var scalar model.Scalar
+		if err := json.Unmarshal(resp.Data.Result, &scalar); err != nil {
+			return nil, errors.Wrap(err, "failed to decode scalar result")
+		}
+
+		// Same as rules.EngineQueryFunc, which converts scalars to a single sample vector.
+		return promql.Vector{promql.Sample{
+			Point:  promql.Point{T: int64(scalar.Timestamp), V: float64(scalar.Value)},
+			Metric: labels.Labels{},
+		}}, nil
+
+	default:
+		return nil, fmt.Errorf("rule result is not a vector or scalar: %q", resp.Data.ResultType)
+	}
+}
+
+func metricToLabels(m model.Metric) labels.Labels {
+	b := labels.NewBuilder(nil)
+	for name, value := range m {
+		b.Set(string(name), string(value))
+	}
+	return b.Labels()
+}
+
+// RemoteQueryFunc returns a new query function which runs queries through the query-frontend,
+// passing an altered timestamp like EngineQueryFunc.
+func RemoteQueryFunc(c *FrontendClient, overrides RulesLimits, userID string) rules.QueryFunc {
+	return func(ctx context.Context, qs string, t time.Time) (promql.Vector, error) {
+		// Delay the evaluation of all rules by a set interval to give a buffer
+		// to metric that haven't been forwarded to cortex yet.
+		evaluationDelay := overrides.EvaluationDelay(userID)
+		return c.InstantQuery(user.InjectOrgID(ctx, userID), qs, t.Add(-evaluationDelay))
+	}
+}
@@ -0,0 +1,140 @@
+package ruler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/httpgrpc"
+	"github.com/weaveworks/common/user"
+	"google.golang.org/grpc"
+)
+
+type mockHTTPGRPCClient struct {
+	responses []*httpgrpc.HTTPResponse
+	errs      []error
+	requests  []*httpgrpc.HTTPRequest
+}
+
+func (m *mockHTTPGRPCClient) Handle(_ context.Context, req *httpgrpc.HTTPRequest, _ ...grpc.CallOption) (*httpgrpc.HTTPResponse, error) {
+	i := len(m.requests)
+	m.requests = append(m.requests, req)
+
+	if i < len(m.errs) && m.errs[i] != nil {
+		return nil, m.errs[i]
+	}
+	return m.responses[i], nil
+}
+
+func TestFrontendClient_InstantQuery(t *testing.T) {
+	ts := time.Unix(1600000000, 500*int64(time.Millisecond))
+
+	for name, tc := range map[string]struct {
+		responses        []*httpgrpc.HTTPResponse
+		errs             []error
+		expected         promql.Vector
+		expectedErr      bool
+		expectedRequests int
+	}{
+		"vector result": {
+			responses: []*httpgrpc.HTTPResponse{{
+				Code: http.StatusOK,
+				Body: []byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{"__name__":"up","job":"foo"},"value":[1600000000.5,"1"]}]}}`),
+			}},
+			expected: promql.Vector{{
+				Point:  promql.Point{T: 1600000000500, V: 1},
+				Metric: labels.FromStrings("__name__", "up", "job", "foo"),
+			}},
+			expectedRequests: 1,
+		},
+		"scalar result": {
+			responses: []*httpgrpc.HTTPResponse{{
+				Code: http.StatusOK,
+				Body: []byte(`{"status":"success","data":{"resultType":"scalar","result":[1600000000.5,"2"]}}`),
+			}},
+			expected: promql.Vector{{
+				Point:  promql.Point{T: 1600000000500, V: 2},
+				Metric: labels.Labels{},
+			}},
+			expectedRequests: 1,
+		},
+		"matrix result": {
+			responses: []*httpgrpc.HTTPResponse{{
+				Code: http.StatusOK,
+				Body: []byte(`{"status":"success","data":{"resultType":"matrix","result":[]}}`),
+			}},
+			expectedErr:      true,
+			expectedRequests: 1,
+		},
+		"4xx responses are not retried": {
+			responses: []*httpgrpc.HTTPResponse{{
+				Code: http.StatusUnprocessableEntity,
+				Body: []byte(`{"status":"error","errorType":"execution","error":"limit exceeded"}`),
+			}},
+			expectedErr:      true,
+			expectedRequests: 1,
+		},
+		"5xx responses are retried": {
+			responses: []*httpgrpc.HTTPResponse{
+				nil,
+				{Code: http.StatusOK, Body: []byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`)},
+			},
+			errs: []error{
+				httpgrpc.Errorf(http.StatusInternalServerError, "internal error"),
+			},
+			expected:         promql.Vector{},
+			expectedRequests: 2,
+		},
+		"network errors are retried up to the max retries": {
+			errs:             []error{errors.New("unavailable"), errors.New("unavailable"), errors.New("unavailable")},
+			expectedErr:      true,
+			expectedRequests: 3,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mock := &mockHTTPGRPCClient{responses: tc.responses, errs: tc.errs}
+			c := NewFrontendClient(mock, time.Minute, 2, "/prometheus")
+
+			res, err := c.InstantQuery(user.InjectOrgID(context.Background(), "user-1"), "up", ts)
+			if tc.expectedErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, res)
+			}
+
+			require.Len(t, mock.requests, tc.expectedRequests)
+
+			req := mock.requests[0]
+			assert.Equal(t, http.MethodPost, req.Method)
+			assert.Equal(t, "/prometheus/api/v1/query", req.Url)
+
+			values, err := url.ParseQuery(string(req.Body))
+			require.NoError(t, err)
+			assert.Equal(t, "up", values.Get("query"))
+			assert.Equal(t, "1600000000.5", values.Get("time"))
+
+			var orgID []string
+			for _, h := range req.Headers {
+				if h.Key == user.OrgIDHeaderName {
+					orgID = h.Values
+				}
+			}
+			assert.Equal(t, []string{"user-1"}, orgID)
+		})
+	}
+}
+
+func TestFrontendClient_InstantQueryRequiresTenant(t *testing.T) {
+	c := NewFrontendClient(&mockHTTPGRPCClient{}, time.Minute, 2, "/prometheus")
+
+	_, err := c.InstantQuery(context.Background(), "up", time.Now())
+	require.Error(t, err)
+}
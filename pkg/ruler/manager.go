@@ -12,7 +12,6 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
-	"github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/notifier"
 	"github.com/prometheus/prometheus/pkg/rulefmt"
 	promRules "github.com/prometheus/prometheus/rules"
@@ -24,8 +23,8 @@ import (
 
 type DefaultMultiTenantManager struct {
 	cfg            Config
-	notifierCfg    *config.Config
 	managerFactory ManagerFactory
+	limits         NotifierLimits
 
 	mapper *mapper
 
@@ -47,9 +46,9 @@ type DefaultMultiTenantManager struct {
 	logger                        log.Logger
 }
 
-func NewDefaultMultiTenantManager(cfg Config, managerFactory ManagerFactory, reg prometheus.Registerer, logger log.Logger) (*DefaultMultiTenantManager, error) {
-	ncfg, err := buildNotifierConfig(&cfg)
-	if err != nil {
+func NewDefaultMultiTenantManager(cfg Config, managerFactory ManagerFactory, limits NotifierLimits, reg prometheus.Registerer, logger log.Logger) (*DefaultMultiTenantManager, error) {
+	// Validate the global notifier config upfront, before any tenant override is applied.
+	if _, err := buildNotifierConfig(&cfg, notifierOverrides{}); err != nil {
 		return nil, err
 	}
 
@@ -60,8 +59,8 @@ func NewDefaultMultiTenantManager(cfg Config, managerFactory ManagerFactory, reg
 
 	return &DefaultMultiTenantManager{
 		cfg:                cfg,
-		notifierCfg:        ncfg,
 		managerFactory:     managerFactory,
+		limits:             limits,
 		notifiers:          map[string]*rulerNotifier{},
 		mapper:             newMapper(cfg.RulePath, logger),
 		userManagers:       map[string]RulesManager{},
@@ -117,6 +116,10 @@ func (r *DefaultMultiTenantManager) SyncRuleGroups(ctx context.Context, ruleGrou
 	}
 
 	r.managersTotal.Set(float64(len(r.userManagers)))
+
+	// Tenant overrides may have changed since the notifiers have been created
+	// (e.g. the runtime config has been reloaded), so we re-apply them.
+	r.syncNotifiersConfig()
 }
 
 // syncRulesToManager maps the rule files to disk, detects any changes and will create/update the
@@ -185,6 +188,12 @@ func (r *DefaultMultiTenantManager) getOrCreateNotifier(userID string) (*notifie
 		return n.notifier, nil
 	}
 
+	overrides := getNotifierOverrides(r.limits, userID)
+	ncfg, err := buildNotifierConfig(&r.cfg, overrides)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build the notifier config")
+	}
+
 	reg := prometheus.WrapRegistererWith(prometheus.Labels{"user": userID}, r.registry)
 	reg = prometheus.WrapRegistererWithPrefix("cortex_", reg)
 	n = newRulerNotifier(&notifier.Options{
@@ -210,14 +219,41 @@ func (r *DefaultMultiTenantManager) getOrCreateNotifier(userID string) (*notifie
 	n.run()
 
 	// This should never fail, unless there's a programming mistake.
-	if err := n.applyConfig(r.notifierCfg); err != nil {
+	if err := n.applyConfig(ncfg); err != nil {
 		return nil, err
 	}
 
+	n.overrides = overrides
 	r.notifiers[userID] = n
 	return n.notifier, nil
 }
 
+// syncNotifiersConfig re-applies the notifier config of each tenant whose overrides have changed.
+// If the new config is invalid, the tenant's notifier keeps running with the previous one.
+func (r *DefaultMultiTenantManager) syncNotifiersConfig() {
+	r.notifiersMtx.Lock()
+	defer r.notifiersMtx.Unlock()
+
+	for userID, n := range r.notifiers {
+		overrides := getNotifierOverrides(r.limits, userID)
+		if overrides == n.overrides {
+			continue
+		}
+
+		ncfg, err := buildNotifierConfig(&r.cfg, overrides)
+		if err == nil {
+			err = n.applyConfig(ncfg)
+		}
+		if err != nil {
+			level.Error(r.logger).Log("msg", "failed to apply the updated notifier config", "user", userID, "err", err)
+			continue
+		}
+
+		n.overrides = overrides
+		level.Info(r.logger).Log("msg", "applied the updated notifier config", "user", userID)
+	}
+}
+
 func (r *DefaultMultiTenantManager) GetRules(userID string) []*promRules.Group {
 	var groups []*promRules.Group
 	r.userManagerMtx.Lock()
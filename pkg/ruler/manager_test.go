@@ -26,7 +26,7 @@ func TestSyncRuleGroups(t *testing.T) {
 		_ = os.RemoveAll(dir)
 	})
 
-	m, err := NewDefaultMultiTenantManager(Config{RulePath: dir}, factory, nil, log.NewNopLogger())
+	m, err := NewDefaultMultiTenantManager(Config{RulePath: dir}, factory, ruleLimits{}, nil, log.NewNopLogger())
 	require.NoError(t, err)
 
 	const user = "testUser"
@@ -98,6 +98,51 @@ func TestSyncRuleGroups(t *testing.T) {
 	})
 }
 
+func TestSyncRuleGroups_ShouldReapplyNotifierOverrides(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rules")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	limits := &ruleLimits{notifier: notifierOverrides{alertmanagerURL: "http://alertmanager-1"}}
+	m, err := NewDefaultMultiTenantManager(Config{RulePath: dir}, factory, limits, nil, log.NewNopLogger())
+	require.NoError(t, err)
+	t.Cleanup(m.Stop)
+
+	const user = "testUser"
+
+	userRules := map[string]rulespb.RuleGroupList{
+		user: {
+			&rulespb.RuleGroupDesc{
+				Name:      "group1",
+				Namespace: "ns",
+				Interval:  1 * time.Minute,
+				User:      user,
+			},
+		},
+	}
+	m.SyncRuleGroups(context.Background(), userRules)
+	require.Equal(t, limits.notifier, getNotifierOverridesApplied(m, user))
+
+	// Changed overrides are re-applied at the next sync.
+	limits.notifier = notifierOverrides{alertmanagerURL: "http://alertmanager-2", apiVersion: "v2"}
+	m.SyncRuleGroups(context.Background(), userRules)
+	require.Equal(t, limits.notifier, getNotifierOverridesApplied(m, user))
+
+	// Invalid overrides are not applied, and the notifier keeps the previous config.
+	limits.notifier = notifierOverrides{alertmanagerURL: "http://alertmanager-3", apiVersion: "v3"}
+	m.SyncRuleGroups(context.Background(), userRules)
+	require.Equal(t, notifierOverrides{alertmanagerURL: "http://alertmanager-2", apiVersion: "v2"}, getNotifierOverridesApplied(m, user))
+}
+
+func getNotifierOverridesApplied(m *DefaultMultiTenantManager, user string) notifierOverrides {
+	m.notifiersMtx.Lock()
+	defer m.notifiersMtx.Unlock()
+
+	return m.notifiers[user].overrides
+}
+
 func getManager(m *DefaultMultiTenantManager, user string) RulesManager {
 	m.userManagerMtx.Lock()
 	defer m.userManagerMtx.Unlock()
@@ -32,6 +32,91 @@ func (cfg *NotifierConfig) RegisterFlags(f *flag.FlagSet) {
 	cfg.BasicAuth.RegisterFlagsWithPrefix("ruler.alertmanager-client.", f)
 }
 
+// NotifierLimits defines the per-tenant overrides of the notifier config.
+type NotifierLimits interface {
+	RulerAlertmanagerURL(userID string) string
+	RulerAlertmanagerAPIVersion(userID string) string
+	RulerAlertmanagerClientBasicAuthUsername(userID string) string
+	RulerAlertmanagerClientBasicAuthPassword(userID string) string
+	RulerAlertmanagerClientBearerToken(userID string) string
+	RulerAlertmanagerClientTLSCAPath(userID string) string
+	RulerAlertmanagerClientTLSCertPath(userID string) string
+	RulerAlertmanagerClientTLSKeyPath(userID string) string
+	RulerAlertmanagerClientTLSServerName(userID string) string
+	RulerAlertmanagerClientTLSInsecureSkipVerify(userID string) bool
+}
+
+// notifierOverrides holds the notifier config overrides of a tenant.
+type notifierOverrides struct {
+	alertmanagerURL       string
+	apiVersion            string
+	basicAuthUsername     string
+	basicAuthPassword     string
+	bearerToken           string
+	tlsCAPath             string
+	tlsCertPath           string
+	tlsKeyPath            string
+	tlsServerName         string
+	tlsInsecureSkipVerify bool
+}
+
+func getNotifierOverrides(limits NotifierLimits, userID string) notifierOverrides {
+	return notifierOverrides{
+		alertmanagerURL:       limits.RulerAlertmanagerURL(userID),
+		apiVersion:            limits.RulerAlertmanagerAPIVersion(userID),
+		basicAuthUsername:     limits.RulerAlertmanagerClientBasicAuthUsername(userID),
+		basicAuthPassword:     limits.RulerAlertmanagerClientBasicAuthPassword(userID),
+		bearerToken:           limits.RulerAlertmanagerClientBearerToken(userID),
+		tlsCAPath:             limits.RulerAlertmanagerClientTLSCAPath(userID),
+		tlsCertPath:           limits.RulerAlertmanagerClientTLSCertPath(userID),
+		tlsKeyPath:            limits.RulerAlertmanagerClientTLSKeyPath(userID),
+		tlsServerName:         limits.RulerAlertmanagerClientTLSServerName(userID),
+		tlsInsecureSkipVerify: limits.RulerAlertmanagerClientTLSInsecureSkipVerify(userID),
+	}
+}
+
+// applyTo returns a copy of the ruler config with the overrides applied.
+func (o notifierOverrides) applyTo(cfg Config) (Config, error) {
+	if o.alertmanagerURL != "" {
+		cfg.AlertmanagerURL = o.alertmanagerURL
+	}
+
+	switch config.AlertmanagerAPIVersion(o.apiVersion) {
+	case "":
+	case config.AlertmanagerAPIVersionV1:
+		cfg.AlertmanangerEnableV2API = false
+	case config.AlertmanagerAPIVersionV2:
+		cfg.AlertmanangerEnableV2API = true
+	default:
+		return cfg, fmt.Errorf("unsupported Alertmanager API version %q", o.apiVersion)
+	}
+
+	if o.basicAuthUsername != "" {
+		cfg.Notifier.BasicAuth = util.BasicAuth{
+			Username: o.basicAuthUsername,
+			Password: o.basicAuthPassword,
+		}
+	}
+
+	if o.tlsCAPath != "" {
+		cfg.Notifier.TLS.CAPath = o.tlsCAPath
+	}
+	if o.tlsCertPath != "" {
+		cfg.Notifier.TLS.CertPath = o.tlsCertPath
+	}
+	if o.tlsKeyPath != "" {
+		cfg.Notifier.TLS.KeyPath = o.tlsKeyPath
+	}
+	if o.tlsServerName != "" {
+		cfg.Notifier.TLS.ServerName = o.tlsServerName
+	}
+	if o.tlsInsecureSkipVerify {
+		cfg.Notifier.TLS.InsecureSkipVerify = true
+	}
+
+	return cfg, nil
+}
+
 // rulerNotifier bundles a notifier.Manager together with an associated
 // Alertmanager service discovery manager and handles the lifecycle
 // of both actors.
@@ -41,6 +126,9 @@ type rulerNotifier struct {
 	sdManager *discovery.Manager
 	wg        sync.WaitGroup
 	logger    gklog.Logger
+
+	// Overrides the current config has been built from.
+	overrides notifierOverrides
 }
 
 func newRulerNotifier(o *notifier.Options, l gklog.Logger) *rulerNotifier {
@@ -86,9 +174,15 @@ func (rn *rulerNotifier) stop() {
 	rn.wg.Wait()
 }
 
-// Builds a Prometheus config.Config from a ruler.Config with just the required
-// options to configure notifications to Alertmanager.
-func buildNotifierConfig(rulerConfig *Config) (*config.Config, error) {
+// Builds a Prometheus config.Config from a ruler.Config and the tenant overrides
+// with just the required options to configure notifications to Alertmanager.
+func buildNotifierConfig(globalConfig *Config, overrides notifierOverrides) (*config.Config, error) {
+	cfg, err := overrides.applyTo(*globalConfig)
+	if err != nil {
+		return nil, err
+	}
+	rulerConfig := &cfg
+
 	amURLs := strings.Split(rulerConfig.AlertmanagerURL, ",")
 	validURLs := make([]*url.URL, 0, len(amURLs))
 
@@ -124,7 +218,15 @@ func buildNotifierConfig(rulerConfig *Config) (*config.Config, error) {
 
 	amConfigs := make([]*config.AlertmanagerConfig, 0, len(validURLs))
 	for _, url := range validURLs {
-		amConfigs = append(amConfigs, amConfigFromURL(rulerConfig, url, apiVersion))
+		amConfig := amConfigFromURL(rulerConfig, url, apiVersion)
+
+		// The bearer token and basic authentication are mutually exclusive.
+		if overrides.bearerToken != "" {
+			amConfig.HTTPClientConfig.BasicAuth = nil
+			amConfig.HTTPClientConfig.BearerToken = config_util.Secret(overrides.bearerToken)
+		}
+
+		amConfigs = append(amConfigs, amConfig)
 	}
 
 	promConfig := &config.Config{
@@ -0,0 +1,113 @@
+package ruler
+
+import (
+	"context"
+	"net/url"
+	"path/filepath"
+
+	"github.com/go-kit/kit/log/level"
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/cortexproject/cortex/pkg/ring"
+	"github.com/cortexproject/cortex/pkg/ruler/rulespb"
+	"github.com/cortexproject/cortex/pkg/util"
+)
+
+// userGroupKey identifies a rule group of a tenant.
+type userGroupKey struct {
+	user      string
+	namespace string
+	group     string
+}
+
+// newUserGroupKeyFromFile returns the key of a rule group loaded by the rules manager from the mapped file.
+func newUserGroupKeyFromFile(userID, file, group string) userGroupKey {
+	// The mapped filename is url path escaped encoded to make handling `/` characters easier.
+	namespace, err := url.PathUnescape(filepath.Base(file))
+	if err != nil {
+		namespace = filepath.Base(file)
+	}
+	return userGroupKey{user: userID, namespace: namespace, group: group}
+}
+
+// groupReplicaElector decides whether the results of a rule group evaluation should be written.
+type groupReplicaElector interface {
+	// allowWrite returns whether the results of the evaluation of the rule group, identified
+	// by its mapped file and name, should be written.
+	allowWrite(userID, file, group string) bool
+}
+
+type groupReplicaElectorContextKey struct{}
+
+func withGroupReplicaElector(ctx context.Context, e groupReplicaElector) context.Context {
+	return context.WithValue(ctx, groupReplicaElectorContextKey{}, e)
+}
+
+func groupReplicaElectorFromContext(ctx context.Context) groupReplicaElector {
+	e, _ := ctx.Value(groupReplicaElectorContextKey{}).(groupReplicaElector)
+	return e
+}
+
+// ruleGroupFromContext returns the mapped file and name of the rule group being evaluated,
+// as set by the Prometheus rules manager in the evaluation context.
+func ruleGroupFromContext(ctx context.Context) (file, group string, ok bool) {
+	origin, _ := ctx.Value(promql.QueryOrigin{}).(map[string]interface{})
+	ruleGroup, _ := origin["ruleGroup"].(map[string]string)
+	if ruleGroup == nil {
+		return "", "", false
+	}
+	return ruleGroup["file"], ruleGroup["name"], true
+}
+
+func (r *Ruler) replicationEnabled() bool {
+	return r.cfg.EnableSharding && r.cfg.Ring.ReplicationFactor > 1
+}
+
+// getTenantRing returns the ring the tenant's rule groups are sharded on.
+func (r *Ruler) getTenantRing(userID string) ring.ReadRing {
+	if r.cfg.ShardingStrategy == util.ShardingStrategyShuffle {
+		// A shard size of 0 returns the full ring.
+		return r.ring.ShuffleShard(userID, r.limits.RulerTenantShardSize(userID))
+	}
+	return r.ring
+}
+
+// updateElectedGroups updates the owned rule groups this ruler is the elected replica for.
+// If the elected replica becomes unhealthy, the ring change triggers a rules sync and the
+// next healthy replica gets elected, without restarting the evaluation of the rule group.
+func (r *Ruler) updateElectedGroups(configs map[string]rulespb.RuleGroupList) {
+	elected := map[userGroupKey]struct{}{}
+	instanceAddr := r.lifecycler.GetInstanceAddr()
+
+	for userID, groups := range configs {
+		userRing := r.getTenantRing(userID)
+
+		for _, g := range groups {
+			ok, err := instanceIsElectedForRuleGroup(userRing, g, instanceAddr)
+			if err != nil {
+				r.ringCheckErrors.Inc()
+				level.Error(r.logger).Log("msg", "failed to check if the ruler replica is elected for the rule group", "user", userID, "namespace", g.Namespace, "group", g.Name, "err", err)
+				continue
+			}
+
+			if ok {
+				elected[userGroupKey{user: userID, namespace: g.Namespace, group: g.Name}] = struct{}{}
+			}
+		}
+	}
+
+	r.electedGroupsMtx.Lock()
+	r.electedGroups = elected
+	r.electedGroupsMtx.Unlock()
+}
+
+func (r *Ruler) allowWrite(userID, file, group string) bool {
+	r.electedGroupsMtx.RLock()
+	_, ok := r.electedGroups[newUserGroupKeyFromFile(userID, file, group)]
+	r.electedGroupsMtx.RUnlock()
+
+	if !ok {
+		r.replicaSkippedWrites.Inc()
+	}
+	return ok
+}
@@ -0,0 +1,61 @@
+package ruler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockGroupReplicaElector map[string]bool
+
+func (m mockGroupReplicaElector) allowWrite(_, _, group string) bool {
+	return m[group]
+}
+
+func TestPusherAppender_ShouldOnlyWriteForElectedGroups(t *testing.T) {
+	elector := mockGroupReplicaElector{"elected": true}
+
+	for name, tc := range map[string]struct {
+		ctx           context.Context
+		expectedWrite bool
+	}{
+		"replication disabled": {
+			ctx:           ruleGroupContext(context.Background(), "not-elected"),
+			expectedWrite: true,
+		},
+		"elected replica": {
+			ctx:           ruleGroupContext(withGroupReplicaElector(context.Background(), elector), "elected"),
+			expectedWrite: true,
+		},
+		"not elected replica": {
+			ctx:           ruleGroupContext(withGroupReplicaElector(context.Background(), elector), "not-elected"),
+			expectedWrite: false,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			pusher := &fakePusher{}
+			pa := NewPusherAppendable(pusher, "user-1", ruleLimits{}, prometheus.NewCounter(prometheus.CounterOpts{}), prometheus.NewCounter(prometheus.CounterOpts{}))
+
+			app := pa.Appender(tc.ctx)
+			_, err := app.Append(0, labels.FromStrings(labels.MetricName, "foo"), 120_000, 1)
+			require.NoError(t, err)
+			require.NoError(t, app.Commit())
+
+			assert.Equal(t, tc.expectedWrite, pusher.request != nil)
+		})
+	}
+}
+
+func ruleGroupContext(ctx context.Context, group string) context.Context {
+	return promql.NewOriginContext(ctx, map[string]interface{}{
+		"ruleGroup": map[string]string{
+			"file": "/rules/user-1/namespace",
+			"name": group,
+		},
+	})
+}
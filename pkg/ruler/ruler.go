@@ -44,8 +44,9 @@ var (
 	supportedShardingStrategies = []string{util.ShardingStrategyDefault, util.ShardingStrategyShuffle}
 
 	// Validation errors.
-	errInvalidShardingStrategy = errors.New("invalid sharding strategy")
-	errInvalidTenantShardSize  = errors.New("invalid tenant shard size, the value must be greater than 0")
+	errInvalidShardingStrategy  = errors.New("invalid sharding strategy")
+	errInvalidTenantShardSize   = errors.New("invalid tenant shard size, the value must be greater than 0")
+	errInvalidReplicationFactor = errors.New("invalid ring replication factor, the value must be greater than 0")
 )
 
 const (
@@ -73,6 +74,14 @@ type Config struct {
 	ClientTLSConfig grpcclient.Config `yaml:"ruler_client"`
 	// How frequently to evaluate rules by default.
 	EvaluationInterval time.Duration `yaml:"evaluation_interval"`
+	// Address of the query-frontend to evaluate rules through. If empty, rules are evaluated locally.
+	FrontendAddress string `yaml:"frontend_address"`
+	// Timeout of each query sent to the query-frontend.
+	FrontendTimeout time.Duration `yaml:"frontend_timeout"`
+	// Max number of retries of failed queries sent to the query-frontend.
+	FrontendMaxRetries int `yaml:"frontend_max_retries"`
+	// GRPC Client configuration for the query-frontend.
+	FrontendClient grpcclient.Config `yaml:"frontend_client"`
 	// How frequently to poll for updated rules.
 	PollInterval time.Duration `yaml:"poll_interval"`
 	// Rule Storage and Polling configuration.
@@ -117,6 +126,8 @@ type Config struct {
 	RingCheckPeriod time.Duration `yaml:"-"`
 
 	EnableQueryStats bool `yaml:"query_stats_enabled"`
+
+	EvaluationHistorySize int `yaml:"evaluation_history_size"`
 }
 
 // Validate config and returns error on failure
@@ -129,18 +140,32 @@ func (cfg *Config) Validate(limits validation.Limits, log log.Logger) error {
 		return errInvalidTenantShardSize
 	}
 
+	if cfg.Ring.ReplicationFactor <= 0 {
+		return errInvalidReplicationFactor
+	}
+
+	if cfg.EnableSharding {
+		if err := cfg.Ring.TokenGenerator.Validate(); err != nil {
+			return errors.Wrap(err, "invalid ring config")
+		}
+	}
+
 	if err := cfg.StoreConfig.Validate(); err != nil {
 		return errors.Wrap(err, "invalid storage config")
 	}
 	if err := cfg.ClientTLSConfig.Validate(log); err != nil {
 		return errors.Wrap(err, "invalid ruler gRPC client config")
 	}
+	if err := cfg.FrontendClient.Validate(log); err != nil {
+		return errors.Wrap(err, "invalid query-frontend gRPC client config")
+	}
 	return nil
 }
 
 // RegisterFlags adds the flags required to config this to the given FlagSet
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	cfg.ClientTLSConfig.RegisterFlagsWithPrefix("ruler.client", f)
+	cfg.FrontendClient.RegisterFlagsWithPrefix("ruler.frontend-client", f)
 	cfg.StoreConfig.RegisterFlags(f)
 	cfg.Ring.RegisterFlags(f)
 	cfg.Notifier.RegisterFlags(f)
@@ -154,6 +179,9 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.Var(&cfg.ExternalURL, "ruler.external.url", "URL of alerts return path.")
 	f.DurationVar(&cfg.EvaluationInterval, "ruler.evaluation-interval", 1*time.Minute, "How frequently to evaluate rules")
 	f.DurationVar(&cfg.PollInterval, "ruler.poll-interval", 1*time.Minute, "How frequently to poll for rule changes")
+	f.StringVar(&cfg.FrontendAddress, "ruler.frontend-address", "", "GRPC listen address of the query-frontend(s). When set, rules queries are sent to the query-frontend instead of being evaluated by the ruler. Must be a DNS address (prefixed with dns:///) to enable client side load balancing.")
+	f.DurationVar(&cfg.FrontendTimeout, "ruler.frontend-timeout", 2*time.Minute, "Timeout for each query sent to the query-frontend. 0 to disable.")
+	f.IntVar(&cfg.FrontendMaxRetries, "ruler.frontend-max-retries", 3, "Maximum number of retries for a query sent to the query-frontend failing with a 5xx status code or a network error.")
 
 	f.StringVar(&cfg.AlertmanagerURL, "ruler.alertmanager-url", "", "Comma-separated list of URL(s) of the Alertmanager(s) to send notifications to. Each Alertmanager URL is treated as a separate group in the configuration. Multiple Alertmanagers in HA per group can be supported by using DNS resolution via -ruler.alertmanager-discovery.")
 	f.BoolVar(&cfg.AlertmanagerDiscovery, "ruler.alertmanager-discovery", false, "Use DNS SRV records to discover Alertmanager hosts.")
@@ -176,6 +204,7 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.Var(&cfg.DisabledTenants, "ruler.disabled-tenants", "Comma separated list of tenants whose rules this ruler cannot evaluate. If specified, a ruler that would normally pick the specified tenant(s) for processing will ignore them instead. Subject to sharding.")
 
 	f.BoolVar(&cfg.EnableQueryStats, "ruler.query-stats-enabled", false, "Report the wall time for ruler queries to complete as a per user metric and as an info level log message.")
+	f.IntVar(&cfg.EvaluationHistorySize, "ruler.evaluation-history-size", 0, "Number of most recent evaluations to keep for each rule group, exposed by the evaluation history API along with the missed evaluations. 0 to disable.")
 
 	cfg.RingCheckPeriod = 5 * time.Second
 }
@@ -194,6 +223,7 @@ type MultiTenantManager interface {
 }
 
 // Ruler evaluates rules.
+//
 //	+---------------------------------------------------------------+
 //	|                                                               |
 //	|                   Query       +-------------+                 |
@@ -238,6 +268,14 @@ type Ruler struct {
 	ringCheckErrors prometheus.Counter
 	rulerSync       *prometheus.CounterVec
 
+	// Rule groups this ruler is the elected replica for, when the replication is enabled.
+	electedGroupsMtx     sync.RWMutex
+	electedGroups        map[userGroupKey]struct{}
+	replicaSkippedWrites prometheus.Counter
+
+	// Most recent evaluations of the loaded rule groups, nil if disabled.
+	history *evaluationHistory
+
 	allowedTenants *util.AllowedTenants
 
 	registry prometheus.Registerer
@@ -265,6 +303,15 @@ func NewRuler(cfg Config, manager MultiTenantManager, reg prometheus.Registerer,
 			Name: "cortex_ruler_sync_rules_total",
 			Help: "Total number of times the ruler sync operation triggered.",
 		}, []string{"reason"}),
+
+		replicaSkippedWrites: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_ruler_replica_skipped_write_requests_total",
+			Help: "Number of write requests skipped because the ruler is not the elected replica of the rule group.",
+		}),
+	}
+
+	if cfg.EvaluationHistorySize > 0 {
+		ruler.history = newEvaluationHistory(cfg.EvaluationHistorySize, reg)
 	}
 
 	if len(cfg.EnabledTenants) > 0 {
@@ -403,6 +450,7 @@ func tokenForGroup(g *rulespb.RuleGroupDesc) uint32 {
 	return ringHasher.Sum32()
 }
 
+// instanceOwnsRuleGroup returns whether the instance is part of the rule group's replication set.
 func instanceOwnsRuleGroup(r ring.ReadRing, g *rulespb.RuleGroupDesc, instanceAddr string) (bool, error) {
 	hash := tokenForGroup(g)
 
@@ -411,6 +459,19 @@ func instanceOwnsRuleGroup(r ring.ReadRing, g *rulespb.RuleGroupDesc, instanceAd
 		return false, errors.Wrap(err, "error reading ring to verify rule group ownership")
 	}
 
+	return rlrs.Includes(instanceAddr), nil
+}
+
+// instanceIsElectedForRuleGroup returns whether the instance is the first healthy instance
+// of the rule group's replication set.
+func instanceIsElectedForRuleGroup(r ring.ReadRing, g *rulespb.RuleGroupDesc, instanceAddr string) (bool, error) {
+	hash := tokenForGroup(g)
+
+	rlrs, err := r.Get(hash, RingOp, nil, nil, nil)
+	if err != nil {
+		return false, errors.Wrap(err, "error reading ring to verify rule group election")
+	}
+
 	return rlrs.Instances[0].Addr == instanceAddr, nil
 }
 
@@ -488,6 +549,16 @@ func (r *Ruler) syncRules(ctx context.Context, reason string) {
 		return
 	}
 
+	if r.replicationEnabled() {
+		r.updateElectedGroups(configs)
+		ctx = withGroupReplicaElector(ctx, r)
+	}
+
+	if r.history != nil {
+		r.history.sync(configs, r.cfg.EvaluationInterval)
+		ctx = withEvaluationHistory(ctx, r.history)
+	}
+
 	// This will also delete local group files for users that are no longer in 'configs' map.
 	r.manager.SyncRuleGroups(ctx, configs)
 }
@@ -643,7 +714,7 @@ func (r *Ruler) GetRules(ctx context.Context) ([]*GroupStateDesc, error) {
 	}
 
 	if r.cfg.EnableSharding {
-		return r.getShardedRules(ctx)
+		return r.getShardedRules(ctx, userID)
 	}
 
 	return r.getLocalRules(userID)
@@ -675,6 +746,9 @@ func (r *Ruler) getLocalRules(userID string) ([]*GroupStateDesc, error) {
 			EvaluationTimestamp: group.GetLastEvaluation(),
 			EvaluationDuration:  group.GetEvaluationTime(),
 		}
+		if r.history != nil {
+			groupDesc.EvaluationHistory, groupDesc.MissedEvaluations = r.history.get(userGroupKey{user: userID, namespace: decodedNamespace, group: group.Name()})
+		}
 		for _, r := range group.Rules() {
 			lastError := ""
 			if r.LastError() != nil {
@@ -736,8 +810,10 @@ func (r *Ruler) getLocalRules(userID string) ([]*GroupStateDesc, error) {
 	return groupDescs, nil
 }
 
-func (r *Ruler) getShardedRules(ctx context.Context) ([]*GroupStateDesc, error) {
-	rulers, err := r.ring.GetReplicationSetForOperation(RingOp)
+func (r *Ruler) getShardedRules(ctx context.Context, userID string) ([]*GroupStateDesc, error) {
+	// With shuffle sharding, the tenant's rule groups are only evaluated by the rulers
+	// in the tenant's shard, so there's no need to query the other ones.
+	rulers, err := r.getTenantRing(userID).GetReplicationSetForOperation(RingOp)
 	if err != nil {
 		return nil, err
 	}
@@ -750,22 +826,30 @@ func (r *Ruler) getShardedRules(ctx context.Context) ([]*GroupStateDesc, error)
 	var (
 		mergedMx sync.Mutex
 		merged   []*GroupStateDesc
+		failures int
 	)
 
-	// Concurrently fetch rules from all rulers. Since rules are not replicated,
-	// we need all requests to succeed.
+	// Each rule group is evaluated by replication factor rulers, so we can tolerate
+	// up to replication factor - 1 failed requests and still get all the rule groups.
+	maxFailures := r.cfg.Ring.ReplicationFactor - 1
+
+	// Concurrently fetch rules from all rulers.
 	jobs := concurrency.CreateJobsFromStrings(rulers.GetAddresses())
 	err = concurrency.ForEach(ctx, jobs, len(jobs), func(ctx context.Context, job interface{}) error {
 		addr := job.(string)
 
-		grpcClient, err := r.clientsPool.GetClientFor(addr)
+		newGrps, err := r.getRulesFrom(ctx, addr)
 		if err != nil {
-			return errors.Wrapf(err, "unable to get client for ruler %s", addr)
-		}
-
-		newGrps, err := grpcClient.(RulerClient).Rules(ctx, &RulesRequest{})
-		if err != nil {
-			return errors.Wrapf(err, "unable to retrieve rules from ruler %s", addr)
+			mergedMx.Lock()
+			failures++
+			tolerated := failures <= maxFailures
+			mergedMx.Unlock()
+
+			if tolerated {
+				level.Warn(r.logger).Log("msg", "unable to retrieve rules from ruler, skipping it since rule groups are replicated", "ruler", addr, "err", err)
+				return nil
+			}
+			return err
 		}
 
 		mergedMx.Lock()
@@ -774,8 +858,55 @@ func (r *Ruler) getShardedRules(ctx context.Context) ([]*GroupStateDesc, error)
 
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deduplicateGroups(merged), nil
+}
+
+func (r *Ruler) getRulesFrom(ctx context.Context, addr string) (*RulesResponse, error) {
+	grpcClient, err := r.clientsPool.GetClientFor(addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get client for ruler %s", addr)
+	}
+
+	resp, err := grpcClient.(RulerClient).Rules(ctx, &RulesRequest{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to retrieve rules from ruler %s", addr)
+	}
+	return resp, nil
+}
+
+// deduplicateGroups removes the rule groups returned by more than one ruler. This may happen
+// while the ring is changing, because the previous owner of a group keeps evaluating it until
+// its next rules sync, or when rule groups are replicated. The most recently evaluated copy
+// of each group is kept.
+func deduplicateGroups(groups []*GroupStateDesc) []*GroupStateDesc {
+	type groupKey struct {
+		namespace string
+		name      string
+	}
+
+	indexes := make(map[groupKey]int, len(groups))
+	result := make([]*GroupStateDesc, 0, len(groups))
+
+	for _, g := range groups {
+		key := groupKey{namespace: g.Group.Namespace, name: g.Group.Name}
+
+		i, ok := indexes[key]
+		if !ok {
+			indexes[key] = len(result)
+			result = append(result, g)
+			continue
+		}
 
-	return merged, err
+		if g.EvaluationTimestamp.After(result[i].EvaluationTimestamp) {
+			result[i] = g
+		}
+	}
+
+	return result
 }
 
 // Rules implements the rules service
@@ -122,6 +122,10 @@ type GroupStateDesc struct {
 	ActiveRules         []*RuleStateDesc       `protobuf:"bytes,2,rep,name=active_rules,json=activeRules,proto3" json:"active_rules,omitempty"`
 	EvaluationTimestamp time.Time              `protobuf:"bytes,3,opt,name=evaluationTimestamp,proto3,stdtime" json:"evaluationTimestamp"`
 	EvaluationDuration  time.Duration          `protobuf:"bytes,4,opt,name=evaluationDuration,proto3,stdduration" json:"evaluationDuration"`
+	// Most recent evaluations of the group, oldest first.
+	EvaluationHistory []*EvaluationDesc `protobuf:"bytes,5,rep,name=evaluationHistory,proto3" json:"evaluationHistory,omitempty"`
+	// Number of evaluations missed since the group has been loaded by the ruler.
+	MissedEvaluations int64 `protobuf:"varint,6,opt,name=missedEvaluations,proto3" json:"missedEvaluations,omitempty"`
 }
 
 func (m *GroupStateDesc) Reset()      { *m = GroupStateDesc{} }
@@ -184,6 +188,88 @@ func (m *GroupStateDesc) GetEvaluationDuration() time.Duration {
 	return 0
 }
 
+func (m *GroupStateDesc) GetEvaluationHistory() []*EvaluationDesc {
+	if m != nil {
+		return m.EvaluationHistory
+	}
+	return nil
+}
+
+func (m *GroupStateDesc) GetMissedEvaluations() int64 {
+	if m != nil {
+		return m.MissedEvaluations
+	}
+	return 0
+}
+
+// EvaluationDesc is a proto representation of a rule group evaluation
+type EvaluationDesc struct {
+	Timestamp time.Time     `protobuf:"bytes,1,opt,name=timestamp,proto3,stdtime" json:"timestamp"`
+	Duration  time.Duration `protobuf:"bytes,2,opt,name=duration,proto3,stdduration" json:"duration"`
+	Samples   int64         `protobuf:"varint,3,opt,name=samples,proto3" json:"samples,omitempty"`
+	Error     string        `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (m *EvaluationDesc) Reset()      { *m = EvaluationDesc{} }
+func (*EvaluationDesc) ProtoMessage() {}
+func (*EvaluationDesc) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9ecbec0a4cfddea6, []int{3}
+}
+func (m *EvaluationDesc) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *EvaluationDesc) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_EvaluationDesc.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *EvaluationDesc) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EvaluationDesc.Merge(m, src)
+}
+func (m *EvaluationDesc) XXX_Size() int {
+	return m.Size()
+}
+func (m *EvaluationDesc) XXX_DiscardUnknown() {
+	xxx_messageInfo_EvaluationDesc.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EvaluationDesc proto.InternalMessageInfo
+
+func (m *EvaluationDesc) GetTimestamp() time.Time {
+	if m != nil {
+		return m.Timestamp
+	}
+	return time.Time{}
+}
+
+func (m *EvaluationDesc) GetDuration() time.Duration {
+	if m != nil {
+		return m.Duration
+	}
+	return 0
+}
+
+func (m *EvaluationDesc) GetSamples() int64 {
+	if m != nil {
+		return m.Samples
+	}
+	return 0
+}
+
+func (m *EvaluationDesc) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 // RuleStateDesc is a proto representation of a Prometheus Rule
 type RuleStateDesc struct {
 	Rule                *rulespb.RuleDesc `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
@@ -198,7 +284,7 @@ type RuleStateDesc struct {
 func (m *RuleStateDesc) Reset()      { *m = RuleStateDesc{} }
 func (*RuleStateDesc) ProtoMessage() {}
 func (*RuleStateDesc) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9ecbec0a4cfddea6, []int{3}
+	return fileDescriptor_9ecbec0a4cfddea6, []int{4}
 }
 func (m *RuleStateDesc) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -291,7 +377,7 @@ type AlertStateDesc struct {
 func (m *AlertStateDesc) Reset()      { *m = AlertStateDesc{} }
 func (*AlertStateDesc) ProtoMessage() {}
 func (*AlertStateDesc) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9ecbec0a4cfddea6, []int{4}
+	return fileDescriptor_9ecbec0a4cfddea6, []int{5}
 }
 func (m *AlertStateDesc) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -373,6 +459,7 @@ func init() {
 	proto.RegisterType((*RulesRequest)(nil), "ruler.RulesRequest")
 	proto.RegisterType((*RulesResponse)(nil), "ruler.RulesResponse")
 	proto.RegisterType((*GroupStateDesc)(nil), "ruler.GroupStateDesc")
+	proto.RegisterType((*EvaluationDesc)(nil), "ruler.EvaluationDesc")
 	proto.RegisterType((*RuleStateDesc)(nil), "ruler.RuleStateDesc")
 	proto.RegisterType((*AlertStateDesc)(nil), "ruler.AlertStateDesc")
 }
@@ -380,50 +467,55 @@ func init() {
 func init() { proto.RegisterFile("ruler.proto", fileDescriptor_9ecbec0a4cfddea6) }
 
 var fileDescriptor_9ecbec0a4cfddea6 = []byte{
-	// 677 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x54, 0xcf, 0x4f, 0x13, 0x41,
-	0x14, 0xde, 0x29, 0x6c, 0x69, 0xa7, 0x88, 0xc9, 0x50, 0xcd, 0xda, 0x98, 0x29, 0xa9, 0x17, 0x62,
-	0xc2, 0x92, 0x20, 0x89, 0xf1, 0x80, 0x66, 0x09, 0xe8, 0xc5, 0x83, 0x59, 0xd4, 0x2b, 0x99, 0xb6,
-	0xc3, 0xb2, 0xba, 0xec, 0xac, 0x33, 0xb3, 0x0d, 0x47, 0xfe, 0x04, 0x8e, 0x9e, 0x3d, 0xf9, 0xa7,
-	0x70, 0xe4, 0x48, 0x8c, 0x41, 0x59, 0x2e, 0x1e, 0xf9, 0x13, 0xcc, 0xfc, 0x58, 0xdb, 0x2a, 0x26,
-	0x6e, 0x0c, 0x97, 0x76, 0xdf, 0xbc, 0xf7, 0x7d, 0xdf, 0xbc, 0xef, 0xcd, 0x0c, 0x6c, 0xf1, 0x3c,
-	0xa1, 0xdc, 0xcf, 0x38, 0x93, 0x0c, 0xb9, 0x3a, 0xe8, 0xac, 0x44, 0xb1, 0xdc, 0xcf, 0xfb, 0xfe,
-	0x80, 0x1d, 0xac, 0x46, 0x2c, 0x62, 0xab, 0x3a, 0xdb, 0xcf, 0xf7, 0x74, 0xa4, 0x03, 0xfd, 0x65,
-	0x50, 0x1d, 0x1c, 0x31, 0x16, 0x25, 0x74, 0x5c, 0x35, 0xcc, 0x39, 0x91, 0x31, 0x4b, 0x6d, 0xbe,
-	0xfb, 0x7b, 0x5e, 0xc6, 0x07, 0x54, 0x48, 0x72, 0x90, 0xd9, 0x82, 0x27, 0x13, 0x7a, 0x03, 0xc6,
-	0x25, 0x3d, 0xcc, 0x38, 0x7b, 0x47, 0x07, 0xd2, 0x46, 0xab, 0xd9, 0xfb, 0xa8, 0x4c, 0xf4, 0xed,
-	0x87, 0x85, 0x6e, 0xfc, 0x0b, 0x54, 0x77, 0xa5, 0x7f, 0x45, 0xd6, 0x37, 0xff, 0x06, 0xde, 0x5b,
-	0x80, 0xf3, 0xa1, 0x0a, 0x43, 0xfa, 0x21, 0xa7, 0x42, 0xf6, 0x9e, 0xc2, 0x5b, 0x36, 0x16, 0x19,
-	0x4b, 0x05, 0x45, 0x2b, 0xb0, 0x1e, 0x71, 0x96, 0x67, 0xc2, 0x03, 0x4b, 0x33, 0xcb, 0xad, 0xb5,
-	0x3b, 0xbe, 0xf1, 0xeb, 0x85, 0x5a, 0xdc, 0x91, 0x44, 0xd2, 0x2d, 0x2a, 0x06, 0xa1, 0x2d, 0xea,
-	0x7d, 0xaa, 0xc1, 0x85, 0xe9, 0x14, 0x7a, 0x08, 0x5d, 0x9d, 0xf4, 0xc0, 0x12, 0x58, 0x6e, 0xad,
-	0xb5, 0x7d, 0xa3, 0xaf, 0x64, 0x74, 0xa5, 0xc6, 0x9b, 0x12, 0xf4, 0x18, 0xce, 0x93, 0x81, 0x8c,
-	0x47, 0x74, 0x57, 0x17, 0x79, 0x35, 0xad, 0xd9, 0xb6, 0x9a, 0x0a, 0x32, 0x96, 0x6c, 0x99, 0x4a,
-	0xbd, 0x5d, 0xf4, 0x16, 0x2e, 0xd2, 0x11, 0x49, 0x72, 0x6d, 0xfb, 0xeb, 0xd2, 0x5e, 0x6f, 0x46,
-	0x4b, 0x76, 0x7c, 0x33, 0x00, 0xbf, 0x1c, 0x80, 0xff, 0xab, 0x62, 0xb3, 0x71, 0x72, 0xde, 0x75,
-	0x8e, 0xbf, 0x75, 0x41, 0x78, 0x1d, 0x01, 0xda, 0x81, 0x68, 0xbc, 0xbc, 0x65, 0xc7, 0xea, 0xcd,
-	0x6a, 0xda, 0x7b, 0x7f, 0xd0, 0x96, 0x05, 0x86, 0xf5, 0xa3, 0x62, 0xbd, 0x06, 0xde, 0xfb, 0x5a,
-	0x33, 0x2e, 0x8f, 0x3d, 0x7a, 0x00, 0x67, 0x55, 0x8b, 0xd6, 0xa2, 0xdb, 0x13, 0x16, 0xe9, 0x56,
-	0x75, 0x12, 0xb5, 0xa1, 0x2b, 0x14, 0xc2, 0xab, 0x2d, 0x81, 0xe5, 0x66, 0x68, 0x02, 0x74, 0x17,
-	0xd6, 0xf7, 0x29, 0x49, 0xe4, 0xbe, 0x6e, 0xb6, 0x19, 0xda, 0x08, 0xdd, 0x87, 0xcd, 0x84, 0x08,
-	0xb9, 0xcd, 0x39, 0xe3, 0x7a, 0xc3, 0xcd, 0x70, 0xbc, 0xa0, 0xc6, 0x4a, 0x12, 0xca, 0xa5, 0xf0,
-	0xdc, 0xa9, 0xb1, 0x06, 0x6a, 0x71, 0x62, 0xac, 0xa6, 0xe8, 0x6f, 0xf6, 0xd6, 0x6f, 0xc6, 0xde,
-	0xb9, 0xff, 0xb3, 0xf7, 0xc8, 0x85, 0x0b, 0xd3, 0x7d, 0x8c, 0xad, 0x03, 0x93, 0xd6, 0xa5, 0xb0,
-	0x9e, 0x90, 0x3e, 0x4d, 0xca, 0x73, 0xb6, 0xe8, 0x97, 0x77, 0xcc, 0x7f, 0xa9, 0xd6, 0x5f, 0x91,
-	0x98, 0x6f, 0x06, 0x4a, 0xeb, 0xcb, 0x79, 0xb7, 0xd2, 0x1d, 0x35, 0xf8, 0x60, 0x48, 0x32, 0x49,
-	0x79, 0x68, 0x55, 0xd0, 0x21, 0x6c, 0x91, 0x34, 0x65, 0x52, 0x6f, 0x53, 0x78, 0x33, 0x37, 0x2a,
-	0x3a, 0x29, 0xa5, 0xfa, 0x57, 0x3e, 0x51, 0x7d, 0x10, 0x40, 0x68, 0x02, 0x14, 0xc0, 0xa6, 0xbd,
-	0x6d, 0x44, 0x7a, 0x6e, 0x85, 0x59, 0x36, 0x0c, 0x2c, 0x90, 0xe8, 0x19, 0x6c, 0xec, 0xc5, 0x9c,
-	0x0e, 0x15, 0x43, 0x95, 0xd3, 0x30, 0xa7, 0x51, 0x81, 0x44, 0xdb, 0xb0, 0xc5, 0xa9, 0x60, 0xc9,
-	0xc8, 0x70, 0xcc, 0x55, 0xe0, 0x80, 0x25, 0x30, 0x90, 0xe8, 0x39, 0x9c, 0x57, 0x87, 0x7b, 0x57,
-	0xd0, 0x54, 0x2a, 0x9e, 0x46, 0x15, 0x1e, 0x85, 0xdc, 0xa1, 0xa9, 0x34, 0xdb, 0x19, 0x91, 0x24,
-	0x1e, 0xee, 0xe6, 0xa9, 0x8c, 0x13, 0xaf, 0x59, 0x85, 0x46, 0x03, 0xdf, 0x28, 0xdc, 0xda, 0x06,
-	0x74, 0xd5, 0xe5, 0xe5, 0x68, 0xdd, 0x7c, 0x08, 0xb4, 0x38, 0xf1, 0x86, 0x95, 0xaf, 0x6d, 0xa7,
-	0x3d, 0xbd, 0x68, 0x9e, 0xdc, 0x9e, 0xb3, 0xb9, 0x7e, 0x7a, 0x81, 0x9d, 0xb3, 0x0b, 0xec, 0x5c,
-	0x5d, 0x60, 0x70, 0x54, 0x60, 0xf0, 0xb9, 0xc0, 0xe0, 0xa4, 0xc0, 0xe0, 0xb4, 0xc0, 0xe0, 0x7b,
-	0x81, 0xc1, 0x8f, 0x02, 0x3b, 0x57, 0x05, 0x06, 0xc7, 0x97, 0xd8, 0x39, 0xbd, 0xc4, 0xce, 0xd9,
-	0x25, 0x76, 0xfa, 0x75, 0xbd, 0xbd, 0x47, 0x3f, 0x03, 0x00, 0x00, 0xff, 0xff, 0x29, 0x57, 0x9d,
-	0xdd, 0xd2, 0x06, 0x00, 0x00,
+	// 764 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0xcd, 0x6e, 0x13, 0x3b,
+	0x14, 0x1e, 0x37, 0xcd, 0x9f, 0xd3, 0xe6, 0xaa, 0x6e, 0xef, 0xd5, 0xdc, 0x08, 0x39, 0x51, 0xd8,
+	0x54, 0x88, 0x4e, 0xa5, 0x52, 0x09, 0xb1, 0x28, 0x28, 0xa1, 0x05, 0x16, 0x2c, 0xd0, 0x14, 0xd8,
+	0x56, 0x4e, 0xe2, 0xa6, 0x03, 0x93, 0xf1, 0x60, 0x7b, 0xa2, 0xb2, 0xeb, 0x23, 0x74, 0x83, 0xc4,
+	0x23, 0xf0, 0x22, 0x48, 0x5d, 0x76, 0x59, 0x21, 0x54, 0x68, 0xba, 0x61, 0xd9, 0x47, 0x40, 0xb6,
+	0x67, 0x32, 0x49, 0x5b, 0xa4, 0x8e, 0x50, 0x37, 0x89, 0xcf, 0xcf, 0xf7, 0x1d, 0xfb, 0x3b, 0xc7,
+	0x1e, 0x58, 0xe1, 0x91, 0x4f, 0xb9, 0x13, 0x72, 0x26, 0x19, 0xca, 0x6b, 0xa3, 0xb6, 0xd2, 0xf7,
+	0xe4, 0x5e, 0xd4, 0x71, 0xba, 0x6c, 0xb0, 0xda, 0x67, 0x7d, 0xb6, 0xaa, 0xa3, 0x9d, 0x68, 0x57,
+	0x5b, 0xda, 0xd0, 0x2b, 0x83, 0xaa, 0xe1, 0x3e, 0x63, 0x7d, 0x9f, 0xa6, 0x59, 0xbd, 0x88, 0x13,
+	0xe9, 0xb1, 0x20, 0x8e, 0xd7, 0x2f, 0xc7, 0xa5, 0x37, 0xa0, 0x42, 0x92, 0x41, 0x18, 0x27, 0x3c,
+	0x9a, 0xa8, 0xd7, 0x65, 0x5c, 0xd2, 0xfd, 0x90, 0xb3, 0x77, 0xb4, 0x2b, 0x63, 0x6b, 0x35, 0x7c,
+	0xdf, 0x4f, 0x02, 0x9d, 0x78, 0x11, 0x43, 0x37, 0x6e, 0x02, 0xd5, 0xa7, 0xd2, 0xbf, 0x22, 0xec,
+	0x98, 0x7f, 0x03, 0x6f, 0x56, 0xe1, 0x9c, 0xab, 0x4c, 0x97, 0x7e, 0x88, 0xa8, 0x90, 0xcd, 0xc7,
+	0x70, 0x3e, 0xb6, 0x45, 0xc8, 0x02, 0x41, 0xd1, 0x0a, 0x2c, 0xf4, 0x39, 0x8b, 0x42, 0x61, 0x83,
+	0x46, 0x6e, 0xb9, 0xb2, 0xf6, 0xaf, 0x63, 0xf4, 0x7a, 0xae, 0x9c, 0xdb, 0x92, 0x48, 0xba, 0x49,
+	0x45, 0xd7, 0x8d, 0x93, 0x9a, 0x9f, 0x72, 0xb0, 0x3a, 0x1d, 0x42, 0xf7, 0x60, 0x5e, 0x07, 0x6d,
+	0xd0, 0x00, 0xcb, 0x95, 0xb5, 0x25, 0xc7, 0xd4, 0x57, 0x65, 0x74, 0xa6, 0xc6, 0x9b, 0x14, 0xf4,
+	0x10, 0xce, 0x91, 0xae, 0xf4, 0x86, 0x74, 0x47, 0x27, 0xd9, 0x33, 0x8d, 0xdc, 0x18, 0xc2, 0x35,
+	0x24, 0x2d, 0x59, 0x31, 0x99, 0x7a, 0xbb, 0xe8, 0x2d, 0x5c, 0xa4, 0x43, 0xe2, 0x47, 0x5a, 0xf6,
+	0xd7, 0x89, 0xbc, 0x76, 0x4e, 0x97, 0xac, 0x39, 0xa6, 0x01, 0x4e, 0xd2, 0x00, 0x67, 0x9c, 0xd1,
+	0x2e, 0x1d, 0x9d, 0xd6, 0xad, 0xc3, 0x1f, 0x75, 0xe0, 0x5e, 0x47, 0x80, 0xb6, 0x21, 0x4a, 0xdd,
+	0x9b, 0x71, 0x5b, 0xed, 0x59, 0x4d, 0xfb, 0xff, 0x15, 0xda, 0x24, 0xc1, 0xb0, 0x7e, 0x56, 0xac,
+	0xd7, 0xc0, 0xd1, 0x53, 0xb8, 0x90, 0x7a, 0x5f, 0x78, 0x42, 0x32, 0xfe, 0xd1, 0xce, 0x4f, 0xc9,
+	0xbb, 0x95, 0xa2, 0xd4, 0x59, 0xaf, 0xe6, 0xa3, 0xfb, 0x70, 0x61, 0xe0, 0x09, 0x41, 0x7b, 0x69,
+	0xaa, 0xb0, 0x0b, 0x0d, 0xb0, 0x9c, 0x73, 0xaf, 0x06, 0x9a, 0x5f, 0x01, 0xac, 0x4e, 0x73, 0xa2,
+	0x36, 0x2c, 0x8f, 0xe7, 0xd0, 0x06, 0x19, 0x84, 0x4a, 0x61, 0xe8, 0x09, 0x2c, 0x25, 0xb3, 0x6e,
+	0xcf, 0xdc, 0x5c, 0x94, 0x31, 0x08, 0xd9, 0xb0, 0x28, 0xc8, 0x20, 0x54, 0xbd, 0xce, 0xe9, 0xbd,
+	0x27, 0x26, 0x5a, 0x82, 0x79, 0xca, 0x39, 0xe3, 0x5a, 0xec, 0xb2, 0x6b, 0x8c, 0xe6, 0xf7, 0x19,
+	0x38, 0x3f, 0x35, 0x06, 0xe8, 0x2e, 0x9c, 0x55, 0x92, 0xc5, 0x27, 0xf8, 0x67, 0x62, 0xba, 0xb4,
+	0x72, 0x3a, 0xa8, 0xc8, 0x84, 0x42, 0xe8, 0x4d, 0x96, 0x5d, 0x63, 0xa0, 0xff, 0x60, 0x61, 0x8f,
+	0x12, 0x5f, 0xee, 0xe9, 0xda, 0x65, 0x37, 0xb6, 0xd0, 0x1d, 0x58, 0xf6, 0x89, 0x90, 0x5b, 0x13,
+	0xe5, 0x53, 0x87, 0xba, 0x11, 0xc4, 0xa7, 0x5c, 0x8a, 0x4b, 0x2d, 0x6b, 0x29, 0xe7, 0xc4, 0x8d,
+	0x30, 0x49, 0x7f, 0x9a, 0xcc, 0xc2, 0xed, 0x4c, 0x66, 0xf1, 0xaf, 0x26, 0xb3, 0x79, 0x90, 0x87,
+	0xd5, 0xe9, 0x73, 0xa4, 0xd2, 0x81, 0x49, 0xe9, 0x02, 0x58, 0xf0, 0x49, 0x87, 0xfa, 0xc9, 0x15,
+	0x5d, 0x74, 0x92, 0xe7, 0xc9, 0x79, 0xa9, 0xfc, 0xaf, 0x88, 0xc7, 0xdb, 0x2d, 0x55, 0xeb, 0xdb,
+	0x69, 0x3d, 0xd3, 0xf3, 0x66, 0xf0, 0xad, 0x1e, 0x09, 0x25, 0xe5, 0x6e, 0x5c, 0x05, 0xed, 0xc3,
+	0x0a, 0x09, 0x02, 0x26, 0xe3, 0x39, 0xcf, 0xdd, 0x6a, 0xd1, 0xc9, 0x52, 0xea, 0xfc, 0x4a, 0x27,
+	0xaa, 0x07, 0x01, 0xb8, 0xc6, 0x40, 0x2d, 0x58, 0x8e, 0x1f, 0x2a, 0x22, 0xed, 0x7c, 0x86, 0x5e,
+	0x96, 0x0c, 0xac, 0x25, 0xd5, 0xdd, 0xd9, 0xf5, 0x38, 0xed, 0x29, 0x86, 0x2c, 0xd3, 0x50, 0xd4,
+	0xa8, 0x96, 0x44, 0x5b, 0xb0, 0xc2, 0xa9, 0x60, 0xfe, 0xd0, 0x70, 0x14, 0x33, 0x70, 0xc0, 0x04,
+	0xd8, 0x92, 0xe8, 0x19, 0x9c, 0x53, 0xc3, 0xbd, 0x23, 0x68, 0x20, 0x15, 0x4f, 0x29, 0x0b, 0x8f,
+	0x42, 0x6e, 0xd3, 0x40, 0x9a, 0xed, 0x0c, 0x89, 0xef, 0xf5, 0x76, 0xa2, 0x40, 0x7a, 0xbe, 0x5d,
+	0xce, 0x42, 0xa3, 0x81, 0x6f, 0x14, 0x6e, 0x6d, 0x03, 0xe6, 0xd5, 0xe5, 0xe5, 0x68, 0xdd, 0x2c,
+	0x04, 0x5a, 0x9c, 0x78, 0xfe, 0x93, 0x0f, 0x55, 0x6d, 0x69, 0xda, 0x69, 0xbe, 0x56, 0x4d, 0xab,
+	0xbd, 0x7e, 0x7c, 0x86, 0xad, 0x93, 0x33, 0x6c, 0x5d, 0x9c, 0x61, 0x70, 0x30, 0xc2, 0xe0, 0xcb,
+	0x08, 0x83, 0xa3, 0x11, 0x06, 0xc7, 0x23, 0x0c, 0x7e, 0x8e, 0x30, 0xf8, 0x35, 0xc2, 0xd6, 0xc5,
+	0x08, 0x83, 0xc3, 0x73, 0x6c, 0x1d, 0x9f, 0x63, 0xeb, 0xe4, 0x1c, 0x5b, 0x9d, 0x82, 0xde, 0xde,
+	0x83, 0xdf, 0x03, 0x00, 0x35, 0xec, 0x44, 0x34, 0x0d, 0x08, 0x00, 0x00,
 }
 
 func (this *RulesRequest) Equal(that interface{}) bool {
@@ -512,6 +604,50 @@ func (this *GroupStateDesc) Equal(that interface{}) bool {
 	if this.EvaluationDuration != that1.EvaluationDuration {
 		return false
 	}
+	if len(this.EvaluationHistory) != len(that1.EvaluationHistory) {
+		return false
+	}
+	for i := range this.EvaluationHistory {
+		if !this.EvaluationHistory[i].Equal(that1.EvaluationHistory[i]) {
+			return false
+		}
+	}
+	if this.MissedEvaluations != that1.MissedEvaluations {
+		return false
+	}
+	return true
+}
+func (this *EvaluationDesc) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*EvaluationDesc)
+	if !ok {
+		that2, ok := that.(EvaluationDesc)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.Timestamp.Equal(that1.Timestamp) {
+		return false
+	}
+	if this.Duration != that1.Duration {
+		return false
+	}
+	if this.Samples != that1.Samples {
+		return false
+	}
+	if this.Error != that1.Error {
+		return false
+	}
 	return true
 }
 func (this *RuleStateDesc) Equal(that interface{}) bool {
@@ -644,7 +780,7 @@ func (this *GroupStateDesc) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 8)
+	s := make([]string, 0, 10)
 	s = append(s, "&ruler.GroupStateDesc{")
 	if this.Group != nil {
 		s = append(s, "Group: "+fmt.Sprintf("%#v", this.Group)+",\n")
@@ -654,6 +790,23 @@ func (this *GroupStateDesc) GoString() string {
 	}
 	s = append(s, "EvaluationTimestamp: "+fmt.Sprintf("%#v", this.EvaluationTimestamp)+",\n")
 	s = append(s, "EvaluationDuration: "+fmt.Sprintf("%#v", this.EvaluationDuration)+",\n")
+	if this.EvaluationHistory != nil {
+		s = append(s, "EvaluationHistory: "+fmt.Sprintf("%#v", this.EvaluationHistory)+",\n")
+	}
+	s = append(s, "MissedEvaluations: "+fmt.Sprintf("%#v", this.MissedEvaluations)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *EvaluationDesc) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&ruler.EvaluationDesc{")
+	s = append(s, "Timestamp: "+fmt.Sprintf("%#v", this.Timestamp)+",\n")
+	s = append(s, "Duration: "+fmt.Sprintf("%#v", this.Duration)+",\n")
+	s = append(s, "Samples: "+fmt.Sprintf("%#v", this.Samples)+",\n")
+	s = append(s, "Error: "+fmt.Sprintf("%#v", this.Error)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -864,6 +1017,25 @@ func (m *GroupStateDesc) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.MissedEvaluations != 0 {
+		i = encodeVarintRuler(dAtA, i, uint64(m.MissedEvaluations))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.EvaluationHistory) > 0 {
+		for iNdEx := len(m.EvaluationHistory) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.EvaluationHistory[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRuler(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
 	n1, err1 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.EvaluationDuration, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.EvaluationDuration):])
 	if err1 != nil {
 		return 0, err1
@@ -909,7 +1081,7 @@ func (m *GroupStateDesc) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *RuleStateDesc) Marshal() (dAtA []byte, err error) {
+func (m *EvaluationDesc) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -919,31 +1091,82 @@ func (m *RuleStateDesc) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *RuleStateDesc) MarshalTo(dAtA []byte) (int, error) {
+func (m *EvaluationDesc) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *RuleStateDesc) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *EvaluationDesc) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	n4, err4 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.EvaluationDuration, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.EvaluationDuration):])
+	if len(m.Error) > 0 {
+		i -= len(m.Error)
+		copy(dAtA[i:], m.Error)
+		i = encodeVarintRuler(dAtA, i, uint64(len(m.Error)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Samples != 0 {
+		i = encodeVarintRuler(dAtA, i, uint64(m.Samples))
+		i--
+		dAtA[i] = 0x18
+	}
+	n4, err4 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.Duration, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.Duration):])
 	if err4 != nil {
 		return 0, err4
 	}
 	i -= n4
 	i = encodeVarintRuler(dAtA, i, uint64(n4))
 	i--
-	dAtA[i] = 0x3a
-	n5, err5 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.EvaluationTimestamp, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.EvaluationTimestamp):])
+	dAtA[i] = 0x12
+	n5, err5 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Timestamp, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Timestamp):])
 	if err5 != nil {
 		return 0, err5
 	}
 	i -= n5
 	i = encodeVarintRuler(dAtA, i, uint64(n5))
 	i--
+	dAtA[i] = 0xa
+	return len(dAtA) - i, nil
+}
+
+func (m *RuleStateDesc) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RuleStateDesc) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RuleStateDesc) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	n6, err6 := github_com_gogo_protobuf_types.StdDurationMarshalTo(m.EvaluationDuration, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdDuration(m.EvaluationDuration):])
+	if err6 != nil {
+		return 0, err6
+	}
+	i -= n6
+	i = encodeVarintRuler(dAtA, i, uint64(n6))
+	i--
+	dAtA[i] = 0x3a
+	n7, err7 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.EvaluationTimestamp, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.EvaluationTimestamp):])
+	if err7 != nil {
+		return 0, err7
+	}
+	i -= n7
+	i = encodeVarintRuler(dAtA, i, uint64(n7))
+	i--
 	dAtA[i] = 0x32
 	if len(m.Alerts) > 0 {
 		for iNdEx := len(m.Alerts) - 1; iNdEx >= 0; iNdEx-- {
@@ -1015,45 +1238,45 @@ func (m *AlertStateDesc) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
-	n7, err7 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.ValidUntil, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.ValidUntil):])
-	if err7 != nil {
-		return 0, err7
-	}
-	i -= n7
-	i = encodeVarintRuler(dAtA, i, uint64(n7))
-	i--
-	dAtA[i] = 0x4a
-	n8, err8 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.LastSentAt, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.LastSentAt):])
-	if err8 != nil {
-		return 0, err8
-	}
-	i -= n8
-	i = encodeVarintRuler(dAtA, i, uint64(n8))
-	i--
-	dAtA[i] = 0x42
-	n9, err9 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.ResolvedAt, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.ResolvedAt):])
+	n9, err9 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.ValidUntil, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.ValidUntil):])
 	if err9 != nil {
 		return 0, err9
 	}
 	i -= n9
 	i = encodeVarintRuler(dAtA, i, uint64(n9))
 	i--
-	dAtA[i] = 0x3a
-	n10, err10 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.FiredAt, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.FiredAt):])
+	dAtA[i] = 0x4a
+	n10, err10 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.LastSentAt, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.LastSentAt):])
 	if err10 != nil {
 		return 0, err10
 	}
 	i -= n10
 	i = encodeVarintRuler(dAtA, i, uint64(n10))
 	i--
-	dAtA[i] = 0x32
-	n11, err11 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.ActiveAt, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.ActiveAt):])
+	dAtA[i] = 0x42
+	n11, err11 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.ResolvedAt, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.ResolvedAt):])
 	if err11 != nil {
 		return 0, err11
 	}
 	i -= n11
 	i = encodeVarintRuler(dAtA, i, uint64(n11))
 	i--
+	dAtA[i] = 0x3a
+	n12, err12 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.FiredAt, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.FiredAt):])
+	if err12 != nil {
+		return 0, err12
+	}
+	i -= n12
+	i = encodeVarintRuler(dAtA, i, uint64(n12))
+	i--
+	dAtA[i] = 0x32
+	n13, err13 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.ActiveAt, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.ActiveAt):])
+	if err13 != nil {
+		return 0, err13
+	}
+	i -= n13
+	i = encodeVarintRuler(dAtA, i, uint64(n13))
+	i--
 	dAtA[i] = 0x2a
 	if m.Value != 0 {
 		i -= 8
@@ -1154,6 +1377,35 @@ func (m *GroupStateDesc) Size() (n int) {
 	n += 1 + l + sovRuler(uint64(l))
 	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.EvaluationDuration)
 	n += 1 + l + sovRuler(uint64(l))
+	if len(m.EvaluationHistory) > 0 {
+		for _, e := range m.EvaluationHistory {
+			l = e.Size()
+			n += 1 + l + sovRuler(uint64(l))
+		}
+	}
+	if m.MissedEvaluations != 0 {
+		n += 1 + sovRuler(uint64(m.MissedEvaluations))
+	}
+	return n
+}
+
+func (m *EvaluationDesc) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Timestamp)
+	n += 1 + l + sovRuler(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdDuration(m.Duration)
+	n += 1 + l + sovRuler(uint64(l))
+	if m.Samples != 0 {
+		n += 1 + sovRuler(uint64(m.Samples))
+	}
+	l = len(m.Error)
+	if l > 0 {
+		n += 1 + l + sovRuler(uint64(l))
+	}
 	return n
 }
 
@@ -1269,11 +1521,31 @@ func (this *GroupStateDesc) String() string {
 		repeatedStringForActiveRules += strings.Replace(f.String(), "RuleStateDesc", "RuleStateDesc", 1) + ","
 	}
 	repeatedStringForActiveRules += "}"
+	repeatedStringForEvaluationHistory := "[]*EvaluationDesc{"
+	for _, f := range this.EvaluationHistory {
+		repeatedStringForEvaluationHistory += strings.Replace(f.String(), "EvaluationDesc", "EvaluationDesc", 1) + ","
+	}
+	repeatedStringForEvaluationHistory += "}"
 	s := strings.Join([]string{`&GroupStateDesc{`,
 		`Group:` + strings.Replace(fmt.Sprintf("%v", this.Group), "RuleGroupDesc", "rulespb.RuleGroupDesc", 1) + `,`,
 		`ActiveRules:` + repeatedStringForActiveRules + `,`,
 		`EvaluationTimestamp:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.EvaluationTimestamp), "Timestamp", "timestamp.Timestamp", 1), `&`, ``, 1) + `,`,
 		`EvaluationDuration:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.EvaluationDuration), "Duration", "duration.Duration", 1), `&`, ``, 1) + `,`,
+		`EvaluationHistory:` + repeatedStringForEvaluationHistory + `,`,
+		`MissedEvaluations:` + fmt.Sprintf("%v", this.MissedEvaluations) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *EvaluationDesc) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&EvaluationDesc{`,
+		`Timestamp:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Timestamp), "Timestamp", "timestamp.Timestamp", 1), `&`, ``, 1) + `,`,
+		`Duration:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Duration), "Duration", "duration.Duration", 1), `&`, ``, 1) + `,`,
+		`Samples:` + fmt.Sprintf("%v", this.Samples) + `,`,
+		`Error:` + fmt.Sprintf("%v", this.Error) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -1630,6 +1902,229 @@ func (m *GroupStateDesc) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EvaluationHistory", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRuler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRuler
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRuler
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EvaluationHistory = append(m.EvaluationHistory, &EvaluationDesc{})
+			if err := m.EvaluationHistory[len(m.EvaluationHistory)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MissedEvaluations", wireType)
+			}
+			m.MissedEvaluations = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRuler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MissedEvaluations |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRuler(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthRuler
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthRuler
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *EvaluationDesc) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRuler
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: EvaluationDesc: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: EvaluationDesc: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRuler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRuler
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRuler
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Timestamp, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Duration", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRuler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRuler
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRuler
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdDurationUnmarshal(&m.Duration, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Samples", wireType)
+			}
+			m.Samples = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRuler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Samples |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRuler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRuler
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRuler
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Error = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRuler(dAtA[iNdEx:])
@@ -40,6 +40,10 @@ type RingConfig struct {
 	InstanceAddr           string   `yaml:"instance_addr" doc:"hidden"`
 	NumTokens              int      `yaml:"num_tokens"`
 
+	TokenGenerator ring.TokenGeneratorConfig `yaml:",inline"`
+
+	ReplicationFactor int `yaml:"replication_factor"`
+
 	// Injected internally
 	ListenPort int `yaml:"-"`
 
@@ -66,6 +70,8 @@ func (cfg *RingConfig) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&cfg.InstancePort, "ruler.ring.instance-port", 0, "Port to advertise in the ring (defaults to server.grpc-listen-port).")
 	f.StringVar(&cfg.InstanceID, "ruler.ring.instance-id", hostname, "Instance ID to register in the ring.")
 	f.IntVar(&cfg.NumTokens, "ruler.ring.num-tokens", 128, "Number of tokens for each ruler.")
+	cfg.TokenGenerator.RegisterFlagsWithPrefix("ruler.ring.", f)
+	f.IntVar(&cfg.ReplicationFactor, "ruler.ring.replication-factor", 1, "The number of rulers evaluating each rule group. When greater than 1, the results of recording rules are only written by the first healthy ruler of each rule group, while alerts are sent by all of them and deduplicated by the Alertmanager.")
 }
 
 // ToLifecyclerConfig returns a LifecyclerConfig based on the ruler
@@ -78,12 +84,18 @@ func (cfg *RingConfig) ToLifecyclerConfig() (ring.BasicLifecyclerConfig, error)
 
 	instancePort := ring.GetInstancePort(cfg.InstancePort, cfg.ListenPort)
 
+	tokenGenerator, err := ring.NewTokenGenerator(cfg.TokenGenerator, cfg.InstanceID, "")
+	if err != nil {
+		return ring.BasicLifecyclerConfig{}, err
+	}
+
 	return ring.BasicLifecyclerConfig{
 		ID:                  cfg.InstanceID,
 		Addr:                fmt.Sprintf("%s:%d", instanceAddr, instancePort),
 		HeartbeatPeriod:     cfg.HeartbeatPeriod,
 		TokensObservePeriod: 0,
 		NumTokens:           cfg.NumTokens,
+		TokenGenerator:      tokenGenerator,
 	}, nil
 }
 
@@ -95,8 +107,11 @@ func (cfg *RingConfig) ToRingConfig() ring.Config {
 	rc.HeartbeatTimeout = cfg.HeartbeatTimeout
 	rc.SubringCacheDisabled = true
 
-	// Each rule group is loaded to *exactly* one ruler.
+	// Each rule group is loaded to *exactly* one ruler, unless replication is enabled.
 	rc.ReplicationFactor = 1
+	if cfg.ReplicationFactor > 1 {
+		rc.ReplicationFactor = cfg.ReplicationFactor
+	}
 
 	return rc
 }
@@ -7,6 +7,7 @@ import (
 	"math/rand"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
@@ -75,6 +76,7 @@ type ruleLimits struct {
 	tenantShard          int
 	maxRulesPerRuleGroup int
 	maxRuleGroups        int
+	notifier             notifierOverrides
 }
 
 func (r ruleLimits) EvaluationDelay(_ string) time.Duration {
@@ -93,6 +95,46 @@ func (r ruleLimits) RulerMaxRulesPerRuleGroup(_ string) int {
 	return r.maxRulesPerRuleGroup
 }
 
+func (r ruleLimits) RulerAlertmanagerURL(_ string) string {
+	return r.notifier.alertmanagerURL
+}
+
+func (r ruleLimits) RulerAlertmanagerAPIVersion(_ string) string {
+	return r.notifier.apiVersion
+}
+
+func (r ruleLimits) RulerAlertmanagerClientBasicAuthUsername(_ string) string {
+	return r.notifier.basicAuthUsername
+}
+
+func (r ruleLimits) RulerAlertmanagerClientBasicAuthPassword(_ string) string {
+	return r.notifier.basicAuthPassword
+}
+
+func (r ruleLimits) RulerAlertmanagerClientBearerToken(_ string) string {
+	return r.notifier.bearerToken
+}
+
+func (r ruleLimits) RulerAlertmanagerClientTLSCAPath(_ string) string {
+	return r.notifier.tlsCAPath
+}
+
+func (r ruleLimits) RulerAlertmanagerClientTLSCertPath(_ string) string {
+	return r.notifier.tlsCertPath
+}
+
+func (r ruleLimits) RulerAlertmanagerClientTLSKeyPath(_ string) string {
+	return r.notifier.tlsKeyPath
+}
+
+func (r ruleLimits) RulerAlertmanagerClientTLSServerName(_ string) string {
+	return r.notifier.tlsServerName
+}
+
+func (r ruleLimits) RulerAlertmanagerClientTLSInsecureSkipVerify(_ string) bool {
+	return r.notifier.tlsInsecureSkipVerify
+}
+
 func testSetup(t *testing.T, cfg Config) (*promql.Engine, storage.QueryableFunc, Pusher, log.Logger, RulesLimits, func()) {
 	dir, err := ioutil.TempDir("", filepath.Base(t.Name()))
 	assert.NoError(t, err)
@@ -124,7 +166,7 @@ func testSetup(t *testing.T, cfg Config) (*promql.Engine, storage.QueryableFunc,
 
 func newManager(t *testing.T, cfg Config) (*DefaultMultiTenantManager, func()) {
 	engine, noopQueryable, pusher, logger, overrides, cleanup := testSetup(t, cfg)
-	manager, err := NewDefaultMultiTenantManager(cfg, DefaultTenantManagerFactory(cfg, pusher, noopQueryable, engine, overrides, nil), prometheus.NewRegistry(), logger)
+	manager, err := NewDefaultMultiTenantManager(cfg, DefaultTenantManagerFactory(cfg, pusher, noopQueryable, engine, nil, overrides, nil), overrides, prometheus.NewRegistry(), logger)
 	require.NoError(t, err)
 
 	return manager, cleanup
@@ -136,8 +178,8 @@ func newRuler(t *testing.T, cfg Config) (*Ruler, func()) {
 	require.NoError(t, err)
 
 	reg := prometheus.NewRegistry()
-	managerFactory := DefaultTenantManagerFactory(cfg, pusher, noopQueryable, engine, overrides, reg)
-	manager, err := NewDefaultMultiTenantManager(cfg, managerFactory, reg, log.NewNopLogger())
+	managerFactory := DefaultTenantManagerFactory(cfg, pusher, noopQueryable, engine, nil, overrides, reg)
+	manager, err := NewDefaultMultiTenantManager(cfg, managerFactory, overrides, reg, log.NewNopLogger())
 	require.NoError(t, err)
 
 	ruler, err := NewRuler(
@@ -276,14 +318,18 @@ func TestSharding(t *testing.T) {
 	type expectedRulesMap map[string]map[string]rulespb.RuleGroupList
 
 	type testCase struct {
-		sharding         bool
-		shardingStrategy string
-		shuffleShardSize int
-		setupRing        func(*ring.Desc)
-		enabledUsers     []string
-		disabledUsers    []string
+		sharding          bool
+		shardingStrategy  string
+		shuffleShardSize  int
+		replicationFactor int
+		setupRing         func(*ring.Desc)
+		enabledUsers      []string
+		disabledUsers     []string
 
 		expectedRules expectedRulesMap
+
+		// ruler ID -> list of groups the ruler is the elected replica for (only checked if set).
+		expectedElected map[string][]*rulespb.RuleGroupDesc
 	}
 
 	const (
@@ -614,6 +660,74 @@ func TestSharding(t *testing.T) {
 			},
 		},
 
+		"default sharding, three rulers, replication factor 2": {
+			sharding:          true,
+			shardingStrategy:  util.ShardingStrategyDefault,
+			replicationFactor: 2,
+
+			// Tokens are sorted as: user3Group1, user1Group2, user1Group1, user2Group1.
+			setupRing: func(desc *ring.Desc) {
+				desc.AddIngester(ruler1, ruler1Addr, "", sortTokens([]uint32{user1Group1Token + 1}), ring.ACTIVE, time.Now())
+				desc.AddIngester(ruler2, ruler2Addr, "", sortTokens([]uint32{user1Group2Token + 1}), ring.ACTIVE, time.Now())
+				desc.AddIngester(ruler3, ruler3Addr, "", sortTokens([]uint32{user2Group1Token + 1, user3Group1Token + 1}), ring.ACTIVE, time.Now())
+			},
+
+			expectedRules: expectedRulesMap{
+				ruler1: map[string]rulespb.RuleGroupList{
+					user1: {user1Group1, user1Group2},
+				},
+				ruler2: map[string]rulespb.RuleGroupList{
+					user1: {user1Group2},
+					user2: {user2Group1},
+					user3: {user3Group1},
+				},
+				ruler3: map[string]rulespb.RuleGroupList{
+					user1: {user1Group1},
+					user2: {user2Group1},
+					user3: {user3Group1},
+				},
+			},
+
+			expectedElected: map[string][]*rulespb.RuleGroupDesc{
+				ruler1: {user1Group1},
+				ruler2: {user1Group2},
+				ruler3: {user2Group1, user3Group1},
+			},
+		},
+
+		"default sharding, three rulers, replication factor 2, elected ruler unhealthy": {
+			sharding:          true,
+			shardingStrategy:  util.ShardingStrategyDefault,
+			replicationFactor: 2,
+
+			setupRing: func(desc *ring.Desc) {
+				desc.AddIngester(ruler1, ruler1Addr, "", sortTokens([]uint32{user1Group1Token + 1}), ring.ACTIVE, time.Now())
+				desc.AddIngester(ruler2, ruler2Addr, "", sortTokens([]uint32{user1Group2Token + 1}), ring.ACTIVE, time.Now())
+				desc.AddIngester(ruler3, ruler3Addr, "", sortTokens([]uint32{user2Group1Token + 1, user3Group1Token + 1}), ring.ACTIVE, time.Now())
+
+				// Ruler 3 hasn't heartbeated the ring for a long time.
+				desc.Ingesters[ruler3] = ring.InstanceDesc{Addr: ruler3Addr, Timestamp: time.Now().Add(-time.Hour).Unix(), State: ring.ACTIVE, Tokens: sortTokens([]uint32{user2Group1Token + 1, user3Group1Token + 1})}
+			},
+
+			expectedRules: expectedRulesMap{
+				ruler1: map[string]rulespb.RuleGroupList{
+					user1: {user1Group1, user1Group2},
+				},
+				ruler2: map[string]rulespb.RuleGroupList{
+					user1: {user1Group2},
+					user2: {user2Group1},
+					user3: {user3Group1},
+				},
+				ruler3: map[string]rulespb.RuleGroupList{},
+			},
+
+			// Ruler 2 takes over the rule groups ruler 3 was elected for.
+			expectedElected: map[string][]*rulespb.RuleGroupDesc{
+				ruler1: {user1Group1},
+				ruler2: {user1Group2, user2Group1, user3Group1},
+			},
+		},
+
 		"shuffle sharding, three rulers, shard size 2, single disabled user": {
 			sharding:         true,
 			shardingStrategy: util.ShardingStrategyShuffle,
@@ -659,6 +773,7 @@ func TestSharding(t *testing.T) {
 					EnabledTenants:   tc.enabledUsers,
 					DisabledTenants:  tc.disabledUsers,
 				}
+				cfg.Ring.ReplicationFactor = tc.replicationFactor
 
 				r, cleanup := newRuler(t, cfg)
 				r.limits = ruleLimits{evalDelay: 0, tenantShard: tc.shuffleShardSize}
@@ -728,10 +843,49 @@ func TestSharding(t *testing.T) {
 			addToExpected(ruler3, r3)
 
 			require.Equal(t, tc.expectedRules, expected)
+
+			if tc.expectedElected != nil {
+				rulers := map[string]*Ruler{ruler1: r1, ruler2: r2, ruler3: r3}
+
+				for id, loaded := range expected {
+					r := rulers[id]
+					r.updateElectedGroups(loaded)
+
+					for _, groups := range allRules {
+						for _, g := range groups {
+							file := filepath.Join(r.cfg.RulePath, g.User, url.PathEscape(g.Namespace))
+							assert.Equal(t, containsGroup(tc.expectedElected[id], g), r.allowWrite(g.User, file, g.Name), "ruler: %s, user: %s, group: %s", id, g.User, g.Name)
+						}
+					}
+				}
+			}
 		})
 	}
 }
 
+func TestDeduplicateGroups(t *testing.T) {
+	now := time.Now()
+
+	first := &GroupStateDesc{Group: &rulespb.RuleGroupDesc{Namespace: "namespace", Name: "first"}, EvaluationTimestamp: now}
+	firstStale := &GroupStateDesc{Group: &rulespb.RuleGroupDesc{Namespace: "namespace", Name: "first"}, EvaluationTimestamp: now.Add(-time.Minute)}
+	second := &GroupStateDesc{Group: &rulespb.RuleGroupDesc{Namespace: "namespace", Name: "second"}, EvaluationTimestamp: now}
+	otherNamespace := &GroupStateDesc{Group: &rulespb.RuleGroupDesc{Namespace: "other", Name: "first"}, EvaluationTimestamp: now}
+
+	assert.Equal(t, []*GroupStateDesc{}, deduplicateGroups(nil))
+	assert.Equal(t, []*GroupStateDesc{first, second, otherNamespace}, deduplicateGroups([]*GroupStateDesc{first, second, otherNamespace}))
+	assert.Equal(t, []*GroupStateDesc{first, second}, deduplicateGroups([]*GroupStateDesc{firstStale, second, first}))
+	assert.Equal(t, []*GroupStateDesc{first, second}, deduplicateGroups([]*GroupStateDesc{first, second, firstStale}))
+}
+
+func containsGroup(groups []*rulespb.RuleGroupDesc, group *rulespb.RuleGroupDesc) bool {
+	for _, g := range groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
 // User shuffle shard token.
 func userToken(user string, skip int) uint32 {
 	r := rand.New(rand.NewSource(util.ShuffleShardSeed(user, "")))
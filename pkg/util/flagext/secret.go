@@ -1,5 +1,9 @@
 package flagext
 
+import (
+	"encoding/json"
+)
+
 type Secret struct {
 	Value string
 }
@@ -32,3 +36,21 @@ func (v Secret) MarshalYAML() (interface{}, error) {
 	}
 	return "********", nil
 }
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *Secret) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	return v.Set(s)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (v Secret) MarshalJSON() ([]byte, error) {
+	if len(v.Value) == 0 {
+		return json.Marshal("")
+	}
+	return json.Marshal("********")
+}
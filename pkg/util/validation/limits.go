@@ -53,6 +53,7 @@ type Limits struct {
 	EnforceMetadataMetricName bool                `yaml:"enforce_metadata_metric_name" json:"enforce_metadata_metric_name"`
 	EnforceMetricName         bool                `yaml:"enforce_metric_name" json:"enforce_metric_name"`
 	IngestionTenantShardSize  int                 `yaml:"ingestion_tenant_shard_size" json:"ingestion_tenant_shard_size"`
+	ShardByAllLabels          bool                `yaml:"shard_by_all_labels" json:"shard_by_all_labels" doc:"nocli|description=Distribute the tenant's samples across ingesters based on all labels, as opposed to solely by user and metric name. It allows to switch a single tenant to all-labels sharding at runtime, without restarting Cortex. Ignored if -distributor.shard-by-all-labels is enabled, which applies to all tenants. Once enabled, the ingesters keep applying the limits as if the series were sharded by metric name for -ingester.shard-by-all-labels-grace-period. The switch is one-way: once disabled again, queries with a metric name equality matcher are only sent to the ingesters owning the metric name, so the series written while it was enabled are missing from the query results as long as they're queried from the ingesters (-querier.query-ingesters-within)."`
 	MetricRelabelConfigs      []*relabel.Config   `yaml:"metric_relabel_configs,omitempty" json:"metric_relabel_configs,omitempty" doc:"nocli|description=List of metric relabel configurations. Note that in most situations, it is more effective to use metrics relabeling directly in the Prometheus server, e.g. remote_write.write_relabel_configs."`
 
 	// Ingester enforced limits.
@@ -88,11 +89,28 @@ type Limits struct {
 	RulerMaxRulesPerRuleGroup   int            `yaml:"ruler_max_rules_per_rule_group" json:"ruler_max_rules_per_rule_group"`
 	RulerMaxRuleGroupsPerTenant int            `yaml:"ruler_max_rule_groups_per_tenant" json:"ruler_max_rule_groups_per_tenant"`
 
+	// Ruler notifier overrides. Each of them, when set, replaces the corresponding ruler
+	// config option for the tenant.
+	RulerAlertmanagerURL                         string         `yaml:"ruler_alertmanager_url" json:"ruler_alertmanager_url" doc:"nocli|description=Comma-separated list of URL(s) of the Alertmanager(s) to send the tenant's notifications to. If not set, -ruler.alertmanager-url is used. Changes to the tenant's notifier overrides are applied at the next rules sync, which runs every -ruler.poll-interval."`
+	RulerAlertmanagerAPIVersion                  string         `yaml:"ruler_alertmanager_api_version" json:"ruler_alertmanager_api_version" doc:"nocli|description=Alertmanager API version (v1 or v2) used to send the tenant's notifications. If not set, -ruler.alertmanager-use-v2 is used."`
+	RulerAlertmanagerClientBasicAuthUsername     string         `yaml:"ruler_alertmanager_client_basic_auth_username" json:"ruler_alertmanager_client_basic_auth_username" doc:"nocli|description=HTTP basic authentication username used to send the tenant's notifications. If not set, -ruler.alertmanager-client.basic-auth-username is used."`
+	RulerAlertmanagerClientBasicAuthPassword     flagext.Secret `yaml:"ruler_alertmanager_client_basic_auth_password" json:"ruler_alertmanager_client_basic_auth_password" doc:"nocli|description=HTTP basic authentication password used to send the tenant's notifications. Ignored if the basic authentication username override is not set."`
+	RulerAlertmanagerClientBearerToken           flagext.Secret `yaml:"ruler_alertmanager_client_bearer_token" json:"ruler_alertmanager_client_bearer_token" doc:"nocli|description=Bearer token used to send the tenant's notifications. If set, it takes precedence over basic authentication."`
+	RulerAlertmanagerClientTLSCAPath             string         `yaml:"ruler_alertmanager_client_tls_ca_path" json:"ruler_alertmanager_client_tls_ca_path" doc:"nocli|description=Path to the CA certificates file used to validate the Alertmanager server certificate for the tenant's notifications. If not set, -ruler.alertmanager-client.tls-ca-path is used."`
+	RulerAlertmanagerClientTLSCertPath           string         `yaml:"ruler_alertmanager_client_tls_cert_path" json:"ruler_alertmanager_client_tls_cert_path" doc:"nocli|description=Path to the client certificate file used to send the tenant's notifications. If not set, -ruler.alertmanager-client.tls-cert-path is used."`
+	RulerAlertmanagerClientTLSKeyPath            string         `yaml:"ruler_alertmanager_client_tls_key_path" json:"ruler_alertmanager_client_tls_key_path" doc:"nocli|description=Path to the key file for the client certificate used to send the tenant's notifications. If not set, -ruler.alertmanager-client.tls-key-path is used."`
+	RulerAlertmanagerClientTLSServerName         string         `yaml:"ruler_alertmanager_client_tls_server_name" json:"ruler_alertmanager_client_tls_server_name" doc:"nocli|description=Server name used to verify the Alertmanager server certificate for the tenant's notifications. If not set, -ruler.alertmanager-client.tls-server-name is used."`
+	RulerAlertmanagerClientTLSInsecureSkipVerify bool           `yaml:"ruler_alertmanager_client_tls_insecure_skip_verify" json:"ruler_alertmanager_client_tls_insecure_skip_verify" doc:"nocli|description=Skip validating the Alertmanager server certificate for the tenant's notifications."`
+
 	// Store-gateway.
 	StoreGatewayTenantShardSize int `yaml:"store_gateway_tenant_shard_size" json:"store_gateway_tenant_shard_size"`
 
 	// Compactor.
 	CompactorBlocksRetentionPeriod model.Duration `yaml:"compactor_blocks_retention_period" json:"compactor_blocks_retention_period"`
+	CompactorBlockUploadEnabled    bool           `yaml:"compactor_block_upload_enabled" json:"compactor_block_upload_enabled"`
+
+	// Purger.
+	ChunksRetentionPeriod model.Duration `yaml:"chunks_retention_period" json:"chunks_retention_period"`
 
 	// This config doesn't have a CLI flag registered here because they're registered in
 	// their own original config struct.
@@ -168,6 +186,9 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&l.RulerMaxRuleGroupsPerTenant, "ruler.max-rule-groups-per-tenant", 0, "Maximum number of rule groups per-tenant. 0 to disable.")
 
 	f.Var(&l.CompactorBlocksRetentionPeriod, "compactor.blocks-retention-period", "Delete blocks containing samples older than the specified retention period. 0 to disable.")
+	f.BoolVar(&l.CompactorBlockUploadEnabled, "compactor.block-upload-enabled", false, "Enable the block upload API for the tenant, which allows to backfill historical data by uploading TSDB blocks.")
+
+	f.Var(&l.ChunksRetentionPeriod, "purger.chunks-retention-period", "Delete chunks containing samples older than the specified retention period. This limit is only enforced by the purger when running the Cortex chunks storage. 0 to disable.")
 
 	// Store-gateway.
 	f.IntVar(&l.StoreGatewayTenantShardSize, "store-gateway.tenant-shard-size", 0, "The default tenant's shard size when the shuffle-sharding strategy is used. Must be set when the store-gateway sharding is enabled with the shuffle-sharding strategy. When this setting is specified in the per-tenant overrides, a value of 0 disables shuffle sharding for the tenant.")
@@ -195,7 +216,7 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 func (l *Limits) Validate(shardByAllLabels bool) error {
 	// The ingester.max-global-series-per-user metric is not supported
 	// if shard-by-all-labels is disabled
-	if l.MaxGlobalSeriesPerUser > 0 && !shardByAllLabels {
+	if l.MaxGlobalSeriesPerUser > 0 && !shardByAllLabels && !l.ShardByAllLabels {
 		return errMaxGlobalSeriesPerUserValidation
 	}
 
@@ -486,6 +507,11 @@ func (o *Overrides) IngestionTenantShardSize(userID string) int {
 	return o.getOverridesForUser(userID).IngestionTenantShardSize
 }
 
+// ShardByAllLabels returns whether the tenant's series are distributed across ingesters based on all labels.
+func (o *Overrides) ShardByAllLabels(userID string) bool {
+	return o.getOverridesForUser(userID).ShardByAllLabels
+}
+
 // EvaluationDelay returns the rules evaluation delay for a given user.
 func (o *Overrides) EvaluationDelay(userID string) time.Duration {
 	return time.Duration(o.getOverridesForUser(userID).RulerEvaluationDelay)
@@ -496,6 +522,16 @@ func (o *Overrides) CompactorBlocksRetentionPeriod(userID string) time.Duration
 	return time.Duration(o.getOverridesForUser(userID).CompactorBlocksRetentionPeriod)
 }
 
+// ChunksRetentionPeriod returns the chunks storage retention period for a given user.
+func (o *Overrides) ChunksRetentionPeriod(userID string) time.Duration {
+	return time.Duration(o.getOverridesForUser(userID).ChunksRetentionPeriod)
+}
+
+// CompactorBlockUploadEnabled returns whether the block upload API is enabled for a given user.
+func (o *Overrides) CompactorBlockUploadEnabled(userID string) bool {
+	return o.getOverridesForUser(userID).CompactorBlockUploadEnabled
+}
+
 // MetricRelabelConfigs returns the metric relabel configs for a given user.
 func (o *Overrides) MetricRelabelConfigs(userID string) []*relabel.Config {
 	return o.getOverridesForUser(userID).MetricRelabelConfigs
@@ -516,6 +552,57 @@ func (o *Overrides) RulerMaxRuleGroupsPerTenant(userID string) int {
 	return o.getOverridesForUser(userID).RulerMaxRuleGroupsPerTenant
 }
 
+// RulerAlertmanagerURL returns the Alertmanager URL(s) override for a given user.
+func (o *Overrides) RulerAlertmanagerURL(userID string) string {
+	return o.getOverridesForUser(userID).RulerAlertmanagerURL
+}
+
+// RulerAlertmanagerAPIVersion returns the Alertmanager API version override for a given user.
+func (o *Overrides) RulerAlertmanagerAPIVersion(userID string) string {
+	return o.getOverridesForUser(userID).RulerAlertmanagerAPIVersion
+}
+
+// RulerAlertmanagerClientBasicAuthUsername returns the Alertmanager client basic auth username override for a given user.
+func (o *Overrides) RulerAlertmanagerClientBasicAuthUsername(userID string) string {
+	return o.getOverridesForUser(userID).RulerAlertmanagerClientBasicAuthUsername
+}
+
+// RulerAlertmanagerClientBasicAuthPassword returns the Alertmanager client basic auth password override for a given user.
+func (o *Overrides) RulerAlertmanagerClientBasicAuthPassword(userID string) string {
+	return o.getOverridesForUser(userID).RulerAlertmanagerClientBasicAuthPassword.Value
+}
+
+// RulerAlertmanagerClientBearerToken returns the Alertmanager client bearer token override for a given user.
+func (o *Overrides) RulerAlertmanagerClientBearerToken(userID string) string {
+	return o.getOverridesForUser(userID).RulerAlertmanagerClientBearerToken.Value
+}
+
+// RulerAlertmanagerClientTLSCAPath returns the Alertmanager client TLS CA path override for a given user.
+func (o *Overrides) RulerAlertmanagerClientTLSCAPath(userID string) string {
+	return o.getOverridesForUser(userID).RulerAlertmanagerClientTLSCAPath
+}
+
+// RulerAlertmanagerClientTLSCertPath returns the Alertmanager client TLS certificate path override for a given user.
+func (o *Overrides) RulerAlertmanagerClientTLSCertPath(userID string) string {
+	return o.getOverridesForUser(userID).RulerAlertmanagerClientTLSCertPath
+}
+
+// RulerAlertmanagerClientTLSKeyPath returns the Alertmanager client TLS key path override for a given user.
+func (o *Overrides) RulerAlertmanagerClientTLSKeyPath(userID string) string {
+	return o.getOverridesForUser(userID).RulerAlertmanagerClientTLSKeyPath
+}
+
+// RulerAlertmanagerClientTLSServerName returns the Alertmanager client TLS server name override for a given user.
+func (o *Overrides) RulerAlertmanagerClientTLSServerName(userID string) string {
+	return o.getOverridesForUser(userID).RulerAlertmanagerClientTLSServerName
+}
+
+// RulerAlertmanagerClientTLSInsecureSkipVerify returns whether the Alertmanager client skips
+// the server certificate validation for a given user.
+func (o *Overrides) RulerAlertmanagerClientTLSInsecureSkipVerify(userID string) bool {
+	return o.getOverridesForUser(userID).RulerAlertmanagerClientTLSInsecureSkipVerify
+}
+
 // StoreGatewayTenantShardSize returns the store-gateway shard size for a given user.
 func (o *Overrides) StoreGatewayTenantShardSize(userID string) int {
 	return o.getOverridesForUser(userID).StoreGatewayTenantShardSize
@@ -61,6 +61,11 @@ func TestLimits_Validate(t *testing.T) {
 			shardByAllLabels: true,
 			expected:         nil,
 		},
+		"max-global-series-per-user enabled and shard-by-all-labels=false but enabled in the overrides": {
+			limits:           Limits{MaxGlobalSeriesPerUser: 1000, ShardByAllLabels: true},
+			shardByAllLabels: false,
+			expected:         nil,
+		},
 	}
 
 	for testName, testData := range tests {
@@ -177,6 +182,32 @@ func TestLimitsLoadingFromJson(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestLimitsSecretsAreRedacted(t *testing.T) {
+	inp := `
+ruler_alertmanager_client_basic_auth_password: pa55w0rd
+ruler_alertmanager_client_bearer_token: t0ken
+`
+
+	l := Limits{}
+	require.NoError(t, yaml.UnmarshalStrict([]byte(inp), &l))
+
+	overrides, err := NewOverrides(l, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "pa55w0rd", overrides.RulerAlertmanagerClientBasicAuthPassword("user-1"))
+	assert.Equal(t, "t0ken", overrides.RulerAlertmanagerClientBearerToken("user-1"))
+
+	// The secrets are never exposed when the limits are marshalled, eg. by the runtime config endpoint.
+	out, err := yaml.Marshal(l)
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "pa55w0rd")
+	assert.NotContains(t, string(out), "t0ken")
+
+	out, err = json.Marshal(l)
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "pa55w0rd")
+	assert.NotContains(t, string(out), "t0ken")
+}
+
 func TestLimitsTagsYamlMatchJson(t *testing.T) {
 	limits := reflect.TypeOf(Limits{})
 	n := limits.NumField()
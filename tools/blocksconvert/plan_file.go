@@ -110,3 +110,56 @@ func IsErrorFilename(name string) (bool, string) {
 	}
 	return false, ""
 }
+
+// Verification status files are kept separate from the build status files, so that
+// verifying a plan doesn't change its build status.
+
+// Result of a successful verification, appended to the "verified" status file.
+// Any other result means that the verification found some differences.
+const VerificationResultOK = "ok"
+
+func VerificationProgressFilename(planBaseName string, t time.Time) string {
+	return fmt.Sprintf("%s.verifying.%d", planBaseName, t.Unix())
+}
+
+var verificationProgress = regexp.MustCompile(`^(.+)\.verifying\.(\d+)$`)
+
+func IsVerificationProgressFilename(name string) (bool, string, time.Time) {
+	m := verificationProgress.FindStringSubmatch(name)
+	if len(m) == 0 {
+		return false, "", time.Time{}
+	}
+
+	ts, err := strconv.ParseInt(m[2], 10, 64)
+	if err != nil {
+		return false, "", time.Time{}
+	}
+
+	return true, m[1], time.Unix(ts, 0)
+}
+
+func VerifiedFilename(planBaseName string, result string) string {
+	return fmt.Sprintf("%s.verified.%s", planBaseName, result)
+}
+
+var verified = regexp.MustCompile(`^(.+)\.verified\.([a-zA-Z0-9]+)$`)
+
+func IsVerifiedFilename(name string) (bool, string, string) {
+	m := verified.FindStringSubmatch(name)
+	if len(m) == 0 {
+		return false, "", ""
+	}
+
+	return true, m[1], m[2]
+}
+
+func VerificationErrorFilename(planBaseName string) string {
+	return planBaseName + ".verification-error"
+}
+
+func IsVerificationErrorFilename(name string) (bool, string) {
+	if strings.HasSuffix(name, ".verification-error") {
+		return true, name[:len(name)-len(".verification-error")]
+	}
+	return false, ""
+}
@@ -25,3 +25,26 @@ func TestIsProgressFile(t *testing.T) {
 		require.Equal(t, tc.t, tm, tc.input)
 	}
 }
+
+func TestIsVerificationProgressFile(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		exp   bool
+		base  string
+		t     time.Time
+	}{
+		{input: "hello/world.verifying.123456", exp: true, base: "hello/world", t: time.Unix(123456, 0)},
+		{input: "hello/world.verifying.123456123456123456123456123456123456", exp: false, base: "", t: time.Time{}},
+		{input: "hello/world.progress.123456", exp: false, base: "", t: time.Time{}},
+		{input: "hello/world.verified.ok", exp: false, base: "", t: time.Time{}},
+	} {
+		ok, base, tm := IsVerificationProgressFilename(tc.input)
+		require.Equal(t, tc.exp, ok, tc.input)
+		require.Equal(t, tc.base, base, tc.input)
+		require.Equal(t, tc.t, tm, tc.input)
+
+		// Verification progress files must never be confused with build progress files.
+		ok, _, _ = IsProgressFilename(tc.input)
+		require.False(t, ok && tc.exp, tc.input)
+	}
+}
@@ -12,7 +12,6 @@ import (
 	"github.com/thanos-io/thanos/pkg/objstore"
 
 	"github.com/cortexproject/cortex/pkg/util/services"
-	"github.com/cortexproject/cortex/tools/blocksconvert"
 )
 
 type heartbeat struct {
@@ -21,15 +20,17 @@ type heartbeat struct {
 	log              log.Logger
 	bucket           objstore.Bucket
 	planFileBasename string
+	progressFilename func(planBaseName string, t time.Time) string
 
 	lastProgressFile string
 }
 
-func newHeartbeat(log log.Logger, bucket objstore.Bucket, interval time.Duration, planFileBasename, lastProgressFile string) *heartbeat {
+func newHeartbeat(log log.Logger, bucket objstore.Bucket, interval time.Duration, planFileBasename, lastProgressFile string, progressFilename func(planBaseName string, t time.Time) string) *heartbeat {
 	hb := &heartbeat{
 		log:              log,
 		bucket:           bucket,
 		planFileBasename: planFileBasename,
+		progressFilename: progressFilename,
 		lastProgressFile: lastProgressFile,
 	}
 
@@ -52,7 +53,7 @@ func (hb *heartbeat) heartbeat(ctx context.Context) error {
 	}
 
 	now := time.Now()
-	newProgressFile := blocksconvert.ProgressFilename(hb.planFileBasename, now)
+	newProgressFile := hb.progressFilename(hb.planFileBasename, now)
 	if newProgressFile == hb.lastProgressFile {
 		// when scheduler creates progress file, it can have the same timestamp.
 		return nil
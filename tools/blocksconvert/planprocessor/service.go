@@ -2,6 +2,7 @@ package planprocessor
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"flag"
@@ -31,6 +32,14 @@ type PlanProcessor interface {
 	ProcessPlanEntries(ctx context.Context, entries chan blocksconvert.PlanEntry) (string, error)
 }
 
+// ReportingPlanProcessor is a PlanProcessor producing a report once all plan entries
+// have been processed. The report is uploaded as content of the "finished" status file.
+type ReportingPlanProcessor interface {
+	PlanProcessor
+
+	Report() ([]byte, error)
+}
+
 type Config struct {
 	// Exported config options.
 	Name              string
@@ -56,6 +65,23 @@ func (cfg *Config) RegisterFlags(prefix string, f *flag.FlagSet) {
 // Cleanup function called on startup and after each build. Can be nil.
 // Factory for creating PlanProcessor. Called for each new plan.
 func NewService(cfg Config, plansDirectory string, bucket objstore.Bucket, cleanup func(logger log.Logger) error, factory func(planLog log.Logger, userID string, dayStart, dayEnd time.Time) PlanProcessor, l log.Logger, reg prometheus.Registerer) (*Service, error) {
+	if factory == nil {
+		return nil, errors.New("invalid config")
+	}
+
+	return newService(cfg, plansDirectory, bucket, cleanup, func(planLog log.Logger, userID string, dayStart, dayEnd time.Time, _ string) PlanProcessor {
+		return factory(planLog, userID, dayStart, dayEnd)
+	}, false, l, reg)
+}
+
+// Creates new plan processor service which verifies finished plans. Unlike NewService, this service asks
+// scheduler for finished plans which haven't been verified yet, and maintains verification status files,
+// leaving plan's build status untouched. Factory also receives ID of the block built for the plan.
+func NewVerificationService(cfg Config, plansDirectory string, bucket objstore.Bucket, cleanup func(logger log.Logger) error, factory func(planLog log.Logger, userID string, dayStart, dayEnd time.Time, blockID string) PlanProcessor, l log.Logger, reg prometheus.Registerer) (*Service, error) {
+	return newService(cfg, plansDirectory, bucket, cleanup, factory, true, l, reg)
+}
+
+func newService(cfg Config, plansDirectory string, bucket objstore.Bucket, cleanup func(logger log.Logger) error, factory func(planLog log.Logger, userID string, dayStart, dayEnd time.Time, blockID string) PlanProcessor, verification bool, l log.Logger, reg prometheus.Registerer) (*Service, error) {
 	if cfg.SchedulerEndpoint == "" {
 		return nil, errors.New("no scheduler endpoint")
 	}
@@ -77,6 +103,7 @@ func NewService(cfg Config, plansDirectory string, bucket objstore.Bucket, clean
 		bucket:         bucket,
 		cleanupFn:      cleanup,
 		factory:        factory,
+		verification:   verification,
 		log:            l,
 
 		currentPlanStartTime: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
@@ -108,7 +135,10 @@ type Service struct {
 	plansDirectory string
 	bucket         objstore.Bucket
 	cleanupFn      func(logger log.Logger) error
-	factory        func(planLog log.Logger, userID string, dayStart time.Time, dayEnd time.Time) PlanProcessor
+	factory        func(planLog log.Logger, userID string, dayStart time.Time, dayEnd time.Time, blockID string) PlanProcessor
+
+	// If true, this service verifies finished plans, and uses verification status files.
+	verification bool
 
 	planFileReadPosition prometheus.Gauge
 	planFileSize         prometheus.Gauge
@@ -170,7 +200,7 @@ func (s *Service) running(ctx context.Context) error {
 				schedulerClient = blocksconvert.NewSchedulerClient(conn)
 			}
 
-			resp, err := schedulerClient.NextPlan(ctx, &blocksconvert.NextPlanRequest{Name: s.cfg.Name})
+			resp, err := schedulerClient.NextPlan(ctx, &blocksconvert.NextPlanRequest{Name: s.cfg.Name, Verification: s.verification})
 			if err != nil {
 				level.Error(s.log).Log("msg", "failed to get next plan due to error, closing connection", "err", err)
 				_ = conn.Close()
@@ -191,20 +221,31 @@ func (s *Service) running(ctx context.Context) error {
 			}
 
 			ok, base, _ := blocksconvert.IsProgressFilename(resp.ProgressFile)
+			if s.verification {
+				ok, base, _ = blocksconvert.IsVerificationProgressFilename(resp.ProgressFile)
+			}
 			if !ok || base != planBaseName {
 				level.Error(s.log).Log("msg", "got invalid progress file", "progressFile", resp.ProgressFile)
 				continue
 			}
 
-			level.Info(s.log).Log("msg", "received plan file", "planFile", resp.PlanFile, "progressFile", resp.ProgressFile)
+			if s.verification && resp.BlockID == "" {
+				level.Error(s.log).Log("msg", "got no block ID for plan to verify", "planFile", resp.PlanFile)
+				continue
+			}
+
+			level.Info(s.log).Log("msg", "received plan file", "planFile", resp.PlanFile, "progressFile", resp.ProgressFile, "blockID", resp.BlockID)
 
-			err = s.downloadAndProcessPlanFile(ctx, resp.PlanFile, planBaseName, resp.ProgressFile)
+			err = s.downloadAndProcessPlanFile(ctx, resp.PlanFile, planBaseName, resp.ProgressFile, resp.BlockID)
 			if err != nil {
 				level.Error(s.log).Log("msg", "failed to process plan file", "planFile", resp.PlanFile, "err", err)
 
 				// If context is canceled (blocksconvert is shutting down, or due to hearbeating failure), don't upload error.
 				if !errors.Is(err, context.Canceled) {
 					errorFile := blocksconvert.ErrorFilename(planBaseName)
+					if s.verification {
+						errorFile = blocksconvert.VerificationErrorFilename(planBaseName)
+					}
 					err = s.bucket.Upload(ctx, errorFile, strings.NewReader(err.Error()))
 					if err != nil {
 						level.Error(s.log).Log("msg", "failed to upload error file", "errorFile", errorFile, "err", err)
@@ -220,7 +261,7 @@ func (s *Service) running(ctx context.Context) error {
 	}
 }
 
-func (s *Service) downloadAndProcessPlanFile(ctx context.Context, planFile, planBaseName, lastProgressFile string) error {
+func (s *Service) downloadAndProcessPlanFile(ctx context.Context, planFile, planBaseName, lastProgressFile, blockID string) error {
 	defer s.planFileSize.Set(0)
 	defer s.planFileReadPosition.Set(0)
 	defer s.currentPlanStartTime.Set(0)
@@ -232,7 +273,12 @@ func (s *Service) downloadAndProcessPlanFile(ctx context.Context, planFile, plan
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	hb := newHeartbeat(planLog, s.bucket, s.cfg.HeartbeatPeriod, planBaseName, lastProgressFile)
+	progressFilename := blocksconvert.ProgressFilename
+	if s.verification {
+		progressFilename = blocksconvert.VerificationProgressFilename
+	}
+
+	hb := newHeartbeat(planLog, s.bucket, s.cfg.HeartbeatPeriod, planBaseName, lastProgressFile, progressFilename)
 	hb.AddListener(services.NewListener(nil, nil, nil, nil, func(from services.State, failure error) {
 		level.Error(planLog).Log("msg", "heartbeating failed, aborting build", "failure", failure)
 		cancel()
@@ -275,7 +321,7 @@ func (s *Service) downloadAndProcessPlanFile(ctx context.Context, planFile, plan
 
 	level.Info(planLog).Log("msg", "processing plan file", "user", userID, "dayStart", dayStart, "dayEnd", dayEnd)
 
-	processor := s.factory(planLog, userID, dayStart, dayEnd)
+	processor := s.factory(planLog, userID, dayStart, dayEnd, blockID)
 
 	planEntryCh := make(chan blocksconvert.PlanEntry)
 
@@ -302,9 +348,20 @@ func (s *Service) downloadAndProcessPlanFile(ctx context.Context, planFile, plan
 
 	id := <-idChan
 
+	content := []byte(id)
+	if rp, ok := processor.(ReportingPlanProcessor); ok {
+		content, err = rp.Report()
+		if err != nil {
+			return errors.Wrap(err, "failed to generate report")
+		}
+	}
+
 	// Upload finished status file
 	finishedFile := blocksconvert.FinishedFilename(planBaseName, id)
-	if err := s.bucket.Upload(ctx, finishedFile, strings.NewReader(id)); err != nil {
+	if s.verification {
+		finishedFile = blocksconvert.VerifiedFilename(planBaseName, id)
+	}
+	if err := s.bucket.Upload(ctx, finishedFile, bytes.NewReader(content)); err != nil {
 		return errors.Wrap(err, "failed to upload finished status file")
 	}
 	level.Info(planLog).Log("msg", "uploaded finished file", "file", finishedFile)
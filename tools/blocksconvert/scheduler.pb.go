@@ -32,6 +32,8 @@ const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 type NextPlanRequest struct {
 	// Name of service requesting the plan.
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// If true, the next finished plan whose block has not been verified yet is returned.
+	Verification bool `protobuf:"varint,2,opt,name=verification,proto3" json:"verification,omitempty"`
 }
 
 func (m *NextPlanRequest) Reset()      { *m = NextPlanRequest{} }
@@ -73,9 +75,18 @@ func (m *NextPlanRequest) GetName() string {
 	return ""
 }
 
+func (m *NextPlanRequest) GetVerification() bool {
+	if m != nil {
+		return m.Verification
+	}
+	return false
+}
+
 type NextPlanResponse struct {
 	PlanFile     string `protobuf:"bytes,1,opt,name=planFile,proto3" json:"planFile,omitempty"`
 	ProgressFile string `protobuf:"bytes,2,opt,name=progressFile,proto3" json:"progressFile,omitempty"`
+	// ID of the block built for the plan. Only set for verification.
+	BlockID string `protobuf:"bytes,3,opt,name=blockID,proto3" json:"blockID,omitempty"`
 }
 
 func (m *NextPlanResponse) Reset()      { *m = NextPlanResponse{} }
@@ -124,6 +135,13 @@ func (m *NextPlanResponse) GetProgressFile() string {
 	return ""
 }
 
+func (m *NextPlanResponse) GetBlockID() string {
+	if m != nil {
+		return m.BlockID
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*NextPlanRequest)(nil), "blocksconvert.NextPlanRequest")
 	proto.RegisterType((*NextPlanResponse)(nil), "blocksconvert.NextPlanResponse")
@@ -132,24 +150,26 @@ func init() {
 func init() { proto.RegisterFile("scheduler.proto", fileDescriptor_2b3fc28395a6d9c5) }
 
 var fileDescriptor_2b3fc28395a6d9c5 = []byte{
-	// 264 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x2f, 0x4e, 0xce, 0x48,
-	0x4d, 0x29, 0xcd, 0x49, 0x2d, 0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x4d, 0xca, 0xc9,
-	0x4f, 0xce, 0x2e, 0x4e, 0xce, 0xcf, 0x2b, 0x4b, 0x2d, 0x2a, 0x91, 0xd2, 0x4d, 0xcf, 0x2c, 0xc9,
-	0x28, 0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0x4f, 0xcf, 0x4f, 0xcf, 0xd7, 0x07, 0xab, 0x4a, 0x2a,
-	0x4d, 0x03, 0xf3, 0xc0, 0x1c, 0x30, 0x0b, 0xa2, 0x5b, 0x49, 0x95, 0x8b, 0xdf, 0x2f, 0xb5, 0xa2,
-	0x24, 0x20, 0x27, 0x31, 0x2f, 0x28, 0xb5, 0xb0, 0x34, 0xb5, 0xb8, 0x44, 0x48, 0x88, 0x8b, 0x25,
-	0x2f, 0x31, 0x37, 0x55, 0x82, 0x51, 0x81, 0x51, 0x83, 0x33, 0x08, 0xcc, 0x56, 0x0a, 0xe2, 0x12,
-	0x40, 0x28, 0x2b, 0x2e, 0xc8, 0xcf, 0x2b, 0x4e, 0x15, 0x92, 0xe2, 0xe2, 0x28, 0xc8, 0x49, 0xcc,
-	0x73, 0xcb, 0xcc, 0x81, 0xa9, 0x85, 0xf3, 0x85, 0x94, 0xb8, 0x78, 0x0a, 0x8a, 0xf2, 0xd3, 0x8b,
-	0x52, 0x8b, 0x8b, 0xc1, 0xf2, 0x4c, 0x60, 0x79, 0x14, 0x31, 0xa3, 0x28, 0x2e, 0xce, 0x60, 0x98,
-	0x5f, 0x84, 0x7c, 0xb9, 0x38, 0x60, 0x16, 0x08, 0xc9, 0xe9, 0xa1, 0x78, 0x49, 0x0f, 0xcd, 0x81,
-	0x52, 0xf2, 0x38, 0xe5, 0x21, 0x2e, 0x53, 0x62, 0x70, 0x72, 0xbe, 0xf0, 0x50, 0x8e, 0xe1, 0xc6,
-	0x43, 0x39, 0x86, 0x0f, 0x0f, 0xe5, 0x18, 0x1b, 0x1e, 0xc9, 0x31, 0xae, 0x78, 0x24, 0xc7, 0x78,
-	0xe2, 0x91, 0x1c, 0xe3, 0x85, 0x47, 0x72, 0x8c, 0x0f, 0x1e, 0xc9, 0x31, 0xbe, 0x78, 0x24, 0xc7,
-	0xf0, 0xe1, 0x91, 0x1c, 0xe3, 0x84, 0xc7, 0x72, 0x0c, 0x17, 0x1e, 0xcb, 0x31, 0xdc, 0x78, 0x2c,
-	0xc7, 0x10, 0x85, 0x1a, 0x94, 0x49, 0x6c, 0xe0, 0x20, 0x32, 0x06, 0x04, 0x00, 0x00, 0xff, 0xff,
-	0xf7, 0x7b, 0xa4, 0x64, 0x73, 0x01, 0x00, 0x00,
+	// 295 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x91, 0xb1, 0x4e, 0xf3, 0x30,
+	0x14, 0x85, 0xed, 0xfe, 0xbf, 0x20, 0xb5, 0x40, 0x45, 0x9e, 0xa2, 0x0c, 0x97, 0x2a, 0x53, 0x17,
+	0x52, 0x09, 0xde, 0x00, 0x10, 0x52, 0x07, 0x10, 0x0a, 0x5b, 0xb7, 0x24, 0xb8, 0x69, 0x84, 0x6b,
+	0x07, 0xdb, 0xa9, 0x18, 0x79, 0x04, 0x1e, 0x83, 0x47, 0x61, 0xcc, 0xd8, 0x91, 0x38, 0x0b, 0x63,
+	0x1f, 0x01, 0xe1, 0x2a, 0xa0, 0x20, 0xb1, 0xdd, 0x73, 0x3f, 0xfb, 0xd8, 0xf7, 0x5c, 0x32, 0xd2,
+	0xd9, 0x92, 0xdd, 0x57, 0x9c, 0xa9, 0xa8, 0x54, 0xd2, 0x48, 0x7a, 0x98, 0x72, 0x99, 0x3d, 0xe8,
+	0x4c, 0x8a, 0x35, 0x53, 0x26, 0x38, 0xc9, 0x0b, 0xb3, 0xac, 0xd2, 0x28, 0x93, 0xab, 0x69, 0x2e,
+	0x73, 0x39, 0x75, 0xa7, 0xd2, 0x6a, 0xe1, 0x94, 0x13, 0xae, 0xda, 0xdd, 0x0e, 0x67, 0x64, 0x74,
+	0xc3, 0x9e, 0xcc, 0x2d, 0x4f, 0x44, 0xcc, 0x1e, 0x2b, 0xa6, 0x0d, 0xa5, 0xe4, 0xbf, 0x48, 0x56,
+	0xcc, 0xc7, 0x63, 0x3c, 0x19, 0xc6, 0xae, 0xa6, 0x21, 0x39, 0x58, 0x33, 0x55, 0x2c, 0x8a, 0x2c,
+	0x31, 0x85, 0x14, 0xfe, 0x60, 0x8c, 0x27, 0x5e, 0xdc, 0xeb, 0x85, 0x9c, 0x1c, 0xfd, 0x58, 0xe9,
+	0x52, 0x0a, 0xcd, 0x68, 0x40, 0xbc, 0x92, 0x27, 0xe2, 0xaa, 0xe0, 0x9d, 0xdf, 0xb7, 0xfe, 0xf2,
+	0x2c, 0x95, 0xcc, 0x15, 0xd3, 0xda, 0xf1, 0x81, 0xe3, 0xbd, 0x1e, 0xf5, 0xc9, 0xbe, 0x1b, 0x6f,
+	0x76, 0xe9, 0xff, 0x73, 0xb8, 0x93, 0xa7, 0x73, 0x32, 0xbc, 0xeb, 0x92, 0xa0, 0xd7, 0xc4, 0xeb,
+	0x9e, 0xa6, 0x10, 0xf5, 0x02, 0x89, 0x7e, 0x8d, 0x17, 0x1c, 0xff, 0xc9, 0x77, 0x7f, 0x0e, 0xd1,
+	0xf9, 0x45, 0xdd, 0x00, 0xda, 0x34, 0x80, 0xb6, 0x0d, 0xe0, 0x67, 0x0b, 0xf8, 0xd5, 0x02, 0x7e,
+	0xb3, 0x80, 0x6b, 0x0b, 0xf8, 0xdd, 0x02, 0xfe, 0xb0, 0x80, 0xb6, 0x16, 0xf0, 0x4b, 0x0b, 0xa8,
+	0x6e, 0x01, 0x6d, 0x5a, 0x40, 0xf3, 0xfe, 0x22, 0xd2, 0x3d, 0x17, 0xf0, 0xd9, 0xe7, 0x00, 0x62,
+	0x02, 0x1a, 0xb2, 0xb1, 0x01, 0x00, 0x00,
 }
 
 func (this *NextPlanRequest) Equal(that interface{}) bool {
@@ -174,6 +194,9 @@ func (this *NextPlanRequest) Equal(that interface{}) bool {
 	if this.Name != that1.Name {
 		return false
 	}
+	if this.Verification != that1.Verification {
+		return false
+	}
 	return true
 }
 func (this *NextPlanResponse) Equal(that interface{}) bool {
@@ -201,15 +224,19 @@ func (this *NextPlanResponse) Equal(that interface{}) bool {
 	if this.ProgressFile != that1.ProgressFile {
 		return false
 	}
+	if this.BlockID != that1.BlockID {
+		return false
+	}
 	return true
 }
 func (this *NextPlanRequest) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 5)
+	s := make([]string, 0, 6)
 	s = append(s, "&blocksconvert.NextPlanRequest{")
 	s = append(s, "Name: "+fmt.Sprintf("%#v", this.Name)+",\n")
+	s = append(s, "Verification: "+fmt.Sprintf("%#v", this.Verification)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -217,10 +244,11 @@ func (this *NextPlanResponse) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 6)
+	s := make([]string, 0, 7)
 	s = append(s, "&blocksconvert.NextPlanResponse{")
 	s = append(s, "PlanFile: "+fmt.Sprintf("%#v", this.PlanFile)+",\n")
 	s = append(s, "ProgressFile: "+fmt.Sprintf("%#v", this.ProgressFile)+",\n")
+	s = append(s, "BlockID: "+fmt.Sprintf("%#v", this.BlockID)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -335,6 +363,16 @@ func (m *NextPlanRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Verification {
+		i--
+		if m.Verification {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x10
+	}
 	if len(m.Name) > 0 {
 		i -= len(m.Name)
 		copy(dAtA[i:], m.Name)
@@ -365,6 +403,13 @@ func (m *NextPlanResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.BlockID) > 0 {
+		i -= len(m.BlockID)
+		copy(dAtA[i:], m.BlockID)
+		i = encodeVarintScheduler(dAtA, i, uint64(len(m.BlockID)))
+		i--
+		dAtA[i] = 0x1a
+	}
 	if len(m.ProgressFile) > 0 {
 		i -= len(m.ProgressFile)
 		copy(dAtA[i:], m.ProgressFile)
@@ -403,6 +448,9 @@ func (m *NextPlanRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovScheduler(uint64(l))
 	}
+	if m.Verification {
+		n += 2
+	}
 	return n
 }
 
@@ -420,6 +468,10 @@ func (m *NextPlanResponse) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovScheduler(uint64(l))
 	}
+	l = len(m.BlockID)
+	if l > 0 {
+		n += 1 + l + sovScheduler(uint64(l))
+	}
 	return n
 }
 
@@ -435,6 +487,7 @@ func (this *NextPlanRequest) String() string {
 	}
 	s := strings.Join([]string{`&NextPlanRequest{`,
 		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Verification:` + fmt.Sprintf("%v", this.Verification) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -446,6 +499,7 @@ func (this *NextPlanResponse) String() string {
 	s := strings.Join([]string{`&NextPlanResponse{`,
 		`PlanFile:` + fmt.Sprintf("%v", this.PlanFile) + `,`,
 		`ProgressFile:` + fmt.Sprintf("%v", this.ProgressFile) + `,`,
+		`BlockID:` + fmt.Sprintf("%v", this.BlockID) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -519,6 +573,26 @@ func (m *NextPlanRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Verification", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowScheduler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Verification = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipScheduler(dAtA[iNdEx:])
@@ -636,6 +710,38 @@ func (m *NextPlanResponse) Unmarshal(dAtA []byte) error {
 			}
 			m.ProgressFile = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowScheduler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthScheduler
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthScheduler
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BlockID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipScheduler(dAtA[iNdEx:])
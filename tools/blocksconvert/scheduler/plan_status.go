@@ -3,6 +3,8 @@ package scheduler
 import (
 	"fmt"
 	"time"
+
+	"github.com/cortexproject/cortex/tools/blocksconvert"
 )
 
 type planStatus int
@@ -32,11 +34,46 @@ func (s planStatus) String() string {
 	}
 }
 
+type verificationStatus int
+
+const (
+	NotVerified verificationStatus = iota
+	Verifying
+	Verified
+	VerificationFailed
+	VerificationError
+	VerificationInvalid
+)
+
+func (s verificationStatus) String() string {
+	switch s {
+	case NotVerified:
+		return "NotVerified"
+	case Verifying:
+		return "Verifying"
+	case Verified:
+		return "Verified"
+	case VerificationFailed:
+		return "VerificationFailed"
+	case VerificationError:
+		return "VerificationError"
+	case VerificationInvalid:
+		return "VerificationInvalid"
+	default:
+		panic(fmt.Sprintf("invalid verification status: %d", s))
+	}
+}
+
 type plan struct {
 	PlanFiles     []string
 	ProgressFiles map[string]time.Time
 	Finished      []string
 	ErrorFile     string
+
+	VerificationProgressFiles map[string]time.Time
+	Verified                  []string
+	VerifiedFiles             []string
+	VerificationErrorFile     string
 }
 
 func (ps plan) Status() planStatus {
@@ -58,3 +95,28 @@ func (ps plan) Status() planStatus {
 
 	return New
 }
+
+// VerificationStatus returns the status of the verification of the block built for
+// this plan. Only finished plans can be verified.
+func (ps plan) VerificationStatus() verificationStatus {
+	if len(ps.Verified) > 1 || (len(ps.Verified) > 0 && ps.VerificationErrorFile != "") {
+		return VerificationInvalid
+	}
+
+	if len(ps.Verified) > 0 {
+		if ps.Verified[0] == blocksconvert.VerificationResultOK {
+			return Verified
+		}
+		return VerificationFailed
+	}
+
+	if ps.VerificationErrorFile != "" {
+		return VerificationError
+	}
+
+	if len(ps.VerificationProgressFiles) > 0 {
+		return Verifying
+	}
+
+	return NotVerified
+}
@@ -82,6 +82,14 @@ func newSchedulerWithBucket(l log.Logger, b objstore.Bucket, bucketPrefix string
 			Name: "cortex_blocksconvert_scheduler_scanned_plans",
 			Help: "Number of plans in different status",
 		}, []string{"status"}),
+		planVerificationStatus: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cortex_blocksconvert_scheduler_scanned_finished_plans_verification",
+			Help: "Number of finished plans in different verification status",
+		}, []string{"status"}),
+		queuedVerificationPlansGauge: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "cortex_blocksconvert_scheduler_queued_verification_plans",
+			Help: "Number of finished plans queued for verification",
+		}),
 		queuedPlansGauge: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
 			Name: "cortex_blocksconvert_scheduler_queued_plans",
 			Help: "Number of queued plans",
@@ -116,6 +124,9 @@ type Scheduler struct {
 	oldestPlanTimestamp prometheus.Gauge
 	newestPlanTimestamp prometheus.Gauge
 
+	planVerificationStatus       *prometheus.GaugeVec
+	queuedVerificationPlansGauge prometheus.Gauge
+
 	// Used to avoid scanning while there is dequeuing happening.
 	dequeueWG sync.WaitGroup
 
@@ -123,11 +134,17 @@ type Scheduler struct {
 	scanning     bool
 	allUserPlans map[string]map[string]plan
 	plansQueue   []queuedPlan // Queued plans are sorted by day index - more recent (higher day index) days go first.
+
+	// Finished plans whose block hasn't been verified yet, sorted like plansQueue.
+	verificationQueue []queuedPlan
 }
 
 type queuedPlan struct {
 	DayIndex int
 	PlanFile string
+
+	// ID of the block built for the plan. Only set for plans queued for verification.
+	BlockID string
 }
 
 func (s *Scheduler) scanBucketForPlans(ctx context.Context) error {
@@ -165,7 +182,11 @@ func (s *Scheduler) scanBucketForPlans(ctx context.Context) error {
 	for _, k := range []planStatus{New, InProgress, Finished, Error, Invalid} {
 		stats[k] = 0
 	}
-	var queue []queuedPlan
+	verificationStats := map[verificationStatus]int{}
+	for _, k := range []verificationStatus{NotVerified, Verifying, Verified, VerificationFailed, VerificationError, VerificationInvalid} {
+		verificationStats[k] = 0
+	}
+	var queue, verificationQueue []queuedPlan
 
 	runConcurrently(ctx, s.cfg.PlanScanConcurrency, users, func(user string) {
 		userPrefix := path.Join(s.bucketPrefix, user) + "/"
@@ -183,18 +204,36 @@ func (s *Scheduler) scanBucketForPlans(ctx context.Context) error {
 		for base, plan := range userPlans {
 			st := plan.Status()
 			if st == InProgress {
-				s.deleteObsoleteProgressFiles(ctx, &plan, path.Join(userPrefix, base))
+				if s.deleteObsoleteProgressFiles(ctx, plan.ProgressFiles, blocksconvert.ErrorFilename(path.Join(userPrefix, base))) {
+					plan.ErrorFile = blocksconvert.ErrorFilename(path.Join(userPrefix, base))
+				}
 
 				// After deleting old progress files, status might have changed from InProgress to Error.
 				st = plan.Status()
 			}
 
+			vst := NotVerified
+			if st == Finished {
+				vst = plan.VerificationStatus()
+				if vst == Verifying {
+					if s.deleteObsoleteProgressFiles(ctx, plan.VerificationProgressFiles, blocksconvert.VerificationErrorFilename(path.Join(userPrefix, base))) {
+						plan.VerificationErrorFile = blocksconvert.VerificationErrorFilename(path.Join(userPrefix, base))
+					}
+
+					// After deleting old progress files, status might have changed from Verifying to VerificationError.
+					vst = plan.VerificationStatus()
+				}
+			}
+
 			mu.Lock()
 			allPlans[user][base] = plan
 			stats[st]++
+			if st == Finished {
+				verificationStats[vst]++
+			}
 			mu.Unlock()
 
-			if st != New {
+			if st != New && (st != Finished || vst != NotVerified) {
 				continue
 			}
 
@@ -205,10 +244,18 @@ func (s *Scheduler) scanBucketForPlans(ctx context.Context) error {
 			}
 
 			mu.Lock()
-			queue = append(queue, queuedPlan{
-				DayIndex: int(dayIndex),
-				PlanFile: plan.PlanFiles[0],
-			})
+			if st == New {
+				queue = append(queue, queuedPlan{
+					DayIndex: int(dayIndex),
+					PlanFile: plan.PlanFiles[0],
+				})
+			} else {
+				verificationQueue = append(verificationQueue, queuedPlan{
+					DayIndex: int(dayIndex),
+					PlanFile: plan.PlanFiles[0],
+					BlockID:  plan.Finished[0],
+				})
+			}
 			mu.Unlock()
 		}
 	})
@@ -217,14 +264,21 @@ func (s *Scheduler) scanBucketForPlans(ctx context.Context) error {
 	sort.Slice(queue, func(i, j int) bool {
 		return queue[i].DayIndex > queue[j].DayIndex
 	})
+	sort.Slice(verificationQueue, func(i, j int) bool {
+		return verificationQueue[i].DayIndex > verificationQueue[j].DayIndex
+	})
 
 	for st, c := range stats {
 		s.planStatus.WithLabelValues(st.String()).Set(float64(c))
 	}
+	for st, c := range verificationStats {
+		s.planVerificationStatus.WithLabelValues(st.String()).Set(float64(c))
+	}
 
 	s.scanMu.Lock()
 	s.allUserPlans = allPlans
 	s.plansQueue = queue
+	s.verificationQueue = verificationQueue
 	s.updateQueuedPlansMetrics()
 	s.scanMu.Unlock()
 
@@ -233,34 +287,38 @@ func (s *Scheduler) scanBucketForPlans(ctx context.Context) error {
 		totalPlans += len(p)
 	}
 
-	level.Info(s.log).Log("msg", "plans scan finished", "queued", len(queue), "total_plans", totalPlans)
+	level.Info(s.log).Log("msg", "plans scan finished", "queued", len(queue), "queued_verification", len(verificationQueue), "total_plans", totalPlans)
 
 	return nil
 }
 
-func (s *Scheduler) deleteObsoleteProgressFiles(ctx context.Context, plan *plan, planBaseName string) {
-	for pg, t := range plan.ProgressFiles {
+// Deletes obsolete progress files from the input map, and uploads the error file. Returns true if the error file has been uploaded.
+func (s *Scheduler) deleteObsoleteProgressFiles(ctx context.Context, progressFiles map[string]time.Time, errFile string) bool {
+	errUploaded := false
+
+	for pg, t := range progressFiles {
 		if time.Since(t) < s.cfg.MaxProgressFileAge {
 			continue
 		}
 
 		level.Warn(s.log).Log("msg", "found obsolete progress file, will be deleted and error uploaded", "path", pg)
 
-		errFile := blocksconvert.ErrorFilename(planBaseName)
-		if err := s.bucket.Upload(ctx, blocksconvert.ErrorFilename(planBaseName), strings.NewReader("Obsolete progress file found: "+pg)); err != nil {
+		if err := s.bucket.Upload(ctx, errFile, strings.NewReader("Obsolete progress file found: "+pg)); err != nil {
 			level.Error(s.log).Log("msg", "failed to create error for obsolete progress file", "err", err)
 			continue
 		}
 
-		plan.ErrorFile = errFile
+		errUploaded = true
 
 		if err := s.bucket.Delete(ctx, pg); err != nil {
 			level.Error(s.log).Log("msg", "failed to delete obsolete progress file", "path", pg, "err", err)
 			continue
 		}
 
-		delete(plan.ProgressFiles, pg)
+		delete(progressFiles, pg)
 	}
+
+	return errUploaded
 }
 
 // Returns next plan that builder should work on.
@@ -269,58 +327,68 @@ func (s *Scheduler) NextPlan(ctx context.Context, req *blocksconvert.NextPlanReq
 		return &blocksconvert.NextPlanResponse{}, nil
 	}
 
-	plan, progress := s.nextPlanNoRunningCheck(ctx)
+	plan, progress, blockID := s.nextPlanNoRunningCheck(ctx, req.Verification)
 	if plan != "" {
-		level.Info(s.log).Log("msg", "sending plan file", "plan", plan, "service", req.Name)
+		level.Info(s.log).Log("msg", "sending plan file", "plan", plan, "service", req.Name, "verification", req.Verification)
 	}
 	return &blocksconvert.NextPlanResponse{
 		PlanFile:     plan,
 		ProgressFile: progress,
+		BlockID:      blockID,
 	}, nil
 }
 
-func (s *Scheduler) nextPlanNoRunningCheck(ctx context.Context) (string, string) {
-	p := s.getNextPlanAndIncreaseDequeuingWG()
-	if p == "" {
-		return "", ""
+func (s *Scheduler) nextPlanNoRunningCheck(ctx context.Context, verification bool) (string, string, string) {
+	p := s.getNextPlanAndIncreaseDequeuingWG(verification)
+	if p.PlanFile == "" {
+		return "", "", ""
 	}
 
 	// otherwise dequeueWG has been increased
 	defer s.dequeueWG.Done()
 
 	// Before we return plan file, we create progress file.
-	ok, base := blocksconvert.IsPlanFilename(p)
+	ok, base := blocksconvert.IsPlanFilename(p.PlanFile)
 	if !ok {
 		// Should not happen
-		level.Error(s.log).Log("msg", "enqueued file is not a plan file", "path", p)
-		return "", ""
+		level.Error(s.log).Log("msg", "enqueued file is not a plan file", "path", p.PlanFile)
+		return "", "", ""
 	}
 
 	pg := blocksconvert.StartingFilename(base, time.Now())
+	if verification {
+		pg = blocksconvert.VerificationProgressFilename(base, time.Now())
+	}
+
 	err := s.bucket.Upload(ctx, pg, strings.NewReader("starting"))
 	if err != nil {
 		level.Error(s.log).Log("msg", "failed to create progress file", "path", pg, "err", err)
-		return "", ""
+		return "", "", ""
 	}
 
 	level.Info(s.log).Log("msg", "uploaded new progress file", "progressFile", pg)
-	return p, pg
+	return p.PlanFile, pg, p.BlockID
 }
 
-func (s *Scheduler) getNextPlanAndIncreaseDequeuingWG() string {
+func (s *Scheduler) getNextPlanAndIncreaseDequeuingWG(verification bool) queuedPlan {
 	s.scanMu.Lock()
 	defer s.scanMu.Unlock()
 
 	if s.scanning {
-		return ""
+		return queuedPlan{}
+	}
+
+	queue := &s.plansQueue
+	if verification {
+		queue = &s.verificationQueue
 	}
 
-	if len(s.plansQueue) == 0 {
-		return ""
+	if len(*queue) == 0 {
+		return queuedPlan{}
 	}
 
-	var p string
-	p, s.plansQueue = s.plansQueue[0].PlanFile, s.plansQueue[1:]
+	var p queuedPlan
+	p, *queue = (*queue)[0], (*queue)[1:]
 	s.updateQueuedPlansMetrics()
 
 	s.dequeueWG.Add(1)
@@ -400,6 +468,22 @@ func scanForPlans(ctx context.Context, bucket objstore.Bucket, prefix string) (m
 			p := plans[base]
 			p.ErrorFile = fullPath
 			plans[base] = p
+		} else if ok, base, ts := blocksconvert.IsVerificationProgressFilename(filename); ok {
+			p := plans[base]
+			if p.VerificationProgressFiles == nil {
+				p.VerificationProgressFiles = map[string]time.Time{}
+			}
+			p.VerificationProgressFiles[fullPath] = ts
+			plans[base] = p
+		} else if ok, base, result := blocksconvert.IsVerifiedFilename(filename); ok {
+			p := plans[base]
+			p.Verified = append(p.Verified, result)
+			p.VerifiedFiles = append(p.VerifiedFiles, fullPath)
+			plans[base] = p
+		} else if ok, base := blocksconvert.IsVerificationErrorFilename(filename); ok {
+			p := plans[base]
+			p.VerificationErrorFile = fullPath
+			plans[base] = p
 		}
 
 		return nil
@@ -428,6 +512,13 @@ var plansTemplate = template.Must(template.New("plans").Parse(`
 		{{ end }}
 		</ul>
 
+		<h1>Verification Queue</h1>
+		<ul>
+		{{ range $i, $p := .VerificationQueue }}
+			<li>{{ .DayIndex }} - {{ .PlanFile }} - {{ .BlockID }}</li>
+		{{ end }}
+		</ul>
+
 		<h1>Users</h1>
 		{{ range $u, $up := .Plans }}
 			<h2>{{ $u }}</h2>
@@ -437,6 +528,7 @@ var plansTemplate = template.Must(template.New("plans").Parse(`
 					<tr>
 						<th>Plan File</th>
 						<th>Status</th>
+						<th>Verification</th>
 						<th>Comment</th>
 					</tr>
 				</thead>
@@ -446,10 +538,14 @@ var plansTemplate = template.Must(template.New("plans").Parse(`
 						<tr>
 							<td>{{ range .PlanFiles }}{{ . }}<br />{{ end }}</td>
 							<td>{{ .Status }}</td>
+							<td>{{ if .Finished }}{{ .VerificationStatus }}{{ end }}</td>
 							<td>
 								{{ if .ErrorFile }} <strong>Error:</strong> {{ .ErrorFile }} <br />{{ end }}
 								{{ if .ProgressFiles }} <strong>Progress:</strong> {{ range $p, $t := .ProgressFiles }} {{ $p }} {{ end }} <br /> {{ end }}
 								{{ if .Finished }} <strong>Finished:</strong> {{ .Finished }} <br />{{ end }}
+								{{ if .VerificationErrorFile }} <strong>Verification error:</strong> {{ .VerificationErrorFile }} <br />{{ end }}
+								{{ if .VerificationProgressFiles }} <strong>Verification progress:</strong> {{ range $p, $t := .VerificationProgressFiles }} {{ $p }} {{ end }} <br /> {{ end }}
+								{{ if .VerifiedFiles }} <strong>Verification report:</strong> {{ range .VerifiedFiles }} {{ . }} {{ end }} <br />{{ end }}
 							</td>
 						</tr>
 						{{ end }}
@@ -464,16 +560,19 @@ func (s *Scheduler) httpPlans(writer http.ResponseWriter, req *http.Request) {
 	s.scanMu.Lock()
 	plans := s.allUserPlans
 	queue := s.plansQueue
+	verificationQueue := s.verificationQueue
 	s.scanMu.Unlock()
 
 	data := struct {
-		Now   time.Time
-		Plans map[string]map[string]plan
-		Queue []queuedPlan
+		Now               time.Time
+		Plans             map[string]map[string]plan
+		Queue             []queuedPlan
+		VerificationQueue []queuedPlan
 	}{
-		Now:   time.Now(),
-		Plans: plans,
-		Queue: queue,
+		Now:               time.Now(),
+		Plans:             plans,
+		Queue:             queue,
+		VerificationQueue: verificationQueue,
 	}
 
 	util.RenderHTTPResponse(writer, data, plansTemplate, req)
@@ -482,6 +581,7 @@ func (s *Scheduler) httpPlans(writer http.ResponseWriter, req *http.Request) {
 // This function runs with lock.
 func (s *Scheduler) updateQueuedPlansMetrics() {
 	s.queuedPlansGauge.Set(float64(len(s.plansQueue)))
+	s.queuedVerificationPlansGauge.Set(float64(len(s.verificationQueue)))
 
 	if len(s.plansQueue) > 0 {
 		daySeconds := 24 * time.Hour.Seconds()
@@ -107,7 +107,7 @@ func TestSchedulerScan(t *testing.T) {
 	require.Equal(t, "migration/user1/1.error", s.allUserPlans["user1"]["1"].ErrorFile)
 
 	{
-		p, pg := s.nextPlanNoRunningCheck(context.Background())
+		p, pg, _ := s.nextPlanNoRunningCheck(context.Background(), false)
 		require.Equal(t, "migration/user4/4.plan", p)
 		ok, err := bucket.Exists(context.Background(), pg)
 		require.NoError(t, err)
@@ -115,8 +115,79 @@ func TestSchedulerScan(t *testing.T) {
 	}
 
 	{
-		p, pg := s.nextPlanNoRunningCheck(context.Background())
+		p, pg, _ := s.nextPlanNoRunningCheck(context.Background(), false)
 		require.Equal(t, "", p)
 		require.Equal(t, "", pg)
 	}
 }
+
+func TestSchedulerScanForVerification(t *testing.T) {
+	now := time.Now()
+	nowMinus1Hour := now.Add(-time.Hour)
+
+	bucket := objstore.NewInMemBucket()
+
+	// Finished and not verified yet.
+	require.NoError(t, bucket.Upload(context.Background(), "migration/user1/1.plan", strings.NewReader("")))
+	require.NoError(t, bucket.Upload(context.Background(), "migration/user1/1.finished.01E8GCW9J0HV0992HSZ0N6RAMN", strings.NewReader("")))
+
+	// Finished and verification in progress, but the progress file is too old and will be removed.
+	require.NoError(t, bucket.Upload(context.Background(), "migration/user1/2.plan", strings.NewReader("")))
+	require.NoError(t, bucket.Upload(context.Background(), "migration/user1/2.finished.01EE9Y140JP4T58X8FGTG5T17F", strings.NewReader("")))
+	require.NoError(t, bucket.Upload(context.Background(), fmt.Sprintf("migration/user1/2.verifying.%d", nowMinus1Hour.Unix()), strings.NewReader("")))
+
+	// Finished and verification in progress.
+	require.NoError(t, bucket.Upload(context.Background(), "migration/user2/3.plan", strings.NewReader("")))
+	require.NoError(t, bucket.Upload(context.Background(), "migration/user2/3.finished.01EE9Y140JP4T58X8FGTG5T17F", strings.NewReader("")))
+	require.NoError(t, bucket.Upload(context.Background(), fmt.Sprintf("migration/user2/3.verifying.%d", now.Unix()), strings.NewReader("")))
+
+	// Finished and verified.
+	require.NoError(t, bucket.Upload(context.Background(), "migration/user2/4.plan", strings.NewReader("")))
+	require.NoError(t, bucket.Upload(context.Background(), "migration/user2/4.finished.01EE9Y140JP4T58X8FGTG5T17F", strings.NewReader("")))
+	require.NoError(t, bucket.Upload(context.Background(), "migration/user2/4.verified.failed", strings.NewReader("")))
+
+	// Not finished yet.
+	require.NoError(t, bucket.Upload(context.Background(), "migration/user3/5.plan", strings.NewReader("")))
+
+	s := newSchedulerWithBucket(log.NewLogfmtLogger(os.Stdout), bucket, "migration", blocksconvert.AllowAllUsers, nil, Config{
+		ScanInterval:        10 * time.Second,
+		PlanScanConcurrency: 5,
+		MaxProgressFileAge:  5 * time.Minute,
+	}, nil)
+
+	require.NoError(t, s.scanBucketForPlans(context.Background()))
+	require.Equal(t, []queuedPlan{
+		{DayIndex: 5, PlanFile: "migration/user3/5.plan"},
+	}, s.plansQueue)
+	require.Equal(t, []queuedPlan{
+		{DayIndex: 1, PlanFile: "migration/user1/1.plan", BlockID: "01E8GCW9J0HV0992HSZ0N6RAMN"},
+	}, s.verificationQueue)
+
+	require.Equal(t, VerificationError, s.allUserPlans["user1"]["2"].VerificationStatus())
+	require.Equal(t, "migration/user1/2.verification-error", s.allUserPlans["user1"]["2"].VerificationErrorFile)
+	require.Equal(t, Verifying, s.allUserPlans["user2"]["3"].VerificationStatus())
+	require.Equal(t, VerificationFailed, s.allUserPlans["user2"]["4"].VerificationStatus())
+
+	{
+		p, pg, blockID := s.nextPlanNoRunningCheck(context.Background(), true)
+		require.Equal(t, "migration/user1/1.plan", p)
+		require.Equal(t, "01E8GCW9J0HV0992HSZ0N6RAMN", blockID)
+
+		ok, _, _ := blocksconvert.IsVerificationProgressFilename(pg)
+		require.True(t, ok)
+
+		ok, err := bucket.Exists(context.Background(), pg)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	{
+		p, pg, blockID := s.nextPlanNoRunningCheck(context.Background(), true)
+		require.Equal(t, "", p)
+		require.Equal(t, "", pg)
+		require.Equal(t, "", blockID)
+	}
+
+	// The build queue is not affected by verification.
+	require.Equal(t, 1, len(s.plansQueue))
+}
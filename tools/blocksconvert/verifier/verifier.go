@@ -0,0 +1,585 @@
+package verifier
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"flag"
+	"hash"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash"
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/oklog/ulid"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/prometheus/prometheus/tsdb/index"
+	"github.com/thanos-io/thanos/pkg/block"
+	"github.com/thanos-io/thanos/pkg/objstore"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/chunk/storage"
+	"github.com/cortexproject/cortex/pkg/querier/iterators"
+	"github.com/cortexproject/cortex/pkg/storage/bucket"
+	"github.com/cortexproject/cortex/pkg/util"
+	"github.com/cortexproject/cortex/pkg/util/services"
+	"github.com/cortexproject/cortex/tools/blocksconvert"
+	"github.com/cortexproject/cortex/tools/blocksconvert/planprocessor"
+)
+
+// Result of verification which found some differences between chunks and the block.
+const verificationResultFailed = "failed"
+
+type Config struct {
+	OutputDirectory       string
+	Concurrency           int
+	MaxReportedMismatches int
+
+	PlanProcessorConfig planprocessor.Config
+}
+
+func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
+	cfg.PlanProcessorConfig.RegisterFlags("verifier", f)
+
+	f.StringVar(&cfg.OutputDirectory, "verifier.output-dir", "", "Local directory used for storing temporary plan files and downloaded blocks (will be created, if missing).")
+	f.IntVar(&cfg.Concurrency, "verifier.concurrency", 128, "Number of concurrent series processors.")
+	f.IntVar(&cfg.MaxReportedMismatches, "verifier.max-reported-mismatches", 100, "Maximum number of mismatched series included in the verification report of a single plan. Mismatches are always counted.")
+}
+
+func NewVerifier(cfg Config, scfg blocksconvert.SharedConfig, l log.Logger, reg prometheus.Registerer) (services.Service, error) {
+	err := scfg.SchemaConfig.Load()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load schema")
+	}
+
+	bucketClient, err := scfg.GetBucket(l, reg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.OutputDirectory == "" {
+		return nil, errors.New("no output directory")
+	}
+	if err := os.MkdirAll(cfg.OutputDirectory, os.FileMode(0700)); err != nil {
+		return nil, errors.Wrap(err, "failed to create output directory")
+	}
+
+	v := &Verifier{
+		cfg: cfg,
+
+		bucketClient:  bucketClient,
+		schemaConfig:  scfg.SchemaConfig,
+		storageConfig: scfg.StorageConfig,
+
+		fetchedChunks: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_blocksconvert_verifier_fetched_chunks_total",
+			Help: "Fetched chunks",
+		}),
+		chunksNotFound: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_blocksconvert_verifier_chunks_not_found_total",
+			Help: "Number of chunks that were not found on the storage.",
+		}),
+		verifiedSeries: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "cortex_blocksconvert_verifier_series_total",
+			Help: "Verified series",
+		}),
+		mismatchedSeries: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_blocksconvert_verifier_mismatched_series_total",
+			Help: "Number of series that don't match between chunks and blocks, by reason.",
+		}, []string{"reason"}),
+		verifiedPlans: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Name: "cortex_blocksconvert_verifier_plans_total",
+			Help: "Number of verified plans, by result.",
+		}, []string{"result"}),
+		verificationInProgress: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Name: "cortex_blocksconvert_verifier_in_progress",
+			Help: "Verification in progress",
+		}),
+	}
+
+	return planprocessor.NewVerificationService(cfg.PlanProcessorConfig, filepath.Join(cfg.OutputDirectory, "plans"), bucketClient, v.cleanupFn, v.planProcessorFactory, l, reg)
+}
+
+// Verifier compares series and samples stored in the chunks storage with the block built
+// by the builder. Result of the comparison is uploaded as the plan's verification status file.
+type Verifier struct {
+	cfg Config
+
+	bucketClient  objstore.Bucket
+	schemaConfig  chunk.SchemaConfig
+	storageConfig storage.Config
+
+	fetchedChunks          prometheus.Counter
+	chunksNotFound         prometheus.Counter
+	verifiedSeries         prometheus.Counter
+	mismatchedSeries       *prometheus.CounterVec
+	verifiedPlans          *prometheus.CounterVec
+	verificationInProgress prometheus.Gauge
+}
+
+func (v *Verifier) cleanupFn(log log.Logger) error {
+	// Delete all downloaded blocks, they are only useful while verifying a plan.
+	dir := filepath.Join(v.cfg.OutputDirectory, "blocks")
+
+	level.Info(log).Log("msg", "deleting downloaded blocks", "dir", dir)
+	return os.RemoveAll(dir)
+}
+
+func (v *Verifier) planProcessorFactory(planLog log.Logger, userID string, start time.Time, end time.Time, blockID string) planprocessor.PlanProcessor {
+	return &verifierProcessor{
+		verifier: v,
+		log:      planLog,
+		userID:   userID,
+		dayStart: start,
+		dayEnd:   end,
+		blockID:  blockID,
+	}
+}
+
+type verifierProcessor struct {
+	verifier *Verifier
+
+	log      log.Logger
+	userID   string
+	dayStart time.Time
+	dayEnd   time.Time
+	blockID  string
+
+	report *Report
+}
+
+// Report is the result of verification of a single plan.
+type Report struct {
+	UserID   string    `json:"user_id"`
+	DayStart time.Time `json:"day_start"`
+	DayEnd   time.Time `json:"day_end"`
+	BlockID  string    `json:"block_id"`
+
+	ChunksNotFound int `json:"chunks_not_found"`
+
+	ChunksSeries  int    `json:"chunks_series"`
+	ChunksSamples uint64 `json:"chunks_samples"`
+	BlockSeries   int    `json:"block_series"`
+	BlockSamples  uint64 `json:"block_samples"`
+
+	// Series found in chunks, but missing in the block.
+	MissingSeries int `json:"missing_series"`
+	// Series found in the block, but not in chunks.
+	ExtraSeries int `json:"extra_series"`
+	// Series with different number of samples in chunks and in the block.
+	SamplesMismatches int `json:"samples_mismatches"`
+	// Series with the same number of samples, but different sample values.
+	ChecksumMismatches int `json:"checksum_mismatches"`
+
+	// Examples of mismatched series, limited by -verifier.max-reported-mismatches.
+	Mismatches          []SeriesMismatch `json:"mismatches,omitempty"`
+	MismatchesTruncated bool             `json:"mismatches_truncated,omitempty"`
+}
+
+// SeriesMismatch describes single series which doesn't match between chunks and the block.
+type SeriesMismatch struct {
+	Reason string `json:"reason"`
+
+	// Series ID from the plan file. Empty for series found only in the block.
+	SeriesID string `json:"series_id,omitempty"`
+	// Series labels. Empty for series missing in the block.
+	Labels string `json:"labels,omitempty"`
+
+	ChunksSamples uint64 `json:"chunks_samples"`
+	BlockSamples  uint64 `json:"block_samples"`
+}
+
+const (
+	reasonMissingSeries    = "missing_series"
+	reasonExtraSeries      = "extra_series"
+	reasonSamplesMismatch  = "samples_mismatch"
+	reasonChecksumMismatch = "checksum_mismatch"
+)
+
+func (r *Report) Ok() bool {
+	return r.MissingSeries == 0 && r.ExtraSeries == 0 && r.SamplesMismatches == 0 && r.ChecksumMismatches == 0
+}
+
+func (r *Report) addMismatch(m SeriesMismatch, maxReported int) {
+	switch m.Reason {
+	case reasonMissingSeries:
+		r.MissingSeries++
+	case reasonExtraSeries:
+		r.ExtraSeries++
+	case reasonSamplesMismatch:
+		r.SamplesMismatches++
+	case reasonChecksumMismatch:
+		r.ChecksumMismatches++
+	}
+
+	if len(r.Mismatches) >= maxReported {
+		r.MismatchesTruncated = true
+		return
+	}
+	r.Mismatches = append(r.Mismatches, m)
+}
+
+// Summary of samples of single series. Timestamps are not part of the checksum, because
+// builder may adjust them (see -builder.timestamp-tolerance).
+type seriesSummary struct {
+	seriesID string
+	samples  uint64
+	checksum uint64
+}
+
+func (p *verifierProcessor) ProcessPlanEntries(ctx context.Context, planEntryCh chan blocksconvert.PlanEntry) (string, error) {
+	p.verifier.verificationInProgress.Set(1)
+	defer p.verifier.verificationInProgress.Set(0)
+
+	blockID, err := ulid.Parse(p.blockID)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid block ID %q", p.blockID)
+	}
+
+	chunkClient, err := p.verifier.createChunkClientForDay(p.dayStart)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create chunk client")
+	}
+	defer chunkClient.Stop()
+
+	startTime := model.TimeFromUnixNano(p.dayStart.UnixNano())
+	endTime := model.TimeFromUnixNano(p.dayEnd.UnixNano())
+
+	var (
+		mu             sync.Mutex
+		expected       = map[uint64]seriesSummary{}
+		chunksNotFound = 0
+	)
+
+	g, gctx := errgroup.WithContext(ctx)
+	for i := 0; i < p.verifier.cfg.Concurrency; i++ {
+		g.Go(func() error {
+			for {
+				select {
+				case <-gctx.Done():
+					return nil
+
+				case e, ok := <-planEntryCh:
+					if !ok {
+						// End of input.
+						return nil
+					}
+
+					cs, err := p.fetchChunks(gctx, chunkClient, e)
+					if err != nil {
+						return err
+					}
+
+					notFound := len(e.Chunks) - len(cs)
+					if notFound > 0 {
+						p.verifier.chunksNotFound.Add(float64(notFound))
+						level.Warn(p.log).Log("msg", "chunks for series not found", "seriesID", e.SeriesID, "expected", len(e.Chunks), "got", len(cs))
+					}
+
+					if len(cs) == 0 {
+						mu.Lock()
+						chunksNotFound += notFound
+						mu.Unlock()
+						continue
+					}
+
+					lbls, summary := summarizeChunks(cs, startTime, endTime)
+					summary.seriesID = e.SeriesID
+
+					mu.Lock()
+					chunksNotFound += notFound
+					// Builder doesn't write series without samples into the block.
+					if summary.samples > 0 {
+						expected[lbls.Hash()] = summary
+					}
+					mu.Unlock()
+				}
+			}
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return "", errors.Wrap(err, "failed to read chunks")
+	}
+
+	blockDir := filepath.Join(p.verifier.cfg.OutputDirectory, "blocks", blockID.String())
+	defer func() {
+		if err := os.RemoveAll(blockDir); err != nil {
+			level.Warn(p.log).Log("msg", "failed to delete local block", "err", err)
+		}
+	}()
+
+	// No per-tenant config provider because the blocksconvert tool doesn't support it.
+	userBucket := bucket.NewUserBucketClient(p.userID, p.verifier.bucketClient, nil)
+	if err := block.Download(ctx, p.log, userBucket, blockID, blockDir); err != nil {
+		return "", errors.Wrap(err, "failed to download block")
+	}
+
+	report, err := compareWithBlock(blockDir, expected, p.verifier.cfg.MaxReportedMismatches)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to compare block")
+	}
+
+	report.UserID = p.userID
+	report.DayStart = p.dayStart
+	report.DayEnd = p.dayEnd
+	report.BlockID = p.blockID
+	report.ChunksNotFound = chunksNotFound
+	p.report = report
+
+	p.verifier.verifiedSeries.Add(float64(report.ChunksSeries))
+	p.verifier.mismatchedSeries.WithLabelValues(reasonMissingSeries).Add(float64(report.MissingSeries))
+	p.verifier.mismatchedSeries.WithLabelValues(reasonExtraSeries).Add(float64(report.ExtraSeries))
+	p.verifier.mismatchedSeries.WithLabelValues(reasonSamplesMismatch).Add(float64(report.SamplesMismatches))
+	p.verifier.mismatchedSeries.WithLabelValues(reasonChecksumMismatch).Add(float64(report.ChecksumMismatches))
+
+	result := blocksconvert.VerificationResultOK
+	if !report.Ok() {
+		result = verificationResultFailed
+	}
+	p.verifier.verifiedPlans.WithLabelValues(result).Inc()
+
+	level.Info(p.log).Log("msg", "verified block", "ulid", p.blockID, "result", result,
+		"chunksSeries", report.ChunksSeries, "blockSeries", report.BlockSeries,
+		"missingSeries", report.MissingSeries, "extraSeries", report.ExtraSeries,
+		"samplesMismatches", report.SamplesMismatches, "checksumMismatches", report.ChecksumMismatches)
+
+	return result, nil
+}
+
+// Report implements planprocessor.ReportingPlanProcessor.
+func (p *verifierProcessor) Report() ([]byte, error) {
+	return json.Marshal(p.report)
+}
+
+func (p *verifierProcessor) fetchChunks(ctx context.Context, chunkClient chunk.Client, e blocksconvert.PlanEntry) ([]chunk.Chunk, error) {
+	b := util.NewBackoff(ctx, util.BackoffConfig{
+		MinBackoff: 1 * time.Second,
+		MaxBackoff: 5 * time.Second,
+		MaxRetries: 5,
+	})
+
+	var (
+		cs  []chunk.Chunk
+		err error
+	)
+
+	// Retry on temporary errors, same as builder does.
+	for b.Ongoing() {
+		cs, err = fetchChunks(ctx, p.userID, chunkClient, e.Chunks)
+		if err == nil {
+			p.verifier.fetchedChunks.Add(float64(len(cs)))
+			return cs, nil
+		}
+
+		level.Warn(p.log).Log("msg", "failed to fetch chunks for series", "series", e.SeriesID, "err", err, "retries", b.NumRetries()+1)
+		b.Wait()
+	}
+
+	return nil, errors.Wrapf(b.Err(), "failed to fetch chunks for series %s", e.SeriesID)
+}
+
+func fetchChunks(ctx context.Context, userID string, chunkClient chunk.Client, chunkIDs []string) ([]chunk.Chunk, error) {
+	chunks := make([]chunk.Chunk, 0, len(chunkIDs))
+	for _, cid := range chunkIDs {
+		c, err := chunk.ParseExternalKey(userID, cid)
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, c)
+	}
+
+	cs, err := chunkClient.GetChunks(ctx, chunks)
+	if err != nil && !errors.Is(err, chunk.ErrStorageObjectNotFound) {
+		return nil, errors.Wrap(err, "fetching chunks")
+	}
+	return cs, nil
+}
+
+// Computes labels and summary of samples from chunks in the same way as builder does when building the block:
+// samples are merged and deduplicated, and only samples in [start, end) are used.
+func summarizeChunks(cs []chunk.Chunk, start, end model.Time) (labels.Labels, seriesSummary) {
+	lbls := normalizeLabels(cs[0].Metric)
+
+	s := seriesSummary{}
+	d := xxhash.New()
+
+	it := iterators.NewChunkMergeIterator(cs, start, end)
+	for it.Next() && it.Err() == nil {
+		t, v := it.At()
+
+		mt := model.Time(t)
+		if mt < start {
+			continue
+		}
+		if mt >= end {
+			break
+		}
+
+		s.samples++
+		writeValue(d, v)
+	}
+
+	s.checksum = d.Sum64()
+	return lbls, s
+}
+
+// Returns sorted labels without duplicates, to match labels used by builder.
+func normalizeLabels(lbls labels.Labels) labels.Labels {
+	out := make(labels.Labels, 0, len(lbls))
+	out = append(out, lbls...)
+	sort.Sort(out)
+
+	for ix := 1; ix < len(out); {
+		if out[ix] == out[ix-1] {
+			out = append(out[:ix], out[ix+1:]...)
+			continue
+		}
+		ix++
+	}
+	return out
+}
+
+func writeValue(d hash.Hash64, v float64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+	_, _ = d.Write(buf[:])
+}
+
+// Compares all series in the block with expected series, and returns the report.
+func compareWithBlock(blockDir string, expected map[uint64]seriesSummary, maxReported int) (*Report, error) {
+	b, err := tsdb.OpenBlock(nil, blockDir, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "open block")
+	}
+	defer b.Close()
+
+	ir, err := b.Index()
+	if err != nil {
+		return nil, errors.Wrap(err, "open index")
+	}
+	defer ir.Close()
+
+	cr, err := b.Chunks()
+	if err != nil {
+		return nil, errors.Wrap(err, "open chunks")
+	}
+	defer cr.Close()
+
+	r := &Report{ChunksSeries: len(expected)}
+	for _, s := range expected {
+		r.ChunksSamples += s.samples
+	}
+
+	p, err := ir.Postings(index.AllPostingsKey())
+	if err != nil {
+		return nil, errors.Wrap(err, "postings")
+	}
+
+	seen := make(map[uint64]struct{}, len(expected))
+
+	var (
+		lbls labels.Labels
+		chks []chunks.Meta
+	)
+	for p.Next() {
+		if err := ir.Series(p.At(), &lbls, &chks); err != nil {
+			return nil, errors.Wrapf(err, "read series %d", p.At())
+		}
+
+		actual := seriesSummary{}
+		d := xxhash.New()
+		for _, meta := range chks {
+			c, err := cr.Chunk(meta.Ref)
+			if err != nil {
+				return nil, errors.Wrapf(err, "read chunk for series %s", lbls.String())
+			}
+
+			it := c.Iterator(nil)
+			for it.Next() {
+				_, v := it.At()
+				actual.samples++
+				writeValue(d, v)
+			}
+			if it.Err() != nil {
+				return nil, errors.Wrapf(it.Err(), "iterate chunk for series %s", lbls.String())
+			}
+		}
+		actual.checksum = d.Sum64()
+
+		r.BlockSeries++
+		r.BlockSamples += actual.samples
+
+		h := lbls.Hash()
+		exp, ok := expected[h]
+		if !ok {
+			r.addMismatch(SeriesMismatch{Reason: reasonExtraSeries, Labels: lbls.String(), BlockSamples: actual.samples}, maxReported)
+			continue
+		}
+		seen[h] = struct{}{}
+
+		switch {
+		case exp.samples != actual.samples:
+			r.addMismatch(SeriesMismatch{Reason: reasonSamplesMismatch, SeriesID: exp.seriesID, Labels: lbls.String(), ChunksSamples: exp.samples, BlockSamples: actual.samples}, maxReported)
+		case exp.checksum != actual.checksum:
+			r.addMismatch(SeriesMismatch{Reason: reasonChecksumMismatch, SeriesID: exp.seriesID, Labels: lbls.String(), ChunksSamples: exp.samples, BlockSamples: actual.samples}, maxReported)
+		}
+	}
+	if p.Err() != nil {
+		return nil, errors.Wrap(p.Err(), "iterate postings")
+	}
+
+	// Sort missing series to get deterministic report.
+	var missing []seriesSummary
+	for h, s := range expected {
+		if _, ok := seen[h]; !ok {
+			missing = append(missing, s)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		return missing[i].seriesID < missing[j].seriesID
+	})
+	for _, s := range missing {
+		r.addMismatch(SeriesMismatch{Reason: reasonMissingSeries, SeriesID: s.seriesID, ChunksSamples: s.samples}, maxReported)
+	}
+
+	return r, nil
+}
+
+// Finds storage configuration for given day, and builds a client.
+func (v *Verifier) createChunkClientForDay(dayStart time.Time) (chunk.Client, error) {
+	for ix, s := range v.schemaConfig.Configs {
+		if dayStart.Unix() < s.From.Unix() {
+			continue
+		}
+
+		if ix+1 < len(v.schemaConfig.Configs) && dayStart.Unix() > v.schemaConfig.Configs[ix+1].From.Unix() {
+			continue
+		}
+
+		objectStoreType := s.ObjectType
+		if objectStoreType == "" {
+			objectStoreType = s.IndexType
+		}
+		// No registerer, to avoid problems with registering same metrics multiple times.
+		chunks, err := storage.NewChunkClient(objectStoreType, v.storageConfig, v.schemaConfig, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating object client")
+		}
+		return chunks, nil
+	}
+
+	return nil, errors.Errorf("no schema for day %v", dayStart.Format("2006-01-02"))
+}
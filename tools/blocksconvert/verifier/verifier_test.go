@@ -0,0 +1,148 @@
+package verifier
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/tsdbutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cortexproject/cortex/pkg/chunk"
+	"github.com/cortexproject/cortex/pkg/chunk/encoding"
+	"github.com/cortexproject/cortex/pkg/util"
+	util_log "github.com/cortexproject/cortex/pkg/util/log"
+)
+
+type sample struct {
+	t int64
+	v float64
+}
+
+func (s sample) T() int64   { return s.t }
+func (s sample) V() float64 { return s.v }
+
+func TestCompareWithBlock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "verifier")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	dayStart := time.Now().Add(-24 * time.Hour).Truncate(24 * time.Hour)
+	dayEnd := dayStart.Add(24 * time.Hour)
+	start := util.TimeToMillis(dayStart)
+
+	generateSamples := func(count int, value float64) []tsdbutil.Sample {
+		var out []tsdbutil.Sample
+		for i := 0; i < count; i++ {
+			out = append(out, sample{t: start + int64(i)*15000, v: value + float64(i)})
+		}
+		return out
+	}
+
+	ok := labels.FromStrings(labels.MetricName, "ok")
+	differentValues := labels.FromStrings(labels.MetricName, "different_values")
+	differentCount := labels.FromStrings(labels.MetricName, "different_count")
+	missing := labels.FromStrings(labels.MetricName, "missing")
+	extra := labels.FromStrings(labels.MetricName, "extra")
+
+	// Series stored in chunks.
+	expected := map[uint64]seriesSummary{}
+	for _, s := range []struct {
+		lbls    labels.Labels
+		samples []tsdbutil.Sample
+	}{
+		{lbls: ok, samples: generateSamples(200, 1)},
+		{lbls: differentValues, samples: generateSamples(200, 1)},
+		{lbls: differentCount, samples: generateSamples(200, 1)},
+		{lbls: missing, samples: generateSamples(200, 1)},
+	} {
+		cs := buildChunks(t, s.lbls, s.samples, dayStart, dayEnd)
+
+		lbls, summary := summarizeChunks(cs, model.TimeFromUnixNano(dayStart.UnixNano()), model.TimeFromUnixNano(dayEnd.UnixNano()))
+		require.Equal(t, s.lbls, lbls)
+		require.Equal(t, uint64(len(s.samples)), summary.samples)
+
+		summary.seriesID = s.lbls.Get(labels.MetricName)
+		expected[lbls.Hash()] = summary
+	}
+
+	// Series stored in the block.
+	blockDir, err := tsdb.CreateBlock([]storage.Series{
+		storage.NewListSeries(ok, generateSamples(200, 1)),
+		storage.NewListSeries(differentValues, generateSamples(200, 2)),
+		storage.NewListSeries(differentCount, generateSamples(150, 1)),
+		storage.NewListSeries(extra, generateSamples(200, 1)),
+	}, dir, 0, util_log.Logger)
+	require.NoError(t, err)
+
+	report, err := compareWithBlock(blockDir, expected, 10)
+	require.NoError(t, err)
+
+	assert.False(t, report.Ok())
+	assert.Equal(t, 4, report.ChunksSeries)
+	assert.Equal(t, uint64(800), report.ChunksSamples)
+	assert.Equal(t, 4, report.BlockSeries)
+	assert.Equal(t, uint64(750), report.BlockSamples)
+	assert.Equal(t, 1, report.MissingSeries)
+	assert.Equal(t, 1, report.ExtraSeries)
+	assert.Equal(t, 1, report.SamplesMismatches)
+	assert.Equal(t, 1, report.ChecksumMismatches)
+	assert.False(t, report.MismatchesTruncated)
+
+	mismatches := map[string]SeriesMismatch{}
+	for _, m := range report.Mismatches {
+		mismatches[m.Reason] = m
+	}
+	assert.Equal(t, SeriesMismatch{Reason: reasonMissingSeries, SeriesID: "missing", ChunksSamples: 200}, mismatches[reasonMissingSeries])
+	assert.Equal(t, SeriesMismatch{Reason: reasonExtraSeries, Labels: extra.String(), BlockSamples: 200}, mismatches[reasonExtraSeries])
+	assert.Equal(t, SeriesMismatch{Reason: reasonSamplesMismatch, SeriesID: "different_count", Labels: differentCount.String(), ChunksSamples: 200, BlockSamples: 150}, mismatches[reasonSamplesMismatch])
+	assert.Equal(t, SeriesMismatch{Reason: reasonChecksumMismatch, SeriesID: "different_values", Labels: differentValues.String(), ChunksSamples: 200, BlockSamples: 200}, mismatches[reasonChecksumMismatch])
+
+	// Mismatches are still counted, even if they are not reported.
+	report, err = compareWithBlock(blockDir, expected, 1)
+	require.NoError(t, err)
+	assert.Len(t, report.Mismatches, 1)
+	assert.True(t, report.MismatchesTruncated)
+	assert.Equal(t, 1, report.MissingSeries)
+	assert.Equal(t, 1, report.ExtraSeries)
+}
+
+func TestNormalizeLabels(t *testing.T) {
+	input := labels.Labels{
+		{Name: "label1", Value: "world"},
+		{Name: labels.MetricName, Value: "hello"},
+		{Name: "label1", Value: "world"},
+	}
+
+	assert.Equal(t, labels.FromStrings(labels.MetricName, "hello", "label1", "world"), normalizeLabels(input))
+}
+
+func buildChunks(t *testing.T, metric labels.Labels, samples []tsdbutil.Sample, start, end time.Time) []chunk.Chunk {
+	var ecs []encoding.Chunk
+
+	pc := encoding.New()
+	for _, s := range samples {
+		overflow, err := pc.Add(model.SamplePair{Timestamp: model.Time(s.T()), Value: model.SampleValue(s.V())})
+		require.NoError(t, err)
+
+		if overflow != nil {
+			ecs = append(ecs, pc)
+			pc = overflow
+		}
+	}
+	ecs = append(ecs, pc)
+
+	var cs []chunk.Chunk
+	for _, ec := range ecs {
+		cs = append(cs, chunk.NewChunk("test", 0, metric, ec, model.TimeFromUnixNano(start.UnixNano()), model.TimeFromUnixNano(end.UnixNano())))
+	}
+	return cs
+}
@@ -361,6 +361,15 @@ func getCustomFieldEntry(field reflect.StructField, fieldValue reflect.Value, fl
 		if err != nil {
 			return nil, err
 		}
+		if fieldFlag == nil {
+			return &configEntry{
+				kind:      "field",
+				name:      getFieldName(field),
+				required:  isFieldRequired(field),
+				fieldDesc: getFieldDescription(field, ""),
+				fieldType: "string",
+			}, nil
+		}
 
 		return &configEntry{
 			kind:         "field",
@@ -0,0 +1,278 @@
+package kvmigrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/go-kit/kit/log/level"
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/cortexproject/cortex/pkg/ring/kv"
+	"github.com/cortexproject/cortex/pkg/ring/kv/codec"
+	"github.com/cortexproject/cortex/pkg/ring/kv/memberlist"
+	"github.com/cortexproject/cortex/pkg/util/services"
+)
+
+// Status of a key, comparing the source and the destination stores.
+const (
+	StatusMissing   = "missing"   // The key doesn't exist in the destination.
+	StatusDifferent = "different" // The key exists in the destination with a different value.
+	StatusEqual     = "equal"     // The key exists in the destination with the same value.
+	StatusNotFound  = "not found" // The key doesn't exist in the source, so it's not migrated.
+)
+
+// KeySet is a set of keys stored with the same codec.
+type KeySet struct {
+	Codec codec.Codec
+
+	// Keys migrated if they exist in the source.
+	Keys []string
+
+	// If not empty, all the keys listed under this prefix in the source are migrated too.
+	Prefix string
+}
+
+// ClientFactory returns a client for a store, using the given codec.
+type ClientFactory func(codec.Codec) (kv.Client, error)
+
+// Key is a key to migrate, along with its value in the source store.
+type Key struct {
+	Key    string
+	Codec  codec.Codec
+	Value  interface{}
+	Status string
+}
+
+type clients struct {
+	source      kv.Client
+	destination kv.Client
+}
+
+// Migrator copies keys from a source KV store to a destination KV store.
+type Migrator struct {
+	sets    []KeySet
+	clients map[string]clients // by codec ID
+	logger  log.Logger
+}
+
+// New makes a new Migrator for the given key sets.
+func New(sets []KeySet, newSource, newDestination ClientFactory, logger log.Logger) (*Migrator, error) {
+	m := &Migrator{
+		sets:    sets,
+		clients: map[string]clients{},
+		logger:  logger,
+	}
+
+	for _, set := range sets {
+		id := set.Codec.CodecID()
+		if _, ok := m.clients[id]; ok {
+			continue
+		}
+
+		source, err := newSource(set.Codec)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create source KV client")
+		}
+		destination, err := newDestination(set.Codec)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create destination KV client")
+		}
+		m.clients[id] = clients{source: source, destination: destination}
+	}
+
+	return m, nil
+}
+
+// Plan reads all the keys from the source store, and compares them with the destination store.
+func (m *Migrator) Plan(ctx context.Context) ([]Key, error) {
+	var keys []Key
+
+	for _, set := range m.sets {
+		c := m.clients[set.Codec.CodecID()]
+
+		names := append([]string(nil), set.Keys...)
+		if set.Prefix != "" {
+			listed, err := c.source.List(ctx, set.Prefix)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to list keys with prefix %s in the source", set.Prefix)
+			}
+			names = append(names, listed...)
+		}
+		sort.Strings(names)
+
+		for i, name := range names {
+			if i > 0 && names[i-1] == name {
+				continue
+			}
+
+			value, err := c.source.Get(ctx, name)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read key %s from the source", name)
+			}
+
+			key := Key{Key: name, Codec: set.Codec, Value: value, Status: StatusNotFound}
+			if value != nil {
+				current, err := c.destination.Get(ctx, name)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to read key %s from the destination", name)
+				}
+				key.Status = compare(value, current)
+			}
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+// Migrate writes to the destination store the keys which are missing. The keys which already exist in the
+// destination (eg. written by the components already running on it) are skipped, unless force is true, in
+// which case they're overwritten. It returns the keys written to the destination.
+func (m *Migrator) Migrate(ctx context.Context, keys []Key, force bool) ([]Key, error) {
+	var migrated []Key
+
+	for _, key := range keys {
+		if key.Status != StatusMissing && (key.Status != StatusDifferent || !force) {
+			if key.Status == StatusDifferent {
+				level.Warn(m.logger).Log("msg", "skipped key already existing in the destination", "key", key.Key, "codec", key.Codec.CodecID())
+			}
+			continue
+		}
+
+		written := false
+		c := m.clients[key.Codec.CodecID()]
+		err := c.destination.CAS(ctx, key.Key, func(in interface{}) (interface{}, bool, error) {
+			// The key may have been written to the destination since it was planned.
+			if in != nil && !force {
+				written = false
+				return nil, false, nil
+			}
+			written = true
+			return key.Value, true, nil
+		})
+		if err != nil {
+			return migrated, errors.Wrapf(err, "failed to write key %s to the destination", key.Key)
+		}
+		if !written {
+			level.Warn(m.logger).Log("msg", "skipped key already existing in the destination", "key", key.Key, "codec", key.Codec.CodecID())
+			continue
+		}
+
+		level.Info(m.logger).Log("msg", "migrated key", "key", key.Key, "codec", key.Codec.CodecID())
+		migrated = append(migrated, key)
+	}
+	return migrated, nil
+}
+
+// Verify waits until the destination store holds the same value of each migrated key, or the
+// timeout expires. It returns the keys whose value in the destination differs.
+func (m *Migrator) Verify(ctx context.Context, keys []Key, timeout time.Duration) ([]Key, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		var pending []Key
+		for _, key := range keys {
+			if key.Status == StatusNotFound {
+				continue
+			}
+
+			current, err := m.clients[key.Codec.CodecID()].destination.Get(ctx, key.Key)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read key %s from the destination", key.Key)
+			}
+			if status := compare(key.Value, current); status != StatusEqual {
+				key.Status = status
+				pending = append(pending, key)
+			}
+		}
+
+		if len(pending) == 0 || time.Now().After(deadline) {
+			return pending, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return pending, ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// WritePlan prints the keys and their status as a table.
+func WritePlan(w io.Writer, keys []Key) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tCODEC\tSTATUS")
+	for _, key := range keys {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", key.Key, key.Codec.CodecID(), key.Status)
+	}
+	return tw.Flush()
+}
+
+func compare(source, destination interface{}) string {
+	if destination == nil {
+		return StatusMissing
+	}
+
+	// Stores backed by memberlist hide the tombstones (eg. LEFT ring instances), and turn the
+	// entries missing in the written value into tombstones, so they're ignored on both sides.
+	source, destination = withoutTombstones(source), withoutTombstones(destination)
+
+	equal := false
+	if s, ok := source.(proto.Message); ok {
+		if d, ok := destination.(proto.Message); ok {
+			equal = proto.Equal(s, d)
+		}
+	} else {
+		equal = reflect.DeepEqual(source, destination)
+	}
+
+	if equal {
+		return StatusEqual
+	}
+	return StatusDifferent
+}
+
+// withoutTombstones returns a copy of the value without tombstones, if it supports them.
+func withoutTombstones(value interface{}) interface{} {
+	m, ok := value.(memberlist.Mergeable)
+	if !ok {
+		return value
+	}
+
+	m = m.Clone()
+	_, _ = m.RemoveTombstones(time.Time{})
+	return m
+}
+
+// NewMemberlistKV returns a memberlist KV joining the cluster through the configured members,
+// and waits until the state has been pulled from them. The returned function must be called
+// once done, in order to propagate the changes and leave the cluster.
+func NewMemberlistKV(cfg memberlist.KVConfig, codecs []codec.Codec, logger log.Logger) (*memberlist.KV, func(), error) {
+	// The cluster is joined synchronously below, once memberlist is running.
+	joinMembers := cfg.JoinMembers
+	cfg.JoinMembers = nil
+	cfg.Codecs = codecs
+
+	memberlistKV := memberlist.NewKV(cfg, logger)
+	if err := services.StartAndAwaitRunning(context.Background(), memberlistKV); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to start memberlist")
+	}
+	stop := func() {
+		_ = services.StopAndAwaitTerminated(context.Background(), memberlistKV)
+	}
+
+	// Joining a member pulls its full state.
+	if _, err := memberlistKV.JoinMembers(joinMembers); err != nil {
+		stop()
+		return nil, nil, errors.Wrap(err, "failed to join memberlist cluster")
+	}
+
+	return memberlistKV, stop, nil
+}
@@ -0,0 +1,210 @@
+package kvmigrate
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cortexproject/cortex/pkg/distributor"
+	"github.com/cortexproject/cortex/pkg/ring"
+	"github.com/cortexproject/cortex/pkg/ring/kv"
+	"github.com/cortexproject/cortex/pkg/ring/kv/codec"
+	"github.com/cortexproject/cortex/pkg/ring/kv/consul"
+	"github.com/cortexproject/cortex/pkg/ring/kv/memberlist"
+	"github.com/cortexproject/cortex/pkg/util/flagext"
+)
+
+// inMemoryStore returns a client factory which returns the same in-memory client for each codec.
+func inMemoryStore() (ClientFactory, map[string]kv.Client) {
+	clients := map[string]kv.Client{}
+	return func(c codec.Codec) (kv.Client, error) {
+		if _, ok := clients[c.CodecID()]; !ok {
+			clients[c.CodecID()] = consul.NewInMemoryClient(c)
+		}
+		return clients[c.CodecID()], nil
+	}, clients
+}
+
+func put(t *testing.T, client kv.Client, key string, value interface{}) {
+	require.NoError(t, client.CAS(context.Background(), key, func(interface{}) (interface{}, bool, error) {
+		return value, true, nil
+	}))
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+	ringCodec, replicaCodec := ring.GetCodec(), distributor.GetReplicaDescCodec()
+
+	newSource, source := inMemoryStore()
+	newDestination, destination := inMemoryStore()
+
+	sets := []KeySet{
+		{Codec: ringCodec, Keys: []string{"collectors/ring", "collectors/compactor", "rulers/ring"}},
+		{Codec: replicaCodec, Prefix: "ha-tracker/"},
+	}
+	m, err := New(sets, newSource, newDestination, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ingesters := ring.NewDesc()
+	ingesters.AddIngester("ingester-1", "127.0.0.1", "", []uint32{1}, ring.ACTIVE, time.Now())
+	compactors := ring.NewDesc()
+	compactors.AddIngester("compactor-1", "127.0.0.2", "", []uint32{2}, ring.ACTIVE, time.Now())
+	staleCompactors := ring.NewDesc()
+	staleCompactors.AddIngester("compactor-0", "127.0.0.3", "", []uint32{3}, ring.ACTIVE, time.Now())
+
+	put(t, source[ringCodec.CodecID()], "collectors/ring", ingesters)
+	put(t, source[ringCodec.CodecID()], "collectors/compactor", compactors)
+	put(t, destination[ringCodec.CodecID()], "collectors/ring", ingesters)
+	put(t, destination[ringCodec.CodecID()], "collectors/compactor", staleCompactors)
+	put(t, source[replicaCodec.CodecID()], "ha-tracker/user-1/cluster", &distributor.ReplicaDesc{Replica: "replica-1", ReceivedAt: 1})
+	put(t, source[replicaCodec.CodecID()], "ha-tracker/user-2/cluster", &distributor.ReplicaDesc{Replica: "replica-2", ReceivedAt: 2})
+
+	keys, err := m.Plan(ctx)
+	require.NoError(t, err)
+
+	statuses := map[string]string{}
+	for _, k := range keys {
+		statuses[k.Key] = k.Status
+	}
+	assert.Equal(t, map[string]string{
+		"collectors/compactor":      StatusDifferent,
+		"collectors/ring":           StatusEqual,
+		"rulers/ring":               StatusNotFound,
+		"ha-tracker/user-1/cluster": StatusMissing,
+		"ha-tracker/user-2/cluster": StatusMissing,
+	}, statuses)
+
+	buf := bytes.Buffer{}
+	require.NoError(t, WritePlan(&buf, keys))
+	assert.Contains(t, buf.String(), "ha-tracker/user-1/cluster  replicaDesc  missing")
+
+	// The keys already existing in the destination are not overwritten, unless forced.
+	migrated, err := m.Migrate(ctx, keys, false)
+	require.NoError(t, err)
+	require.Len(t, migrated, 2)
+	assert.Equal(t, "ha-tracker/user-1/cluster", migrated[0].Key)
+	assert.Equal(t, "ha-tracker/user-2/cluster", migrated[1].Key)
+
+	pending, err := m.Verify(ctx, migrated, time.Second)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	value, err := destination[ringCodec.CodecID()].Get(ctx, "collectors/compactor")
+	require.NoError(t, err)
+	assert.Contains(t, value.(*ring.Desc).Ingesters, "compactor-0")
+	assert.NotContains(t, value.(*ring.Desc).Ingesters, "compactor-1")
+
+	migrated, err = m.Migrate(ctx, keys, true)
+	require.NoError(t, err)
+	require.Len(t, migrated, 3)
+
+	pending, err = m.Verify(ctx, keys, time.Second)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	value, err = destination[ringCodec.CodecID()].Get(ctx, "collectors/compactor")
+	require.NoError(t, err)
+	assert.Contains(t, value.(*ring.Desc).Ingesters, "compactor-1")
+	assert.NotContains(t, value.(*ring.Desc).Ingesters, "compactor-0")
+
+	value, err = destination[replicaCodec.CodecID()].Get(ctx, "ha-tracker/user-2/cluster")
+	require.NoError(t, err)
+	assert.Equal(t, "replica-2", value.(*distributor.ReplicaDesc).Replica)
+
+	// A key changed in the destination after the migration is reported by the verification.
+	put(t, destination[replicaCodec.CodecID()], "ha-tracker/user-1/cluster", &distributor.ReplicaDesc{Replica: "replica-3", ReceivedAt: 3})
+	pending, err = m.Verify(ctx, keys, 0)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "ha-tracker/user-1/cluster", pending[0].Key)
+	assert.Equal(t, StatusDifferent, pending[0].Status)
+}
+
+func TestMigrator_MemberlistDestination(t *testing.T) {
+	ctx := context.Background()
+	ringCodec := ring.GetCodec()
+
+	var cfg memberlist.KVConfig
+	flagext.DefaultValues(&cfg)
+	cfg.TCPTransport.BindAddrs = []string{"localhost"}
+	cfg.TCPTransport.BindPort = 0
+
+	mkv, stop, err := NewMemberlistKV(cfg, []codec.Codec{ringCodec}, log.NewNopLogger())
+	require.NoError(t, err)
+	defer stop()
+
+	newSource, source := inMemoryStore()
+	m, err := New([]KeySet{{Codec: ringCodec, Keys: []string{"collectors/ring"}}}, newSource, func(c codec.Codec) (kv.Client, error) {
+		return memberlist.NewClient(mkv, c)
+	}, log.NewNopLogger())
+	require.NoError(t, err)
+
+	// The destination has an instance missing in the source, which is turned into a tombstone
+	// by the memberlist merge, while the source has a LEFT instance.
+	destination, err := memberlist.NewClient(mkv, ringCodec)
+	require.NoError(t, err)
+	stale := ring.NewDesc()
+	stale.AddIngester("ingester-0", "127.0.0.1", "", []uint32{1}, ring.ACTIVE, time.Now())
+	put(t, destination, "collectors/ring", stale)
+
+	ingesters := ring.NewDesc()
+	ingesters.AddIngester("ingester-1", "127.0.0.2", "", []uint32{2}, ring.ACTIVE, time.Now())
+	ingesters.AddIngester("ingester-2", "127.0.0.3", "", nil, ring.LEFT, time.Now())
+	put(t, source[ringCodec.CodecID()], "collectors/ring", ingesters)
+
+	keys, err := m.Plan(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, StatusDifferent, keys[0].Status)
+
+	migrated, err := m.Migrate(ctx, keys, true)
+	require.NoError(t, err)
+	require.Len(t, migrated, 1)
+
+	pending, err := m.Verify(ctx, migrated, 0)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	value, err := destination.Get(ctx, "collectors/ring")
+	require.NoError(t, err)
+	assert.Contains(t, value.(*ring.Desc).Ingesters, "ingester-1")
+	assert.NotContains(t, value.(*ring.Desc).Ingesters, "ingester-0")
+}
+
+func TestMigrator_ShouldNotOverwriteKeysWrittenAfterPlanning(t *testing.T) {
+	ctx := context.Background()
+	ringCodec := ring.GetCodec()
+
+	newSource, source := inMemoryStore()
+	newDestination, destination := inMemoryStore()
+	m, err := New([]KeySet{{Codec: ringCodec, Keys: []string{"collectors/ring"}}}, newSource, newDestination, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ingesters := ring.NewDesc()
+	ingesters.AddIngester("ingester-1", "127.0.0.1", "", []uint32{1}, ring.ACTIVE, time.Now())
+	put(t, source[ringCodec.CodecID()], "collectors/ring", ingesters)
+
+	keys, err := m.Plan(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, StatusMissing, keys[0].Status)
+
+	// An instance registers to the destination ring before the migration.
+	current := ring.NewDesc()
+	current.AddIngester("ingester-2", "127.0.0.2", "", []uint32{2}, ring.ACTIVE, time.Now())
+	put(t, destination[ringCodec.CodecID()], "collectors/ring", current)
+
+	migrated, err := m.Migrate(ctx, keys, false)
+	require.NoError(t, err)
+	assert.Empty(t, migrated)
+
+	value, err := destination[ringCodec.CodecID()].Get(ctx, "collectors/ring")
+	require.NoError(t, err)
+	assert.Contains(t, value.(*ring.Desc).Ingesters, "ingester-2")
+	assert.NotContains(t, value.(*ring.Desc).Ingesters, "ingester-1")
+}
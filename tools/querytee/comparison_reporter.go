@@ -0,0 +1,154 @@
+package querytee
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/atomic"
+)
+
+// ComparisonReport contains all the information required to investigate
+// a failed comparison between the responses of two backends.
+type ComparisonReport struct {
+	Time      time.Time      `json:"time"`
+	RouteName string         `json:"route"`
+	Request   ReportRequest  `json:"request"`
+	Expected  ReportResponse `json:"expected"`
+	Actual    ReportResponse `json:"actual"`
+	Error     string         `json:"error"`
+	Diff      *ResponsesDiff `json:"diff,omitempty"`
+}
+
+// ReportRequest is the request sent to the backends.
+type ReportRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	OrgID  string `json:"org_id,omitempty"`
+}
+
+// ReportResponse is the response received from a backend.
+type ReportResponse struct {
+	Backend string          `json:"backend"`
+	Status  int             `json:"status"`
+	Error   string          `json:"error,omitempty"`
+	Body    json.RawMessage `json:"body,omitempty"`
+}
+
+func newReportResponse(res *backendResponse) ReportResponse {
+	out := ReportResponse{
+		Backend: res.backend.name,
+		Status:  res.status,
+	}
+
+	if res.err != nil {
+		out.Error = res.err.Error()
+	}
+
+	// Keep the body as is if it's a valid JSON, otherwise encode it as a string.
+	if len(res.body) > 0 {
+		if json.Valid(res.body) {
+			out.Body = res.body
+		} else if encoded, err := json.Marshal(string(res.body)); err == nil {
+			out.Body = encoded
+		}
+	}
+
+	return out
+}
+
+// ComparisonReporter records reports of failed comparisons.
+type ComparisonReporter interface {
+	Report(report ComparisonReport) error
+	Close() error
+}
+
+// NewComparisonReporter creates a reporter based on the input config. Returns
+// nil if reporting is disabled.
+func NewComparisonReporter(cfg ProxyConfig) (ComparisonReporter, error) {
+	switch {
+	case cfg.ComparisonReportFile != "":
+		return NewFileComparisonReporter(cfg.ComparisonReportFile)
+	case cfg.ComparisonReportDir != "":
+		return NewDirComparisonReporter(cfg.ComparisonReportDir)
+	default:
+		return nil, nil
+	}
+}
+
+// FileComparisonReporter appends each report as a JSON line to a file.
+type FileComparisonReporter struct {
+	mtx  sync.Mutex
+	file *os.File
+}
+
+func NewFileComparisonReporter(path string) (*FileComparisonReporter, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, errors.Wrap(err, "open comparison report file")
+	}
+
+	return &FileComparisonReporter{file: file}, nil
+}
+
+func (r *FileComparisonReporter) Report(report ComparisonReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	_, err = r.file.Write(append(data, '\n'))
+	return err
+}
+
+func (r *FileComparisonReporter) Close() error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return r.file.Close()
+}
+
+// DirComparisonReporter writes each report to a dedicated JSON file in a directory.
+type DirComparisonReporter struct {
+	dir string
+	seq atomic.Uint64
+}
+
+func NewDirComparisonReporter(dir string) (*DirComparisonReporter, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrap(err, "create comparison report directory")
+	}
+
+	return &DirComparisonReporter{dir: dir}, nil
+}
+
+func (r *DirComparisonReporter) Report(report ComparisonReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first and then rename it, so that a partially
+	// written report is never picked up by a reader.
+	name := fmt.Sprintf("%s-%s-%d.json", report.Time.UTC().Format("20060102T150405.000000000"), report.RouteName, r.seq.Inc())
+	tmp := filepath.Join(r.dir, "."+name+".tmp")
+
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, filepath.Join(r.dir, name))
+}
+
+func (r *DirComparisonReporter) Close() error {
+	return nil
+}
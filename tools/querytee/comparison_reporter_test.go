@@ -0,0 +1,85 @@
+package querytee
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileComparisonReporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.jsonl")
+
+	reporter, err := NewFileComparisonReporter(path)
+	require.NoError(t, err)
+
+	reports := []ComparisonReport{
+		{Time: time.Unix(1, 0).UTC(), RouteName: "api_v1_query", Request: ReportRequest{Method: "GET", Path: "/api/v1/query", Query: "query=up"}, Error: "first"},
+		{Time: time.Unix(2, 0).UTC(), RouteName: "api_v1_query_range", Request: ReportRequest{Method: "GET", Path: "/api/v1/query_range", Query: "query=up"}, Error: "second"},
+	}
+
+	for _, report := range reports {
+		require.NoError(t, reporter.Report(report))
+	}
+	require.NoError(t, reporter.Close())
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var actual []ComparisonReport
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		report := ComparisonReport{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &report))
+		actual = append(actual, report)
+	}
+
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, reports, actual)
+}
+
+func TestDirComparisonReporter(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "reports")
+
+	reporter, err := NewDirComparisonReporter(dir)
+	require.NoError(t, err)
+
+	expected := ComparisonReport{
+		Time:      time.Unix(1, 0).UTC(),
+		RouteName: "api_v1_query",
+		Request:   ReportRequest{Method: "GET", Path: "/api/v1/query", Query: "query=up", OrgID: "user-1"},
+		Expected:  ReportResponse{Backend: "backend-1", Status: 200, Body: json.RawMessage(`{"status":"success"}`)},
+		Actual:    ReportResponse{Backend: "backend-2", Status: 200, Body: json.RawMessage(`{"status":"error"}`)},
+		Error:     "expected status success but got error",
+		Diff:      &ResponsesDiff{ExpectedStatus: "success", ActualStatus: "error"},
+	}
+
+	require.NoError(t, reporter.Report(expected))
+	require.NoError(t, reporter.Report(expected))
+	require.NoError(t, reporter.Close())
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	for _, f := range files {
+		data, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		require.NoError(t, err)
+
+		actual := ComparisonReport{}
+		require.NoError(t, json.Unmarshal(data, &actual))
+
+		// Compare the bodies separately because they're re-indented.
+		assert.JSONEq(t, string(expected.Expected.Body), string(actual.Expected.Body))
+		assert.JSONEq(t, string(expected.Actual.Body), string(actual.Actual.Body))
+		actual.Expected.Body, actual.Actual.Body = expected.Expected.Body, expected.Actual.Body
+		assert.Equal(t, expected, actual)
+	}
+}
@@ -0,0 +1,311 @@
+package querytee
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+)
+
+// MetadataComparatorFunc helps with comparing the data of responses coming from the
+// metadata routes (series, labels, label values, metric metadata, rules and alerts).
+type MetadataComparatorFunc func(expected, actual json.RawMessage) error
+
+type MetadataResponse struct {
+	Status string
+	Data   json.RawMessage
+}
+
+// MetadataComparator compares metadata responses. All comparisons are order insensitive.
+type MetadataComparator struct {
+	compare MetadataComparatorFunc
+}
+
+func NewMetadataComparator(compare MetadataComparatorFunc) *MetadataComparator {
+	return &MetadataComparator{compare: compare}
+}
+
+// NewSeriesComparator makes a comparator for /api/v1/series responses.
+func NewSeriesComparator() *MetadataComparator {
+	return NewMetadataComparator(compareSeries)
+}
+
+// NewLabelsComparator makes a comparator for /api/v1/labels and /api/v1/label/{name}/values responses.
+func NewLabelsComparator() *MetadataComparator {
+	return NewMetadataComparator(compareStrings)
+}
+
+// NewMetricsMetadataComparator makes a comparator for /api/v1/metadata responses.
+func NewMetricsMetadataComparator() *MetadataComparator {
+	return NewMetadataComparator(compareMetricsMetadata)
+}
+
+// NewRulesComparator makes a comparator for /api/v1/rules responses. The input
+// fields are removed from the responses before comparing them.
+func NewRulesComparator(ignoredFields []string) *MetadataComparator {
+	ignored := toIgnoredFields(ignoredFields)
+
+	return NewMetadataComparator(func(expected, actual json.RawMessage) error {
+		return compareRules(expected, actual, ignored)
+	})
+}
+
+// NewAlertsComparator makes a comparator for /api/v1/alerts responses. The input
+// fields are removed from the responses before comparing them.
+func NewAlertsComparator(ignoredFields []string) *MetadataComparator {
+	ignored := toIgnoredFields(ignoredFields)
+
+	return NewMetadataComparator(func(expected, actual json.RawMessage) error {
+		return compareAlerts(expected, actual, ignored)
+	})
+}
+
+func (c *MetadataComparator) Compare(expectedResponse, actualResponse []byte) error {
+	var expected, actual MetadataResponse
+
+	err := json.Unmarshal(expectedResponse, &expected)
+	if err != nil {
+		return errors.Wrap(err, "unable to unmarshal expected response")
+	}
+
+	err = json.Unmarshal(actualResponse, &actual)
+	if err != nil {
+		return errors.Wrap(err, "unable to unmarshal actual response")
+	}
+
+	if expected.Status != actual.Status {
+		return fmt.Errorf("expected status %s but got %s", expected.Status, actual.Status)
+	}
+
+	return c.compare(expected.Data, actual.Data)
+}
+
+func compareSeries(expectedRaw, actualRaw json.RawMessage) error {
+	var expected, actual []model.LabelSet
+
+	if err := json.Unmarshal(expectedRaw, &expected); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(actualRaw, &actual); err != nil {
+		return err
+	}
+
+	if len(expected) != len(actual) {
+		return fmt.Errorf("expected %d series but got %d", len(expected), len(actual))
+	}
+
+	// Count the occurrences, so that duplicated series don't hide the missing ones.
+	actualSeries := make(map[string]int, len(actual))
+	for _, series := range actual {
+		actualSeries[series.String()]++
+	}
+
+	for _, series := range expected {
+		key := series.String()
+		if actualSeries[key] == 0 {
+			return fmt.Errorf("expected series %s missing from actual response", series)
+		}
+		actualSeries[key]--
+	}
+
+	return nil
+}
+
+func compareStrings(expectedRaw, actualRaw json.RawMessage) error {
+	var expected, actual []string
+
+	if err := json.Unmarshal(expectedRaw, &expected); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(actualRaw, &actual); err != nil {
+		return err
+	}
+
+	if len(expected) != len(actual) {
+		return fmt.Errorf("expected %d values but got %d", len(expected), len(actual))
+	}
+
+	// Count the occurrences, so that duplicated values don't hide the missing ones.
+	actualValues := make(map[string]int, len(actual))
+	for _, value := range actual {
+		actualValues[value]++
+	}
+
+	for _, value := range expected {
+		if actualValues[value] == 0 {
+			return fmt.Errorf("expected value %q missing from actual response", value)
+		}
+		actualValues[value]--
+	}
+
+	return nil
+}
+
+type metricMetadata struct {
+	Type string `json:"type"`
+	Help string `json:"help"`
+	Unit string `json:"unit"`
+}
+
+func compareMetricsMetadata(expectedRaw, actualRaw json.RawMessage) error {
+	var expected, actual map[string][]metricMetadata
+
+	if err := json.Unmarshal(expectedRaw, &expected); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(actualRaw, &actual); err != nil {
+		return err
+	}
+
+	if len(expected) != len(actual) {
+		return fmt.Errorf("expected metadata for %d metrics but got %d", len(expected), len(actual))
+	}
+
+	for metric, expectedMetadata := range expected {
+		actualMetadata, ok := actual[metric]
+		if !ok {
+			return fmt.Errorf("expected metadata for metric %s missing from actual response", metric)
+		}
+
+		if !reflect.DeepEqual(sortedMetricMetadata(expectedMetadata), sortedMetricMetadata(actualMetadata)) {
+			return fmt.Errorf("expected metadata %v for metric %s but got %v", expectedMetadata, metric, actualMetadata)
+		}
+	}
+
+	return nil
+}
+
+func sortedMetricMetadata(metadata []metricMetadata) []metricMetadata {
+	sorted := append([]metricMetadata(nil), metadata...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Type != sorted[j].Type {
+			return sorted[i].Type < sorted[j].Type
+		}
+		if sorted[i].Help != sorted[j].Help {
+			return sorted[i].Help < sorted[j].Help
+		}
+		return sorted[i].Unit < sorted[j].Unit
+	})
+	return sorted
+}
+
+func compareRules(expectedRaw, actualRaw json.RawMessage, ignored map[string]struct{}) error {
+	var expected, actual struct {
+		Groups []map[string]interface{} `json:"groups"`
+	}
+
+	if err := json.Unmarshal(expectedRaw, &expected); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(actualRaw, &actual); err != nil {
+		return err
+	}
+
+	if len(expected.Groups) != len(actual.Groups) {
+		return fmt.Errorf("expected %d rule groups but got %d", len(expected.Groups), len(actual.Groups))
+	}
+
+	// Rule groups are uniquely identified by file and name.
+	groupKey := func(group map[string]interface{}) string {
+		return fmt.Sprintf("%v/%v", group["file"], group["name"])
+	}
+
+	return compareNormalizedItems("rule group", expected.Groups, actual.Groups, groupKey, ignored)
+}
+
+func compareAlerts(expectedRaw, actualRaw json.RawMessage, ignored map[string]struct{}) error {
+	var expected, actual struct {
+		Alerts []map[string]interface{} `json:"alerts"`
+	}
+
+	if err := json.Unmarshal(expectedRaw, &expected); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(actualRaw, &actual); err != nil {
+		return err
+	}
+
+	if len(expected.Alerts) != len(actual.Alerts) {
+		return fmt.Errorf("expected %d alerts but got %d", len(expected.Alerts), len(actual.Alerts))
+	}
+
+	// Alerts are uniquely identified by their labels.
+	alertKey := func(alert map[string]interface{}) string {
+		return canonicalJSON(alert["labels"])
+	}
+
+	return compareNormalizedItems("alert", expected.Alerts, actual.Alerts, alertKey, ignored)
+}
+
+// compareNormalizedItems matches expected and actual items by key and compares
+// them once normalized.
+func compareNormalizedItems(kind string, expected, actual []map[string]interface{}, key func(map[string]interface{}) string, ignored map[string]struct{}) error {
+	actualByKey := make(map[string]interface{}, len(actual))
+	for _, item := range actual {
+		k := key(item)
+		actualByKey[k] = normalizeJSON(item, ignored)
+	}
+
+	for _, item := range expected {
+		k := key(item)
+
+		actualItem, ok := actualByKey[k]
+		if !ok {
+			return fmt.Errorf("expected %s %s missing from actual response", kind, k)
+		}
+
+		expectedItem := normalizeJSON(item, ignored)
+		if !reflect.DeepEqual(expectedItem, actualItem) {
+			return fmt.Errorf("expected %s %s to be %s but got %s", kind, k, canonicalJSON(expectedItem), canonicalJSON(actualItem))
+		}
+	}
+
+	return nil
+}
+
+// normalizeJSON removes the ignored fields from a generic unmarshalled JSON
+// value and sorts all lists, in order to make the comparison order insensitive.
+func normalizeJSON(value interface{}, ignored map[string]struct{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if _, ok := ignored[key]; ok {
+				delete(v, key)
+				continue
+			}
+			v[key] = normalizeJSON(item, ignored)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeJSON(item, ignored)
+		}
+		sort.Slice(v, func(i, j int) bool {
+			return canonicalJSON(v[i]) < canonicalJSON(v[j])
+		})
+	}
+
+	return value
+}
+
+// canonicalJSON returns the JSON encoding of a generic unmarshalled JSON value.
+// Maps keys are sorted by the encoder, so the output is deterministic.
+func canonicalJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+func toIgnoredFields(fields []string) map[string]struct{} {
+	ignored := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		if field != "" {
+			ignored[field] = struct{}{}
+		}
+	}
+	return ignored
+}
@@ -0,0 +1,223 @@
+package querytee
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadataComparator_Series(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		expected string
+		actual   string
+		err      error
+	}{
+		{
+			name:     "same series in different order",
+			expected: `{"status":"success","data":[{"__name__":"up","job":"a"},{"__name__":"up","job":"b"}]}`,
+			actual:   `{"status":"success","data":[{"__name__":"up","job":"b"},{"__name__":"up","job":"a"}]}`,
+		},
+		{
+			name:     "different number of series",
+			expected: `{"status":"success","data":[{"__name__":"up","job":"a"},{"__name__":"up","job":"b"}]}`,
+			actual:   `{"status":"success","data":[{"__name__":"up","job":"a"}]}`,
+			err:      errors.New("expected 2 series but got 1"),
+		},
+		{
+			name:     "different series",
+			expected: `{"status":"success","data":[{"__name__":"up","job":"a"}]}`,
+			actual:   `{"status":"success","data":[{"__name__":"up","job":"b"}]}`,
+			err:      errors.New(`expected series {__name__="up", job="a"} missing from actual response`),
+		},
+		{
+			name:     "duplicated series",
+			expected: `{"status":"success","data":[{"__name__":"up","job":"a"},{"__name__":"up","job":"b"}]}`,
+			actual:   `{"status":"success","data":[{"__name__":"up","job":"a"},{"__name__":"up","job":"a"}]}`,
+			err:      errors.New(`expected series {__name__="up", job="b"} missing from actual response`),
+		},
+		{
+			name:     "different status",
+			expected: `{"status":"success","data":[]}`,
+			actual:   `{"status":"error","data":[]}`,
+			err:      errors.New("expected status success but got error"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewSeriesComparator().Compare([]byte(tc.expected), []byte(tc.actual))
+			if tc.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Equal(t, tc.err.Error(), err.Error())
+		})
+	}
+}
+
+func TestMetadataComparator_Labels(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		expected string
+		actual   string
+		err      error
+	}{
+		{
+			name:     "same values in different order",
+			expected: `{"status":"success","data":["a","b","c"]}`,
+			actual:   `{"status":"success","data":["c","a","b"]}`,
+		},
+		{
+			name:     "different number of values",
+			expected: `{"status":"success","data":["a","b"]}`,
+			actual:   `{"status":"success","data":["a"]}`,
+			err:      errors.New("expected 2 values but got 1"),
+		},
+		{
+			name:     "different values",
+			expected: `{"status":"success","data":["a","b"]}`,
+			actual:   `{"status":"success","data":["a","c"]}`,
+			err:      errors.New(`expected value "b" missing from actual response`),
+		},
+		{
+			name:     "duplicated values",
+			expected: `{"status":"success","data":["a","a"]}`,
+			actual:   `{"status":"success","data":["a","b"]}`,
+			err:      errors.New(`expected value "a" missing from actual response`),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewLabelsComparator().Compare([]byte(tc.expected), []byte(tc.actual))
+			if tc.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Equal(t, tc.err.Error(), err.Error())
+		})
+	}
+}
+
+func TestMetadataComparator_MetricsMetadata(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		expected string
+		actual   string
+		err      error
+	}{
+		{
+			name:     "same metadata in different order",
+			expected: `{"status":"success","data":{"up":[{"type":"gauge","help":"a","unit":""},{"type":"gauge","help":"b","unit":""}]}}`,
+			actual:   `{"status":"success","data":{"up":[{"type":"gauge","help":"b","unit":""},{"type":"gauge","help":"a","unit":""}]}}`,
+		},
+		{
+			name:     "missing metric",
+			expected: `{"status":"success","data":{"up":[{"type":"gauge","help":"a","unit":""}]}}`,
+			actual:   `{"status":"success","data":{"down":[{"type":"gauge","help":"a","unit":""}]}}`,
+			err:      errors.New("expected metadata for metric up missing from actual response"),
+		},
+		{
+			name:     "different metadata",
+			expected: `{"status":"success","data":{"up":[{"type":"gauge","help":"a","unit":""}]}}`,
+			actual:   `{"status":"success","data":{"up":[{"type":"counter","help":"a","unit":""}]}}`,
+			err:      errors.New("expected metadata [{gauge a }] for metric up but got [{counter a }]"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewMetricsMetadataComparator().Compare([]byte(tc.expected), []byte(tc.actual))
+			if tc.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Equal(t, tc.err.Error(), err.Error())
+		})
+	}
+}
+
+func TestMetadataComparator_Rules(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		expected string
+		actual   string
+		err      error
+	}{
+		{
+			name: "same groups in different order with different evaluation timestamps",
+			expected: `{"status":"success","data":{"groups":[
+				{"name":"a","file":"f","rules":[{"name":"r1","type":"recording","health":"ok"}],"interval":60,"lastEvaluation":"2021-01-01T00:00:00Z","evaluationTime":0.1},
+				{"name":"b","file":"f","rules":[{"name":"r2","type":"recording","health":"ok"}],"interval":60,"lastEvaluation":"2021-01-01T00:00:00Z","evaluationTime":0.1}
+			]}}`,
+			actual: `{"status":"success","data":{"groups":[
+				{"name":"b","file":"f","rules":[{"name":"r2","type":"recording","health":"ok"}],"interval":60,"lastEvaluation":"2021-01-01T00:00:10Z","evaluationTime":0.2},
+				{"name":"a","file":"f","rules":[{"name":"r1","type":"recording","health":"ok"}],"interval":60,"lastEvaluation":"2021-01-01T00:00:10Z","evaluationTime":0.2}
+			]}}`,
+		},
+		{
+			name:     "missing group",
+			expected: `{"status":"success","data":{"groups":[{"name":"a","file":"f","rules":[]}]}}`,
+			actual:   `{"status":"success","data":{"groups":[{"name":"b","file":"f","rules":[]}]}}`,
+			err:      errors.New("expected rule group f/a missing from actual response"),
+		},
+		{
+			name:     "different rules",
+			expected: `{"status":"success","data":{"groups":[{"name":"a","file":"f","rules":[{"name":"r1"}]}]}}`,
+			actual:   `{"status":"success","data":{"groups":[{"name":"a","file":"f","rules":[{"name":"r2"}]}]}}`,
+			err:      errors.New(`expected rule group f/a to be {"file":"f","name":"a","rules":[{"name":"r1"}]} but got {"file":"f","name":"a","rules":[{"name":"r2"}]}`),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewRulesComparator([]string{"lastEvaluation", "evaluationTime"}).Compare([]byte(tc.expected), []byte(tc.actual))
+			if tc.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Equal(t, tc.err.Error(), err.Error())
+		})
+	}
+}
+
+func TestMetadataComparator_Alerts(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		expected string
+		actual   string
+		err      error
+	}{
+		{
+			name: "same alerts in different order with different active timestamps",
+			expected: `{"status":"success","data":{"alerts":[
+				{"labels":{"alertname":"a"},"annotations":{},"state":"firing","activeAt":"2021-01-01T00:00:00Z","value":"1e+00"},
+				{"labels":{"alertname":"b"},"annotations":{},"state":"firing","activeAt":"2021-01-01T00:00:00Z","value":"1e+00"}
+			]}}`,
+			actual: `{"status":"success","data":{"alerts":[
+				{"labels":{"alertname":"b"},"annotations":{},"state":"firing","activeAt":"2021-01-01T00:00:10Z","value":"2e+00"},
+				{"labels":{"alertname":"a"},"annotations":{},"state":"firing","activeAt":"2021-01-01T00:00:10Z","value":"2e+00"}
+			]}}`,
+		},
+		{
+			name:     "different number of alerts",
+			expected: `{"status":"success","data":{"alerts":[{"labels":{"alertname":"a"},"state":"firing"}]}}`,
+			actual:   `{"status":"success","data":{"alerts":[]}}`,
+			err:      errors.New("expected 1 alerts but got 0"),
+		},
+		{
+			name:     "different state",
+			expected: `{"status":"success","data":{"alerts":[{"labels":{"alertname":"a"},"state":"firing"}]}}`,
+			actual:   `{"status":"success","data":{"alerts":[{"labels":{"alertname":"a"},"state":"pending"}]}}`,
+			err:      errors.New(`expected alert {"alertname":"a"} to be {"labels":{"alertname":"a"},"state":"firing"} but got {"labels":{"alertname":"a"},"state":"pending"}`),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewAlertsComparator([]string{"activeAt", "value"}).Compare([]byte(tc.expected), []byte(tc.actual))
+			if tc.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Equal(t, tc.err.Error(), err.Error())
+		})
+	}
+}
@@ -18,6 +18,8 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/cortexproject/cortex/pkg/util/flagext"
 )
 
 var (
@@ -31,7 +33,12 @@ type ProxyConfig struct {
 	BackendReadTimeout             time.Duration
 	CompareResponses               bool
 	ValueComparisonTolerance       float64
+	ComparisonIgnoredFields        flagext.StringSliceCSV
 	PassThroughNonRegisteredRoutes bool
+	ComparisonReportFile           string
+	ComparisonReportDir            string
+	ShadowMode                     bool
+	ShadowSamplingRate             float64
 }
 
 func (cfg *ProxyConfig) RegisterFlags(f *flag.FlagSet) {
@@ -41,7 +48,13 @@ func (cfg *ProxyConfig) RegisterFlags(f *flag.FlagSet) {
 	f.DurationVar(&cfg.BackendReadTimeout, "backend.read-timeout", 90*time.Second, "The timeout when reading the response from a backend.")
 	f.BoolVar(&cfg.CompareResponses, "proxy.compare-responses", false, "Compare responses between preferred and secondary endpoints for supported routes.")
 	f.Float64Var(&cfg.ValueComparisonTolerance, "proxy.value-comparison-tolerance", 0.000001, "The tolerance to apply when comparing floating point values in the responses. 0 to disable tolerance and require exact match (not recommended).")
+	cfg.ComparisonIgnoredFields = []string{"activeAt", "evaluationTime", "lastEvaluation", "value"}
+	f.Var(&cfg.ComparisonIgnoredFields, "proxy.compare-ignored-fields", "Comma separated list of volatile fields ignored when comparing /api/v1/rules and /api/v1/alerts responses.")
 	f.BoolVar(&cfg.PassThroughNonRegisteredRoutes, "proxy.passthrough-non-registered-routes", false, "Passthrough requests for non-registered routes to preferred backend.")
+	f.StringVar(&cfg.ComparisonReportFile, "proxy.comparison-report-file", "", "If set, a report containing the request, both responses and their diff is appended as a JSON line to this file for each failed comparison. Requires -proxy.compare-responses=true.")
+	f.StringVar(&cfg.ComparisonReportDir, "proxy.comparison-report-dir", "", "If set, a report containing the request, both responses and their diff is written as a JSON file to this directory for each failed comparison. Requires -proxy.compare-responses=true.")
+	f.BoolVar(&cfg.ShadowMode, "proxy.shadow-mode", false, "Always send back to the client the preferred backend response, as soon as it's received, and send the request to the other backends (and compare responses) asynchronously only for a sample of requests.")
+	f.Float64Var(&cfg.ShadowSamplingRate, "proxy.shadow-sampling-rate", 1, "The fraction of requests (between 0 and 1) sent to the non-preferred backends when running in shadow mode.")
 }
 
 type Route struct {
@@ -57,6 +70,7 @@ type Proxy struct {
 	logger   log.Logger
 	metrics  *ProxyMetrics
 	routes   []Route
+	reporter ComparisonReporter
 
 	// The HTTP server used to run the proxy service.
 	srv         *http.Server
@@ -75,6 +89,22 @@ func NewProxy(cfg ProxyConfig, logger log.Logger, routes []Route, registerer pro
 		return nil, fmt.Errorf("when enabling passthrough for non-registered routes -backend.preferred flag must be set to hostname of backend where those requests needs to be passed")
 	}
 
+	if (cfg.ComparisonReportFile != "" || cfg.ComparisonReportDir != "") && !cfg.CompareResponses {
+		return nil, fmt.Errorf("when enabling comparison reports -proxy.compare-responses flag must be set to true")
+	}
+
+	if cfg.ComparisonReportFile != "" && cfg.ComparisonReportDir != "" {
+		return nil, fmt.Errorf("-proxy.comparison-report-file and -proxy.comparison-report-dir are mutually exclusive")
+	}
+
+	if cfg.ShadowMode && cfg.PreferredBackend == "" {
+		return nil, fmt.Errorf("when enabling shadow mode -backend.preferred flag must be set to hostname of preferred backend")
+	}
+
+	if cfg.ShadowMode && (cfg.ShadowSamplingRate < 0 || cfg.ShadowSamplingRate > 1) {
+		return nil, fmt.Errorf("the shadow sampling rate must be between 0 and 1")
+	}
+
 	p := &Proxy{
 		cfg:     cfg,
 		logger:  logger,
@@ -131,8 +161,8 @@ func NewProxy(cfg ProxyConfig, logger log.Logger, routes []Route, registerer pro
 		}
 	}
 
-	if cfg.CompareResponses && len(p.backends) != 2 {
-		return nil, fmt.Errorf("when enabling comparison of results number of backends should be 2 exactly")
+	if cfg.CompareResponses && len(p.backends) < 2 {
+		return nil, fmt.Errorf("when enabling comparison of results at least 2 backends are required")
 	}
 
 	// At least 2 backends are suggested
@@ -140,6 +170,12 @@ func NewProxy(cfg ProxyConfig, logger log.Logger, routes []Route, registerer pro
 		level.Warn(p.logger).Log("msg", "The proxy is running with only 1 backend. At least 2 backends are required to fulfil the purpose of the proxy and compare results.")
 	}
 
+	reporter, err := NewComparisonReporter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	p.reporter = reporter
+
 	return p, nil
 }
 
@@ -163,7 +199,15 @@ func (p *Proxy) Start() error {
 		if p.cfg.CompareResponses {
 			comparator = route.ResponseComparator
 		}
-		router.Path(route.Path).Methods(route.Methods...).Handler(NewProxyEndpoint(p.backends, route.RouteName, p.metrics, p.logger, comparator))
+
+		endpoint := NewProxyEndpoint(p.backends, route.RouteName, p.metrics, p.logger, comparator)
+		endpoint.reporter = p.reporter
+		if p.cfg.ShadowMode {
+			endpoint.shadowMode = true
+			endpoint.shadowSamplingRate = p.cfg.ShadowSamplingRate
+		}
+
+		router.Path(route.Path).Methods(route.Methods...).Handler(endpoint)
 	}
 
 	if p.cfg.PassThroughNonRegisteredRoutes {
@@ -201,7 +245,15 @@ func (p *Proxy) Stop() error {
 		return nil
 	}
 
-	return p.srv.Shutdown(context.Background())
+	err := p.srv.Shutdown(context.Background())
+
+	if p.reporter != nil {
+		if closeErr := p.reporter.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
 }
 
 func (p *Proxy) Await() {
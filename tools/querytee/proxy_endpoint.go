@@ -2,6 +2,7 @@ package querytee
 
 import (
 	"fmt"
+	"math/rand"
 	"net/http"
 	"strconv"
 	"sync"
@@ -28,6 +29,14 @@ type ProxyEndpoint struct {
 
 	// The route name used to track metrics.
 	routeName string
+
+	// Optional reporter used to record failed comparisons.
+	reporter ComparisonReporter
+
+	// In shadow mode the preferred backend response is always sent back to the client
+	// while the other backends are queried (and compared) only for a sample of requests.
+	shadowMode         bool
+	shadowSamplingRate float64
 }
 
 func NewProxyEndpoint(backends []*ProxyBackend, routeName string, metrics *ProxyMetrics, logger log.Logger, comparator ResponsesComparator) *ProxyEndpoint {
@@ -52,12 +61,23 @@ func NewProxyEndpoint(backends []*ProxyBackend, routeName string, metrics *Proxy
 func (p *ProxyEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	level.Debug(p.logger).Log("msg", "Received request", "path", r.URL.Path, "query", r.URL.RawQuery)
 
+	// In shadow mode, the request is sent to the non-preferred backends only if sampled.
+	backends := p.backends
+	if p.shadowMode && !p.shouldSampleShadowRequest() {
+		backends = p.preferredBackends()
+	}
+
 	// Send the same request to all backends.
-	resCh := make(chan *backendResponse, len(p.backends))
-	go p.executeBackendRequests(r, resCh)
+	resCh := make(chan *backendResponse, len(backends))
+	go p.executeBackendRequests(r, backends, resCh)
 
 	// Wait for the first response that's feasible to be sent back to the client.
-	downstreamRes := p.waitBackendResponseForDownstream(resCh)
+	var downstreamRes *backendResponse
+	if p.shadowMode {
+		downstreamRes = p.waitPreferredBackendResponse(resCh)
+	} else {
+		downstreamRes = p.waitBackendResponseForDownstream(resCh)
+	}
 
 	if downstreamRes.err != nil {
 		http.Error(w, downstreamRes.err.Error(), http.StatusInternalServerError)
@@ -71,13 +91,17 @@ func (p *ProxyEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	p.metrics.responsesTotal.WithLabelValues(downstreamRes.backend.name, r.Method, p.routeName).Inc()
 }
 
-func (p *ProxyEndpoint) executeBackendRequests(r *http.Request, resCh chan *backendResponse) {
-	responses := make([]*backendResponse, 0, len(p.backends))
+func (p *ProxyEndpoint) executeBackendRequests(r *http.Request, backends []*ProxyBackend, resCh chan *backendResponse) {
+	var (
+		responses    = make([]*backendResponse, 0, len(backends))
+		responsesMtx = sync.Mutex{}
+		reportReq    = ReportRequest{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery, OrgID: r.Header.Get(orgIDHeader)}
+	)
 
 	wg := sync.WaitGroup{}
-	wg.Add(len(p.backends))
+	wg.Add(len(backends))
 
-	for _, b := range p.backends {
+	for _, b := range backends {
 		b := b
 
 		go func() {
@@ -105,7 +129,9 @@ func (p *ProxyEndpoint) executeBackendRequests(r *http.Request, resCh chan *back
 
 			// Keep track of the response if required.
 			if p.comparator != nil {
+				responsesMtx.Lock()
 				responses = append(responses, res)
+				responsesMtx.Unlock()
 			}
 
 			resCh <- res
@@ -116,26 +142,100 @@ func (p *ProxyEndpoint) executeBackendRequests(r *http.Request, resCh chan *back
 	wg.Wait()
 	close(resCh)
 
-	// Compare responses.
+	// Compare the preferred backend response against each other backend response. In shadow
+	// mode, the request may have been sent only to the preferred backend.
 	if p.comparator != nil {
-		expectedResponse := responses[0]
-		actualResponse := responses[1]
-		if responses[1].backend.preferred {
-			expectedResponse, actualResponse = actualResponse, expectedResponse
+		var expectedResponse *backendResponse
+		for _, res := range responses {
+			if res.backend.preferred {
+				expectedResponse = res
+				break
+			}
+		}
+		if expectedResponse == nil {
+			return
 		}
 
-		result := comparisonSuccess
-		err := p.compareResponses(expectedResponse, actualResponse)
+		for _, actualResponse := range responses {
+			if actualResponse == expectedResponse {
+				continue
+			}
+
+			result := comparisonSuccess
+			err := p.compareResponses(expectedResponse, actualResponse)
+			if err != nil {
+				level.Error(util_log.Logger).Log("msg", "response comparison failed", "route-name", p.routeName,
+					"query", r.URL.RawQuery, "backend", actualResponse.backend.name, "err", err)
+				result = comparisonFailed
+
+				if p.reporter != nil {
+					p.reportComparison(reportReq, expectedResponse, actualResponse, err)
+				}
+			}
+
+			p.metrics.responsesComparedTotal.WithLabelValues(p.routeName, result).Inc()
+		}
+	}
+}
+
+func (p *ProxyEndpoint) reportComparison(req ReportRequest, expectedResponse, actualResponse *backendResponse, compareErr error) {
+	report := ComparisonReport{
+		Time:      time.Now(),
+		RouteName: p.routeName,
+		Request:   req,
+		Expected:  newReportResponse(expectedResponse),
+		Actual:    newReportResponse(actualResponse),
+		Error:     compareErr.Error(),
+	}
+
+	// The structured diff is built only if both backends successfully returned a response to compare.
+	if differ, ok := p.comparator.(ResponsesDiffer); ok && expectedResponse.status == 200 && actualResponse.status == 200 {
+		diff, err := differ.Diff(expectedResponse.body, actualResponse.body)
 		if err != nil {
-			level.Error(util_log.Logger).Log("msg", "response comparison failed", "route-name", p.routeName,
-				"query", r.URL.RawQuery, "err", err)
-			result = comparisonFailed
+			level.Warn(p.logger).Log("msg", "Unable to diff responses", "route-name", p.routeName, "err", err)
 		}
+		report.Diff = diff
+	}
 
-		p.metrics.responsesComparedTotal.WithLabelValues(p.routeName, result).Inc()
+	if err := p.reporter.Report(report); err != nil {
+		level.Warn(p.logger).Log("msg", "Unable to write comparison report", "route-name", p.routeName, "err", err)
+		p.metrics.comparisonReportFailuresTotal.WithLabelValues(p.routeName).Inc()
 	}
 }
 
+func (p *ProxyEndpoint) shouldSampleShadowRequest() bool {
+	return p.shadowSamplingRate >= 1 || rand.Float64() < p.shadowSamplingRate
+}
+
+func (p *ProxyEndpoint) preferredBackends() []*ProxyBackend {
+	for _, b := range p.backends {
+		if b.preferred {
+			return []*ProxyBackend{b}
+		}
+	}
+
+	return p.backends
+}
+
+// waitPreferredBackendResponse returns the preferred backend response, whatever
+// its outcome, without waiting for the other backends.
+func (p *ProxyEndpoint) waitPreferredBackendResponse(resCh chan *backendResponse) *backendResponse {
+	var first *backendResponse
+
+	for res := range resCh {
+		if res.backend.preferred {
+			return res
+		}
+
+		if first == nil {
+			first = res
+		}
+	}
+
+	// Should never happen, unless there's no preferred backend.
+	return first
+}
+
 func (p *ProxyEndpoint) waitBackendResponseForDownstream(resCh chan *backendResponse) *backendResponse {
 	var (
 		responses                 = make([]*backendResponse, 0, len(p.backends))
@@ -1,12 +1,17 @@
 package querytee
 
 import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 	"time"
 
 	"github.com/go-kit/kit/log"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -107,6 +112,39 @@ func Test_ProxyEndpoint_waitBackendResponseForDownstream(t *testing.T) {
 	}
 }
 
+func Test_ProxyEndpoint_executeBackendRequests_ShouldCompareThePreferredBackendWithEachOtherBackend(t *testing.T) {
+	var backends []*ProxyBackend
+	for idx, body := range []string{"expected", "expected", "unexpected"} {
+		body := body
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(body))
+		}))
+		defer server.Close()
+
+		u, err := url.Parse(server.URL)
+		require.NoError(t, err)
+		backends = append(backends, NewProxyBackend(body, u, time.Second, idx == 0))
+	}
+
+	metrics := NewProxyMetrics(prometheus.NewPedanticRegistry())
+	endpoint := NewProxyEndpoint(backends, "test", metrics, log.NewNopLogger(), &bytesComparator{})
+
+	req := httptest.NewRequest("GET", "/api/v1/query?query=up", nil)
+	endpoint.executeBackendRequests(req, backends, make(chan *backendResponse, len(backends)))
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.responsesComparedTotal.WithLabelValues("test", comparisonSuccess)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.responsesComparedTotal.WithLabelValues("test", comparisonFailed)))
+}
+
+type bytesComparator struct{}
+
+func (c *bytesComparator) Compare(expected, actual []byte) error {
+	if !bytes.Equal(expected, actual) {
+		return errors.New("responses don't match")
+	}
+	return nil
+}
+
 func Test_backendResponse_succeeded(t *testing.T) {
 	tests := map[string]struct {
 		resStatus int
@@ -1,10 +1,12 @@
 package querytee
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
@@ -14,6 +16,8 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/cortexproject/cortex/pkg/util/test"
 )
 
 var testRoutes = []Route{
@@ -177,6 +181,120 @@ func Test_Proxy_RequestsForwarding(t *testing.T) {
 	}
 }
 
+func TestProxy_ShadowMode(t *testing.T) {
+	const (
+		querySingleMetric1 = `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"__name__":"cortex_build_info"},"value":[1583320883,"1"]}]}}`
+		querySingleMetric2 = `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"__name__":"cortex_build_info"},"value":[1583320883,"2"]}]}}`
+	)
+
+	tests := map[string]struct {
+		preferredStatus          int
+		samplingRate             float64
+		expectedStatus           int
+		expectedRes              string
+		expectedSecondaryQueried bool
+		expectedReport           bool
+	}{
+		"preferred backend succeeds and the request is sampled": {
+			preferredStatus:          200,
+			samplingRate:             1,
+			expectedStatus:           200,
+			expectedRes:              querySingleMetric1,
+			expectedSecondaryQueried: true,
+			expectedReport:           true,
+		},
+		"preferred backend succeeds and the request is not sampled": {
+			preferredStatus:          200,
+			samplingRate:             0,
+			expectedStatus:           200,
+			expectedRes:              querySingleMetric1,
+			expectedSecondaryQueried: false,
+			expectedReport:           false,
+		},
+		"preferred backend fails and the request is sampled": {
+			preferredStatus:          500,
+			samplingRate:             1,
+			expectedStatus:           500,
+			expectedRes:              "",
+			expectedSecondaryQueried: true,
+			expectedReport:           true,
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+			secondaryQueried := make(chan struct{}, 1)
+
+			preferred := httptest.NewServer(mockQueryResponse("/api/v1/query", testData.preferredStatus, querySingleMetric1))
+			defer preferred.Close()
+
+			secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				secondaryQueried <- struct{}{}
+				mockQueryResponse("/api/v1/query", 200, querySingleMetric2)(w, r)
+			}))
+			defer secondary.Close()
+
+			reportPath := filepath.Join(t.TempDir(), "report.jsonl")
+
+			// Start the proxy.
+			cfg := ProxyConfig{
+				BackendEndpoints:     strings.Join([]string{preferred.URL, secondary.URL}, ","),
+				PreferredBackend:     "0",
+				ServerServicePort:    0,
+				BackendReadTimeout:   time.Second,
+				CompareResponses:     true,
+				ComparisonReportFile: reportPath,
+				ShadowMode:           true,
+				ShadowSamplingRate:   testData.samplingRate,
+			}
+
+			routes := []Route{
+				{Path: "/api/v1/query", RouteName: "api_v1_query", Methods: []string{"GET"}, ResponseComparator: NewSamplesComparator(0)},
+			}
+
+			p, err := NewProxy(cfg, log.NewNopLogger(), routes, nil)
+			require.NoError(t, err)
+			require.NotNil(t, p)
+			defer p.Stop() //nolint:errcheck
+
+			require.NoError(t, p.Start())
+
+			// Send a query request to the proxy.
+			res, err := http.Get(fmt.Sprintf("http://%s/api/v1/query?query=cortex_build_info", p.Endpoint()))
+			require.NoError(t, err)
+
+			defer res.Body.Close()
+			body, err := ioutil.ReadAll(res.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, testData.expectedStatus, res.StatusCode)
+			assert.Equal(t, testData.expectedRes, string(body))
+
+			if testData.expectedSecondaryQueried {
+				select {
+				case <-secondaryQueried:
+				case <-time.After(time.Second):
+					t.Fatal("the secondary backend has not been queried")
+				}
+			} else {
+				assert.Len(t, secondaryQueried, 0)
+			}
+
+			// The comparison is done asynchronously.
+			if testData.expectedReport {
+				test.Poll(t, time.Second, true, func() interface{} {
+					data, err := ioutil.ReadFile(reportPath)
+					return err == nil && bytes.Contains(data, []byte("query=cortex_build_info"))
+				})
+			} else {
+				data, err := ioutil.ReadFile(reportPath)
+				require.NoError(t, err)
+				assert.Empty(t, data)
+			}
+		})
+	}
+}
+
 func TestProxy_Passthrough(t *testing.T) {
 	type route struct {
 		path, response string
@@ -0,0 +1,233 @@
+package querytee
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/common/model"
+)
+
+// maxSampleDiffs is the maximum number of sample diffs tracked in a single
+// ResponsesDiff, to avoid generating huge reports for completely different responses.
+const maxSampleDiffs = 1000
+
+// ResponsesDiffer can be optionally implemented by a ResponsesComparator to
+// build a structured diff between two responses which don't match.
+type ResponsesDiffer interface {
+	Diff(expected, actual []byte) (*ResponsesDiff, error)
+}
+
+// ResponsesDiff is the structured diff between the expected and actual responses.
+type ResponsesDiff struct {
+	ExpectedStatus     string `json:"expected_status,omitempty"`
+	ActualStatus       string `json:"actual_status,omitempty"`
+	ExpectedResultType string `json:"expected_result_type,omitempty"`
+	ActualResultType   string `json:"actual_result_type,omitempty"`
+
+	// Series found in the expected response but missing in the actual one.
+	SeriesRemoved []string `json:"series_removed,omitempty"`
+
+	// Series found in the actual response but missing in the expected one.
+	SeriesAdded []string `json:"series_added,omitempty"`
+
+	// Samples whose value differs beyond the configured tolerance, or which
+	// are missing in one of the two responses.
+	Samples []SampleDiff `json:"samples,omitempty"`
+
+	// Whether the list of sample diffs has been truncated.
+	SamplesTruncated bool `json:"samples_truncated,omitempty"`
+}
+
+// SampleDiff holds a single sample mismatch. Expected or Actual is nil if the
+// sample is missing in the respective response.
+type SampleDiff struct {
+	Metric    string             `json:"metric"`
+	Timestamp model.Time         `json:"timestamp"`
+	Expected  *model.SampleValue `json:"expected,omitempty"`
+	Actual    *model.SampleValue `json:"actual,omitempty"`
+	Delta     float64            `json:"delta,omitempty"`
+}
+
+// Empty returns true if the diff doesn't contain any difference.
+func (d *ResponsesDiff) Empty() bool {
+	return d.ExpectedStatus == d.ActualStatus &&
+		d.ExpectedResultType == d.ActualResultType &&
+		len(d.SeriesRemoved) == 0 &&
+		len(d.SeriesAdded) == 0 &&
+		len(d.Samples) == 0
+}
+
+func (d *ResponsesDiff) addSample(metric model.Metric, ts model.Time, expected, actual *model.SampleValue) {
+	if len(d.Samples) >= maxSampleDiffs {
+		d.SamplesTruncated = true
+		return
+	}
+
+	diff := SampleDiff{
+		Metric:    metric.String(),
+		Timestamp: ts,
+		Expected:  expected,
+		Actual:    actual,
+	}
+
+	if expected != nil && actual != nil {
+		// The delta is not JSON serializable when the result is NaN or Inf.
+		if delta := float64(*actual) - float64(*expected); !math.IsNaN(delta) && !math.IsInf(delta, 0) {
+			diff.Delta = delta
+		}
+	}
+
+	d.Samples = append(d.Samples, diff)
+}
+
+// Diff implements ResponsesDiffer.
+func (s *SamplesComparator) Diff(expectedResponse, actualResponse []byte) (*ResponsesDiff, error) {
+	var expected, actual SamplesResponse
+
+	if err := json.Unmarshal(expectedResponse, &expected); err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal expected response")
+	}
+
+	if err := json.Unmarshal(actualResponse, &actual); err != nil {
+		return nil, errors.Wrap(err, "unable to unmarshal actual response")
+	}
+
+	diff := &ResponsesDiff{
+		ExpectedStatus:     expected.Status,
+		ActualStatus:       actual.Status,
+		ExpectedResultType: expected.Data.ResultType,
+		ActualResultType:   actual.Data.ResultType,
+	}
+
+	// There's no point in diffing the samples if the result types don't match.
+	if expected.Data.ResultType != actual.Data.ResultType {
+		return diff, nil
+	}
+
+	var err error
+	switch expected.Data.ResultType {
+	case "matrix":
+		err = diffMatrix(diff, expected.Data.Result, actual.Data.Result, s.tolerance)
+	case "vector":
+		err = diffVector(diff, expected.Data.Result, actual.Data.Result, s.tolerance)
+	case "scalar":
+		err = diffScalar(diff, expected.Data.Result, actual.Data.Result, s.tolerance)
+	}
+
+	return diff, err
+}
+
+func diffMatrix(diff *ResponsesDiff, expectedRaw, actualRaw json.RawMessage, tolerance float64) error {
+	var expected, actual model.Matrix
+
+	if err := json.Unmarshal(expectedRaw, &expected); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(actualRaw, &actual); err != nil {
+		return err
+	}
+
+	actualByFingerprint := make(map[model.Fingerprint]*model.SampleStream, len(actual))
+	for _, stream := range actual {
+		actualByFingerprint[stream.Metric.Fingerprint()] = stream
+	}
+
+	for _, expectedStream := range expected {
+		fp := expectedStream.Metric.Fingerprint()
+		actualStream, ok := actualByFingerprint[fp]
+		if !ok {
+			diff.SeriesRemoved = append(diff.SeriesRemoved, expectedStream.Metric.String())
+			continue
+		}
+		delete(actualByFingerprint, fp)
+
+		diffSamplePairs(diff, expectedStream.Metric, expectedStream.Values, actualStream.Values, tolerance)
+	}
+
+	for _, stream := range actualByFingerprint {
+		diff.SeriesAdded = append(diff.SeriesAdded, stream.Metric.String())
+	}
+	sort.Strings(diff.SeriesAdded)
+
+	return nil
+}
+
+// diffSamplePairs compares two lists of samples sorted by timestamp.
+func diffSamplePairs(diff *ResponsesDiff, metric model.Metric, expected, actual []model.SamplePair, tolerance float64) {
+	e, a := 0, 0
+
+	for e < len(expected) || a < len(actual) {
+		switch {
+		case a >= len(actual) || (e < len(expected) && expected[e].Timestamp < actual[a].Timestamp):
+			diff.addSample(metric, expected[e].Timestamp, &expected[e].Value, nil)
+			e++
+		case e >= len(expected) || actual[a].Timestamp < expected[e].Timestamp:
+			diff.addSample(metric, actual[a].Timestamp, nil, &actual[a].Value)
+			a++
+		default:
+			if !compareSampleValue(expected[e].Value, actual[a].Value, tolerance) {
+				diff.addSample(metric, expected[e].Timestamp, &expected[e].Value, &actual[a].Value)
+			}
+			e++
+			a++
+		}
+	}
+}
+
+func diffVector(diff *ResponsesDiff, expectedRaw, actualRaw json.RawMessage, tolerance float64) error {
+	var expected, actual model.Vector
+
+	if err := json.Unmarshal(expectedRaw, &expected); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(actualRaw, &actual); err != nil {
+		return err
+	}
+
+	actualByFingerprint := make(map[model.Fingerprint]*model.Sample, len(actual))
+	for _, sample := range actual {
+		actualByFingerprint[sample.Metric.Fingerprint()] = sample
+	}
+
+	for _, expectedSample := range expected {
+		fp := expectedSample.Metric.Fingerprint()
+		actualSample, ok := actualByFingerprint[fp]
+		if !ok {
+			diff.SeriesRemoved = append(diff.SeriesRemoved, expectedSample.Metric.String())
+			continue
+		}
+		delete(actualByFingerprint, fp)
+
+		diffSamplePairs(diff, expectedSample.Metric,
+			[]model.SamplePair{{Timestamp: expectedSample.Timestamp, Value: expectedSample.Value}},
+			[]model.SamplePair{{Timestamp: actualSample.Timestamp, Value: actualSample.Value}},
+			tolerance)
+	}
+
+	for _, sample := range actualByFingerprint {
+		diff.SeriesAdded = append(diff.SeriesAdded, sample.Metric.String())
+	}
+	sort.Strings(diff.SeriesAdded)
+
+	return nil
+}
+
+func diffScalar(diff *ResponsesDiff, expectedRaw, actualRaw json.RawMessage, tolerance float64) error {
+	var expected, actual model.Scalar
+
+	if err := json.Unmarshal(expectedRaw, &expected); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(actualRaw, &actual); err != nil {
+		return err
+	}
+
+	diffSamplePairs(diff, model.Metric{},
+		[]model.SamplePair{{Timestamp: expected.Timestamp, Value: expected.Value}},
+		[]model.SamplePair{{Timestamp: actual.Timestamp, Value: actual.Value}},
+		tolerance)
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package querytee
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplesComparator_Diff(t *testing.T) {
+	value := func(v float64) *model.SampleValue {
+		sv := model.SampleValue(v)
+		return &sv
+	}
+
+	for _, tc := range []struct {
+		name     string
+		expected string
+		actual   string
+		diff     *ResponsesDiff
+	}{
+		{
+			name:     "same matrix",
+			expected: `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"foo":"bar"},"values":[[1,"1"],[2,"2"]]}]}}`,
+			actual:   `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"foo":"bar"},"values":[[1,"1"],[2,"2"]]}]}}`,
+			diff: &ResponsesDiff{
+				ExpectedStatus: "success", ActualStatus: "success",
+				ExpectedResultType: "matrix", ActualResultType: "matrix",
+			},
+		},
+		{
+			name:     "different result type",
+			expected: `{"status":"success","data":{"resultType":"matrix","result":[]}}`,
+			actual:   `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+			diff: &ResponsesDiff{
+				ExpectedStatus: "success", ActualStatus: "success",
+				ExpectedResultType: "matrix", ActualResultType: "vector",
+			},
+		},
+		{
+			name:     "matrix with series added and removed",
+			expected: `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"foo":"bar"},"values":[[1,"1"]]},{"metric":{"foo":"baz"},"values":[[1,"1"]]}]}}`,
+			actual:   `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"foo":"bar"},"values":[[1,"1"]]},{"metric":{"foo":"qux"},"values":[[1,"1"]]}]}}`,
+			diff: &ResponsesDiff{
+				ExpectedStatus: "success", ActualStatus: "success",
+				ExpectedResultType: "matrix", ActualResultType: "matrix",
+				SeriesRemoved: []string{`{foo="baz"}`},
+				SeriesAdded:   []string{`{foo="qux"}`},
+			},
+		},
+		{
+			name:     "matrix with different and missing samples",
+			expected: `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"foo":"bar"},"values":[[1,"1"],[2,"2"],[3,"3"]]}]}}`,
+			actual:   `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"foo":"bar"},"values":[[1,"1"],[3,"4"],[4,"4"]]}]}}`,
+			diff: &ResponsesDiff{
+				ExpectedStatus: "success", ActualStatus: "success",
+				ExpectedResultType: "matrix", ActualResultType: "matrix",
+				Samples: []SampleDiff{
+					{Metric: `{foo="bar"}`, Timestamp: 2000, Expected: value(2)},
+					{Metric: `{foo="bar"}`, Timestamp: 3000, Expected: value(3), Actual: value(4), Delta: 1},
+					{Metric: `{foo="bar"}`, Timestamp: 4000, Actual: value(4)},
+				},
+			},
+		},
+		{
+			name:     "vector with different sample value within tolerance",
+			expected: `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"foo":"bar"},"value":[1,"1"]}]}}`,
+			actual:   `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"foo":"bar"},"value":[1,"1.0000001"]}]}}`,
+			diff: &ResponsesDiff{
+				ExpectedStatus: "success", ActualStatus: "success",
+				ExpectedResultType: "vector", ActualResultType: "vector",
+			},
+		},
+		{
+			name:     "vector with different sample value beyond tolerance",
+			expected: `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"foo":"bar"},"value":[1,"1"]}]}}`,
+			actual:   `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"foo":"bar"},"value":[1,"3"]},{"metric":{"foo":"baz"},"value":[1,"1"]}]}}`,
+			diff: &ResponsesDiff{
+				ExpectedStatus: "success", ActualStatus: "success",
+				ExpectedResultType: "vector", ActualResultType: "vector",
+				SeriesAdded: []string{`{foo="baz"}`},
+				Samples: []SampleDiff{
+					{Metric: `{foo="bar"}`, Timestamp: 1000, Expected: value(1), Actual: value(3), Delta: 2},
+				},
+			},
+		},
+		{
+			name:     "scalar with different timestamp",
+			expected: `{"status":"success","data":{"resultType":"scalar","result":[1,"1"]}}`,
+			actual:   `{"status":"success","data":{"resultType":"scalar","result":[2,"1"]}}`,
+			diff: &ResponsesDiff{
+				ExpectedStatus: "success", ActualStatus: "success",
+				ExpectedResultType: "scalar", ActualResultType: "scalar",
+				Samples: []SampleDiff{
+					{Metric: `{}`, Timestamp: 1000, Expected: value(1)},
+					{Metric: `{}`, Timestamp: 2000, Actual: value(1)},
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			comparator := NewSamplesComparator(0.001)
+
+			diff, err := comparator.Diff([]byte(tc.expected), []byte(tc.actual))
+			require.NoError(t, err)
+			assert.Equal(t, tc.diff, diff)
+		})
+	}
+}
@@ -0,0 +1,286 @@
+package ringtool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-kit/kit/log"
+	"github.com/pkg/errors"
+
+	"github.com/cortexproject/cortex/pkg/ring"
+	"github.com/cortexproject/cortex/pkg/ring/kv"
+	"github.com/cortexproject/cortex/pkg/ring/kv/codec"
+	"github.com/cortexproject/cortex/pkg/ring/kv/memberlist"
+	"github.com/cortexproject/cortex/pkg/util/services"
+)
+
+const (
+	// OutputTable prints the ring status as a table.
+	OutputTable = "table"
+
+	// OutputJSON prints the ring status as JSON, in the same format served by the ring status pages.
+	OutputJSON = "json"
+)
+
+// NewKVClient returns a client for the KV store holding the ring. When the store is memberlist,
+// the tool joins the memberlist cluster and waits until the state has been pulled from the
+// members it joined. The returned function must be called once done, in order to propagate the
+// changes (if any) and leave the cluster.
+func NewKVClient(cfg kv.Config, memberlistCfg memberlist.KVConfig, logger log.Logger) (kv.Client, func(), error) {
+	ringCodec := ring.GetCodec()
+
+	if cfg.Store != "memberlist" {
+		client, err := kv.NewClient(cfg, ringCodec, nil)
+		return client, func() {}, err
+	}
+
+	// The cluster is joined synchronously below, once memberlist is running.
+	joinMembers := memberlistCfg.JoinMembers
+	memberlistCfg.JoinMembers = nil
+	memberlistCfg.Codecs = []codec.Codec{ringCodec}
+
+	memberlistKV := memberlist.NewKV(memberlistCfg, logger)
+	if err := services.StartAndAwaitRunning(context.Background(), memberlistKV); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to start memberlist")
+	}
+	stop := func() {
+		_ = services.StopAndAwaitTerminated(context.Background(), memberlistKV)
+	}
+
+	// Joining a member pulls its full state.
+	if _, err := memberlistKV.JoinMembers(joinMembers); err != nil {
+		stop()
+		return nil, nil, errors.Wrap(err, "failed to join memberlist cluster")
+	}
+
+	client, err := memberlist.NewClient(memberlistKV, ringCodec)
+	if err != nil {
+		stop()
+		return nil, nil, err
+	}
+
+	return client, stop, nil
+}
+
+// Tool reads and modifies a ring directly in the KV store, without requiring the
+// components using the ring to be running.
+type Tool struct {
+	client           kv.Client
+	key              string
+	heartbeatTimeout time.Duration
+}
+
+// New makes a new Tool operating on the ring stored under key. The heartbeatTimeout is
+// used to report the health of the instances.
+func New(client kv.Client, key string, heartbeatTimeout time.Duration) *Tool {
+	return &Tool{
+		client:           client,
+		key:              key,
+		heartbeatTimeout: heartbeatTimeout,
+	}
+}
+
+// Status returns the current status of the ring.
+func (t *Tool) Status(ctx context.Context) (ring.RingStatus, error) {
+	val, err := t.client.Get(ctx, t.key)
+	if err != nil {
+		return ring.RingStatus{}, errors.Wrapf(err, "failed to read the ring %s", t.key)
+	}
+
+	desc := ring.NewDesc()
+	if val != nil {
+		desc = val.(*ring.Desc)
+	}
+
+	return ring.NewRingStatus(desc, t.heartbeatTimeout, time.Now()), nil
+}
+
+// Forget removes the instances from the ring. It fails if any of the instances is not in the ring.
+func (t *Tool) Forget(ctx context.Context, instanceIDs []string) error {
+	return t.client.CAS(ctx, t.key, func(in interface{}) (out interface{}, retry bool, err error) {
+		if in == nil {
+			return nil, false, fmt.Errorf("the ring %s is empty", t.key)
+		}
+
+		desc := in.(*ring.Desc)
+		for _, id := range instanceIDs {
+			if _, ok := desc.Ingesters[id]; !ok {
+				return nil, false, fmt.Errorf("instance %s not found in the ring", id)
+			}
+			desc.RemoveIngester(id)
+		}
+		return desc, true, nil
+	})
+}
+
+// SetState changes the state of the instance in the ring. The instance heartbeat timestamp is updated too,
+// otherwise the change could be discarded by memberlist. If the instance is running, its lifecycler may
+// override the state at the next heartbeat.
+func (t *Tool) SetState(ctx context.Context, instanceID string, state ring.InstanceState) error {
+	return t.client.CAS(ctx, t.key, func(in interface{}) (out interface{}, retry bool, err error) {
+		if in == nil {
+			return nil, false, fmt.Errorf("the ring %s is empty", t.key)
+		}
+
+		desc := in.(*ring.Desc)
+		instance, ok := desc.Ingesters[instanceID]
+		if !ok {
+			return nil, false, fmt.Errorf("instance %s not found in the ring", instanceID)
+		}
+
+		instance.State = state
+		instance.Timestamp = time.Now().Unix()
+		desc.Ingesters[instanceID] = instance
+		return desc, true, nil
+	})
+}
+
+// ParseInstanceState parses an instance state, case insensitive.
+func ParseInstanceState(s string) (ring.InstanceState, error) {
+	state, ok := ring.InstanceState_value[strings.ToUpper(s)]
+	if !ok {
+		return 0, fmt.Errorf("invalid instance state %q", s)
+	}
+	return ring.InstanceState(state), nil
+}
+
+// ReadStatus reads a ring status in JSON, as written by WriteStatus or served by the ring status pages.
+func ReadStatus(r io.Reader) (ring.RingStatus, error) {
+	status := ring.RingStatus{}
+	if err := json.NewDecoder(r).Decode(&status); err != nil {
+		return status, errors.Wrap(err, "failed to decode the ring status")
+	}
+	return status, nil
+}
+
+// WriteStatus writes the ring status in the given output format. Tokens are only included
+// in the table output if showTokens is true.
+func WriteStatus(w io.Writer, status ring.RingStatus, output string, showTokens bool) error {
+	switch output {
+	case OutputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(status)
+	case OutputTable:
+		return writeStatusTable(w, status, showTokens)
+	default:
+		return fmt.Errorf("unsupported output format %q", output)
+	}
+}
+
+func writeStatusTable(w io.Writer, status ring.RingStatus, showTokens bool) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "INSTANCE\tZONE\tSTATE\tHEALTHY\tADDRESS\tREGISTERED AT\tLAST HEARTBEAT\tTOKENS\tOWNERSHIP")
+	for _, i := range status.Instances {
+		registeredAt := "-"
+		if i.RegisteredAt != nil {
+			registeredAt = i.RegisteredAt.UTC().Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%t\t%s\t%s\t%s ago\t%d\t%.2f%%\n", i.ID, i.Zone, i.State, i.Healthy, i.Address, registeredAt,
+			(time.Duration(i.HeartbeatAgeSeconds) * time.Second).String(), i.NumTokens, i.Ownership)
+	}
+
+	if len(status.Zones) > 1 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "ZONE\tINSTANCES\tHEALTHY\tOWNERSHIP")
+		for _, z := range status.Zones {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%.2f%%\n", z.Zone, z.Instances, z.HealthyInstances, z.Ownership)
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if showTokens {
+		for _, i := range status.Instances {
+			tokens := make([]string, 0, len(i.Tokens))
+			for _, t := range i.Tokens {
+				tokens = append(tokens, fmt.Sprint(t))
+			}
+			fmt.Fprintf(w, "\nInstance %s tokens:\n%s\n", i.ID, strings.Join(tokens, " "))
+		}
+	}
+
+	return nil
+}
+
+// Diff returns the differences between two ring statuses, one per line, sorted by instance.
+// The heartbeat timestamps and the ownership, which change continuously, are not compared.
+func Diff(from, to ring.RingStatus) []string {
+	fromInstances := make(map[string]ring.InstanceStatus, len(from.Instances))
+	for _, i := range from.Instances {
+		fromInstances[i.ID] = i
+	}
+	toInstances := make(map[string]ring.InstanceStatus, len(to.Instances))
+	for _, i := range to.Instances {
+		toInstances[i.ID] = i
+	}
+
+	ids := make([]string, 0, len(fromInstances)+len(toInstances))
+	for id := range fromInstances {
+		ids = append(ids, id)
+	}
+	for id := range toInstances {
+		if _, ok := fromInstances[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var diffs []string
+	for _, id := range ids {
+		f, inFrom := fromInstances[id]
+		t, inTo := toInstances[id]
+
+		switch {
+		case !inFrom:
+			diffs = append(diffs, fmt.Sprintf("+ %s: added (zone: %q, state: %s, address: %s, tokens: %d)", id, t.Zone, t.State, t.Address, t.NumTokens))
+		case !inTo:
+			diffs = append(diffs, fmt.Sprintf("- %s: removed (zone: %q, state: %s, address: %s, tokens: %d)", id, f.Zone, f.State, f.Address, f.NumTokens))
+		default:
+			if f.State != t.State {
+				diffs = append(diffs, fmt.Sprintf("~ %s: state changed from %s to %s", id, f.State, t.State))
+			}
+			if f.Healthy != t.Healthy {
+				diffs = append(diffs, fmt.Sprintf("~ %s: healthy changed from %t to %t", id, f.Healthy, t.Healthy))
+			}
+			if f.Zone != t.Zone {
+				diffs = append(diffs, fmt.Sprintf("~ %s: zone changed from %q to %q", id, f.Zone, t.Zone))
+			}
+			if f.Address != t.Address {
+				diffs = append(diffs, fmt.Sprintf("~ %s: address changed from %s to %s", id, f.Address, t.Address))
+			}
+			if !ring.Tokens(f.Tokens).Equals(t.Tokens) {
+				diffs = append(diffs, fmt.Sprintf("~ %s: tokens changed (%d tokens before, %d tokens after)", id, f.NumTokens, t.NumTokens))
+			}
+			if !registeredAtEqual(f.RegisteredAt, t.RegisteredAt) {
+				diffs = append(diffs, fmt.Sprintf("~ %s: registered at changed from %s to %s", id, formatRegisteredAt(f.RegisteredAt), formatRegisteredAt(t.RegisteredAt)))
+			}
+		}
+	}
+
+	return diffs
+}
+
+func registeredAtEqual(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func formatRegisteredAt(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
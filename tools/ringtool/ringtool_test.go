@@ -0,0 +1,133 @@
+package ringtool
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cortexproject/cortex/pkg/ring"
+	"github.com/cortexproject/cortex/pkg/ring/kv/consul"
+)
+
+func prepareTool(t *testing.T) (*Tool, *consul.Client) {
+	client := consul.NewInMemoryClient(ring.GetCodec())
+
+	now := time.Now()
+	desc := ring.NewDesc()
+	desc.AddIngester("instance-1", "127.0.0.1", "zone-a", []uint32{1, 2}, ring.ACTIVE, now)
+	desc.AddIngester("instance-2", "127.0.0.2", "zone-b", []uint32{3, 4}, ring.ACTIVE, now)
+	desc.AddIngester("instance-3", "127.0.0.3", "zone-b", []uint32{5, 6}, ring.LEAVING, now)
+	require.NoError(t, client.CAS(context.Background(), ring.IngesterRingKey, func(interface{}) (interface{}, bool, error) {
+		return desc, true, nil
+	}))
+
+	return New(client, ring.IngesterRingKey, time.Minute), client
+}
+
+func TestTool_Status(t *testing.T) {
+	tool, _ := prepareTool(t)
+
+	status, err := tool.Status(context.Background())
+	require.NoError(t, err)
+	require.Len(t, status.Instances, 3)
+	assert.Equal(t, "instance-1", status.Instances[0].ID)
+	assert.Equal(t, "ACTIVE", status.Instances[0].State)
+	assert.True(t, status.Instances[0].Healthy)
+	require.Len(t, status.Zones, 2)
+	assert.Equal(t, 2, status.Zones[1].Instances)
+
+	// An empty ring.
+	status, err = New(consul.NewInMemoryClient(ring.GetCodec()), ring.IngesterRingKey, time.Minute).Status(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, status.Instances)
+}
+
+func TestTool_Forget(t *testing.T) {
+	tool, _ := prepareTool(t)
+	ctx := context.Background()
+
+	require.NoError(t, tool.Forget(ctx, []string{"instance-1", "instance-3"}))
+
+	status, err := tool.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, status.Instances, 1)
+	assert.Equal(t, "instance-2", status.Instances[0].ID)
+
+	// Unknown instances are not forgotten.
+	assert.Error(t, tool.Forget(ctx, []string{"instance-2", "unknown"}))
+
+	status, err = tool.Status(ctx)
+	require.NoError(t, err)
+	assert.Len(t, status.Instances, 1)
+}
+
+func TestTool_SetState(t *testing.T) {
+	tool, _ := prepareTool(t)
+	ctx := context.Background()
+
+	state, err := ParseInstanceState("leaving")
+	require.NoError(t, err)
+	require.NoError(t, tool.SetState(ctx, "instance-1", state))
+
+	status, err := tool.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "LEAVING", status.Instances[0].State)
+
+	assert.Error(t, tool.SetState(ctx, "unknown", ring.ACTIVE))
+
+	_, err = ParseInstanceState("unknown")
+	assert.Error(t, err)
+}
+
+func TestWriteAndReadStatus(t *testing.T) {
+	tool, _ := prepareTool(t)
+
+	status, err := tool.Status(context.Background())
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, WriteStatus(buf, status, OutputJSON, false))
+
+	read, err := ReadStatus(buf)
+	require.NoError(t, err)
+	assert.Empty(t, Diff(status, read))
+
+	buf.Reset()
+	require.NoError(t, WriteStatus(buf, status, OutputTable, true))
+	assert.Contains(t, buf.String(), "instance-3")
+	assert.Contains(t, buf.String(), "Instance instance-1 tokens:\n1 2\n")
+
+	assert.Error(t, WriteStatus(buf, status, "yaml", false))
+}
+
+func TestDiff(t *testing.T) {
+	tool, client := prepareTool(t)
+	ctx := context.Background()
+
+	from, err := tool.Status(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, client.CAS(ctx, ring.IngesterRingKey, func(in interface{}) (interface{}, bool, error) {
+		desc := in.(*ring.Desc)
+		desc.RemoveIngester("instance-1")
+		desc.AddIngester("instance-2", "127.0.0.20", "zone-b", []uint32{3, 4}, ring.ACTIVE, time.Now())
+		desc.AddIngester("instance-3", "127.0.0.3", "zone-b", []uint32{7, 8}, ring.LEFT, time.Now())
+		desc.AddIngester("instance-4", "127.0.0.4", "zone-a", []uint32{9}, ring.JOINING, time.Now())
+		return desc, true, nil
+	}))
+
+	to, err := tool.Status(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		`- instance-1: removed (zone: "zone-a", state: ACTIVE, address: 127.0.0.1, tokens: 2)`,
+		`~ instance-2: address changed from 127.0.0.2 to 127.0.0.20`,
+		`~ instance-3: state changed from LEAVING to LEFT`,
+		`~ instance-3: tokens changed (2 tokens before, 2 tokens after)`,
+		`+ instance-4: added (zone: "zone-a", state: JOINING, address: 127.0.0.4, tokens: 1)`,
+	}, Diff(from, to))
+}